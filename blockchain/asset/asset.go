@@ -0,0 +1,95 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package asset
+
+import (
+	"fmt"
+	"madledger/common"
+	"madledger/core"
+	"regexp"
+)
+
+// Asset is a named asset in _asset channel
+type Asset struct {
+	ID string
+	// Issuer is the only one who can issue the asset
+	Issuer   common.Address
+	Decimals uint8
+	// Cap is the max supply of the asset, zero means no limit
+	Cap    uint64
+	Supply uint64
+}
+
+// NewAsset is the constructor of Asset
+func NewAsset(id string, issuer common.Address, decimals uint8, cap uint64) *Asset {
+	return &Asset{
+		ID:       id,
+		Issuer:   issuer,
+		Decimals: decimals,
+		Cap:      cap,
+		Supply:   0,
+	}
+}
+
+// Issue increases supply of the asset
+func (a *Asset) Issue(value uint64) error {
+	supply := a.Supply + value
+	if supply < a.Supply {
+		return fmt.Errorf("supply of asset %s overflow", a.ID)
+	}
+	if a.Cap != 0 && supply > a.Cap {
+		return fmt.Errorf("supply of asset %s exceeds cap %d", a.ID, a.Cap)
+	}
+	a.Supply = supply
+	return nil
+}
+
+// Burn decreases supply of the asset
+func (a *Asset) Burn(value uint64) error {
+	if a.Supply < value {
+		return fmt.Errorf("supply of asset %s is less than %d", a.ID, value)
+	}
+	a.Supply -= value
+	return nil
+}
+
+// IsLegalAssetID return if the id can be used as an asset id
+func IsLegalAssetID(id string) bool {
+	if m, err := regexp.MatchString("^[A-Za-z0-9]{1,16}$", id); err != nil || !m {
+		return false
+	}
+	return true
+}
+
+// IsLegalTxID return if the id is well formed as the id of tx
+func IsLegalTxID(id string) bool {
+	if m, err := regexp.MatchString("^[0-9a-f]{64}$", id); err != nil || !m {
+		return false
+	}
+	return true
+}
+
+// GetAssetKey return the db key of asset
+func GetAssetKey(id string) []byte {
+	return []byte(fmt.Sprintf("%s$%s", core.ASSETCHANNELID, id))
+}
+
+// GetRedeemableKey return the db key of the asset that address exchanged
+// into channel and could be redeemed
+func GetRedeemableKey(channelID string, address common.Address) []byte {
+	return []byte(fmt.Sprintf("%s$redeemable$%s$%s", core.ASSETCHANNELID, channelID, address.String()))
+}
+
+// GetBurnKey return the db key of the tx burning token in channel, it is
+// set when the burned token is redeemed
+func GetBurnKey(channelID, burnID string) []byte {
+	return []byte(fmt.Sprintf("%s$burn$%s$%s", core.ASSETCHANNELID, channelID, burnID))
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package asset
+
+import (
+	"madledger/common"
+)
+
+// buffer keeps the changes of a tx until the tx succeeds, so a failed tx
+// changes nothing. SetAssetAdmin and the token of listener are not kept
+// because they are the last step of tx, nothing fails after them.
+type buffer struct {
+	Storage
+	Listener
+	kvs      map[string][]byte
+	keys     []string
+	accounts map[common.Address]common.Account
+	updated  []common.Address
+	paid     []string
+}
+
+func newBuffer(storage Storage, listener Listener) *buffer {
+	return &buffer{
+		Storage:  storage,
+		Listener: listener,
+		kvs:      make(map[string][]byte),
+		accounts: make(map[common.Address]common.Account),
+	}
+}
+
+// Get is the implementation of Storage
+func (b *buffer) Get(key []byte, couldBeEmpty bool) ([]byte, error) {
+	if value, ok := b.kvs[string(key)]; ok {
+		return value, nil
+	}
+	return b.Storage.Get(key, couldBeEmpty)
+}
+
+// Put is the implementation of Storage
+func (b *buffer) Put(key, value []byte) {
+	if _, ok := b.kvs[string(key)]; !ok {
+		b.keys = append(b.keys, string(key))
+	}
+	b.kvs[string(key)] = value
+}
+
+// GetOrCreateAccount is the implementation of Storage
+func (b *buffer) GetOrCreateAccount(address common.Address) (common.Account, error) {
+	account, ok := b.accounts[address]
+	if !ok {
+		return b.Storage.GetOrCreateAccount(address)
+	}
+	// copy assets so that the account kept is not changed
+	assets := make(map[string]uint64, len(account.Assets))
+	for id, balance := range account.Assets {
+		assets[id] = balance
+	}
+	account.Assets = assets
+	return account, nil
+}
+
+// UpdateAccounts is the implementation of Storage
+func (b *buffer) UpdateAccounts(accs ...common.Account) error {
+	for _, acc := range accs {
+		if _, ok := b.accounts[acc.GetAddress()]; !ok {
+			b.updated = append(b.updated, acc.GetAddress())
+		}
+		b.accounts[acc.GetAddress()] = acc
+	}
+	return nil
+}
+
+// DuePaid is the implementation of Listener
+func (b *buffer) DuePaid(channelID string) {
+	b.paid = append(b.paid, channelID)
+}
+
+// commit writes the changes into storage and notifies the paid dues
+func (b *buffer) commit() error {
+	var accs = make([]common.Account, 0, len(b.updated))
+	for _, address := range b.updated {
+		accs = append(accs, b.accounts[address])
+	}
+	if len(accs) != 0 {
+		if err := b.Storage.UpdateAccounts(accs...); err != nil {
+			return err
+		}
+	}
+	for _, key := range b.keys {
+		b.Storage.Put([]byte(key), b.kvs[key])
+	}
+	for _, channelID := range b.paid {
+		b.Listener.DuePaid(channelID)
+	}
+	return nil
+}
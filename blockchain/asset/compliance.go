@@ -0,0 +1,39 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package asset
+
+import (
+	"fmt"
+	"madledger/common"
+	"madledger/core"
+)
+
+// Here define the actions of compliance
+const (
+	ActionFreeze   = "freeze"
+	ActionUnfreeze = "unfreeze"
+	ActionBurn     = "burn"
+)
+
+// ComplianceRecord records an action the asset admin takes on an account
+type ComplianceRecord struct {
+	TxID        string
+	Action      string
+	AssetID     string `json:",omitempty"`
+	Value       uint64 `json:",omitempty"`
+	Reason      string `json:",omitempty"`
+	BlockNumber uint64
+}
+
+// GetComplianceKey return the db key of compliance history of address
+func GetComplianceKey(address common.Address) []byte {
+	return []byte(fmt.Sprintf("%s$compliance$%s", core.ASSETCHANNELID, address.String()))
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package asset
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"madledger/common"
+	"madledger/core"
+)
+
+// EscrowStatus is the status of escrow
+type EscrowStatus int
+
+// Here define the status of escrow
+const (
+	EscrowLocked EscrowStatus = iota
+	EscrowClaimed
+	EscrowRefunded
+)
+
+// Escrow locks asset of sender until it is claimed by recipient or refunded to sender.
+// If HashLock is empty, the escrow is a time-locked transfer which could be claimed
+// after expiry, else it is a HTLC which could be claimed with the preimage of HashLock
+// before expiry and refunded after expiry.
+type Escrow struct {
+	ID        string
+	Sender    common.Address
+	Recipient common.Address
+	AssetID   string `json:",omitempty"`
+	Value     uint64
+	// HashLock is the sha256 of the preimage
+	HashLock []byte `json:",omitempty"`
+	// ExpireHeight is the number of _asset block, zero means no limit
+	ExpireHeight uint64 `json:",omitempty"`
+	// ExpireTime is the unix time of _asset block, zero means no limit
+	ExpireTime int64 `json:",omitempty"`
+	// Preimage is set when a HTLC is claimed
+	Preimage []byte `json:",omitempty"`
+	Status   EscrowStatus
+}
+
+// NewEscrow is the constructor of Escrow
+func NewEscrow(id string, sender common.Address, payload *Payload, value uint64) (*Escrow, error) {
+	if payload.Address == common.ZeroAddress {
+		return nil, errors.New("the recipient of escrow can not be empty")
+	}
+	if value == 0 {
+		return nil, errors.New("the value of escrow can not be zero")
+	}
+	if payload.ExpireHeight == 0 && payload.ExpireTime == 0 {
+		return nil, errors.New("the expiry of escrow can not be empty")
+	}
+	if len(payload.HashLock) != 0 && len(payload.HashLock) != sha256.Size {
+		return nil, fmt.Errorf("the length of hash lock should be %d", sha256.Size)
+	}
+	return &Escrow{
+		ID:           id,
+		Sender:       sender,
+		Recipient:    payload.Address,
+		AssetID:      payload.AssetID,
+		Value:        value,
+		HashLock:     payload.HashLock,
+		ExpireHeight: payload.ExpireHeight,
+		ExpireTime:   payload.ExpireTime,
+		Status:       EscrowLocked,
+	}, nil
+}
+
+// IsExpired return if the escrow is expired at the block,
+// all limits that are set should be reached
+func (e *Escrow) IsExpired(number uint64, time int64) bool {
+	if e.ExpireHeight != 0 && number < e.ExpireHeight {
+		return false
+	}
+	if e.ExpireTime != 0 && time < e.ExpireTime {
+		return false
+	}
+	return true
+}
+
+// Claim releases the escrow to recipient
+func (e *Escrow) Claim(claimer common.Address, preimage []byte, number uint64, time int64) error {
+	if e.Status != EscrowLocked {
+		return fmt.Errorf("escrow %s is not locked", e.ID)
+	}
+	if claimer != e.Recipient {
+		return fmt.Errorf("%s is not the recipient of escrow %s", claimer.String(), e.ID)
+	}
+	expired := e.IsExpired(number, time)
+	if len(e.HashLock) == 0 {
+		if !expired {
+			return fmt.Errorf("escrow %s is not expired", e.ID)
+		}
+	} else {
+		if expired {
+			return fmt.Errorf("escrow %s is expired", e.ID)
+		}
+		hash := sha256.Sum256(preimage)
+		if !bytes.Equal(hash[:], e.HashLock) {
+			return fmt.Errorf("wrong preimage of escrow %s", e.ID)
+		}
+		e.Preimage = preimage
+	}
+	e.Status = EscrowClaimed
+	return nil
+}
+
+// Refund returns the escrow to sender
+func (e *Escrow) Refund(refunder common.Address, number uint64, time int64) error {
+	if e.Status != EscrowLocked {
+		return fmt.Errorf("escrow %s is not locked", e.ID)
+	}
+	if refunder != e.Sender {
+		return fmt.Errorf("%s is not the sender of escrow %s", refunder.String(), e.ID)
+	}
+	if len(e.HashLock) == 0 {
+		return fmt.Errorf("escrow %s is time locked and can not be refunded", e.ID)
+	}
+	if !e.IsExpired(number, time) {
+		return fmt.Errorf("escrow %s is not expired", e.ID)
+	}
+	e.Status = EscrowRefunded
+	return nil
+}
+
+// GetEscrowKey return the db key of escrow
+func GetEscrowKey(id string) []byte {
+	return []byte(fmt.Sprintf("%s$escrow$%s", core.ASSETCHANNELID, id))
+}
@@ -0,0 +1,524 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package asset
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	cc "madledger/blockchain/config"
+	"madledger/common"
+	"madledger/common/crypto"
+	"madledger/core"
+)
+
+// Storage is where the state of _asset is kept, orderer and peer implement
+// it with their own db
+type Storage interface {
+	// Get return nil if the key is not exist and couldBeEmpty is true
+	Get(key []byte, couldBeEmpty bool) ([]byte, error)
+	Put(key, value []byte)
+	GetOrCreateAccount(address common.Address) (common.Account, error)
+	UpdateAccounts(accs ...common.Account) error
+	IsAssetAdmin(pk crypto.PublicKey, pkAlgo crypto.Algorithm) bool
+	// SetAssetAdmin only works when it is first called
+	SetAssetAdmin(pk crypto.PublicKey, pkAlgo crypto.Algorithm) error
+	GetChannelProfile(id string) (*cc.Profile, error)
+}
+
+// Listener receives the side effects of _asset which orderer and peer
+// handle differently, they are not part of the state of _asset
+type Listener interface {
+	// DuePaid is called when the due of channel is paid off
+	DuePaid(channelID string)
+	// TokenExchanged is called when sender exchanges value of asset into token of channel
+	TokenExchanged(channelID string, sender common.Address, value uint64) error
+}
+
+// Machine is the state machine of _asset, it is deterministic so that
+// orderer and peer get the same state after executing the same blocks
+type Machine struct {
+	storage  Storage
+	listener Listener
+}
+
+// NewMachine is the constructor of Machine
+func NewMachine(storage Storage, listener Listener) *Machine {
+	return &Machine{
+		storage:  storage,
+		listener: listener,
+	}
+}
+
+// Execute executes a tx of _asset block, the error is the status of tx,
+// and nothing is changed if the tx fails
+func (m *Machine) Execute(tx *core.Tx, number uint64, time int64) error {
+	b := newBuffer(m.storage, m.listener)
+	if err := (&Machine{storage: b, listener: b}).execute(tx, number, time); err != nil {
+		return err
+	}
+	return b.commit()
+}
+
+func (m *Machine) execute(tx *core.Tx, number uint64, time int64) error {
+	var payload Payload
+	if err := json.Unmarshal(tx.Data.Payload, &payload); err != nil {
+		return err
+	}
+	sender, err := tx.GetSender()
+	if err != nil {
+		return err
+	}
+	if payload.AssetID != "" && !IsLegalAssetID(payload.AssetID) {
+		return fmt.Errorf("illegal asset id %s", payload.AssetID)
+	}
+	//if receiver is not set, issue or transfer money to a channel
+	value := tx.Data.Value
+	recipient := payload.Address
+	if recipient == common.ZeroAddress {
+		recipient = common.AddressFromChannelID(payload.ChannelID)
+	}
+
+	contract, ok := core.GetSystemContract(tx.GetReceiver())
+	if !ok || contract.ChannelID != core.ASSETCHANNELID {
+		return errors.New("Contract not support in _asset")
+	}
+	switch contract.Type {
+	case core.ISSUE:
+		return m.issue(tx, sender, recipient, value, &payload)
+	case core.TRANSFER:
+		return m.transfer(sender, recipient, value, payload.AssetID, payload.ChannelID)
+	case core.TOKEN:
+		return m.exchangeToken(sender, value, payload.AssetID, payload.ChannelID)
+	case core.REDEEM:
+		return m.redeemToken(tx, value, &payload)
+	case core.ESCROW:
+		return m.escrow(tx.ID, sender, value, &payload)
+	case core.CLAIM:
+		return m.claim(sender, &payload, number, time)
+	case core.REFUND:
+		return m.refund(sender, &payload, number, time)
+	default:
+		return m.comply(tx, contract.Type, value, &payload, number)
+	}
+}
+
+func (m *Machine) issue(tx *core.Tx, sender, receiver common.Address, value uint64, payload *Payload) error {
+	if payload.AssetID != "" {
+		return m.issueAsset(sender, receiver, value, payload)
+	}
+	pk, err := crypto.NewPublicKey(tx.Data.Sig.PK, tx.Data.Sig.Algo)
+	if err != nil {
+		return fmt.Errorf("issue authentication failed: %v", err)
+	}
+	isAdmin := m.storage.IsAssetAdmin(pk, tx.Data.Sig.Algo)
+	if value != 0 {
+		receiverAccount, err := m.storage.GetOrCreateAccount(receiver)
+		if err != nil {
+			return err
+		}
+		valueLeft, err := m.payDue(&receiverAccount, value, payload.ChannelID)
+		if err != nil {
+			return err
+		}
+		if err = receiverAccount.AddBalance(valueLeft); err != nil {
+			return err
+		}
+		if err = m.storage.UpdateAccounts(receiverAccount); err != nil {
+			return err
+		}
+	}
+	// the first issuer becomes the asset admin, this is the last step so
+	// the admin is not set if the issue fails
+	if !isAdmin && m.storage.SetAssetAdmin(pk, tx.Data.Sig.Algo) != nil {
+		return errors.New("issue authentication failed: not the asset admin")
+	}
+	return nil
+}
+
+// issueAsset issue a named asset, the first issue of an asset creates it
+// and the sender becomes the issuer of the asset
+func (m *Machine) issueAsset(sender, receiver common.Address, value uint64, payload *Payload) error {
+	asset, err := m.getAsset(payload.AssetID)
+	if err != nil {
+		return err
+	}
+	if asset == nil {
+		asset = NewAsset(payload.AssetID, sender, payload.Decimals, payload.Cap)
+	} else if asset.Issuer != sender {
+		return fmt.Errorf("issue authentication failed: %s is not the issuer of %s", sender.String(), asset.ID)
+	}
+	if err = asset.Issue(value); err != nil {
+		return err
+	}
+
+	receiverAccount, err := m.storage.GetOrCreateAccount(receiver)
+	if err != nil {
+		return err
+	}
+	if err = receiverAccount.AddAssetBalance(asset.ID, value); err != nil {
+		return err
+	}
+	if err = m.setAsset(asset); err != nil {
+		return err
+	}
+	return m.storage.UpdateAccounts(receiverAccount)
+}
+
+func (m *Machine) transfer(sender, receiver common.Address, value uint64, assetID, channelID string) error {
+	if value == 0 || sender == receiver {
+		return nil
+	}
+	senderAccount, err := m.getUnfrozenAccount(sender)
+	if err != nil {
+		return err
+	}
+	if err = senderAccount.SubAssetBalance(assetID, value); err != nil {
+		return err
+	}
+	receiverAccount, err := m.storage.GetOrCreateAccount(receiver)
+	if err != nil {
+		return err
+	}
+	// only the native asset could pay the due of channel
+	valueLeft := value
+	if assetID == "" {
+		valueLeft, err = m.payDue(&receiverAccount, value, channelID)
+		if err != nil {
+			return err
+		}
+	}
+	if err = receiverAccount.AddAssetBalance(assetID, valueLeft); err != nil {
+		return err
+	}
+	return m.storage.UpdateAccounts(senderAccount, receiverAccount)
+}
+
+func (m *Machine) exchangeToken(sender common.Address, value uint64, assetID, channelID string) error {
+	if assetID != "" {
+		return errors.New("only the native asset could be exchanged for token")
+	}
+	if err := m.transfer(sender, common.AddressFromChannelID(channelID), value, assetID, channelID); err != nil {
+		return err
+	}
+	redeemable, err := m.getUint64(GetRedeemableKey(channelID, sender))
+	if err != nil {
+		return err
+	}
+	m.putUint64(GetRedeemableKey(channelID, sender), redeemable+value)
+	return m.listener.TokenExchanged(channelID, sender, value)
+}
+
+// redeemToken gives the asset exchanged into channel back to the owner
+// after the token is burned in channel. Orderer and the peers not running
+// the channel could not tell whether the token is burned, so the redeem is
+// sent by an admin of channel with the id of the burning tx, and it only
+// depends on the state that all of them have. _asset trusts the admin that
+// the burning tx succeeded and burned value*AssetTokenRatio token of owner.
+func (m *Machine) redeemToken(tx *core.Tx, value uint64, payload *Payload) error {
+	if payload.AssetID != "" {
+		return errors.New("only the native asset could be redeemed from token")
+	}
+	if value == 0 {
+		return nil
+	}
+	profile, err := m.storage.GetChannelProfile(payload.ChannelID)
+	if err != nil {
+		return fmt.Errorf("channel %s is not exist", payload.ChannelID)
+	}
+	sender := &core.Member{PK: tx.Data.Sig.PK}
+	var isAdmin bool
+	for _, admin := range profile.Admins {
+		if admin.Equal(sender) {
+			isAdmin = true
+			break
+		}
+	}
+	if !isAdmin {
+		return fmt.Errorf("redeem authentication failed: not the admin of channel %s", payload.ChannelID)
+	}
+	if payload.Address == common.ZeroAddress {
+		return errors.New("the owner of token can not be empty")
+	}
+	if !IsLegalTxID(payload.BurnID) {
+		return fmt.Errorf("illegal id of burning tx %s", payload.BurnID)
+	}
+	burned, err := m.storage.Get(GetBurnKey(payload.ChannelID, payload.BurnID), true)
+	if err != nil {
+		return err
+	}
+	if burned != nil {
+		return fmt.Errorf("token burned by %s is redeemed", payload.BurnID)
+	}
+	redeemable, err := m.getUint64(GetRedeemableKey(payload.ChannelID, payload.Address))
+	if err != nil {
+		return err
+	}
+	if redeemable < value {
+		return fmt.Errorf("redeemable asset %d is less than %d", redeemable, value)
+	}
+
+	channelAccount, err := m.storage.GetOrCreateAccount(common.AddressFromChannelID(payload.ChannelID))
+	if err != nil {
+		return err
+	}
+	ownerAccount, err := m.getUnfrozenAccount(payload.Address)
+	if err != nil {
+		return err
+	}
+	// only orderer uses the balance of channel to pay block price, so the
+	// part that the balance can not pay is added to due of channel
+	paid := channelAccount.GetBalance()
+	if paid > value {
+		paid = value
+	}
+	if err = channelAccount.SubBalance(paid); err != nil {
+		return err
+	}
+	if err = channelAccount.AddDue(value - paid); err != nil {
+		return err
+	}
+	if err = ownerAccount.AddBalance(value); err != nil {
+		return err
+	}
+	if err = m.storage.UpdateAccounts(channelAccount, ownerAccount); err != nil {
+		return err
+	}
+	m.putUint64(GetRedeemableKey(payload.ChannelID, payload.Address), redeemable-value)
+	m.storage.Put(GetBurnKey(payload.ChannelID, payload.BurnID), []byte(tx.ID))
+	return nil
+}
+
+// escrow locks asset of sender, the id of escrow is the id of tx
+func (m *Machine) escrow(id string, sender common.Address, value uint64, payload *Payload) error {
+	escrow, err := NewEscrow(id, sender, payload, value)
+	if err != nil {
+		return err
+	}
+	exist, err := m.getEscrow(id)
+	if err != nil {
+		return err
+	}
+	if exist != nil {
+		return fmt.Errorf("escrow %s exists", id)
+	}
+
+	senderAccount, err := m.getUnfrozenAccount(sender)
+	if err != nil {
+		return err
+	}
+	if err = senderAccount.SubAssetBalance(escrow.AssetID, value); err != nil {
+		return err
+	}
+	if err = m.setEscrow(escrow); err != nil {
+		return err
+	}
+	return m.storage.UpdateAccounts(senderAccount)
+}
+
+// claim releases the escrow to its recipient
+func (m *Machine) claim(sender common.Address, payload *Payload, number uint64, time int64) error {
+	escrow, err := m.getEscrow(payload.EscrowID)
+	if err != nil {
+		return err
+	}
+	if escrow == nil {
+		return fmt.Errorf("escrow %s is not exist", payload.EscrowID)
+	}
+	if err = escrow.Claim(sender, payload.Preimage, number, time); err != nil {
+		return err
+	}
+	return m.releaseEscrow(escrow, escrow.Recipient)
+}
+
+// refund returns the expired escrow to its sender
+func (m *Machine) refund(sender common.Address, payload *Payload, number uint64, time int64) error {
+	escrow, err := m.getEscrow(payload.EscrowID)
+	if err != nil {
+		return err
+	}
+	if escrow == nil {
+		return fmt.Errorf("escrow %s is not exist", payload.EscrowID)
+	}
+	if err = escrow.Refund(sender, number, time); err != nil {
+		return err
+	}
+	return m.releaseEscrow(escrow, escrow.Sender)
+}
+
+func (m *Machine) releaseEscrow(escrow *Escrow, receiver common.Address) error {
+	receiverAccount, err := m.storage.GetOrCreateAccount(receiver)
+	if err != nil {
+		return err
+	}
+	if err = receiverAccount.AddAssetBalance(escrow.AssetID, escrow.Value); err != nil {
+		return err
+	}
+	if err = m.setEscrow(escrow); err != nil {
+		return err
+	}
+	return m.storage.UpdateAccounts(receiverAccount)
+}
+
+// comply takes the compliance action on account, only the asset admin could do this
+func (m *Machine) comply(tx *core.Tx, txType core.TxType, value uint64, payload *Payload, number uint64) error {
+	pk, err := crypto.NewPublicKey(tx.Data.Sig.PK, tx.Data.Sig.Algo)
+	if err != nil {
+		return err
+	}
+	if !m.storage.IsAssetAdmin(pk, tx.Data.Sig.Algo) {
+		return errors.New("compliance authentication failed: not the asset admin")
+	}
+	if payload.Address == common.ZeroAddress {
+		return errors.New("the account of compliance can not be empty")
+	}
+
+	account, err := m.storage.GetOrCreateAccount(payload.Address)
+	if err != nil {
+		return err
+	}
+	record := ComplianceRecord{
+		TxID:        tx.ID,
+		Reason:      payload.Reason,
+		BlockNumber: number,
+	}
+	switch txType {
+	case core.FREEZE:
+		account.Frozen = true
+		record.Action = ActionFreeze
+	case core.UNFREEZE:
+		account.Frozen = false
+		record.Action = ActionUnfreeze
+	default:
+		if err = m.burn(&account, payload.AssetID, value); err != nil {
+			return err
+		}
+		record.Action = ActionBurn
+		record.AssetID = payload.AssetID
+		record.Value = value
+	}
+	if err = m.addComplianceRecord(payload.Address, record); err != nil {
+		return err
+	}
+	return m.storage.UpdateAccounts(account)
+}
+
+// burn destroys the balance of account, and the supply of named asset decreases
+func (m *Machine) burn(account *common.Account, assetID string, value uint64) error {
+	if err := account.SubAssetBalance(assetID, value); err != nil {
+		return err
+	}
+	if assetID == "" {
+		return nil
+	}
+	asset, err := m.getAsset(assetID)
+	if err != nil {
+		return err
+	}
+	if asset == nil {
+		return fmt.Errorf("asset %s is not exist", assetID)
+	}
+	if err = asset.Burn(value); err != nil {
+		return err
+	}
+	return m.setAsset(asset)
+}
+
+// payDue pays the due of account first and return the value left
+func (m *Machine) payDue(acc *common.Account, value uint64, channelID string) (uint64, error) {
+	due := acc.GetDue()
+	if due == 0 {
+		return value, nil
+	}
+	if value < due {
+		return 0, acc.SubDue(value)
+	}
+	if err := acc.SubDue(due); err != nil {
+		return 0, err
+	}
+	m.listener.DuePaid(channelID)
+	return value - due, nil
+}
+
+func (m *Machine) getUnfrozenAccount(address common.Address) (common.Account, error) {
+	account, err := m.storage.GetOrCreateAccount(address)
+	if err != nil {
+		return account, err
+	}
+	if account.Frozen {
+		return account, fmt.Errorf("account %s is frozen", address.String())
+	}
+	return account, nil
+}
+
+func (m *Machine) getAsset(id string) (*Asset, error) {
+	var asset Asset
+	if exist, err := m.getJSON(GetAssetKey(id), &asset); err != nil || !exist {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+func (m *Machine) setAsset(asset *Asset) error {
+	return m.putJSON(GetAssetKey(asset.ID), asset)
+}
+
+func (m *Machine) getEscrow(id string) (*Escrow, error) {
+	var escrow Escrow
+	if exist, err := m.getJSON(GetEscrowKey(id), &escrow); err != nil || !exist {
+		return nil, err
+	}
+	return &escrow, nil
+}
+
+func (m *Machine) setEscrow(escrow *Escrow) error {
+	return m.putJSON(GetEscrowKey(escrow.ID), escrow)
+}
+
+func (m *Machine) addComplianceRecord(address common.Address, record ComplianceRecord) error {
+	var records []ComplianceRecord
+	if _, err := m.getJSON(GetComplianceKey(address), &records); err != nil {
+		return err
+	}
+	return m.putJSON(GetComplianceKey(address), append(records, record))
+}
+
+func (m *Machine) getJSON(key []byte, v interface{}) (bool, error) {
+	data, err := m.storage.Get(key, true)
+	if err != nil || data == nil {
+		return false, err
+	}
+	return true, json.Unmarshal(data, v)
+}
+
+func (m *Machine) putJSON(key []byte, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	m.storage.Put(key, data)
+	return nil
+}
+
+func (m *Machine) getUint64(key []byte) (uint64, error) {
+	data, err := m.storage.Get(key, true)
+	if err != nil || data == nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(data), nil
+}
+
+func (m *Machine) putUint64(key []byte, value uint64) {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, value)
+	m.storage.Put(key, data)
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package asset
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	cc "madledger/blockchain/config"
+	"madledger/common"
+	"madledger/common/crypto"
+	"madledger/core"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStorage is the Storage kept in memory
+type memoryStorage struct {
+	kvs      map[string][]byte
+	accounts map[common.Address]common.Account
+	admin    []byte
+	profiles map[string]*cc.Profile
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{
+		kvs:      make(map[string][]byte),
+		accounts: make(map[common.Address]common.Account),
+		profiles: make(map[string]*cc.Profile),
+	}
+}
+
+func (s *memoryStorage) Get(key []byte, couldBeEmpty bool) ([]byte, error) {
+	return s.kvs[string(key)], nil
+}
+
+func (s *memoryStorage) Put(key, value []byte) {
+	s.kvs[string(key)] = value
+}
+
+func (s *memoryStorage) GetOrCreateAccount(address common.Address) (common.Account, error) {
+	if account, ok := s.accounts[address]; ok {
+		return account, nil
+	}
+	return *common.NewAccount(address), nil
+}
+
+func (s *memoryStorage) UpdateAccounts(accs ...common.Account) error {
+	for _, acc := range accs {
+		s.accounts[acc.GetAddress()] = acc
+	}
+	return nil
+}
+
+func (s *memoryStorage) IsAssetAdmin(pk crypto.PublicKey, pkAlgo crypto.Algorithm) bool {
+	data, _ := pk.Bytes()
+	return s.admin != nil && bytes.Equal(data, s.admin)
+}
+
+func (s *memoryStorage) SetAssetAdmin(pk crypto.PublicKey, pkAlgo crypto.Algorithm) error {
+	if s.admin != nil {
+		return errors.New("_asset admin exists")
+	}
+	s.admin, _ = pk.Bytes()
+	return nil
+}
+
+func (s *memoryStorage) GetChannelProfile(id string) (*cc.Profile, error) {
+	if profile, ok := s.profiles[id]; ok {
+		return profile, nil
+	}
+	return nil, fmt.Errorf("channel %s is not exist", id)
+}
+
+// recorder records the dues paid, and fails the exchange if err is set
+type recorder struct {
+	paid []string
+	err  error
+}
+
+func (r *recorder) DuePaid(channelID string) {
+	r.paid = append(r.paid, channelID)
+}
+
+func (r *recorder) TokenExchanged(channelID string, sender common.Address, value uint64) error {
+	return r.err
+}
+
+// TestExecuteFailed executes txs which fail after changing something,
+// nothing should be changed by them
+func TestExecuteFailed(t *testing.T) {
+	storage := newMemoryStorage()
+	listener := new(recorder)
+	machine := NewMachine(storage, listener)
+
+	key, err := crypto.GeneratePrivateKey(crypto.KeyAlgoSecp256k1)
+	require.NoError(t, err)
+	sender, err := key.PubKey().Address()
+	require.NoError(t, err)
+	newTx := func(contract common.Address, payload Payload, value uint64) *core.Tx {
+		data, _ := json.Marshal(payload)
+		tx, err := core.NewTx(core.ASSETCHANNELID, contract, data, value, "", key)
+		require.NoError(t, err)
+		return tx
+	}
+
+	// the balance of receiver overflows, so the sender is not the admin
+	full := common.HexToAddress("0x1")
+	storage.UpdateAccounts(common.Account{Address: full, Balance: math.MaxUint64})
+	require.Error(t, machine.Execute(newTx(core.IssueContractAddress, Payload{Address: full}, 1), 1, 0))
+	require.False(t, storage.IsAssetAdmin(key.PubKey(), key.PubKey().Algo()))
+	require.NoError(t, machine.Execute(newTx(core.IssueContractAddress, Payload{Address: sender}, 10), 1, 0))
+	require.True(t, storage.IsAssetAdmin(key.PubKey(), key.PubKey().Algo()))
+
+	// the due of channel is paid but its balance overflows
+	channel := common.AddressFromChannelID("test")
+	storage.UpdateAccounts(common.Account{Address: channel, Balance: math.MaxUint64, Due: 5})
+	require.Error(t, machine.Execute(newTx(core.TransferContractrAddress, Payload{ChannelID: "test"}, 10), 2, 0))
+	account, err := storage.GetOrCreateAccount(channel)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), account.GetDue())
+	account, err = storage.GetOrCreateAccount(sender)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), account.GetBalance())
+	require.Empty(t, listener.paid)
+
+	// the asset is transferred into channel but the token is not exchanged
+	storage.UpdateAccounts(common.Account{Address: channel})
+	listener.err = errors.New("failed to exchange")
+	require.Error(t, machine.Execute(newTx(core.TokenExchangeAddress, Payload{ChannelID: "test"}, 4), 3, 0))
+	account, err = storage.GetOrCreateAccount(sender)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), account.GetBalance())
+	account, err = storage.GetOrCreateAccount(channel)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), account.GetBalance())
+	data, err := storage.Get(GetRedeemableKey("test", sender), true)
+	require.NoError(t, err)
+	require.Nil(t, data)
+
+	// all of them succeed when nothing fails
+	listener.err = nil
+	require.NoError(t, machine.Execute(newTx(core.TokenExchangeAddress, Payload{ChannelID: "test"}, 4), 4, 0))
+	account, err = storage.GetOrCreateAccount(sender)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), account.GetBalance())
+	data, err = storage.Get(GetRedeemableKey("test", sender), true)
+	require.NoError(t, err)
+	require.NotNil(t, data)
+}
+
+// TestRedeemToken redeems the token burned in channel, only the admin of
+// channel could redeem with a well formed id of burning tx once
+func TestRedeemToken(t *testing.T) {
+	storage := newMemoryStorage()
+	machine := NewMachine(storage, new(recorder))
+
+	adminKey, err := crypto.GeneratePrivateKey(crypto.KeyAlgoSecp256k1)
+	require.NoError(t, err)
+	admin, err := core.NewMember(adminKey.PubKey(), "admin")
+	require.NoError(t, err)
+	storage.profiles["test"] = &cc.Profile{Admins: []*core.Member{admin}, AssetTokenRatio: 1}
+	key, err := crypto.GeneratePrivateKey(crypto.KeyAlgoSecp256k1)
+	require.NoError(t, err)
+	owner, err := key.PubKey().Address()
+	require.NoError(t, err)
+	newTx := func(contract common.Address, payload Payload, value uint64, key crypto.PrivateKey) *core.Tx {
+		data, _ := json.Marshal(payload)
+		tx, err := core.NewTx(core.ASSETCHANNELID, contract, data, value, "", key)
+		require.NoError(t, err)
+		return tx
+	}
+	storage.UpdateAccounts(common.Account{Address: owner, Balance: 10})
+	require.NoError(t, machine.Execute(newTx(core.TokenExchangeAddress, Payload{ChannelID: "test"}, 10, key), 1, 0))
+
+	burn, err := core.NewTx("test", core.TokenBurnAddress, nil, 4, "", key)
+	require.NoError(t, err)
+	for i, burnID := range []string{"", "burn", strings.ToUpper(burn.ID), burn.ID[1:]} {
+		redeem := newTx(core.TokenRedeemAddress, Payload{Address: owner, ChannelID: "test", BurnID: burnID}, 4, adminKey)
+		require.Error(t, machine.Execute(redeem, uint64(i+2), 0))
+	}
+	payload := Payload{Address: owner, ChannelID: "test", BurnID: burn.ID}
+	require.Error(t, machine.Execute(newTx(core.TokenRedeemAddress, payload, 4, key), 6, 0))
+	require.NoError(t, machine.Execute(newTx(core.TokenRedeemAddress, payload, 4, adminKey), 7, 0))
+	require.Error(t, machine.Execute(newTx(core.TokenRedeemAddress, payload, 4, adminKey), 8, 0))
+	account, err := storage.GetOrCreateAccount(owner)
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), account.GetBalance())
+}
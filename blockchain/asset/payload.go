@@ -19,4 +19,20 @@ type Payload struct {
 
 	Address   common.Address
 	ChannelID string
+	// AssetID is the id of asset, empty means the native asset
+	AssetID string `json:",omitempty"`
+	// Decimals and Cap only work when the first issue creates the asset
+	Decimals uint8  `json:",omitempty"`
+	Cap      uint64 `json:",omitempty"`
+	// HashLock, ExpireHeight and ExpireTime only work when creating an escrow
+	HashLock     []byte `json:",omitempty"`
+	ExpireHeight uint64 `json:",omitempty"`
+	ExpireTime   int64  `json:",omitempty"`
+	// EscrowID and Preimage work when claiming or refunding an escrow
+	EscrowID string `json:",omitempty"`
+	Preimage []byte `json:",omitempty"`
+	// Reason is the reason code of freeze, unfreeze and burn
+	Reason string `json:",omitempty"`
+	// BurnID is the id of tx burning the token in channel when redeeming
+	BurnID string `json:",omitempty"`
 }
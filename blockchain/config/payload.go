@@ -45,6 +45,20 @@ type Profile struct {
 
 	// Block Storage Price
 	BlockPrice uint64
+
+	// PrecompileGas is the gas of the precompiled SM2 and SM3 contracts in
+	// evm, the default gas is used if it is nil
+	PrecompileGas *PrecompileGas `json:",omitempty"`
+}
+
+// PrecompileGas is the gas of the precompiled contracts, the gas of a call
+// is the base gas plus the gas per word of input
+type PrecompileGas struct {
+	SM3Base             uint64
+	SM3PerWord          uint64
+	SM2VerifyBase       uint64
+	SM2VerifyPerWord    uint64
+	PubKeyToAddressBase uint64
 }
 
 // Verify returns if a payload is packed well
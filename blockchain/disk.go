@@ -11,47 +11,134 @@
 package blockchain
 
 import (
-	"bufio"
-	"encoding/json"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"hash/crc32"
 	"io/ioutil"
+	"madledger/common"
+	"madledger/common/compress"
 	"madledger/common/util"
 	"madledger/core"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 )
 
-// load loads a channel and return the block expected
-// if the envirnoment is not completed, then complete the env
-// todo: the damaged circumstance is not consided completed
-func load(dir string) (uint64, error) {
-	var err error
-	genesisBlockPath, _ := util.MakeFileAbs("0.json", dir)
-	cachePath, _ := util.MakeFileAbs(".cache", dir)
-	if util.FileExists(dir) {
-		gbExist := util.FileExists(genesisBlockPath)
-		cacheExist := util.FileExists(cachePath)
-		if gbExist && cacheExist {
-			num, err := loadCache(cachePath)
-			if err != nil {
-				return 0, err
-			}
-			return num + 1, err
-		}
-		if gbExist && !cacheExist { // only exists gb, then the env is destoryed
-			return 0, fmt.Errorf("Cache file of %s is damaged", dir)
-		}
-		if cacheExist && !gbExist {
-			return 0, fmt.Errorf("Blocks files of %s are damaged", dir)
-		}
-		return 0, nil
+/*
+*  Blocks are appended to segments, each segment holds segmentBlocks blocks.
+*  1. Segment data: <segment>.blk, records of block, and a record is
+*     length(4 bytes) + crc32 of data(4 bytes) + data, the highest bit of
+*     length is set if the data is compressed in snappy
+*  2. Segment index: <segment>.idx, the offset(8 bytes) of each record in data
+*  3. Height: .height, the number of the last block, which is updated after
+*     the block is synced, so records after height are dropped when loading
+*  4. Base: .base, the number of the first block and the hash of its prev
+*     block, it exists only if the chain starts from a snapshot, and the
+*     index entries before base are zero
+ */
+
+const (
+	recordHeaderSize = 8
+	recordSnappy     = 1 << 31
+	indexEntrySize   = 8
+	heightFile       = ".height"
+	baseFile         = ".base"
+)
+
+var (
+	// segmentBlocks is the number of blocks in a segment
+	segmentBlocks uint64 = 10000
+	crcTable             = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// segment is the data and index file of a segment
+type segment struct {
+	id  uint64
+	dat *os.File
+	idx *os.File
+}
+
+func (s *segment) close() {
+	s.dat.Close()
+	s.idx.Close()
+}
+
+// store is the segmented append-only store of blocks
+type store struct {
+	dir string
+	// compression is the compression of blocks appended, blocks could
+	// always be read whatever they are compressed or not
+	compression string
+	// writer is the segment the next block is appended to
+	writer *segment
+	// size is the size of data file of writer
+	size int64
+	// reader caches the last read segment which is not the writer
+	reader *segment
+	// base is the first block stored, and prevHash is the hash of the
+	// block before it
+	base     uint64
+	prevHash common.Hash
+	// readOnly is true if the store is loaded by loadReadOnly
+	readOnly bool
+}
+
+// load loads a channel and return the store and the block expected,
+// the blocks stored in json are migrated to segments
+func load(dir, compression string) (*store, uint64, error) {
+	if err := compress.Check(compression); err != nil {
+		return nil, 0, err
 	}
-	err = initEnv(dir)
+	if err := initEnv(dir); err != nil {
+		return nil, 0, err
+	}
+	s := &store{dir: dir, compression: compression}
+	migrated, err := s.migrate()
 	if err != nil {
-		return 0, err
+		return nil, 0, err
+	}
+	if migrated {
+		log.Infof("Migrate blocks in %s from json to segments", dir)
+	}
+	if err := s.loadBase(); err != nil {
+		return nil, 0, err
 	}
-	return 0, nil
+	expect, err := s.loadHeight()
+	if err != nil {
+		return nil, 0, err
+	}
+	if expect < s.base {
+		expect = s.base
+	}
+	if err := s.openWriter(expect); err != nil {
+		return nil, 0, err
+	}
+	return s, expect, nil
+}
+
+// loadReadOnly loads a channel without modifying anything, there is no
+// dir created, no block migrated and no record after height dropped
+func loadReadOnly(dir string) (*store, uint64, error) {
+	if !util.FileExists(dir) {
+		return nil, 0, fmt.Errorf("The dir %s does not exist", dir)
+	}
+	if util.FileExists(filepath.Join(dir, legacyCacheFile)) && !util.FileExists(filepath.Join(dir, heightFile)) {
+		return nil, 0, fmt.Errorf("Blocks in %s are stored in json and not migrated yet", dir)
+	}
+	s := &store{dir: dir, readOnly: true}
+	if err := s.loadBase(); err != nil {
+		return nil, 0, err
+	}
+	expect, err := s.loadHeight()
+	if err != nil {
+		return nil, 0, err
+	}
+	if expect < s.base {
+		expect = s.base
+	}
+	return s, expect, nil
 }
 
 // initEnv init the env that a channel needs
@@ -65,80 +152,300 @@ func initEnv(dir string) error {
 	return nil
 }
 
-func loadCache(path string) (uint64, error) {
-	file, err := os.Open(path)
+// loadHeight return the block expected according to the height file
+func (s *store) loadHeight() (uint64, error) {
+	path := filepath.Join(s.dir, heightFile)
+	data, err := ioutil.ReadFile(path)
 	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
 		return 0, err
 	}
-	defer file.Close()
+	num, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("The height file %s is damaged", path)
+	}
+	return num + 1, nil
+}
 
-	var data string
-	_, err = fmt.Fscanln(file, &data)
+// loadBase loads the base file if it exists
+func (s *store) loadBase() error {
+	path := filepath.Join(s.dir, baseFile)
+	data, err := ioutil.ReadFile(path)
 	if err != nil {
-		return 0, err
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 {
+		return fmt.Errorf("The base file %s is damaged", path)
+	}
+	if s.base, err = strconv.ParseUint(fields[0], 10, 64); err != nil {
+		return fmt.Errorf("The base file %s is damaged", path)
+	}
+	s.prevHash = common.HexToHash(fields[1])
+	return nil
+}
+
+// setBase makes the empty store start from the block num
+func (s *store) setBase(num uint64, prevHash common.Hash) error {
+	if s.readOnly {
+		return fmt.Errorf("The store of %s is read-only", s.dir)
+	}
+	data := fmt.Sprintf("%d %s", num, util.Hex(prevHash.Bytes()))
+	if err := s.writeFile(baseFile, data); err != nil {
+		return err
+	}
+	s.writer.close()
+	s.writer = nil
+	s.base = num
+	s.prevHash = prevHash
+	return s.openWriter(num)
+}
+
+// updateHeight replaces the height file atomically
+func (s *store) updateHeight(num uint64) error {
+	return s.writeFile(heightFile, strconv.FormatUint(num, 10))
+}
+
+// writeFile replaces the file in dir atomically
+func (s *store) writeFile(name, data string) error {
+	path := filepath.Join(s.dir, name)
+	tmpPath := path + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
 	}
-	num, err := strconv.ParseUint(data, 10, 64)
+	if _, err = file.WriteString(data); err == nil {
+		err = file.Sync()
+	}
+	file.Close()
 	if err != nil {
-		return 0, fmt.Errorf("The checkpoint file %s is damaged", path)
+		return err
 	}
-	return num, nil
+	if err = os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	return syncDir(s.dir)
 }
 
-func (manager *Manager) loadBlock(num uint64) (*core.Block, error) {
-	path := manager.getJSONStorePath(num)
-	data, err := ioutil.ReadFile(path)
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	var block core.Block
-	// However, this can not use json.Unmarshal, because the pk is unable to unmarshal
+	defer d.Close()
+	return d.Sync()
+}
+
+func (s *store) segmentPath(id uint64, ext string) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%06d.%s", id, ext))
+}
 
-	err = json.Unmarshal(data, &block)
+func (s *store) openSegment(id uint64, flag int) (*segment, error) {
+	dat, err := os.OpenFile(s.segmentPath(id, "blk"), flag, 0666)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := os.OpenFile(s.segmentPath(id, "idx"), flag, 0666)
 	if err != nil {
+		dat.Close()
 		return nil, err
 	}
-	return &block, nil
+	return &segment{id: id, dat: dat, idx: idx}, nil
 }
 
-// todo: we are not using fsync here
-func (manager *Manager) storeBlock(block *core.Block) error {
-	path := manager.getJSONStorePath(block.Header.Number)
-	if util.FileExists(path) {
-		return errors.New("The block file is aleardy exists")
+// openWriter opens the segment of block expected, and drops the records
+// which are written but not included in height
+func (s *store) openWriter(expect uint64) error {
+	seg, err := s.openSegment(expect/segmentBlocks, os.O_RDWR|os.O_CREATE)
+	if err != nil {
+		return err
 	}
-	file, err := os.Create(path)
+	count := int64(expect % segmentBlocks)
+	info, err := seg.idx.Stat()
 	if err != nil {
+		seg.close()
 		return err
 	}
-	defer file.Close()
-	enc := json.NewEncoder(file)
-
-	err = enc.Encode(block)
+	var size int64
+	switch {
+	case expect == s.base && s.base != 0:
+		// there is no block before base in the segment
+	case info.Size() < count*indexEntrySize:
+		seg.close()
+		return fmt.Errorf("Index file of segment %d in %s is damaged", seg.id, s.dir)
+	case info.Size() > count*indexEntrySize:
+		// the next record starts at the first dropped entry
+		if size, err = readOffset(seg.idx, count); err != nil {
+			seg.close()
+			return err
+		}
+	case count != 0:
+		if size, err = nextOffset(seg, count-1); err != nil {
+			seg.close()
+			return err
+		}
+	}
+	if err = seg.idx.Truncate(count * indexEntrySize); err == nil {
+		err = seg.dat.Truncate(size)
+	}
 	if err != nil {
+		seg.close()
 		return err
 	}
+	s.writer = seg
+	s.size = size
 	return nil
 }
 
-func (manager *Manager) updateCache(num uint64) error {
-	cachePath, _ := util.MakeFileAbs(".cache", manager.dir)
-	file, err := os.OpenFile(cachePath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
-	if err != nil {
+// append appends a block and syncs it, the height should be updated after it
+func (s *store) append(block *core.Block) error {
+	if s.readOnly {
+		return fmt.Errorf("The store of %s is read-only", s.dir)
+	}
+	if s.writer == nil {
+		return fmt.Errorf("The store of %s is closed", s.dir)
+	}
+	num := block.Header.Number
+	if id := num / segmentBlocks; id != s.writer.id {
+		// a new segment is always empty
+		seg, err := s.openSegment(id, os.O_RDWR|os.O_CREATE|os.O_TRUNC)
+		if err != nil {
+			return err
+		}
+		s.writer.close()
+		s.writer = seg
+		s.size = 0
+	}
+	data := block.Bytes()
+	length := uint32(len(data))
+	if compress.Enabled(s.compression) {
+		compressed, err := compress.Encode(s.compression, data)
+		if err != nil {
+			return err
+		}
+		data = compressed
+		length = uint32(len(data)) | recordSnappy
+	}
+	record := make([]byte, recordHeaderSize+len(data))
+	binary.BigEndian.PutUint32(record[0:], length)
+	binary.BigEndian.PutUint32(record[4:], crc32.Checksum(data, crcTable))
+	copy(record[recordHeaderSize:], data)
+	if _, err := s.writer.dat.WriteAt(record, s.size); err != nil {
 		return err
 	}
-	defer file.Close()
+	var entry [indexEntrySize]byte
+	binary.BigEndian.PutUint64(entry[:], uint64(s.size))
+	if _, err := s.writer.idx.WriteAt(entry[:], int64(num%segmentBlocks)*indexEntrySize); err != nil {
+		return err
+	}
+	if err := s.writer.dat.Sync(); err != nil {
+		return err
+	}
+	if err := s.writer.idx.Sync(); err != nil {
+		return err
+	}
+	s.size += int64(len(record))
+	return nil
+}
 
-	writer := bufio.NewWriter(file)
-	writer.WriteString(fmt.Sprintf("%d", num))
-	err = writer.Flush()
+// read reads the block of num, the caller should make sure the block exists
+func (s *store) read(num uint64) (*core.Block, error) {
+	if num < s.base {
+		return nil, fmt.Errorf("The block %d is before the base %d of %s", num, s.base, s.dir)
+	}
+	var seg *segment
+	id := num / segmentBlocks
+	switch {
+	case s.writer != nil && s.writer.id == id:
+		seg = s.writer
+	case s.reader != nil && s.reader.id == id:
+		seg = s.reader
+	default:
+		var err error
+		seg, err = s.openSegment(id, os.O_RDONLY)
+		if err != nil {
+			return nil, err
+		}
+		if s.reader != nil {
+			s.reader.close()
+		}
+		s.reader = seg
+	}
+	data, err := readRecord(seg, int64(num%segmentBlocks))
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("Failed to read block %d in %s: %v", num, s.dir, err)
 	}
+	return core.UnmarshalBlock(data)
+}
 
-	return nil
+func (s *store) close() {
+	if s.writer != nil {
+		s.writer.close()
+		s.writer = nil
+	}
+	if s.reader != nil {
+		s.reader.close()
+		s.reader = nil
+	}
+}
+
+func readOffset(idx *os.File, i int64) (int64, error) {
+	var entry [indexEntrySize]byte
+	if _, err := idx.ReadAt(entry[:], i*indexEntrySize); err != nil {
+		return 0, err
+	}
+	return int64(binary.BigEndian.Uint64(entry[:])), nil
+}
+
+// nextOffset return the offset after the record i
+func nextOffset(seg *segment, i int64) (int64, error) {
+	offset, err := readOffset(seg.idx, i)
+	if err != nil {
+		return 0, err
+	}
+	var header [recordHeaderSize]byte
+	if _, err := seg.dat.ReadAt(header[:], offset); err != nil {
+		return 0, err
+	}
+	return offset + recordHeaderSize + int64(binary.BigEndian.Uint32(header[0:])&^recordSnappy), nil
+}
+
+func readRecord(seg *segment, i int64) ([]byte, error) {
+	offset, err := readOffset(seg.idx, i)
+	if err != nil {
+		return nil, err
+	}
+	var header [recordHeaderSize]byte
+	if _, err := seg.dat.ReadAt(header[:], offset); err != nil {
+		return nil, err
+	}
+	length := binary.BigEndian.Uint32(header[0:])
+	data := make([]byte, length&^recordSnappy)
+	if _, err := seg.dat.ReadAt(data, offset+recordHeaderSize); err != nil {
+		return nil, err
+	}
+	if crc32.Checksum(data, crcTable) != binary.BigEndian.Uint32(header[4:]) {
+		return nil, errors.New("checksum mismatch")
+	}
+	if length&recordSnappy != 0 {
+		return compress.Decode(compress.Snappy, data)
+	}
+	return data, nil
+}
+
+func (manager *Manager) loadBlock(num uint64) (*core.Block, error) {
+	return manager.store.read(num)
 }
 
-func (manager *Manager) getJSONStorePath(num uint64) string {
-	path, _ := util.MakeFileAbs(fmt.Sprintf("%d.json", num), manager.dir)
-	return path
+func (manager *Manager) storeBlock(block *core.Block) error {
+	return manager.store.append(block)
+}
+
+func (manager *Manager) updateCache(num uint64) error {
+	return manager.store.updateHeight(num)
 }
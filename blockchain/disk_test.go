@@ -0,0 +1,274 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package blockchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"madledger/common/compress"
+	"madledger/core"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testDir = ".blockchain"
+)
+
+func newTestBlock(num uint64, prev *core.Block) *core.Block {
+	tx := core.NewTxWithoutSig("test", []byte(fmt.Sprintf("block %d", num)), 0)
+	if prev == nil {
+		return core.NewBlock("test", num, core.GenesisBlockPrevHash, []*core.Tx{tx})
+	}
+	return core.NewBlock("test", num, prev.Hash().Bytes(), []*core.Tx{tx})
+}
+
+func addTestBlocks(t *testing.T, manager *Manager, count uint64) {
+	prev := manager.GetPrevBlock()
+	for i := uint64(0); i < count; i++ {
+		block := newTestBlock(manager.GetExpect(), prev)
+		require.NoError(t, manager.AddBlock(block))
+		prev = block
+	}
+}
+
+func requireChain(t *testing.T, manager *Manager, expect uint64) {
+	require.Equal(t, expect, manager.GetExpect())
+	var prev *core.Block
+	for i := uint64(0); i < expect; i++ {
+		block, err := manager.GetBlock(i)
+		require.NoError(t, err)
+		require.Equal(t, i, block.Header.Number)
+		if prev != nil {
+			require.Equal(t, prev.Hash().Bytes(), block.Header.PrevBlock)
+		}
+		prev = block
+	}
+	_, err := manager.GetBlock(expect)
+	require.Error(t, err)
+}
+
+func TestSegments(t *testing.T) {
+	defer func(n uint64) { segmentBlocks = n }(segmentBlocks)
+	segmentBlocks = 4
+	os.RemoveAll(testDir)
+	defer os.RemoveAll(testDir)
+
+	manager, err := NewManager("test", testDir, "")
+	require.NoError(t, err)
+	require.False(t, manager.HasGenesisBlock())
+	require.Nil(t, manager.GetPrevBlock())
+	addTestBlocks(t, manager, 10)
+	requireChain(t, manager, 10)
+	require.FileExists(t, filepath.Join(testDir, "000002.blk"))
+	manager.Close()
+
+	manager, err = NewManager("test", testDir, "")
+	require.NoError(t, err)
+	requireChain(t, manager, 10)
+	addTestBlocks(t, manager, 3)
+	requireChain(t, manager, 13)
+	manager.Close()
+}
+
+func TestRecover(t *testing.T) {
+	defer func(n uint64) { segmentBlocks = n }(segmentBlocks)
+	segmentBlocks = 4
+	os.RemoveAll(testDir)
+	defer os.RemoveAll(testDir)
+
+	manager, err := NewManager("test", testDir, "")
+	require.NoError(t, err)
+	addTestBlocks(t, manager, 6)
+	// records which are appended but not included in height are dropped
+	prev := manager.GetPrevBlock()
+	for i := uint64(6); i < 9; i++ {
+		block := newTestBlock(i, prev)
+		require.NoError(t, manager.store.append(block))
+		prev = block
+	}
+	manager.Close()
+
+	manager, err = NewManager("test", testDir, "")
+	require.NoError(t, err)
+	requireChain(t, manager, 6)
+	addTestBlocks(t, manager, 4)
+	requireChain(t, manager, 10)
+	manager.Close()
+
+	// damaged record is detected by checksum
+	path := filepath.Join(testDir, "000001.blk")
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	data[recordHeaderSize+1] ^= 0xff
+	require.NoError(t, ioutil.WriteFile(path, data, 0666))
+	manager, err = NewManager("test", testDir, "")
+	require.NoError(t, err)
+	_, err = manager.GetBlock(4)
+	require.Error(t, err)
+	manager.Close()
+}
+
+func TestReadOnly(t *testing.T) {
+	defer func(n uint64) { segmentBlocks = n }(segmentBlocks)
+	segmentBlocks = 4
+	os.RemoveAll(testDir)
+	defer os.RemoveAll(testDir)
+
+	_, err := NewReadOnlyManager("test", testDir)
+	require.Error(t, err)
+	require.NoFileExists(t, testDir)
+
+	manager, err := NewManager("test", testDir, "")
+	require.NoError(t, err)
+	addTestBlocks(t, manager, 6)
+	prev := manager.GetPrevBlock()
+	for i := uint64(6); i < 9; i++ {
+		block := newTestBlock(i, prev)
+		require.NoError(t, manager.store.append(block))
+		prev = block
+	}
+	manager.Close()
+	info, err := os.Stat(filepath.Join(testDir, "000001.idx"))
+	require.NoError(t, err)
+
+	// records after height are not dropped
+	manager, err = NewReadOnlyManager("test", testDir)
+	require.NoError(t, err)
+	requireChain(t, manager, 6)
+	require.Error(t, manager.AddBlock(newTestBlock(6, manager.GetPrevBlock())))
+	manager.Close()
+	stat, err := os.Stat(filepath.Join(testDir, "000001.idx"))
+	require.NoError(t, err)
+	require.Equal(t, info.Size(), stat.Size())
+
+	// blocks in json are not migrated
+	require.NoError(t, os.RemoveAll(testDir))
+	require.NoError(t, os.MkdirAll(testDir, 0777))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(testDir, ".cache"), []byte("0"), 0666))
+	_, err = NewReadOnlyManager("test", testDir)
+	require.Error(t, err)
+	require.FileExists(t, filepath.Join(testDir, ".cache"))
+}
+
+func TestBase(t *testing.T) {
+	defer func(n uint64) { segmentBlocks = n }(segmentBlocks)
+	segmentBlocks = 4
+	os.RemoveAll(testDir)
+	defer os.RemoveAll(testDir)
+
+	// the full chain which the snapshot is taken from
+	full, err := NewManager("test", filepath.Join(testDir, "full"), "")
+	require.NoError(t, err)
+	addSignedBlocks(t, full, "test", 10)
+	prev, err := full.GetBlock(5)
+	require.NoError(t, err)
+
+	manager, err := NewManager("test", filepath.Join(testDir, "base"), "")
+	require.NoError(t, err)
+	require.NoError(t, manager.StartAt(6, prev.Hash()))
+	require.Equal(t, uint64(6), manager.GetExpect())
+	require.True(t, manager.HasGenesisBlock())
+	// the block must link to the base
+	require.Error(t, manager.AddBlock(newTestBlock(6, nil)))
+	for i := uint64(6); i < 10; i++ {
+		block, err := full.GetBlock(i)
+		require.NoError(t, err)
+		require.NoError(t, manager.AddBlock(block))
+	}
+	_, err = manager.GetBlock(5)
+	require.Error(t, err)
+	require.Error(t, manager.StartAt(3, prev.Hash()))
+	manager.Close()
+
+	manager, err = NewManager("test", filepath.Join(testDir, "base"), "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), manager.GetBase())
+	addSignedBlocks(t, manager, "test", 3)
+	for i := uint64(6); i < 13; i++ {
+		block, err := manager.GetBlock(i)
+		require.NoError(t, err)
+		require.Equal(t, i, block.Header.Number)
+	}
+	report := manager.Verify(nil)
+	require.Nil(t, report.Corruption)
+	require.Equal(t, uint64(7), report.Blocks)
+	manager.Close()
+	full.Close()
+}
+
+func TestMigrate(t *testing.T) {
+	defer func(n uint64) { segmentBlocks = n }(segmentBlocks)
+	segmentBlocks = 4
+	os.RemoveAll(testDir)
+	defer os.RemoveAll(testDir)
+
+	require.NoError(t, os.MkdirAll(testDir, 0777))
+	var blocks []*core.Block
+	var prev *core.Block
+	for i := uint64(0); i < 7; i++ {
+		block := newTestBlock(i, prev)
+		file, err := os.Create(filepath.Join(testDir, fmt.Sprintf("%d.json", i)))
+		require.NoError(t, err)
+		require.NoError(t, json.NewEncoder(file).Encode(block))
+		file.Close()
+		blocks = append(blocks, block)
+		prev = block
+	}
+	require.NoError(t, ioutil.WriteFile(filepath.Join(testDir, ".cache"), []byte("6"), 0666))
+
+	manager, err := NewManager("test", testDir, "")
+	require.NoError(t, err)
+	requireChain(t, manager, 7)
+	for i, block := range blocks {
+		stored, err := manager.GetBlock(uint64(i))
+		require.NoError(t, err)
+		require.Equal(t, block.Bytes(), stored.Bytes())
+	}
+	require.NoFileExists(t, filepath.Join(testDir, "0.json"))
+	require.NoFileExists(t, filepath.Join(testDir, ".cache"))
+	addTestBlocks(t, manager, 2)
+	manager.Close()
+
+	manager, err = NewManager("test", testDir, "")
+	require.NoError(t, err)
+	requireChain(t, manager, 9)
+	manager.Close()
+}
+
+func TestCompression(t *testing.T) {
+	os.RemoveAll(testDir)
+	defer os.RemoveAll(testDir)
+
+	_, err := NewManager("test", testDir, "zip")
+	require.Error(t, err)
+
+	manager, err := NewManager("test", testDir, "")
+	require.NoError(t, err)
+	addTestBlocks(t, manager, 3)
+	manager.Close()
+	// blocks could be read whatever they are compressed or not
+	manager, err = NewManager("test", testDir, compress.Snappy)
+	require.NoError(t, err)
+	addTestBlocks(t, manager, 3)
+	manager.Close()
+
+	manager, err = NewManager("test", testDir, compress.None)
+	require.NoError(t, err)
+	requireChain(t, manager, 6)
+	addTestBlocks(t, manager, 1)
+	requireChain(t, manager, 7)
+	manager.Close()
+}
@@ -11,8 +11,10 @@
 package blockchain
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"madledger/common"
 	"madledger/core"
 	"sync"
 
@@ -29,11 +31,13 @@ type Manager struct {
 	id     string
 	dir    string
 	expect uint64
+	store  *store
 }
 
-// NewManager is the constructor of manager
-func NewManager(id, dir string) (*Manager, error) {
-	expect, err := load(dir)
+// NewManager is the constructor of manager, compression is the
+// compression of blocks stored, empty means no compression
+func NewManager(id, dir, compression string) (*Manager, error) {
+	store, expect, err := load(dir, compression)
 	if err != nil {
 		return nil, err
 	}
@@ -42,11 +46,29 @@ func NewManager(id, dir string) (*Manager, error) {
 		id:     id,
 		dir:    dir,
 		expect: expect,
+		store:  store,
 	}
 
 	return &m, nil
 }
 
+// NewReadOnlyManager opens the blocks in dir without modifying anything,
+// so blocks could be read while the node is running or stopped unexpectedly.
+// Blocks could not be added into it.
+func NewReadOnlyManager(id, dir string) (*Manager, error) {
+	store, expect, err := loadReadOnly(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Manager{
+		lock:   new(sync.Mutex),
+		id:     id,
+		dir:    dir,
+		expect: expect,
+		store:  store,
+	}, nil
+}
+
 // HasGenesisBlock return if the channel has a genesis block
 func (manager *Manager) HasGenesisBlock() bool {
 	manager.lock.Lock()
@@ -73,6 +95,53 @@ func (manager *Manager) GetExpect() uint64 {
 	return manager.expect
 }
 
+// GetBase return the first block stored, it is not 0 only if the
+// chain starts from a snapshot
+func (manager *Manager) GetBase() uint64 {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	return manager.store.base
+}
+
+// StartAt makes the empty chain start from the block num, whose prev
+// block hash is prevHash, the blocks before num are never stored
+func (manager *Manager) StartAt(num uint64, prevHash common.Hash) error {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	if manager.expect != 0 {
+		return fmt.Errorf("Channel %s has %d blocks already", manager.id, manager.expect)
+	}
+	if num == 0 {
+		return nil
+	}
+	if err := manager.store.setBase(num, prevHash); err != nil {
+		return err
+	}
+	manager.expect = num
+	return nil
+}
+
+// GetLastHash return the hash of the last block, it is the prev hash of
+// base if there is no block after base
+func (manager *Manager) GetLastHash() (common.Hash, error) {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	if manager.expect == 0 {
+		return common.Hash{}, fmt.Errorf("Channel %s has no block", manager.id)
+	}
+	if manager.expect == manager.store.base {
+		return manager.store.prevHash, nil
+	}
+	block, err := manager.loadBlock(manager.expect - 1)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return block.Hash(), nil
+}
+
 // GetPrevBlock return the prev block
 func (manager *Manager) GetPrevBlock() *core.Block {
 	manager.lock.Lock()
@@ -88,6 +157,25 @@ func (manager *Manager) GetPrevBlock() *core.Block {
 	return block
 }
 
+// HasBlock return if the block is stored already, it is an error if
+// another block is stored with the same number
+func (manager *Manager) HasBlock(block *core.Block) (bool, error) {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	if block.Header.Number >= manager.expect {
+		return false, nil
+	}
+	stored, err := manager.loadBlock(block.Header.Number)
+	if err != nil {
+		return false, err
+	}
+	if stored.Hash() != block.Hash() {
+		return false, fmt.Errorf("Channel %s stores another block %d", manager.id, block.Header.Number)
+	}
+	return true, nil
+}
+
 // AddBlock add a block into the chain
 func (manager *Manager) AddBlock(block *core.Block) error {
 	manager.lock.Lock()
@@ -96,6 +184,9 @@ func (manager *Manager) AddBlock(block *core.Block) error {
 	if block.Header.Number != manager.expect {
 		return fmt.Errorf("Channel %s expect block %d while receive block %d", manager.id, manager.expect, block.Header.Number)
 	}
+	if base := manager.store.base; base != 0 && block.Header.Number == base && !bytes.Equal(block.Header.PrevBlock, manager.store.prevHash.Bytes()) {
+		return fmt.Errorf("Channel %s block %d does not link to the base", manager.id, base)
+	}
 	var err error
 	defer func() {
 		log.Infof("Channel %s add block %d, err: %v", manager.id, manager.expect, err)
@@ -112,3 +203,11 @@ func (manager *Manager) AddBlock(block *core.Block) error {
 	manager.expect++
 	return nil
 }
+
+// Close closes the files of blocks
+func (manager *Manager) Close() {
+	manager.lock.Lock()
+	defer manager.lock.Unlock()
+
+	manager.store.close()
+}
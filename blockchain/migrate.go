@@ -0,0 +1,119 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package blockchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"madledger/common/util"
+	"madledger/core"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Before segments, every block is stored in <num>.json and the number of the
+// last block is stored in .cache.
+const (
+	legacyCacheFile = ".cache"
+)
+
+// migrate appends the blocks stored in json to segments if there is no height
+// file, and removes the json files after the height is updated. It return true
+// if any block is migrated.
+func (s *store) migrate() (bool, error) {
+	cachePath := filepath.Join(s.dir, legacyCacheFile)
+	genesisBlockPath := s.legacyBlockPath(0)
+	cacheExist := util.FileExists(cachePath)
+	gbExist := util.FileExists(genesisBlockPath)
+	if util.FileExists(filepath.Join(s.dir, heightFile)) {
+		// the last migration is interrupted after the height is updated
+		if !cacheExist {
+			return false, nil
+		}
+		last, err := loadLegacyCache(cachePath)
+		if err != nil {
+			return false, err
+		}
+		return false, s.removeLegacy(last)
+	}
+	if !cacheExist && !gbExist {
+		return false, nil
+	}
+	if gbExist && !cacheExist { // only exists gb, then the env is destoryed
+		return false, fmt.Errorf("Cache file of %s is damaged", s.dir)
+	}
+	if cacheExist && !gbExist {
+		return false, fmt.Errorf("Blocks files of %s are damaged", s.dir)
+	}
+	last, err := loadLegacyCache(cachePath)
+	if err != nil {
+		return false, err
+	}
+	if err = s.openWriter(0); err != nil {
+		return false, err
+	}
+	defer s.close()
+	for num := uint64(0); num <= last; num++ {
+		block, err := s.loadLegacyBlock(num)
+		if err != nil {
+			return false, err
+		}
+		if err = s.append(block); err != nil {
+			return false, err
+		}
+	}
+	if err = s.updateHeight(last); err != nil {
+		return false, err
+	}
+	return true, s.removeLegacy(last)
+}
+
+func loadLegacyCache(path string) (uint64, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	num, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("The checkpoint file %s is damaged", path)
+	}
+	return num, nil
+}
+
+func (s *store) loadLegacyBlock(num uint64) (*core.Block, error) {
+	data, err := ioutil.ReadFile(s.legacyBlockPath(num))
+	if err != nil {
+		return nil, err
+	}
+	var block core.Block
+	if err = json.Unmarshal(data, &block); err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// removeLegacy removes json files from the last one and .cache at last,
+// so it could be continued if interrupted
+func (s *store) removeLegacy(last uint64) error {
+	for num := last + 1; num > 0; num-- {
+		if err := os.Remove(s.legacyBlockPath(num - 1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Remove(filepath.Join(s.dir, legacyCacheFile))
+}
+
+func (s *store) legacyBlockPath(num uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%d.json", num))
+}
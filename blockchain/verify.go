@@ -0,0 +1,288 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package blockchain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"madledger/common"
+	"madledger/common/crypto"
+	"madledger/common/util"
+	"madledger/core"
+	"path/filepath"
+	"sort"
+)
+
+// Corruption is the first corruption found in a channel
+type Corruption struct {
+	Number uint64
+	Reason string
+}
+
+// Report is the result of verifying a channel
+type Report struct {
+	ChannelID string
+	// Blocks is the number of blocks verified
+	Blocks uint64
+	// Unanchored is the number of tail blocks not recorded in _global yet,
+	// because the global tx may be still in consensus when the node stops
+	Unanchored uint64
+	// Corruption is nil if the channel is intact
+	Corruption *Corruption
+}
+
+// String return the report in one line
+func (r *Report) String() string {
+	if r.Corruption == nil {
+		return fmt.Sprintf("channel %s: ok, %d blocks, %d unanchored", r.ChannelID, r.Blocks, r.Unanchored)
+	}
+	return fmt.Sprintf("channel %s: corrupted at block %d, %s", r.ChannelID, r.Corruption.Number, r.Corruption.Reason)
+}
+
+// Anchors is the hashes of blocks recorded in the _global channel,
+// channel id -> block number -> block hash
+type Anchors map[string]map[uint64]common.Hash
+
+// Verify walks the blocks and checks the links, merkle roots and sigs of txs.
+// Blocks of channels other than _global are checked against anchors if
+// anchors is not nil, and the anchors of _global blocks are collected into
+// anchors if the manager is the _global channel.
+func (manager *Manager) Verify(anchors Anchors) *Report {
+	report := &Report{ChannelID: manager.id}
+	expect := manager.GetExpect()
+	base := manager.GetBase()
+	prevHash := core.GenesisBlockPrevHash
+	if base != 0 {
+		prevHash = manager.store.prevHash.Bytes()
+	}
+	// first is the first block anchored, blocks before base are not stored,
+	// so they are anchored in the _global blocks which may be not stored too,
+	// and genesis blocks are created by _config txs without anchors
+	var first, last uint64 = base, base
+	for i := base; i < expect; i++ {
+		block, err := manager.GetBlock(i)
+		if err != nil {
+			report.Corruption = &Corruption{i, err.Error()}
+			return report
+		}
+		if reason := verifyBlock(manager.id, i, block, prevHash); reason != "" {
+			report.Corruption = &Corruption{i, reason}
+			return report
+		}
+		if anchors != nil {
+			if manager.id == core.GLOBALCHANNELID {
+				if reason := collectAnchors(anchors, block); reason != "" {
+					report.Corruption = &Corruption{i, reason}
+					return report
+				}
+			} else if hash, ok := anchors[manager.id][i]; ok {
+				if hash != block.Hash() {
+					report.Corruption = &Corruption{i, fmt.Sprintf("hash %s does not match %s in _global", util.Hex(block.Hash().Bytes()), util.Hex(hash.Bytes()))}
+					return report
+				}
+				if last == base {
+					first = i
+				}
+				last = i + 1
+			}
+		}
+		prevHash = block.Hash().Bytes()
+		report.Blocks++
+	}
+	if anchors != nil && manager.id != core.GLOBALCHANNELID {
+		// blocks are anchored in order, so only the tail could be missing
+		for i := first; i < last; i++ {
+			if _, ok := anchors[manager.id][i]; !ok {
+				report.Corruption = &Corruption{i, "block is not recorded in _global"}
+				return report
+			}
+		}
+		report.Unanchored = expect - last
+	}
+	return report
+}
+
+// VerifyBlock checks the header, merkle root and sigs of txs of block num,
+// but not the link to the prev block, so blocks could be checked in any order
+func VerifyBlock(channelID string, num uint64, block *core.Block) error {
+	if reason := verifyHeader(channelID, num, block); reason != "" {
+		return errors.New(reason)
+	}
+	if reason := verifyTxs(channelID, num, block); reason != "" {
+		return errors.New(reason)
+	}
+	return nil
+}
+
+// verifyBlock return the reason if the block is corrupted, else return ""
+func verifyBlock(channelID string, num uint64, block *core.Block, prevHash []byte) string {
+	if reason := verifyHeader(channelID, num, block); reason != "" {
+		return reason
+	}
+	if !bytes.Equal(block.Header.PrevBlock, prevHash) {
+		return fmt.Sprintf("prev block %s does not match %s", util.Hex(block.Header.PrevBlock), util.Hex(prevHash))
+	}
+	return verifyTxs(channelID, num, block)
+}
+
+func verifyHeader(channelID string, num uint64, block *core.Block) string {
+	if block.Header == nil {
+		return "header is missing"
+	}
+	if block.Header.ChannelID != channelID || block.Header.Number != num {
+		return fmt.Sprintf("header is %s:%d", block.Header.ChannelID, block.Header.Number)
+	}
+	return ""
+}
+
+func verifyTxs(channelID string, num uint64, block *core.Block) string {
+	if !bytes.Equal(block.Header.MerkleRoot, core.CalcMerkleRoot(block.Transactions)) {
+		return "merkle root does not match the txs"
+	}
+	for _, tx := range block.Transactions {
+		if !verifyTx(channelID, num, tx) {
+			return fmt.Sprintf("tx %s is not signed well", tx.ID)
+		}
+	}
+	return ""
+}
+
+// verifyTx return if the tx is signed well, txs in genesis blocks and
+// _global are created by the orderer without sig, so only their ids are
+// checked
+func verifyTx(channelID string, num uint64, tx *core.Tx) bool {
+	if len(tx.Data.Sig.PK) != 0 {
+		return tx.Verify()
+	}
+	if num != 0 && channelID != core.GLOBALCHANNELID {
+		return false
+	}
+	return tx.ID == util.Hex(tx.Hash()) || tx.ID == util.Hex(tx.Hash(crypto.KeyAlgoSM2))
+}
+
+// collectAnchors add the payloads of global txs in block into anchors
+func collectAnchors(anchors Anchors, block *core.Block) string {
+	for _, tx := range block.Transactions {
+		payload, err := tx.GetGlobalTxPayload()
+		if err != nil {
+			return fmt.Sprintf("tx %s is not a global tx: %v", tx.ID, err)
+		}
+		if anchors[payload.ChannelID] == nil {
+			anchors[payload.ChannelID] = make(map[uint64]common.Hash)
+		}
+		anchors[payload.ChannelID][payload.Num] = payload.Hash
+	}
+	return ""
+}
+
+// VerifyChannels verifies the channels stored in dir, _global is always
+// verified first to collect the anchors, and all channels are verified if
+// channels is empty. The channels are opened read-only, so records after
+// the height are left for the node to drop. The node should be stopped
+// before verifying.
+func VerifyChannels(dir string, channels ...string) ([]*Report, error) {
+	if len(channels) == 0 {
+		var err error
+		if channels, err = listChannels(dir); err != nil {
+			return nil, err
+		}
+	}
+	var ids = []string{core.GLOBALCHANNELID}
+	for _, id := range channels {
+		if id != core.GLOBALCHANNELID {
+			ids = append(ids, id)
+		}
+	}
+	var reports []*Report
+	var anchors = make(Anchors)
+	for _, id := range ids {
+		path := filepath.Join(dir, id)
+		if !util.FileExists(path) {
+			return nil, fmt.Errorf("channel %s is not stored in %s", id, dir)
+		}
+		var report *Report
+		manager, err := NewReadOnlyManager(id, path)
+		if err != nil {
+			report = &Report{ChannelID: id, Corruption: &Corruption{0, err.Error()}}
+		} else {
+			report = manager.Verify(anchors)
+			manager.Close()
+		}
+		if id == core.GLOBALCHANNELID && report.Corruption != nil {
+			// anchors after the corruption are unknown
+			anchors = nil
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// VerifyAndPrint verifies the channels stored in dir as VerifyChannels and
+// prints the report of each channel into w, it return error if any
+// corruption is found
+func VerifyAndPrint(w io.Writer, dir string, channels ...string) error {
+	reports, err := VerifyChannels(dir, channels...)
+	if err != nil {
+		return err
+	}
+	var corrupted int
+	for _, report := range reports {
+		fmt.Fprintln(w, report)
+		if report.Corruption != nil {
+			corrupted++
+		}
+	}
+	if corrupted != 0 {
+		return fmt.Errorf("%d of %d channels are corrupted", corrupted, len(reports))
+	}
+	return nil
+}
+
+// Heights return the number of blocks of each channel stored in dir,
+// the channels are opened read-only
+func Heights(dir string) (map[string]uint64, error) {
+	var heights = make(map[string]uint64)
+	if !util.FileExists(dir) {
+		return heights, nil
+	}
+	channels, err := listChannels(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range channels {
+		manager, err := NewReadOnlyManager(id, filepath.Join(dir, id))
+		if err != nil {
+			return nil, err
+		}
+		heights[id] = manager.GetExpect()
+		manager.Close()
+	}
+	return heights, nil
+}
+
+// listChannels return the sorted ids of channels stored in dir
+func listChannels(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var channels []string
+	for _, info := range infos {
+		if info.IsDir() {
+			channels = append(channels, info.Name())
+		}
+	}
+	sort.Strings(channels)
+	return channels, nil
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package blockchain
+
+import (
+	"bytes"
+	"fmt"
+	"madledger/common"
+	"madledger/common/crypto"
+	"madledger/core"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// addSignedBlocks add blocks with signed txs into the channel and return them,
+// the genesis block is created without sig as the orderer does
+func addSignedBlocks(t *testing.T, manager *Manager, channelID string, count uint64) []*core.Block {
+	privKey, err := crypto.GeneratePrivateKey()
+	require.NoError(t, err)
+	var blocks []*core.Block
+	prev := manager.GetPrevBlock()
+	for i := uint64(0); i < count; i++ {
+		num := manager.GetExpect()
+		tx := core.NewTxWithoutSig(channelID, []byte(fmt.Sprintf("block %d", num)), 0)
+		if num != 0 {
+			tx, err = core.NewTx(channelID, common.ZeroAddress, []byte(fmt.Sprintf("block %d", num)), 0, "", privKey)
+			require.NoError(t, err)
+		}
+		prevHash := core.GenesisBlockPrevHash
+		if prev != nil {
+			prevHash = prev.Hash().Bytes()
+		}
+		block := core.NewBlock(channelID, num, prevHash, []*core.Tx{tx})
+		require.NoError(t, manager.AddBlock(block))
+		blocks = append(blocks, block)
+		prev = block
+	}
+	return blocks
+}
+
+func TestVerifyChannels(t *testing.T) {
+	os.RemoveAll(testDir)
+	defer os.RemoveAll(testDir)
+
+	manager, err := NewManager("test", filepath.Join(testDir, "test"), "")
+	require.NoError(t, err)
+	blocks := addSignedBlocks(t, manager, "test", 5)
+	manager.Close()
+
+	// the last block is not anchored
+	global, err := NewManager(core.GLOBALCHANNELID, filepath.Join(testDir, core.GLOBALCHANNELID), "")
+	require.NoError(t, err)
+	var prev = core.GenesisBlockPrevHash
+	for i, block := range blocks[:4] {
+		tx := core.NewGlobalTx("test", uint64(i), block.Hash())
+		globalBlock := core.NewBlock(core.GLOBALCHANNELID, uint64(i), prev, []*core.Tx{tx})
+		require.NoError(t, global.AddBlock(globalBlock))
+		prev = globalBlock.Hash().Bytes()
+	}
+	global.Close()
+
+	reports, err := VerifyChannels(testDir)
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	require.Equal(t, core.GLOBALCHANNELID, reports[0].ChannelID)
+	require.Nil(t, reports[0].Corruption)
+	require.Equal(t, uint64(4), reports[0].Blocks)
+	require.Equal(t, "test", reports[1].ChannelID)
+	require.Nil(t, reports[1].Corruption)
+	require.Equal(t, uint64(5), reports[1].Blocks)
+	require.Equal(t, uint64(1), reports[1].Unanchored)
+
+	_, err = VerifyChannels(testDir, "nochannel")
+	require.Error(t, err)
+
+	var output bytes.Buffer
+	require.NoError(t, VerifyAndPrint(&output, testDir, "test"))
+	require.Equal(t, "channel _global: ok, 4 blocks, 0 unanchored\nchannel test: ok, 5 blocks, 1 unanchored\n", output.String())
+
+	manager, err = NewManager("test", filepath.Join(testDir, "test"), "")
+	require.NoError(t, err)
+	defer manager.Close()
+	report := manager.Verify(Anchors{"test": {0: blocks[0].Hash(), 2: blocks[2].Hash()}})
+	require.Equal(t, &Corruption{1, "block is not recorded in _global"}, report.Corruption)
+	report = manager.Verify(Anchors{"test": {0: blocks[0].Hash(), 1: common.Hash{}}})
+	require.Equal(t, uint64(1), report.Corruption.Number)
+	require.Contains(t, report.Corruption.Reason, "does not match")
+	// the genesis block is not anchored
+	report = manager.Verify(Anchors{"test": {1: blocks[1].Hash(), 2: blocks[2].Hash()}})
+	require.Nil(t, report.Corruption)
+	require.Equal(t, uint64(2), report.Unanchored)
+}
+
+func TestVerifyBlocks(t *testing.T) {
+	os.RemoveAll(testDir)
+	defer os.RemoveAll(testDir)
+
+	manager, err := NewManager("test", testDir, "")
+	require.NoError(t, err)
+	defer manager.Close()
+	blocks := addSignedBlocks(t, manager, "test", 2)
+	require.Nil(t, manager.Verify(nil).Corruption)
+
+	// unsigned tx out of genesis block
+	tx := core.NewTxWithoutSig("test", []byte("unsigned"), 0)
+	block := core.NewBlock("test", 2, blocks[1].Hash().Bytes(), []*core.Tx{tx})
+	require.NoError(t, manager.AddBlock(block))
+	report := manager.Verify(nil)
+	require.Equal(t, uint64(2), report.Blocks)
+	require.Equal(t, uint64(2), report.Corruption.Number)
+	require.Contains(t, report.Corruption.Reason, "not signed")
+
+	// wrong merkle root and wrong link
+	prev := block
+	block = core.NewBlock("test", 3, prev.Hash().Bytes(), blocks[1].Transactions)
+	require.Empty(t, verifyBlock("test", 3, block, prev.Hash().Bytes()))
+	require.Equal(t, "merkle root does not match the txs", verifyBlock("test", 3, &core.Block{
+		Header:       block.Header,
+		Transactions: blocks[0].Transactions,
+	}, prev.Hash().Bytes()))
+	require.Contains(t, verifyBlock("test", 3, block, blocks[1].Hash().Bytes()), "prev block")
+	require.Contains(t, verifyBlock("test", 4, block, core.GenesisBlockPrevHash), "header is test:3")
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package account
+
+import (
+	"errors"
+	"madledger/client/lib"
+	"madledger/client/util"
+	"madledger/common"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	complianceCmd = &cobra.Command{
+		Use: "compliance",
+	}
+	complianceViper = viper.New()
+)
+
+func init() {
+	complianceCmd.RunE = runCompliance
+	complianceCmd.Flags().StringP("config", "c", "client.yaml", "The config file of client")
+	complianceViper.BindPFlag("config", complianceCmd.Flags().Lookup("config"))
+	complianceCmd.Flags().StringP("address", "a", "", "The hex address of account, empty means the account of client")
+	complianceViper.BindPFlag("address", complianceCmd.Flags().Lookup("address"))
+}
+
+func runCompliance(cmd *cobra.Command, args []string) error {
+	cfgFile := complianceViper.GetString("config")
+	if cfgFile == "" {
+		return errors.New("The config file of client can not be nil")
+	}
+
+	client, err := lib.NewClient(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	var address common.Address
+	if hexAddress := complianceViper.GetString("address"); hexAddress != "" {
+		address = common.HexToAddress(hexAddress)
+	} else if address, err = client.GetPrivKey().PubKey().Address(); err != nil {
+		return err
+	}
+
+	records, err := client.GetComplianceHistory(address)
+	if err != nil {
+		return err
+	}
+
+	table := util.NewTable()
+	table.SetHeader("TxID", "Action", "Asset", "Value", "Reason", "BlockNumber")
+	for _, record := range records {
+		table.AddRow(record.TxID, record.Action, record.AssetID, record.Value, record.Reason, record.BlockNumber)
+	}
+	table.Render()
+
+	return nil
+}
@@ -32,6 +32,8 @@ func init() {
 	listViper.BindPFlag("config", listCmd.Flags().Lookup("config"))
 	listCmd.Flags().StringP("channel", "n", "", "The name of channel")
 	listViper.BindPFlag("channel", listCmd.Flags().Lookup("channel"))
+	listCmd.Flags().StringP("asset", "s", "", "The id of asset, empty means the native asset")
+	listViper.BindPFlag("asset", listCmd.Flags().Lookup("asset"))
 }
 
 func runList(cmd *cobra.Command, args []string) error {
@@ -50,10 +52,11 @@ func runList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	info, err := client.GetAccountBalance(address)
+	account, err := client.GetAccountInfo(address, listViper.GetString("asset"))
 	if err != nil {
 		return err
 	}
+	info := account.GetBalance()
 
 	table := util.NewTable()
 
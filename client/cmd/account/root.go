@@ -26,5 +26,6 @@ var (
 // Cmd return the account command
 func Cmd() *cobra.Command {
 	accountCmd.AddCommand(listCmd)
+	accountCmd.AddCommand(complianceCmd)
 	return accountCmd
 }
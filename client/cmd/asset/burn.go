@@ -0,0 +1,89 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package asset
+
+import (
+	"encoding/json"
+	"errors"
+	"madledger/blockchain/asset"
+	"madledger/client/lib"
+	"madledger/client/util"
+	"madledger/common"
+	coreTypes "madledger/core"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	burnCmd = &cobra.Command{
+		Use: "burn",
+	}
+	burnViper = viper.New()
+)
+
+func init() {
+	burnCmd.RunE = runBurn
+	burnCmd.Flags().StringP("config", "c", "client.yaml", "The config file of client")
+	burnViper.BindPFlag("config", burnCmd.Flags().Lookup("config"))
+
+	burnCmd.Flags().StringP("address", "a", "", "The hex address of account")
+	burnViper.BindPFlag("address", burnCmd.Flags().Lookup("address"))
+
+	burnCmd.Flags().StringP("reason", "r", "", "The reason code")
+	burnViper.BindPFlag("reason", burnCmd.Flags().Lookup("reason"))
+
+	burnCmd.Flags().Int64P("value", "v", 0, "The amount of asset to burn")
+	burnViper.BindPFlag("value", burnCmd.Flags().Lookup("value"))
+
+	burnCmd.Flags().StringP("asset", "s", "", "The id of asset, empty means the native asset")
+	burnViper.BindPFlag("asset", burnCmd.Flags().Lookup("asset"))
+}
+
+func runBurn(cmd *cobra.Command, args []string) error {
+	cfgFile := burnViper.GetString("config")
+	if cfgFile == "" {
+		return errors.New("The config file of client can not be nil")
+	}
+	address := burnViper.GetString("address")
+	if address == "" {
+		return errors.New("The address of account can not be nil")
+	}
+	value := burnViper.GetInt64("value")
+	if value <= 0 {
+		return errors.New("the amount can not be less than or equal to 0")
+	}
+
+	client, err := lib.NewClient(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(asset.Payload{
+		Address: common.HexToAddress(address),
+		AssetID: burnViper.GetString("asset"),
+		Reason:  burnViper.GetString("reason"),
+	})
+	if err != nil {
+		return err
+	}
+	tx, err := coreTypes.NewTx(coreTypes.ASSETCHANNELID, coreTypes.BurnContractAddress, payload, uint64(value), "", client.GetPrivKey())
+	if err != nil {
+		return err
+	}
+
+	status, err := client.AddTx(tx)
+	table := util.NewTable()
+	table.SetHeader("Status", "Error")
+	table.AddRow(status, err)
+	table.Render()
+	return err
+}
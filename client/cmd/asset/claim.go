@@ -0,0 +1,82 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package asset
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"madledger/blockchain/asset"
+	"madledger/client/lib"
+	"madledger/client/util"
+	coreTypes "madledger/core"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	claimCmd = &cobra.Command{
+		Use: "claim",
+	}
+	claimViper = viper.New()
+)
+
+func init() {
+	claimCmd.RunE = runClaim
+	claimCmd.Flags().StringP("config", "c", "client.yaml", "The config file of client")
+	claimViper.BindPFlag("config", claimCmd.Flags().Lookup("config"))
+
+	claimCmd.Flags().StringP("id", "i", "", "The id of escrow")
+	claimViper.BindPFlag("id", claimCmd.Flags().Lookup("id"))
+
+	claimCmd.Flags().StringP("preimage", "p", "", "The hex preimage of hash lock")
+	claimViper.BindPFlag("preimage", claimCmd.Flags().Lookup("preimage"))
+}
+
+func runClaim(cmd *cobra.Command, args []string) error {
+	cfgFile := claimViper.GetString("config")
+	if cfgFile == "" {
+		return errors.New("The config file of client can not be nil")
+	}
+	id := claimViper.GetString("id")
+	if id == "" {
+		return errors.New("The id of escrow can not be nil")
+	}
+	preimage, err := hex.DecodeString(claimViper.GetString("preimage"))
+	if err != nil {
+		return err
+	}
+
+	client, err := lib.NewClient(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(asset.Payload{
+		EscrowID: id,
+		Preimage: preimage,
+	})
+	if err != nil {
+		return err
+	}
+	tx, err := coreTypes.NewTx(coreTypes.ASSETCHANNELID, coreTypes.ClaimContractAddress, payload, 0, "", client.GetPrivKey())
+	if err != nil {
+		return err
+	}
+
+	status, err := client.AddTx(tx)
+	table := util.NewTable()
+	table.SetHeader("Status", "Error")
+	table.AddRow(status, err)
+	table.Render()
+	return err
+}
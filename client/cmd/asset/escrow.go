@@ -0,0 +1,108 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package asset
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"madledger/blockchain/asset"
+	"madledger/client/lib"
+	"madledger/client/util"
+	"madledger/common"
+	coreTypes "madledger/core"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	escrowCmd = &cobra.Command{
+		Use: "escrow",
+	}
+	escrowViper = viper.New()
+)
+
+func init() {
+	escrowCmd.RunE = runEscrow
+	escrowCmd.Flags().StringP("config", "c", "client.yaml", "The config file of client")
+	escrowViper.BindPFlag("config", escrowCmd.Flags().Lookup("config"))
+
+	escrowCmd.Flags().Int64P("value", "v", 0, "The amount of asset to lock")
+	escrowViper.BindPFlag("value", escrowCmd.Flags().Lookup("value"))
+
+	escrowCmd.Flags().StringP("address", "a", "", "The hex address of recipient")
+	escrowViper.BindPFlag("address", escrowCmd.Flags().Lookup("address"))
+
+	escrowCmd.Flags().StringP("asset", "s", "", "The id of asset, empty means the native asset")
+	escrowViper.BindPFlag("asset", escrowCmd.Flags().Lookup("asset"))
+
+	escrowCmd.Flags().StringP("hashlock", "l", "", "The hex sha256 of preimage, empty means a time-locked transfer")
+	escrowViper.BindPFlag("hashlock", escrowCmd.Flags().Lookup("hashlock"))
+
+	escrowCmd.Flags().Uint64P("height", "e", 0, "The _asset block height when escrow expires")
+	escrowViper.BindPFlag("height", escrowCmd.Flags().Lookup("height"))
+
+	escrowCmd.Flags().Int64P("time", "t", 0, "The unix time when escrow expires")
+	escrowViper.BindPFlag("time", escrowCmd.Flags().Lookup("time"))
+}
+
+func runEscrow(cmd *cobra.Command, args []string) error {
+	cfgFile := escrowViper.GetString("config")
+	if cfgFile == "" {
+		return errors.New("The config file of client can not be nil")
+	}
+	value := escrowViper.GetInt64("value")
+	if value <= 0 {
+		return errors.New("the amount can not be less than or equal to 0")
+	}
+	receiver := escrowViper.GetString("address")
+	if receiver == "" {
+		return errors.New("The address of recipient can not be nil")
+	}
+	hashLock, err := hex.DecodeString(escrowViper.GetString("hashlock"))
+	if err != nil {
+		return err
+	}
+	height := escrowViper.GetUint64("height")
+	expireTime := escrowViper.GetInt64("time")
+	if height == 0 && expireTime == 0 {
+		return errors.New("Specify the height or time when escrow expires")
+	}
+
+	client, err := lib.NewClient(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(asset.Payload{
+		Address:      common.HexToAddress(receiver),
+		AssetID:      escrowViper.GetString("asset"),
+		HashLock:     hashLock,
+		ExpireHeight: height,
+		ExpireTime:   expireTime,
+	})
+	if err != nil {
+		return err
+	}
+	tx, err := coreTypes.NewTx(coreTypes.ASSETCHANNELID, coreTypes.EscrowContractAddress, payload, uint64(value), "", client.GetPrivKey())
+	if err != nil {
+		return err
+	}
+
+	// the id of escrow is the id of tx
+	status, err := client.AddTx(tx)
+	table := util.NewTable()
+	table.SetHeader("EscrowID", "Status", "Error")
+	table.AddRow(tx.ID, status, err)
+	table.Render()
+	return err
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package asset
+
+import (
+	"encoding/json"
+	"errors"
+	"madledger/blockchain/asset"
+	"madledger/client/lib"
+	"madledger/client/util"
+	"madledger/common"
+	coreTypes "madledger/core"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	freezeCmd = &cobra.Command{
+		Use: "freeze",
+	}
+	freezeViper = viper.New()
+)
+
+func init() {
+	freezeCmd.RunE = runFreeze
+	freezeCmd.Flags().StringP("config", "c", "client.yaml", "The config file of client")
+	freezeViper.BindPFlag("config", freezeCmd.Flags().Lookup("config"))
+
+	freezeCmd.Flags().StringP("address", "a", "", "The hex address of account")
+	freezeViper.BindPFlag("address", freezeCmd.Flags().Lookup("address"))
+
+	freezeCmd.Flags().StringP("reason", "r", "", "The reason code")
+	freezeViper.BindPFlag("reason", freezeCmd.Flags().Lookup("reason"))
+}
+
+func runFreeze(cmd *cobra.Command, args []string) error {
+	cfgFile := freezeViper.GetString("config")
+	if cfgFile == "" {
+		return errors.New("The config file of client can not be nil")
+	}
+	address := freezeViper.GetString("address")
+	if address == "" {
+		return errors.New("The address of account can not be nil")
+	}
+
+	client, err := lib.NewClient(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(asset.Payload{
+		Address: common.HexToAddress(address),
+		Reason:  freezeViper.GetString("reason"),
+	})
+	if err != nil {
+		return err
+	}
+	tx, err := coreTypes.NewTx(coreTypes.ASSETCHANNELID, coreTypes.FreezeContractAddress, payload, 0, "", client.GetPrivKey())
+	if err != nil {
+		return err
+	}
+
+	status, err := client.AddTx(tx)
+	table := util.NewTable()
+	table.SetHeader("Status", "Error")
+	table.AddRow(status, err)
+	table.Render()
+	return err
+}
@@ -46,6 +46,15 @@ func init() {
 	issueCmd.Flags().StringP("address", "a", "0",
 		"receiver's hex address to be issued in asset channel")
 	issueViper.BindPFlag("address", issueCmd.Flags().Lookup("address"))
+
+	issueCmd.Flags().StringP("asset", "s", "", "The id of asset, empty means the native asset")
+	issueViper.BindPFlag("asset", issueCmd.Flags().Lookup("asset"))
+
+	issueCmd.Flags().Uint8P("decimals", "d", 0, "The decimals of asset, only work when the asset is created")
+	issueViper.BindPFlag("decimals", issueCmd.Flags().Lookup("decimals"))
+
+	issueCmd.Flags().Uint64P("cap", "p", 0, "The supply cap of asset, only work when the asset is created")
+	issueViper.BindPFlag("cap", issueCmd.Flags().Lookup("cap"))
 }
 
 func runIssue(cmd *cobra.Command, args []string) error {
@@ -76,9 +85,17 @@ func runIssue(cmd *cobra.Command, args []string) error {
 		return errors.New("Specify issued account")
 	}
 
+	assetID := issueViper.GetString("asset")
+	if assetID != "" && !asset.IsLegalAssetID(assetID) {
+		return errors.New("The id of asset should be [A-Za-z0-9]{1,16}")
+	}
+
 	payload, err := json.Marshal(asset.Payload{
 		ChannelID: channelID,
 		Address:   recipient,
+		AssetID:   assetID,
+		Decimals:  uint8(issueViper.GetUint("decimals")),
+		Cap:       issueViper.GetUint64("cap"),
 	})
 	if err != nil {
 		return err
@@ -0,0 +1,74 @@
+package asset
+
+import (
+	"errors"
+	"madledger/client/lib"
+	"madledger/client/util"
+	pb "madledger/protos"
+
+	coreTypes "madledger/core"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	redeemCmd = &cobra.Command{
+		Use: "redeem",
+	}
+	redeemViper = viper.New()
+)
+
+func init() {
+	redeemCmd.RunE = runRedeem
+	redeemCmd.Flags().StringP("channelID", "n", "", "The name of channel")
+	redeemViper.BindPFlag("channelID", redeemCmd.Flags().Lookup("channelID"))
+
+	redeemCmd.Flags().Int64P("value", "v", 0, "The amount of asset to redeem, the token burned is value*AssetTokenRatio")
+	redeemViper.BindPFlag("value", redeemCmd.Flags().Lookup("value"))
+
+	redeemCmd.Flags().StringP("burnID", "b", "", "The id of tx burning token, only the admin of channel redeems with it and the value is ignored")
+	redeemViper.BindPFlag("burnID", redeemCmd.Flags().Lookup("burnID"))
+
+	redeemCmd.Flags().StringP("config", "c", "client.yaml", "The config file of client")
+	redeemViper.BindPFlag("config", redeemCmd.Flags().Lookup("config"))
+}
+
+// runRedeem burns the token in channel if burnID is not set, then the admin
+// of channel redeems the asset with the id of the burning tx, the owner and
+// the amount of asset are taken from the status of the burning tx
+func runRedeem(cmd *cobra.Command, args []string) error {
+	cfgFile := redeemViper.GetString("config")
+	if cfgFile == "" {
+		return errors.New("The config file of client can not be nil")
+	}
+	channelID := redeemViper.GetString("channelID")
+	if channelID == "" {
+		return errors.New("The name of channel should be [a-z0-9]{1,32} such as test, test01 and etc")
+	}
+	burnID := redeemViper.GetString("burnID")
+	value := uint64(redeemViper.GetInt64("value"))
+	if burnID == "" && value <= 0 {
+		return errors.New("the amount can not be less than or equal to 0")
+	}
+	client, err := lib.NewClient(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	var tx *coreTypes.Tx
+	var status *pb.TxStatus
+	if burnID == "" {
+		tx, status, err = client.BurnToken(channelID, value)
+	} else {
+		tx, status, err = client.RedeemToken(channelID, burnID)
+	}
+	if tx == nil {
+		return err
+	}
+	table := util.NewTable()
+	table.SetHeader("TxID", "Status", "Error")
+	table.AddRow(tx.ID, status, err)
+	table.Render()
+	return err
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package asset
+
+import (
+	"encoding/json"
+	"errors"
+	"madledger/blockchain/asset"
+	"madledger/client/lib"
+	"madledger/client/util"
+	coreTypes "madledger/core"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	refundCmd = &cobra.Command{
+		Use: "refund",
+	}
+	refundViper = viper.New()
+)
+
+func init() {
+	refundCmd.RunE = runRefund
+	refundCmd.Flags().StringP("config", "c", "client.yaml", "The config file of client")
+	refundViper.BindPFlag("config", refundCmd.Flags().Lookup("config"))
+
+	refundCmd.Flags().StringP("id", "i", "", "The id of escrow")
+	refundViper.BindPFlag("id", refundCmd.Flags().Lookup("id"))
+}
+
+func runRefund(cmd *cobra.Command, args []string) error {
+	cfgFile := refundViper.GetString("config")
+	if cfgFile == "" {
+		return errors.New("The config file of client can not be nil")
+	}
+	id := refundViper.GetString("id")
+	if id == "" {
+		return errors.New("The id of escrow can not be nil")
+	}
+
+	client, err := lib.NewClient(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(asset.Payload{
+		EscrowID: id,
+	})
+	if err != nil {
+		return err
+	}
+	tx, err := coreTypes.NewTx(coreTypes.ASSETCHANNELID, coreTypes.RefundContractAddress, payload, 0, "", client.GetPrivKey())
+	if err != nil {
+		return err
+	}
+
+	status, err := client.AddTx(tx)
+	table := util.NewTable()
+	table.SetHeader("Status", "Error")
+	table.AddRow(status, err)
+	table.Render()
+	return err
+}
@@ -28,5 +28,12 @@ func Cmd() *cobra.Command {
 	assetCmd.AddCommand(issueCmd)
 	assetCmd.AddCommand(transferCmd)
 	assetCmd.AddCommand(tokenCmd)
+	assetCmd.AddCommand(redeemCmd)
+	assetCmd.AddCommand(escrowCmd)
+	assetCmd.AddCommand(claimCmd)
+	assetCmd.AddCommand(refundCmd)
+	assetCmd.AddCommand(freezeCmd)
+	assetCmd.AddCommand(unfreezeCmd)
+	assetCmd.AddCommand(burnCmd)
 	return assetCmd
 }
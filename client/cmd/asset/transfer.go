@@ -43,6 +43,9 @@ func init() {
 
 	transferCmd.Flags().StringP("address", "a", "", "receiver's hex address to be transfered")
 	transferViper.BindPFlag("address", transferCmd.Flags().Lookup("address"))
+
+	transferCmd.Flags().StringP("asset", "s", "", "The id of asset, empty means the native asset")
+	transferViper.BindPFlag("asset", transferCmd.Flags().Lookup("asset"))
 }
 
 func runTransfer(cmd *cobra.Command, args []string) error {
@@ -76,6 +79,7 @@ func runTransfer(cmd *cobra.Command, args []string) error {
 	payload, err := json.Marshal(asset.Payload{
 		ChannelID: channelID,
 		Address:   recipient,
+		AssetID:   transferViper.GetString("asset"),
 	})
 	if err != nil {
 		return err
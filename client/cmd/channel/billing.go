@@ -0,0 +1,77 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package channel
+
+import (
+	"errors"
+	"madledger/client/lib"
+	"madledger/client/util"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	billingCmd = &cobra.Command{
+		Use: "billing",
+	}
+	billingViper = viper.New()
+)
+
+func init() {
+	billingCmd.RunE = runBilling
+	billingCmd.Flags().StringP("config", "c", "client.yaml", "The config file of client")
+	billingViper.BindPFlag("config", billingCmd.Flags().Lookup("config"))
+	billingCmd.Flags().StringP("name", "n", "", "The name of channel")
+	billingViper.BindPFlag("name", billingCmd.Flags().Lookup("name"))
+	billingCmd.Flags().IntP("count", "l", 10, "The number of latest charges, 0 means all")
+	billingViper.BindPFlag("count", billingCmd.Flags().Lookup("count"))
+}
+
+func runBilling(cmd *cobra.Command, args []string) error {
+	cfgFile := billingViper.GetString("config")
+	if cfgFile == "" {
+		return errors.New("The config file of client can not be nil")
+	}
+	name := billingViper.GetString("name")
+	if name == "" {
+		return errors.New("The name of channel should be [a-z0-9]{1,32} such as test, test01 and etc")
+	}
+	count := billingViper.GetInt("count")
+	if count < 0 {
+		return errors.New("The count can not be negative")
+	}
+	client, err := lib.NewClient(cfgFile)
+	if err != nil {
+		return err
+	}
+
+	billing, err := client.GetChannelBilling(name, uint32(count))
+	if err != nil {
+		return err
+	}
+
+	table := util.NewTable()
+	table.SetHeader("Name", "Balance", "Due", "Halted", "BlockPrice")
+	table.AddRow(billing.ChannelID, billing.Balance, billing.Due, billing.Halted, billing.BlockPrice)
+	table.Render()
+
+	table = util.NewTable()
+	table.SetHeader("BlockNumber", "Size", "Fee", "Paid", "Due", "Time")
+	for _, record := range billing.Records {
+		table.AddRow(record.BlockNumber, record.Size, record.Fee, record.Paid, record.Due,
+			time.Unix(record.Time, 0).Format("2006-01-02 15:04:05"))
+	}
+	table.Render()
+
+	return nil
+}
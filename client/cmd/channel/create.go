@@ -12,7 +12,10 @@ package channel
 
 import (
 	"errors"
+	cc "madledger/blockchain/config"
 	"madledger/client/lib"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -37,6 +40,8 @@ func init() {
 	createViper.BindPFlag("maxGas", createCmd.Flags().Lookup("maxGas"))
 	createCmd.Flags().Uint64P("ratio", "r", 1, "Numbers of token exchanged from one asset")
 	createViper.BindPFlag("ratio", createCmd.Flags().Lookup("ratio"))
+	createCmd.Flags().StringP("precompileGas", "p", "", "The gas of precompiled contracts, which is sm3Base,sm3PerWord,sm2VerifyBase,sm2VerifyPerWord,pubKeyToAddressBase")
+	createViper.BindPFlag("precompileGas", createCmd.Flags().Lookup("precompileGas"))
 
 }
 
@@ -56,9 +61,47 @@ func runCreate(cmd *cobra.Command, args []string) error {
 
 	ratio := createViper.GetUint64("ratio")
 
+	precompileGas, err := parsePrecompileGas(createViper.GetString("precompileGas"))
+	if err != nil {
+		return err
+	}
+
 	client, err := lib.NewClient(cfgFile)
 	if err != nil {
 		return err
 	}
-	return client.CreateChannel(name, true, nil, nil, gasPrice, ratio, maxGas)
+	return client.CreateChannelWithProfile(name, &cc.Profile{
+		Public:          true,
+		GasPrice:        gasPrice,
+		AssetTokenRatio: ratio,
+		MaxGas:          maxGas,
+		PrecompileGas:   precompileGas,
+	})
+}
+
+// parsePrecompileGas parses the gas of precompiled contracts, it return nil
+// if s is empty so the default gas is used
+func parsePrecompileGas(s string) (*cc.PrecompileGas, error) {
+	if s == "" {
+		return nil, nil
+	}
+	fields := strings.Split(s, ",")
+	if len(fields) != 5 {
+		return nil, errors.New("The gas of precompiled contracts should be 5 numbers split by comma")
+	}
+	var values = make([]uint64, len(fields))
+	for i, field := range fields {
+		value, err := strconv.ParseUint(strings.TrimSpace(field), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return &cc.PrecompileGas{
+		SM3Base:             values[0],
+		SM3PerWord:          values[1],
+		SM2VerifyBase:       values[2],
+		SM2VerifyPerWord:    values[3],
+		PubKeyToAddressBase: values[4],
+	}, nil
 }
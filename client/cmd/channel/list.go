@@ -56,7 +56,7 @@ func runList(cmd *cobra.Command, args []string) error {
 	table := util.NewTable()
 	table.SetHeader("Name", "System", "BlockSize", "Identity", "MaxGas", "GasPrice", "AssetTokenRatio")
 	for _, info := range infos {
-		table.AddRow(info.Name, info.System, info.BlockSize, info.Identity)
+		table.AddRow(info.Name, info.System, info.BlockSize, info.Identity, info.MaxGas, info.GasPrice, info.AssetTokenRatio)
 	}
 	table.Render()
 	return nil
@@ -27,5 +27,6 @@ var (
 func Cmd() *cobra.Command {
 	channelCmd.AddCommand(createCmd)
 	channelCmd.AddCommand(listCmd)
+	channelCmd.AddCommand(billingCmd)
 	return channelCmd
 }
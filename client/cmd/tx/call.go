@@ -45,6 +45,8 @@ func init() {
 	callViper.BindPFlag("receiver", callCmd.Flags().Lookup("receiver"))
 	callCmd.Flags().Int64P("value", "v", 0, "The value of the tx")
 	callViper.BindPFlag("value", callCmd.Flags().Lookup("value"))
+	callCmd.Flags().Uint64P("gas", "g", 0, "The gas limit of the tx, it is estimated if zero")
+	callViper.BindPFlag("gas", callCmd.Flags().Lookup("gas"))
 }
 
 func runCall(cmd *cobra.Command, args []string) error {
@@ -80,7 +82,11 @@ func runCall(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	tx, err := core.NewTx(channelID, common.HexToAddress(receiver), payloadBytes, value, "", client.GetPrivKey())
+	gas, err := getGas(client, callViper.GetUint64("gas"), channelID, common.HexToAddress(receiver), payloadBytes, value)
+	if err != nil {
+		return err
+	}
+	tx, err := core.NewTxWithGas(channelID, common.HexToAddress(receiver), payloadBytes, value, "", gas, client.GetPrivKey())
 	if err != nil {
 		return err
 	}
@@ -101,6 +107,7 @@ func runCall(cmd *cobra.Command, args []string) error {
 		table.AddRow(status.BlockNumber, status.BlockIndex, values)
 	}
 	table.Render()
+	renderReceipt(status)
 
 	return nil
 }
@@ -30,7 +30,7 @@ var (
 
 func init() {
 	createCmd.RunE = runCreate
-	createCmd.Flags().StringP("bin", "b", "", "The bin of tx")
+	createCmd.Flags().StringP("bin", "b", "", "The bin of tx, hex of evm code or wasm module")
 	createViper.BindPFlag("bin", createCmd.Flags().Lookup("bin"))
 	createCmd.Flags().StringP("config", "c", "client.yaml", "The config file of client")
 	createViper.BindPFlag("config", createCmd.Flags().Lookup("config"))
@@ -38,6 +38,8 @@ func init() {
 	createViper.BindPFlag("channelID", createCmd.Flags().Lookup("channelID"))
 	createCmd.Flags().Int64P("value", "v", 0, "The value of the tx")
 	createViper.BindPFlag("value", createCmd.Flags().Lookup("value"))
+	createCmd.Flags().Uint64P("gas", "g", 0, "The gas limit of the tx, it is estimated if zero")
+	createViper.BindPFlag("gas", createCmd.Flags().Lookup("gas"))
 }
 
 type createTxStatus struct {
@@ -70,7 +72,11 @@ func runCreate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	tx, err := core.NewTx(channelID, common.ZeroAddress, contractCodes, value, "", client.GetPrivKey())
+	gas, err := getGas(client, createViper.GetUint64("gas"), channelID, common.ZeroAddress, contractCodes, value)
+	if err != nil {
+		return err
+	}
+	tx, err := core.NewTxWithGas(channelID, common.ZeroAddress, contractCodes, value, "", gas, client.GetPrivKey())
 	if err != nil {
 		return err
 	}
@@ -87,6 +93,7 @@ func runCreate(cmd *cobra.Command, args []string) error {
 		table.AddRow(status.BlockNumber, status.BlockIndex, status.ContractAddress)
 	}
 	table.Render()
+	renderReceipt(status)
 
 	return nil
 }
@@ -13,7 +13,13 @@ package tx
 import (
 	"encoding/hex"
 	"io/ioutil"
+	"madledger/client/lib"
+	"madledger/client/util"
+	"madledger/common"
+	"madledger/executor/wasm"
+	pb "madledger/protos"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -30,6 +36,7 @@ func Cmd() *cobra.Command {
 	txCmd.AddCommand(createCmd)
 	txCmd.AddCommand(callCmd)
 	txCmd.AddCommand(historyCmd)
+	txCmd.AddCommand(traceCmd)
 	return txCmd
 }
 
@@ -38,5 +45,42 @@ func readCodes(file string) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	// the wasm module is binary
+	if wasm.IsWASM(data) {
+		return data, nil
+	}
 	return hex.DecodeString(string(data))
 }
+
+// getGas return gas if it is not zero, else it estimates the gas of tx
+func getGas(client *lib.Client, gas uint64, channelID string, receiver common.Address, payload []byte, value uint64) (uint64, error) {
+	if gas != 0 {
+		return gas, nil
+	}
+	estimate, err := client.EstimateGas(channelID, receiver, payload, value)
+	if err != nil {
+		return 0, err
+	}
+	return estimate.Gas, nil
+}
+
+// renderReceipt renders the receipt of tx and the logs emitted
+func renderReceipt(status *pb.TxStatus) {
+	table := util.NewTable()
+	table.SetHeader("Code", "Sender", "GasLimit", "GasUsed", "Tokens")
+	table.AddRow(status.Code.String(), status.Sender, status.GasLimit, status.GasUsed, status.Tokens)
+	table.Render()
+	if len(status.Logs) == 0 {
+		return
+	}
+	table = util.NewTable()
+	table.SetHeader("Address", "Topics", "Data")
+	for _, log := range status.Logs {
+		var topics []string
+		for _, topic := range log.Topics {
+			topics = append(topics, hex.EncodeToString(topic))
+		}
+		table.AddRow(common.BytesToAddress(log.Address).String(), strings.Join(topics, ","), hex.EncodeToString(log.Data))
+	}
+	table.Render()
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package tx
+
+import (
+	"encoding/hex"
+	"errors"
+	"madledger/client/lib"
+	"madledger/client/util"
+	"madledger/common"
+	pb "madledger/protos"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	traceCmd = &cobra.Command{
+		Use: "trace",
+	}
+	traceViper = viper.New()
+)
+
+func init() {
+	traceCmd.RunE = runTrace
+	traceCmd.Flags().StringP("config", "c", "client.yaml", "The config file of client")
+	traceViper.BindPFlag("config", traceCmd.Flags().Lookup("config"))
+	traceCmd.Flags().StringP("channelID", "n", "", "The channelID of the tx")
+	traceViper.BindPFlag("channelID", traceCmd.Flags().Lookup("channelID"))
+	traceCmd.Flags().StringP("txID", "t", "", "The id of the tx")
+	traceViper.BindPFlag("txID", traceCmd.Flags().Lookup("txID"))
+	traceCmd.Flags().BoolP("steps", "s", false, "Print the ops run by evm")
+	traceViper.BindPFlag("steps", traceCmd.Flags().Lookup("steps"))
+	traceCmd.Flags().Bool("noStack", false, "Do not record the stack of ops")
+	traceViper.BindPFlag("noStack", traceCmd.Flags().Lookup("noStack"))
+	traceCmd.Flags().Bool("noMemory", false, "Do not record the memory of ops")
+	traceViper.BindPFlag("noMemory", traceCmd.Flags().Lookup("noMemory"))
+	traceCmd.Flags().Uint64("maxSteps", 0, "The max number of ops, 0 means the limit of peer")
+	traceViper.BindPFlag("maxSteps", traceCmd.Flags().Lookup("maxSteps"))
+}
+
+func runTrace(cmd *cobra.Command, args []string) error {
+	cfgFile := traceViper.GetString("config")
+	if cfgFile == "" {
+		return errors.New("The config file of client can not be nil")
+	}
+	channelID := traceViper.GetString("channelID")
+	if channelID == "" {
+		return errors.New("The channelID of tx can not be nil")
+	}
+	txID := traceViper.GetString("txID")
+	if txID == "" {
+		return errors.New("The id of tx can not be nil")
+	}
+
+	client, err := lib.NewClient(cfgFile)
+	if err != nil {
+		return err
+	}
+	trace, err := client.TraceTransaction(channelID, txID, lib.TraceOptions{
+		DisableStack:  traceViper.GetBool("noStack"),
+		DisableMemory: traceViper.GetBool("noMemory"),
+		MaxSteps:      traceViper.GetUint64("maxSteps"),
+	})
+	if err != nil {
+		return err
+	}
+
+	if traceViper.GetBool("steps") {
+		table := util.NewTable()
+		table.SetHeader("Depth", "PC", "Op", "Gas", "GasCost", "Stack", "Memory")
+		for _, step := range trace.Steps {
+			var stack []string
+			for i := len(step.Stack) - 1; i >= 0; i-- {
+				stack = append(stack, trimWord(step.Stack[i]))
+			}
+			table.AddRow(step.Depth, step.PC, step.Op, step.Gas, step.GasCost, strings.Join(stack, ","), hex.EncodeToString(step.Memory))
+		}
+		table.Render()
+	}
+
+	table := util.NewTable()
+	table.SetHeader("Op", "From", "To", "Value", "Gas", "GasUsed", "Error")
+	addCalls(table, trace.Call, 0)
+	table.Render()
+
+	if len(trace.StorageChanges) != 0 {
+		table = util.NewTable()
+		table.SetHeader("Address", "Key", "Before", "After")
+		for _, change := range trace.StorageChanges {
+			table.AddRow(common.BytesToAddress(change.Address).String(), trimWord(change.Key), trimWord(change.Before), trimWord(change.After))
+		}
+		table.Render()
+	}
+
+	renderReceipt(trace.Status)
+	return nil
+}
+
+// addCalls adds the call and the calls it makes to table, the op is
+// indented by the depth of call
+func addCalls(table util.Table, call *pb.TraceCall, depth int) {
+	if call == nil {
+		return
+	}
+	table.AddRow(strings.Repeat("  ", depth)+call.Op, common.BytesToAddress(call.From).String(),
+		common.BytesToAddress(call.To).String(), call.Value, call.Gas, call.GasUsed, call.Err)
+	for _, child := range call.Calls {
+		addCalls(table, child, depth+1)
+	}
+}
+
+// trimWord return the hex of word without the leading zeros
+func trimWord(word []byte) string {
+	s := strings.TrimLeft(hex.EncodeToString(word), "0")
+	if s == "" {
+		return "0"
+	}
+	return s
+}
@@ -12,8 +12,11 @@ package lib
 
 // ChannelInfo contains the info of channel
 type ChannelInfo struct {
-	Name      string
-	System    bool
-	BlockSize uint64
-	Identity  string
+	Name            string
+	System          bool
+	BlockSize       uint64
+	Identity        string
+	MaxGas          uint64
+	GasPrice        uint64
+	AssetTokenRatio uint64
 }
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"madledger/common"
 	"madledger/common/crypto"
+	"madledger/common/smt"
 	"madledger/core"
 	"sort"
 	"strings"
@@ -27,6 +28,7 @@ import (
 
 	"google.golang.org/grpc"
 
+	"madledger/blockchain/asset"
 	cc "madledger/blockchain/config"
 	"madledger/client/config"
 	pb "madledger/protos"
@@ -157,10 +159,13 @@ func (c *Client) ListChannel(system bool) ([]ChannelInfo, error) {
 
 	for i, channel := range infos.Channels {
 		channelInfos = append(channelInfos, ChannelInfo{
-			Name:      channel.ChannelID,
-			System:    false,
-			BlockSize: channel.BlockSize,
-			Identity:  channel.Identity.String(),
+			Name:            channel.ChannelID,
+			System:          false,
+			BlockSize:       channel.BlockSize,
+			Identity:        channel.Identity.String(),
+			MaxGas:          channel.MaxGas,
+			GasPrice:        channel.GasPrice,
+			AssetTokenRatio: channel.AssetTokenRatio,
 		})
 		if strings.HasPrefix(channel.ChannelID, "_") {
 			channelInfos[i].System = true
@@ -184,29 +189,35 @@ func (c *Client) ListChannel(system bool) ([]ChannelInfo, error) {
 // CreateChannel create a channel
 func (c *Client) CreateChannel(channelID string, public bool, admins, members []*core.Member,
 	gasPrice uint64, ratio uint64, maxGas uint64) error {
+	return c.CreateChannelWithProfile(channelID, &cc.Profile{
+		Public:          public,
+		Admins:          admins,
+		Members:         members,
+		GasPrice:        gasPrice,
+		AssetTokenRatio: ratio,
+		MaxGas:          maxGas,
+	})
+}
+
+// CreateChannelWithProfile create a channel with profile, the client is
+// added into the admins of channel
+func (c *Client) CreateChannelWithProfile(channelID string, profile *cc.Profile) error {
 	// log.Infof("Create channel %s", channelID)
 	self, err := core.NewMember(c.GetPrivKey().PubKey(), "admin")
 	if err != nil {
 		return err
 	}
-	admins = unionMembers(admins, []*core.Member{self})
+	profile.Admins = unionMembers(profile.Admins, []*core.Member{self})
 	// if this is a public channel, there is no need to contain members
-	if public {
-		members = make([]*core.Member, 0)
+	if profile.Public {
+		profile.Members = make([]*core.Member, 0)
 	} else {
-		members = unionMembers(admins, members)
+		profile.Members = unionMembers(profile.Admins, profile.Members)
 	}
 	payload, _ := json.Marshal(cc.Payload{
 		ChannelID: channelID,
-		Profile: &cc.Profile{
-			Public:          public,
-			Admins:          admins,
-			Members:         members,
-			GasPrice:        gasPrice,
-			AssetTokenRatio: ratio,
-			MaxGas:          maxGas,
-		},
-		Version: 1,
+		Profile:   profile,
+		Version:   1,
 	})
 	coreTx, _ := core.NewTx(core.CONFIGCHANNELID, core.CreateChannelContractAddress, payload, 0, "", c.GetPrivKey())
 	pbTx, _ := pb.NewTx(coreTx)
@@ -285,6 +296,53 @@ func (c *Client) AddTx(tx *core.Tx) (*pb.TxStatus, error) {
 	return result.(*pb.TxStatus), nil
 }
 
+// WaitTx waits until every peer runs tx, the queries of peers after it are
+// answered by the state after tx whichever peer answers
+func (c *Client) WaitTx(tx *core.Tx) error {
+	errs := make(chan error, len(c.peerClients))
+	for i := range c.peerClients {
+		go func(i int) {
+			_, err := c.peerClients[i].GetTxStatus(context.Background(), &pb.GetTxStatusRequest{
+				ChannelID: tx.Data.ChannelID,
+				TxID:      tx.ID,
+				Behavior:  pb.Behavior_RETURN_UNTIL_READY,
+			})
+			errs <- err
+		}(i)
+	}
+	for range c.peerClients {
+		if err := <-errs; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTxStatus return the status of tx which is run already, only the peers
+// running the channel have it
+func (c *Client) GetTxStatus(channelID, txID string) (*pb.TxStatus, error) {
+	collector := NewCollector(len(c.peerClients), 1)
+	for i := range c.peerClients {
+		go func(i int) {
+			status, err := c.peerClients[i].GetTxStatus(context.Background(), &pb.GetTxStatusRequest{
+				ChannelID: channelID,
+				TxID:      txID,
+				Behavior:  pb.Behavior_FAIL_IF_NOT_READY,
+			})
+			if err != nil {
+				collector.AddError(err)
+			} else {
+				collector.Add(status)
+			}
+		}(i)
+	}
+	result, err := collector.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return result.(*pb.TxStatus), nil
+}
+
 // GetHistory return the history of address
 // TODO: Support bft
 func (c *Client) GetHistory(address []byte) (*pb.TxHistory, error) {
@@ -337,24 +395,125 @@ func (c *Client) GetPrivKey() crypto.PrivateKey {
 
 // GetAccountBalance return balance of account
 func (c *Client) GetAccountBalance(address common.Address) (uint64, error) {
+	info, err := c.GetAccountInfo(address, "")
+	if err != nil {
+		return 0, err
+	}
+	return info.GetBalance(), nil
+}
+
+// GetAccountInfo return balance of the asset and infos of the asset if it is a named asset
+func (c *Client) GetAccountInfo(address common.Address, assetID string) (*pb.AccountInfo, error) {
 	var times int
 	var acc *pb.AccountInfo
 	var err error
 	for i, ordererClient := range c.ordererClients {
 		acc, err = ordererClient.GetAccountInfo(context.Background(), &pb.GetAccountInfoRequest{
 			Address: address.Bytes(),
+			AssetID: assetID,
+		})
+		times = i + 1
+		if err != nil {
+			// try to use other ordererClients until the last one still returns an error
+			if times == len(c.ordererClients) {
+				return nil, err
+			}
+		} else {
+			break
+		}
+	}
+	return acc, nil
+}
+
+// GetComplianceHistory return the actions the asset admin takes on the account
+func (c *Client) GetComplianceHistory(address common.Address) ([]*pb.ComplianceRecord, error) {
+	var times int
+	var history *pb.ComplianceHistory
+	var err error
+	for i, ordererClient := range c.ordererClients {
+		history, err = ordererClient.GetComplianceHistory(context.Background(), &pb.GetComplianceHistoryRequest{
+			Address: address.Bytes(),
+		})
+		times = i + 1
+		if err != nil {
+			// try to use other ordererClients until the last one still returns an error
+			if times == len(c.ordererClients) {
+				return nil, err
+			}
+		} else {
+			break
+		}
+	}
+	return history.GetRecords(), nil
+}
+
+// GetChannelBilling return the billing statement of user channel with the latest count charges,
+// zero count means all charges
+func (c *Client) GetChannelBilling(channelID string, count uint32) (*pb.ChannelBilling, error) {
+	var times int
+	var billing *pb.ChannelBilling
+	var err error
+	for i, ordererClient := range c.ordererClients {
+		billing, err = ordererClient.GetChannelBilling(context.Background(), &pb.GetChannelBillingRequest{
+			ChannelID: channelID,
+			Count:     count,
 		})
 		times = i + 1
 		if err != nil {
 			// try to use other ordererClients until the last one still returns an error
 			if times == len(c.ordererClients) {
-				return 0, err
+				return nil, err
 			}
 		} else {
 			break
 		}
 	}
-	return acc.GetBalance(), nil
+	return billing, nil
+}
+
+// WatchBilling return the stream of billing events of channel from the first
+// available orderer, empty channelID means all user channels. The events
+// after it returns are all sent until ctx is canceled.
+func (c *Client) WatchBilling(ctx context.Context, channelID string) (pb.Orderer_WatchBillingClient, error) {
+	var err error
+	for _, ordererClient := range c.ordererClients {
+		var stream pb.Orderer_WatchBillingClient
+		stream, err = ordererClient.WatchBilling(ctx, &pb.WatchBillingRequest{
+			ChannelID: channelID,
+		})
+		if err != nil {
+			continue
+		}
+		// the header is sent after the orderer registers the events
+		if _, err = stream.Header(); err != nil {
+			continue
+		}
+		return stream, nil
+	}
+	return nil, err
+}
+
+// GetPeerAssetBalance return balance of the asset which is recorded by peers
+func (c *Client) GetPeerAssetBalance(address common.Address, assetID string) (uint64, error) {
+	collector := NewCollector(len(c.peerClients), 1)
+	for i := range c.peerClients {
+		go func(i int) {
+			info, err := c.peerClients[i].GetTokenInfo(context.Background(), &pb.GetTokenInfoRequest{
+				Address: address.Bytes(),
+				AssetID: assetID,
+			})
+			if err != nil {
+				collector.AddError(err)
+			} else {
+				collector.Add(info)
+			}
+		}(i)
+	}
+	result, err := collector.Wait()
+	if err != nil {
+		return 0, err
+	}
+	return result.(*pb.TokenInfo).GetAssetBalance(), nil
 }
 
 // GetTokenInfo return balance of account
@@ -380,3 +539,273 @@ func (c *Client) GetTokenInfo(address common.Address, channelID []byte) (uint64,
 	}
 	return result.(*pb.TokenInfo).GetBalance(), err
 }
+
+// BurnToken burns the token that value of asset is exchanged for in channel,
+// an admin of channel redeems the asset with the id of the burning tx
+func (c *Client) BurnToken(channelID string, value uint64) (*core.Tx, *pb.TxStatus, error) {
+	tx, err := core.NewTx(channelID, core.TokenBurnAddress, nil, value, "", c.GetPrivKey())
+	if err != nil {
+		return nil, nil, err
+	}
+	status, err := c.AddTx(tx)
+	return tx, status, err
+}
+
+// RedeemToken redeems the asset of the token burned by burnID in channel,
+// only an admin of channel could redeem. The owner and the amount of asset
+// are taken from the status of the burning tx.
+func (c *Client) RedeemToken(channelID, burnID string) (*core.Tx, *pb.TxStatus, error) {
+	burn, err := c.GetTxStatus(channelID, burnID)
+	if err != nil {
+		return nil, nil, err
+	}
+	// only the burning tx charges token without using gas
+	if burn.Code != pb.TxCode_SUCCESS || burn.GasUsed != 0 || burn.Tokens == 0 {
+		return nil, nil, fmt.Errorf("the token is not burned by %s: %s", burnID, burn.Err)
+	}
+	infos, err := c.ListChannel(false)
+	if err != nil {
+		return nil, nil, err
+	}
+	var ratio uint64
+	for _, info := range infos {
+		if info.Name == channelID {
+			ratio = info.AssetTokenRatio
+		}
+	}
+	if ratio == 0 || burn.Tokens%ratio != 0 {
+		return nil, nil, fmt.Errorf("the token %d burned by %s is not exchanged under the ratio %d", burn.Tokens, burnID, ratio)
+	}
+	payload, err := json.Marshal(asset.Payload{
+		Address:   common.HexToAddress(burn.Sender),
+		ChannelID: channelID,
+		BurnID:    burnID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	tx, err := core.NewTx(core.ASSETCHANNELID, core.TokenRedeemAddress, payload, burn.Tokens/ratio, "", c.GetPrivKey())
+	if err != nil {
+		return nil, nil, err
+	}
+	status, err := c.AddTx(tx)
+	return tx, status, err
+}
+
+// GetStateRoot return the state root of channel after the latest block
+func (c *Client) GetStateRoot(channelID string) (*pb.StateRoot, error) {
+	collector := NewCollector(len(c.peerClients), 1)
+	for i := range c.peerClients {
+		go func(i int) {
+			root, err := c.peerClients[i].GetStateRoot(context.Background(), &pb.GetStateRootRequest{
+				ChannelID: channelID,
+				Latest:    true,
+			})
+			if err != nil {
+				collector.AddError(err)
+			} else {
+				collector.Add(root)
+			}
+		}(i)
+	}
+	result, err := collector.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return result.(*pb.StateRoot), nil
+}
+
+// GetStateProof return the proof of account of address, or its storage slot
+// if slot is not nil, in the state of channel after the latest block. The
+// proof is verified against its root, which could be compared with the
+// roots of other peers.
+func (c *Client) GetStateProof(channelID string, address common.Address, slot []byte) (*pb.StateProof, error) {
+	collector := NewCollector(len(c.peerClients), 1)
+	for i := range c.peerClients {
+		go func(i int) {
+			proof, err := c.peerClients[i].GetStateProof(context.Background(), &pb.GetStateProofRequest{
+				ChannelID: channelID,
+				Latest:    true,
+				Address:   address.Bytes(),
+				Slot:      slot,
+			})
+			if err != nil {
+				collector.AddError(err)
+			} else {
+				collector.Add(proof)
+			}
+		}(i)
+	}
+	result, err := collector.Wait()
+	if err != nil {
+		return nil, err
+	}
+	proof := result.(*pb.StateProof)
+	key := core.StateKey(channelID, address, slot)
+	var value []byte
+	if len(proof.Value) != 0 {
+		value = proof.Value
+	}
+	err = (&smt.Proof{
+		Siblings:  proof.Siblings,
+		KeyHash:   proof.KeyHash,
+		ValueHash: proof.ValueHash,
+	}).Verify(proof.Root, key, value)
+	if err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// GetAccountAt return the account of address in the state of channel after
+// block num
+func (c *Client) GetAccountAt(channelID string, address common.Address, num uint64) (*pb.StateAccount, error) {
+	collector := NewCollector(len(c.peerClients), 1)
+	for i := range c.peerClients {
+		go func(i int) {
+			account, err := c.peerClients[i].GetAccountAt(context.Background(), &pb.GetAccountAtRequest{
+				ChannelID: channelID,
+				Num:       num,
+				Address:   address.Bytes(),
+			})
+			if err != nil {
+				collector.AddError(err)
+			} else {
+				collector.Add(account)
+			}
+		}(i)
+	}
+	result, err := collector.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return result.(*pb.StateAccount), nil
+}
+
+// GetStorageAt return the storage slot key of contract in the state of
+// channel after block num
+func (c *Client) GetStorageAt(channelID string, contract common.Address, key []byte, num uint64) ([]byte, error) {
+	collector := NewCollector(len(c.peerClients), 1)
+	for i := range c.peerClients {
+		go func(i int) {
+			storage, err := c.peerClients[i].GetStorageAt(context.Background(), &pb.GetStorageAtRequest{
+				ChannelID: channelID,
+				Num:       num,
+				Address:   contract.Bytes(),
+				Key:       key,
+			})
+			if err != nil {
+				collector.AddError(err)
+			} else {
+				collector.Add(storage)
+			}
+		}(i)
+	}
+	result, err := collector.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return result.(*pb.StateStorage).Value, nil
+}
+
+// CallAt calls the contract with payload on the state of channel after
+// block num, nothing is changed and no tx is sent
+func (c *Client) CallAt(channelID string, num uint64, contract common.Address, payload []byte) (*pb.CallResult, error) {
+	caller, err := c.GetPrivKey().PubKey().Address()
+	if err != nil {
+		return nil, err
+	}
+	collector := NewCollector(len(c.peerClients), 1)
+	for i := range c.peerClients {
+		go func(i int) {
+			result, err := c.peerClients[i].CallAt(context.Background(), &pb.CallAtRequest{
+				ChannelID: channelID,
+				Num:       num,
+				Caller:    caller.Bytes(),
+				Contract:  contract.Bytes(),
+				Payload:   payload,
+			})
+			if err != nil {
+				collector.AddError(err)
+			} else {
+				collector.Add(result)
+			}
+		}(i)
+	}
+	result, err := collector.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return result.(*pb.CallResult), nil
+}
+
+// EstimateGas dry-runs a tx with payload to receiver on the state of channel
+// after the latest block, and return the least gas limit the tx succeeds
+// with. The receiver is the zero address if the tx creates a contract.
+func (c *Client) EstimateGas(channelID string, receiver common.Address, payload []byte, value uint64) (*pb.GasEstimate, error) {
+	caller, err := c.GetPrivKey().PubKey().Address()
+	if err != nil {
+		return nil, err
+	}
+	collector := NewCollector(len(c.peerClients), 1)
+	for i := range c.peerClients {
+		go func(i int) {
+			estimate, err := c.peerClients[i].EstimateGas(context.Background(), &pb.EstimateGasRequest{
+				ChannelID: channelID,
+				Caller:    caller.Bytes(),
+				Receiver:  receiver.Bytes(),
+				Payload:   payload,
+				Value:     value,
+			})
+			if err != nil {
+				collector.AddError(err)
+			} else {
+				collector.Add(estimate)
+			}
+		}(i)
+	}
+	result, err := collector.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return result.(*pb.GasEstimate), nil
+}
+
+// TraceOptions decides what peers record in a trace, a zero limit means the
+// limit of peer, and the trace fails if it exceeds the limits
+type TraceOptions struct {
+	DisableStack  bool
+	DisableMemory bool
+	// MaxSteps is the max number of steps
+	MaxSteps uint64
+	// MaxBytes is the max size of stack and memory of all steps
+	MaxBytes uint64
+}
+
+// TraceTransaction runs the tx again on the peers in the context of its
+// block, and return the ops and calls it runs
+func (c *Client) TraceTransaction(channelID, txID string, options TraceOptions) (*pb.TxTrace, error) {
+	collector := NewCollector(len(c.peerClients), 1)
+	for i := range c.peerClients {
+		go func(i int) {
+			trace, err := c.peerClients[i].TraceTransaction(context.Background(), &pb.TraceTransactionRequest{
+				ChannelID:     channelID,
+				TxID:          txID,
+				DisableStack:  options.DisableStack,
+				DisableMemory: options.DisableMemory,
+				MaxSteps:      options.MaxSteps,
+				MaxBytes:      options.MaxBytes,
+			})
+			if err != nil {
+				collector.AddError(err)
+			} else {
+				collector.Add(trace)
+			}
+		}(i)
+	}
+	result, err := collector.Wait()
+	if err != nil {
+		return nil, err
+	}
+	return result.(*pb.TxTrace), nil
+}
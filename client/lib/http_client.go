@@ -112,10 +112,13 @@ func (c *HTTPClient) ListChannelByHTTP(system bool) ([]ChannelInfo, error) {
 	}
 	for i, channel := range infos.ChannelInfos.Channels {
 		channelInfos = append(channelInfos, ChannelInfo{
-			Name:      channel.ChannelID,
-			System:    false,
-			BlockSize: channel.BlockSize,
-			Identity:  channel.Identity.String(),
+			Name:            channel.ChannelID,
+			System:          false,
+			BlockSize:       channel.BlockSize,
+			Identity:        channel.Identity.String(),
+			MaxGas:          channel.MaxGas,
+			GasPrice:        channel.GasPrice,
+			AssetTokenRatio: channel.AssetTokenRatio,
 		})
 		if strings.HasPrefix(channel.ChannelID, "_") {
 			channelInfos[i].System = true
@@ -174,7 +177,7 @@ func (c *HTTPClient) CreateChannelByHTTP(channelID string, public bool, admins,
 	for i, ordererHTTPClient := range c.ordererHTTPClients {
 		coreTxBytes, _ := coreTx.Bytes()
 		requestBody, _ := json.Marshal(map[string]string{
-			"tx": string(coreTxBytes),
+			"tx": hex.EncodeToString(coreTxBytes),
 		})
 		resp, err := http.Post("http://"+ordererHTTPClient+"/v1/createchannel", "application/json", bytes.NewBuffer(requestBody))
 		if err != nil {
@@ -218,7 +221,7 @@ func (c *HTTPClient) AddTxByHTTP(tx *core.Tx) (*pb.TxStatus, error) {
 	for i, ordererHTTPClient := range c.ordererHTTPClients {
 		coreTxBytes, _ := tx.Bytes()
 		requestBody, _ := json.Marshal(map[string]string{
-			"tx": string(coreTxBytes),
+			"tx": hex.EncodeToString(coreTxBytes),
 		})
 		resp, err := http.Post("http://"+ordererHTTPClient+"/v1/addtx", "application/json", bytes.NewBuffer(requestBody))
 		if err != nil {
@@ -337,38 +340,124 @@ type GetAccountBalanceResp struct {
 
 //GetAccountBalanceByHTTP Get Account Balance By HTTP
 func (c *HTTPClient) GetAccountBalanceByHTTP(address common.Address) (uint64, error) {
+	info, err := c.GetAccountInfoByHTTP(address, "")
+	if err != nil {
+		return 0, err
+	}
+	return info.GetBalance(), nil
+}
+
+// GetAccountInfoByHTTP return balance of the asset and infos of the asset by http
+func (c *HTTPClient) GetAccountInfoByHTTP(address common.Address, assetID string) (*pb.AccountInfo, error) {
 	var times int
 	var info GetAccountBalanceResp
 	for i := range c.ordererHTTPClients {
 		requestBody, _ := json.Marshal(map[string]string{
 			"address": hex.EncodeToString(address.Bytes()),
+			"asset":   assetID,
 		})
 		resp, err := http.Post("http://"+c.ordererHTTPClients[i]+"/v1/getaccountinfo", "application/json", bytes.NewBuffer(requestBody))
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
 
 		body, err := ioutil.ReadAll(resp.Body)
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
 		defer resp.Body.Close()
 		err = json.Unmarshal(body, &info)
 		if err != nil {
-			return 0, err
+			return nil, err
+		}
+		if info.Error != "" {
+			return nil, errors.New(info.Error)
 		}
 		times = i + 1
 		if err != nil {
 			// try to use other ordererClients until the last one still returns an error
 			if times == len(c.ordererHTTPClients) {
-				return 0, err
+				return nil, err
 			}
 		} else {
 			break
 		}
 
 	}
-	return info.Account.GetBalance(), nil
+	return &info.Account, nil
+}
+
+// GetComplianceHistoryResp ...
+type GetComplianceHistoryResp struct {
+	Error   string               `json:"error"`
+	History pb.ComplianceHistory `json:"compliancehistory"`
+}
+
+// GetComplianceHistoryByHTTP return the actions the asset admin takes on the account by http
+func (c *HTTPClient) GetComplianceHistoryByHTTP(address common.Address) ([]*pb.ComplianceRecord, error) {
+	var history GetComplianceHistoryResp
+	var err error
+	for i := range c.ordererHTTPClients {
+		requestBody, _ := json.Marshal(map[string]string{
+			"address": hex.EncodeToString(address.Bytes()),
+		})
+		var resp *http.Response
+		resp, err = http.Post("http://"+c.ordererHTTPClients[i]+"/v1/getcompliancehistory", "application/json", bytes.NewBuffer(requestBody))
+		if err != nil {
+			// try to use other ordererClients until the last one still returns an error
+			continue
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(body, &history); err != nil {
+			return nil, err
+		}
+		if history.Error != "" {
+			return nil, errors.New(history.Error)
+		}
+		return history.History.GetRecords(), nil
+	}
+	return nil, err
+}
+
+// GetChannelBillingResp ...
+type GetChannelBillingResp struct {
+	Error   string            `json:"error"`
+	Billing pb.ChannelBilling `json:"channelbilling"`
+}
+
+// GetChannelBillingByHTTP return the billing statement of user channel by http
+func (c *HTTPClient) GetChannelBillingByHTTP(channelID string, count uint32) (*pb.ChannelBilling, error) {
+	var billing GetChannelBillingResp
+	var err error
+	for i := range c.ordererHTTPClients {
+		requestBody, _ := json.Marshal(map[string]interface{}{
+			"channelID": channelID,
+			"count":     count,
+		})
+		var resp *http.Response
+		resp, err = http.Post("http://"+c.ordererHTTPClients[i]+"/v1/getchannelbilling", "application/json", bytes.NewBuffer(requestBody))
+		if err != nil {
+			// try to use other ordererClients until the last one still returns an error
+			continue
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if err = json.Unmarshal(body, &billing); err != nil {
+			return nil, err
+		}
+		if billing.Error != "" {
+			return nil, errors.New(billing.Error)
+		}
+		return &billing.Billing, nil
+	}
+	return nil, err
 }
 
 // GetTokenInfoResp ...
@@ -23,7 +23,11 @@ type Account struct {
 	Balance uint64
 	// storage debt a user channel owe to our system
 	// if not zero, the channel should be halted till it pays off
-	Due         uint64
+	Due uint64
+	// Assets holds balances of named assets, the native asset is kept in Balance
+	Assets map[string]uint64 `json:",omitempty"`
+	// Frozen account can not transfer asset out, it is set by the asset admin
+	Frozen      bool `json:",omitempty"`
 	Code        []byte
 	Nonce       uint64
 	SuicideMark bool
@@ -68,6 +72,45 @@ func (a *Account) SubBalance(balance uint64) error {
 	return nil
 }
 
+// GetAssetBalance return balance of the asset, empty assetID means the native asset
+func (a *Account) GetAssetBalance(assetID string) uint64 {
+	if assetID == "" {
+		return a.Balance
+	}
+	return a.Assets[assetID]
+}
+
+// AddAssetBalance add balance of the asset
+func (a *Account) AddAssetBalance(assetID string, balance uint64) error {
+	if assetID == "" {
+		return a.AddBalance(balance)
+	}
+	if _, overflow := math.SafeAdd(a.Assets[assetID], balance); overflow {
+		return errors.New("Overflow")
+	}
+	if a.Assets == nil {
+		a.Assets = make(map[string]uint64)
+	}
+	a.Assets[assetID] += balance
+	return nil
+}
+
+// SubAssetBalance sub balance of the asset
+func (a *Account) SubAssetBalance(assetID string, balance uint64) error {
+	if assetID == "" {
+		return a.SubBalance(balance)
+	}
+	if _, overflow := math.SafeSub(a.Assets[assetID], balance); overflow {
+		return errors.New("Overflow")
+	}
+	if a.Assets[assetID] == balance {
+		delete(a.Assets, assetID)
+	} else {
+		a.Assets[assetID] -= balance
+	}
+	return nil
+}
+
 // GetDue return due of an account
 func (a *Account) GetDue() uint64 {
 	return a.Due
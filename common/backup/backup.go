@@ -0,0 +1,471 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"madledger/blockchain"
+	"madledger/common/util"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+/*
+*  A backup is a tar.gz archive, which contains sections of a node:
+*  1. blocks: the block stores of all channels
+*  2. db: the leveldb state of the orderer or peer
+*  3. consensus: the metadata of raft or tendermint, only for orderer
+*  The manifest is the last entry of the archive, which records the sha256 of
+*  each file, so an archive without manifest is not complete.
+ */
+
+// Here defines the sections of a backup
+const (
+	BlocksSection    = "blocks"
+	DBSection        = "db"
+	ConsensusSection = "consensus"
+)
+
+const (
+	// Version is the version of the backup format
+	Version      = 1
+	manifestName = "MANIFEST.json"
+)
+
+// File is a file in the backup
+type File struct {
+	Name   string
+	Size   int64
+	SHA256 string
+}
+
+// Manifest describes a backup
+type Manifest struct {
+	Version int
+	// Node is orderer or peer
+	Node string
+	// Online is true if the backup is taken while the node is running
+	Online bool
+	Time   int64
+	// Heights is the number of blocks of each channel
+	Heights map[string]uint64
+	Files   []File
+}
+
+// Write writes the backup of sections, which are section -> dir, into w.
+// The heights of channels are read from the blocks section.
+func Write(w io.Writer, node string, online bool, sections map[string]string) error {
+	heights, err := blockchain.Heights(sections[BlocksSection])
+	if err != nil {
+		return err
+	}
+	writer := NewWriter(w, node, online)
+	writer.SetHeights(heights)
+	for _, section := range []string{BlocksSection, DBSection, ConsensusSection} {
+		if dir, ok := sections[section]; ok {
+			if err := writer.AddDir(section, dir); err != nil {
+				return err
+			}
+		}
+	}
+	return writer.Close()
+}
+
+// Writer writes a backup
+type Writer struct {
+	gz       *gzip.Writer
+	tw       *tar.Writer
+	manifest Manifest
+}
+
+// NewWriter is the constructor of Writer
+func NewWriter(w io.Writer, node string, online bool) *Writer {
+	gz := gzip.NewWriter(w)
+	return &Writer{
+		gz: gz,
+		tw: tar.NewWriter(gz),
+		manifest: Manifest{
+			Version: Version,
+			Node:    node,
+			Online:  online,
+			Time:    util.Now(),
+			Heights: make(map[string]uint64),
+		},
+	}
+}
+
+// SetHeights set the heights of channels recorded in manifest
+func (w *Writer) SetHeights(heights map[string]uint64) {
+	w.manifest.Heights = heights
+}
+
+// AddDir adds all files in dir into the section, nothing is added if the
+// dir does not exist
+func (w *Writer) AddDir(section, dir string) error {
+	if !util.FileExists(dir) {
+		return nil
+	}
+	return filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		name := path.Join(section, filepath.ToSlash(rel))
+		if info.IsDir() {
+			return w.tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeDir,
+				Name:     name + "/",
+				Mode:     0755,
+				ModTime:  info.ModTime(),
+			})
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		return w.addFile(name, file, info)
+	})
+}
+
+func (w *Writer) addFile(name, file string, info os.FileInfo) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := w.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0644,
+		Size:     info.Size(),
+		ModTime:  info.ModTime(),
+	}); err != nil {
+		return err
+	}
+	h := sha256.New()
+	n, err := io.Copy(w.tw, io.TeeReader(io.LimitReader(f, info.Size()), h))
+	if err != nil {
+		return err
+	}
+	if n != info.Size() {
+		return fmt.Errorf("%s is truncated while adding", file)
+	}
+	w.manifest.Files = append(w.manifest.Files, File{
+		Name:   name,
+		Size:   n,
+		SHA256: hex.EncodeToString(h.Sum(nil)),
+	})
+	return nil
+}
+
+// Close writes the manifest and flushes the archive
+func (w *Writer) Close() error {
+	data, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := w.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     manifestName,
+		Mode:     0644,
+		Size:     int64(len(data)),
+		ModTime:  time.Now(),
+	}); err != nil {
+		return err
+	}
+	if _, err := w.tw.Write(data); err != nil {
+		return err
+	}
+	if err := w.tw.Close(); err != nil {
+		return err
+	}
+	return w.gz.Close()
+}
+
+// walk reads the archive, and calls fn on each file except the manifest,
+// then checks the files against the manifest
+func walk(file string, fn func(hdr *tar.Header, r io.Reader) error) (*Manifest, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	var files = make(map[string]File)
+	var manifest *Manifest
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if manifest != nil {
+			return nil, errors.New("Entries after the manifest")
+		}
+		if err := checkName(hdr.Name); err != nil {
+			return nil, err
+		}
+		if hdr.Name == manifestName {
+			manifest = new(Manifest)
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := fn(hdr, nil); err != nil {
+				return nil, err
+			}
+		case tar.TypeReg:
+			h := sha256.New()
+			if err := fn(hdr, io.TeeReader(tr, h)); err != nil {
+				return nil, err
+			}
+			// drain the rest if fn does not read all
+			if _, err := io.Copy(h, tr); err != nil {
+				return nil, err
+			}
+			files[hdr.Name] = File{Name: hdr.Name, Size: hdr.Size, SHA256: hex.EncodeToString(h.Sum(nil))}
+		default:
+			return nil, fmt.Errorf("Unsupported entry %s", hdr.Name)
+		}
+	}
+	if manifest == nil {
+		return nil, errors.New("The manifest is missing, the backup is not complete")
+	}
+	if manifest.Version != Version {
+		return nil, fmt.Errorf("Unsupported backup version %d", manifest.Version)
+	}
+	if len(files) != len(manifest.Files) {
+		return nil, fmt.Errorf("The backup contains %d files while manifest records %d", len(files), len(manifest.Files))
+	}
+	for _, expect := range manifest.Files {
+		if files[expect.Name] != expect {
+			return nil, fmt.Errorf("Checksum of %s does not match", expect.Name)
+		}
+	}
+	return manifest, nil
+}
+
+func checkName(name string) error {
+	clean := path.Clean(name)
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("Illegal entry %s", name)
+	}
+	return nil
+}
+
+// Verify checks the checksums of all files and return the manifest
+func Verify(file string) (*Manifest, error) {
+	return walk(file, func(*tar.Header, io.Reader) error { return nil })
+}
+
+// Restore extracts the sections of the backup of node into the targets,
+// which are section -> dir. Targets should not exist or be empty, and each
+// section is extracted into a staging dir first, then renamed to target
+// after the checksums are verified.
+func Restore(file, node string, targets map[string]string) (*Manifest, error) {
+	var sections []string
+	for section, dir := range targets {
+		if err := checkEmpty(dir); err != nil {
+			return nil, err
+		}
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+	// extract into staging dirs
+	staging := func(section string) string {
+		return filepath.Clean(targets[section]) + ".restore"
+	}
+	for _, section := range sections {
+		if err := os.RemoveAll(staging(section)); err != nil {
+			return nil, err
+		}
+	}
+	manifest, err := walk(file, func(hdr *tar.Header, r io.Reader) error {
+		name := path.Clean(hdr.Name)
+		section := strings.SplitN(name, "/", 2)[0]
+		if _, ok := targets[section]; !ok {
+			return fmt.Errorf("There is no target for section %s", section)
+		}
+		dst := filepath.Join(staging(section), filepath.FromSlash(strings.TrimPrefix(name, section)))
+		if hdr.Typeflag == tar.TypeDir {
+			return os.MkdirAll(dst, 0755)
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if _, err := io.Copy(f, r); err != nil {
+			return err
+		}
+		return f.Sync()
+	})
+	if err == nil && manifest.Node != node {
+		err = fmt.Errorf("The backup is taken from %s, not %s", manifest.Node, node)
+	}
+	if err != nil {
+		for _, section := range sections {
+			os.RemoveAll(staging(section))
+		}
+		return nil, err
+	}
+	for _, section := range sections {
+		if !util.FileExists(staging(section)) {
+			continue
+		}
+		if err := os.RemoveAll(targets[section]); err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(filepath.Clean(targets[section])), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.Rename(staging(section), targets[section]); err != nil {
+			return nil, err
+		}
+	}
+	// make sure the node is back to the height of backup
+	if dir, ok := targets[BlocksSection]; ok {
+		heights, err := blockchain.Heights(dir)
+		if err != nil {
+			return nil, err
+		}
+		for id, height := range manifest.Heights {
+			if heights[id] != height {
+				return nil, fmt.Errorf("Channel %s is restored to %d blocks while the backup has %d", id, heights[id], height)
+			}
+		}
+		if len(heights) != len(manifest.Heights) {
+			return nil, fmt.Errorf("%d channels are restored while the backup has %d", len(heights), len(manifest.Heights))
+		}
+	}
+	return manifest, nil
+}
+
+// checkEmpty return error if the dir exists and is not empty
+func checkEmpty(dir string) error {
+	infos, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(infos) != 0 {
+		return fmt.Errorf("%s is not empty", dir)
+	}
+	return nil
+}
+
+// CopyDir copies files in src to dst, files removed while copying are
+// skipped, which happens when copying the dir of a running consensus
+func CopyDir(src, dst string) error {
+	if !util.FileExists(src) {
+		return nil
+	}
+	return filepath.Walk(src, func(file string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		err = copyFile(file, target)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	})
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	_, err = io.Copy(out, in)
+	return err
+}
+
+// SnapshotLevelDB writes a consistent copy of the leveldb into dir
+func SnapshotLevelDB(db *leveldb.DB, dir string) error {
+	snap, err := db.GetSnapshot()
+	if err != nil {
+		return err
+	}
+	defer snap.Release()
+	dst, err := leveldb.OpenFile(dir, nil)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+	iter := snap.NewIterator(nil, nil)
+	defer iter.Release()
+	batch := new(leveldb.Batch)
+	for iter.Next() {
+		batch.Put(iter.Key(), iter.Value())
+		if batch.Len() >= 1024 {
+			if err := dst.Write(batch, nil); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return dst.Write(batch, nil)
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package backup
+
+import (
+	"bytes"
+	"io/ioutil"
+	"madledger/blockchain"
+	"madledger/common/util"
+	"madledger/core"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testDir = ".backup"
+
+func writeTestBackup(t *testing.T) string {
+	blocks := filepath.Join(testDir, "node", "blocks")
+	manager, err := blockchain.NewManager("test", filepath.Join(blocks, "test"), "")
+	require.NoError(t, err)
+	tx := core.NewTxWithoutSig("test", []byte("backup"), 0)
+	require.NoError(t, manager.AddBlock(core.NewBlock("test", 0, core.GenesisBlockPrevHash, []*core.Tx{tx})))
+	manager.Close()
+	db := filepath.Join(testDir, "node", "db")
+	require.NoError(t, os.MkdirAll(filepath.Join(db, "sub"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(db, "sub", "data"), []byte("data"), 0644))
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, "peer", false, map[string]string{
+		BlocksSection: blocks,
+		DBSection:     db,
+	}))
+	file := filepath.Join(testDir, "node.backup")
+	require.NoError(t, ioutil.WriteFile(file, buf.Bytes(), 0644))
+	return file
+}
+
+func TestRestore(t *testing.T) {
+	os.RemoveAll(testDir)
+	defer os.RemoveAll(testDir)
+	file := writeTestBackup(t)
+
+	manifest, err := Verify(file)
+	require.NoError(t, err)
+	require.Equal(t, "peer", manifest.Node)
+	require.Equal(t, map[string]uint64{"test": 1}, manifest.Heights)
+
+	targets := map[string]string{
+		BlocksSection: filepath.Join(testDir, "restore", "blocks"),
+		DBSection:     filepath.Join(testDir, "restore", "db"),
+	}
+	_, err = Restore(file, "orderer", targets)
+	require.Error(t, err)
+	require.False(t, util.FileExists(targets[BlocksSection]))
+
+	_, err = Restore(file, "peer", map[string]string{DBSection: targets[DBSection]})
+	require.Error(t, err)
+
+	manifest, err = Restore(file, "peer", targets)
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint64{"test": 1}, manifest.Heights)
+	data, err := ioutil.ReadFile(filepath.Join(targets[DBSection], "sub", "data"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("data"), data)
+	// targets are not empty
+	_, err = Restore(file, "peer", targets)
+	require.Error(t, err)
+}
+
+func TestCorruptedBackup(t *testing.T) {
+	os.RemoveAll(testDir)
+	defer os.RemoveAll(testDir)
+	file := writeTestBackup(t)
+	data, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+
+	// the manifest is missing
+	require.NoError(t, ioutil.WriteFile(file, data[:len(data)/2], 0644))
+	_, err = Verify(file)
+	require.Error(t, err)
+
+	// a file is modified
+	var buf bytes.Buffer
+	writer := NewWriter(&buf, "peer", false)
+	require.NoError(t, writer.AddDir(DBSection, filepath.Join(testDir, "node", "db")))
+	writer.manifest.Files[0].SHA256 = writer.manifest.Files[0].SHA256[1:] + "0"
+	require.NoError(t, writer.Close())
+	require.NoError(t, ioutil.WriteFile(file, buf.Bytes(), 0644))
+	_, err = Verify(file)
+	require.Error(t, err)
+	_, err = Restore(file, "peer", map[string]string{DBSection: filepath.Join(testDir, "restore")})
+	require.Error(t, err)
+	require.False(t, util.FileExists(filepath.Join(testDir, "restore")))
+	require.False(t, util.FileExists(filepath.Join(testDir, "restore.restore")))
+}
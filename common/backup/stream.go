@@ -0,0 +1,75 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package backup
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+
+	"google.golang.org/grpc/peer"
+)
+
+// ChunkSize is the max size of data in one message
+const ChunkSize = 1 << 20
+
+// chunkWriter sends the data written in chunks
+type chunkWriter func(data []byte) error
+
+func (w chunkWriter) Write(p []byte) (int, error) {
+	for i := 0; i < len(p); i += ChunkSize {
+		end := i + ChunkSize
+		if end > len(p) {
+			end = len(p)
+		}
+		if err := w(p[i:end]); err != nil {
+			return i, err
+		}
+	}
+	return len(p), nil
+}
+
+// NewStreamWriter return a writer which sends data by send, and Flush
+// should be called after writing
+func NewStreamWriter(send func(data []byte) error) *bufio.Writer {
+	return bufio.NewWriterSize(chunkWriter(send), ChunkSize)
+}
+
+// Receive writes data received by recv into w until io.EOF
+func Receive(w io.Writer, recv func() ([]byte, error)) error {
+	for {
+		data, err := recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+}
+
+// CheckLocal return error if the grpc caller is not from loopback, because
+// a backup contains all data of the node
+func CheckLocal(ctx context.Context) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return errors.New("Unknown caller")
+	}
+	if addr, ok := p.Addr.(*net.TCPAddr); ok && addr.IP.IsLoopback() {
+		return nil
+	}
+	return errors.New("Backup is only allowed from local")
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package compress
+
+import (
+	"fmt"
+
+	"github.com/golang/snappy"
+)
+
+// Here define the supported compressions
+const (
+	None   = "none"
+	Snappy = "snappy"
+)
+
+// Check return error if the compression is not supported,
+// empty name is the same as None
+func Check(name string) error {
+	switch name {
+	case "", None, Snappy:
+		return nil
+	default:
+		return fmt.Errorf("Unsupported compression %s", name)
+	}
+}
+
+// Enabled return if the compression is not None
+func Enabled(name string) bool {
+	return name != "" && name != None
+}
+
+// Encode compress data by the compression
+func Encode(name string, data []byte) ([]byte, error) {
+	switch name {
+	case "", None:
+		return data, nil
+	case Snappy:
+		return snappy.Encode(nil, data), nil
+	default:
+		return nil, fmt.Errorf("Unsupported compression %s", name)
+	}
+}
+
+// Decode decompress data by the compression
+func Decode(name string, data []byte) ([]byte, error) {
+	switch name {
+	case "", None:
+		return data, nil
+	case Snappy:
+		return snappy.Decode(nil, data)
+	default:
+		return nil, fmt.Errorf("Unsupported compression %s", name)
+	}
+}
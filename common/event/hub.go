@@ -60,6 +60,15 @@ func (h *Hub) Done(id string, res interface{}) {
 	delete(h.events, id)
 }
 
+// Forget removes the result of an event which is done, so the events which are
+// watched once do not stay in the hub forever
+func (h *Hub) Forget(id string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	delete(h.finish, id)
+}
+
 // Watch watch an event
 // Note: CallBack function is not called only after watch done but also succeed register watch event,
 // and it should be setted carefully
@@ -105,6 +114,31 @@ func (h *Hub) Broadcast(topic string, msg interface{}) {
 	}
 }
 
+// Publish broadcast msg of topic without blocking, the receiver which falls
+// behind is unregistered and its channel is closed
+func (h *Hub) Publish(topic string, msg interface{}) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	var tokens = h.topics[topic]
+	for i := range tokens {
+		ch, ok := h.chs[tokens[i]]
+		if !ok {
+			continue
+		}
+		select {
+		case ch <- msg:
+		default:
+			close(ch)
+			delete(h.chs, tokens[i])
+			h.topics[topic] = removeFromSlice(h.topics[topic], tokens[i])
+		}
+	}
+	if len(h.topics[topic]) == 0 {
+		delete(h.topics, topic)
+	}
+}
+
 // Register will register an id, the register should hold the token to delete itself
 func (h *Hub) Register(topic string) (ch chan interface{}, token int) {
 	h.lock.Lock()
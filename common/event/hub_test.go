@@ -70,3 +70,44 @@ func TestRegister(t *testing.T) {
 	}()
 	<-finish
 }
+
+func TestPublish(t *testing.T) {
+	var hub = NewHub()
+	var topic = util.RandomString(10)
+	slow, _ := hub.Register(topic)
+	fast, token := hub.Register(topic)
+	for i := 0; i < 200; i++ {
+		hub.Publish(topic, i)
+		if msg := <-fast; msg.(int) != i {
+			t.Fatal()
+		}
+	}
+	// the slow receiver is dropped after 128 msgs
+	for i := 0; i < 128; i++ {
+		if msg := <-slow; msg.(int) != i {
+			t.Fatal()
+		}
+	}
+	if _, ok := <-slow; ok {
+		t.Fatal()
+	}
+	hub.UnRegister(topic, token)
+	hub.Publish(topic, 200)
+}
+
+func TestForget(t *testing.T) {
+	var hub = NewHub()
+	var id = util.RandomString(10)
+	hub.Done(id, 1)
+	if num := hub.Watch(id, nil).(int); num != 1 {
+		t.Fatal()
+	}
+	hub.Forget(id)
+	if len(hub.finish) != 0 {
+		t.Fatal()
+	}
+	hub.Done(id, 2)
+	if num := hub.Watch(id, nil).(int); num != 2 {
+		t.Fatal()
+	}
+}
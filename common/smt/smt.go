@@ -0,0 +1,368 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package smt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"madledger/common/crypto/hash"
+)
+
+/*
+*  A compact sparse merkle tree over sha256(key), nodes are stored by hash
+*  1. Empty: the hash is 32 zero bytes, it is never stored
+*  2. Leaf: 0x00 + sha256(key) + uvarint(len(key)) + key + value,
+*     the hash is sha256(0x00 + sha256(key) + sha256(value))
+*  3. Internal: 0x01 + left + right, the hash is sha256(node)
+*  A subtree which contains only one leaf is the leaf itself, so the root
+*  is determined by the (key, value)s only. Nodes are never removed, so the
+*  tree at any root stored could be read.
+ */
+
+const (
+	leafPrefix     = 0x00
+	internalPrefix = 0x01
+	hashSize       = 32
+)
+
+// EmptyRoot is the root of the tree without leaves
+var EmptyRoot = make([]byte, hashSize)
+
+// Store provides the nodes of tree
+type Store interface {
+	// Get return the node of hash
+	Get(hash []byte) ([]byte, error)
+}
+
+// Tree reads nodes from store and writes new nodes by put
+type Tree struct {
+	store Store
+	put   func(hash, node []byte)
+	// nodes are new nodes which may not be in store yet
+	nodes map[string][]byte
+}
+
+// NewTree is the constructor of Tree, put could be nil if the tree is read only
+func NewTree(store Store, put func(hash, node []byte)) *Tree {
+	return &Tree{
+		store: store,
+		put:   put,
+		nodes: make(map[string][]byte),
+	}
+}
+
+// item is a leaf to be put into a subtree, leaf is EmptyRoot to delete key
+type item struct {
+	keyHash []byte
+	leaf    []byte
+}
+
+// Update sets the (key, value)s on the tree at root and return the new
+// root, a nil value deletes the key
+func (t *Tree) Update(root []byte, kvs map[string][]byte) ([]byte, error) {
+	var items []item
+	for key, value := range kvs {
+		keyHash := hash.SHA256([]byte(key))
+		var leaf = EmptyRoot
+		if value != nil {
+			leaf = t.putLeaf(keyHash, []byte(key), value)
+		}
+		items = append(items, item{keyHash: keyHash, leaf: leaf})
+	}
+	return t.update(root, 0, items)
+}
+
+func (t *Tree) update(node []byte, depth int, items []item) ([]byte, error) {
+	if len(items) == 0 {
+		return node, nil
+	}
+	if isEmpty(node) {
+		return t.build(depth, items)
+	}
+	data, err := t.get(node)
+	if err != nil {
+		return nil, err
+	}
+	if data[0] == leafPrefix {
+		// the leaf is kept unless it is updated
+		keyHash := data[1 : 1+hashSize]
+		for _, item := range items {
+			if bytes.Equal(item.keyHash, keyHash) {
+				return t.build(depth, items)
+			}
+		}
+		return t.build(depth, append(items, item{keyHash: keyHash, leaf: node}))
+	}
+	left, right := split(depth, items)
+	l, err := t.update(data[1:1+hashSize], depth+1, left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := t.update(data[1+hashSize:], depth+1, right)
+	if err != nil {
+		return nil, err
+	}
+	return t.combine(l, r)
+}
+
+// build builds a subtree of items
+func (t *Tree) build(depth int, items []item) ([]byte, error) {
+	var leaves []item
+	for _, item := range items {
+		if !isEmpty(item.leaf) {
+			leaves = append(leaves, item)
+		}
+	}
+	switch len(leaves) {
+	case 0:
+		return EmptyRoot, nil
+	case 1:
+		return leaves[0].leaf, nil
+	}
+	left, right := split(depth, leaves)
+	l, err := t.build(depth+1, left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := t.build(depth+1, right)
+	if err != nil {
+		return nil, err
+	}
+	return t.combine(l, r)
+}
+
+// combine return the node of children, a leaf without sibling moves up
+func (t *Tree) combine(left, right []byte) ([]byte, error) {
+	for _, pair := range [][2][]byte{{left, right}, {right, left}} {
+		if !isEmpty(pair[0]) {
+			continue
+		}
+		if isEmpty(pair[1]) {
+			return EmptyRoot, nil
+		}
+		data, err := t.get(pair[1])
+		if err != nil {
+			return nil, err
+		}
+		if data[0] == leafPrefix {
+			return pair[1], nil
+		}
+	}
+	node := make([]byte, 0, 1+2*hashSize)
+	node = append(node, internalPrefix)
+	node = append(append(node, left...), right...)
+	h := hash.SHA256(node)
+	t.putNode(h, node)
+	return h, nil
+}
+
+func (t *Tree) putLeaf(keyHash, key, value []byte) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], uint64(len(key)))
+	node := make([]byte, 0, 1+hashSize+n+len(key)+len(value))
+	node = append(node, leafPrefix)
+	node = append(node, keyHash...)
+	node = append(node, buf[:n]...)
+	node = append(node, key...)
+	node = append(node, value...)
+	h := leafHash(keyHash, hash.SHA256(value))
+	t.putNode(h, node)
+	return h
+}
+
+func (t *Tree) putNode(h, node []byte) {
+	if _, ok := t.nodes[string(h)]; ok {
+		return
+	}
+	t.nodes[string(h)] = node
+	if t.put != nil {
+		t.put(h, node)
+	}
+}
+
+func (t *Tree) get(h []byte) ([]byte, error) {
+	if node, ok := t.nodes[string(h)]; ok {
+		return node, nil
+	}
+	node, err := t.store.Get(h)
+	if err != nil {
+		return nil, err
+	}
+	if len(node) == 0 || (node[0] == internalPrefix && len(node) != 1+2*hashSize) || (node[0] == leafPrefix && len(node) < 1+hashSize) {
+		return nil, fmt.Errorf("The node %x of tree is damaged", h)
+	}
+	return node, nil
+}
+
+// Get return the value of key in the tree at root, or nil if key is not in
+// the tree
+func (t *Tree) Get(root, key []byte) ([]byte, error) {
+	proof, err := t.Prove(root, key)
+	if err != nil {
+		return nil, err
+	}
+	return proof.Value(), nil
+}
+
+// Walk calls visit with the hash of each node of the tree at root, the key
+// is the key of leaf or nil for internal nodes. The children of a node are
+// skipped if visit return false, so nodes shared by trees could be visited
+// once.
+func (t *Tree) Walk(root []byte, visit func(hash, key []byte) bool) error {
+	if isEmpty(root) {
+		return nil
+	}
+	data, err := t.get(root)
+	if err != nil {
+		return err
+	}
+	if data[0] == leafPrefix {
+		rest := data[1+hashSize:]
+		length, n := binary.Uvarint(rest)
+		if n <= 0 || uint64(len(rest)-n) < length {
+			return fmt.Errorf("The node %x of tree is damaged", root)
+		}
+		visit(root, rest[n:n+int(length)])
+		return nil
+	}
+	if !visit(root, nil) {
+		return nil
+	}
+	if err := t.Walk(data[1:1+hashSize], visit); err != nil {
+		return err
+	}
+	return t.Walk(data[1+hashSize:], visit)
+}
+
+// Proof proves the value of a key in the tree
+type Proof struct {
+	// Siblings are the siblings from the root to the leaf
+	Siblings [][]byte
+	// KeyHash and ValueHash are the leaf at the end of path, they are nil if
+	// the end is empty. The key is not in the tree if KeyHash is not the hash
+	// of key.
+	KeyHash   []byte
+	ValueHash []byte
+
+	value []byte
+}
+
+// Prove return the proof of key in the tree at root
+func (t *Tree) Prove(root, key []byte) (*Proof, error) {
+	var proof Proof
+	keyHash := hash.SHA256(key)
+	node := root
+	for depth := 0; !isEmpty(node); depth++ {
+		data, err := t.get(node)
+		if err != nil {
+			return nil, err
+		}
+		if data[0] == leafPrefix {
+			proof.KeyHash = data[1 : 1+hashSize]
+			rest := data[1+hashSize:]
+			length, n := binary.Uvarint(rest)
+			if n <= 0 || uint64(len(rest)-n) < length {
+				return nil, fmt.Errorf("The node %x of tree is damaged", node)
+			}
+			value := rest[n+int(length):]
+			proof.ValueHash = hash.SHA256(value)
+			if bytes.Equal(proof.KeyHash, keyHash) {
+				proof.value = value
+			}
+			break
+		}
+		left, right := data[1:1+hashSize], data[1+hashSize:]
+		if bit(keyHash, depth) {
+			proof.Siblings = append(proof.Siblings, left)
+			node = right
+		} else {
+			proof.Siblings = append(proof.Siblings, right)
+			node = left
+		}
+	}
+	return &proof, nil
+}
+
+// Value return the value of key read while proving, or nil if key is not
+// in the tree
+func (p *Proof) Value() []byte {
+	return p.value
+}
+
+// Verify checks that the value of key is value in the tree at root, a nil
+// value checks that key is not in the tree
+func (p *Proof) Verify(root, key, value []byte) error {
+	keyHash := hash.SHA256(key)
+	var node = EmptyRoot
+	if p.KeyHash != nil {
+		node = leafHash(p.KeyHash, p.ValueHash)
+		for depth := range p.Siblings {
+			if bit(p.KeyHash, depth) != bit(keyHash, depth) {
+				return errors.New("The leaf of proof is not on the path of key")
+			}
+		}
+	}
+	if value != nil {
+		if !bytes.Equal(p.KeyHash, keyHash) || !bytes.Equal(p.ValueHash, hash.SHA256(value)) {
+			return errors.New("The proof does not prove the value")
+		}
+	} else if bytes.Equal(p.KeyHash, keyHash) {
+		return errors.New("The key is in the tree")
+	}
+	for depth := len(p.Siblings) - 1; depth >= 0; depth-- {
+		sibling := p.Siblings[depth]
+		if len(sibling) != hashSize {
+			return errors.New("The proof is damaged")
+		}
+		data := []byte{internalPrefix}
+		if bit(keyHash, depth) {
+			data = append(append(data, sibling...), node...)
+		} else {
+			data = append(append(data, node...), sibling...)
+		}
+		node = hash.SHA256(data)
+	}
+	if !bytes.Equal(node, root) {
+		return errors.New("The proof does not match the root")
+	}
+	return nil
+}
+
+func leafHash(keyHash, valueHash []byte) []byte {
+	data := make([]byte, 0, 1+2*hashSize)
+	data = append(data, leafPrefix)
+	data = append(append(data, keyHash...), valueHash...)
+	return hash.SHA256(data)
+}
+
+func isEmpty(h []byte) bool {
+	return bytes.Equal(h, EmptyRoot)
+}
+
+// bit return if the bit of keyHash at depth is 1
+func bit(keyHash []byte, depth int) bool {
+	return keyHash[depth/8]&(0x80>>uint(depth%8)) != 0
+}
+
+// split splits items by the bit at depth
+func split(depth int, items []item) ([]item, []item) {
+	var left, right []item
+	for _, item := range items {
+		if bit(item.keyHash, depth) {
+			right = append(right, item)
+		} else {
+			left = append(left, item)
+		}
+	}
+	return left, right
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package smt
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type memStore map[string][]byte
+
+func (s memStore) Get(hash []byte) ([]byte, error) {
+	if node, ok := s[string(hash)]; ok {
+		return node, nil
+	}
+	return nil, errors.New("Not found")
+}
+
+func newMemTree(store memStore) *Tree {
+	return NewTree(store, func(hash, node []byte) {
+		store[string(hash)] = node
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	store := make(memStore)
+	tree := newMemTree(store)
+	var kvs = make(map[string][]byte)
+	for i := 0; i < 100; i++ {
+		kvs[fmt.Sprintf("key%d", i)] = []byte(fmt.Sprintf("value%d", i))
+	}
+	root, err := tree.Update(EmptyRoot, kvs)
+	require.NoError(t, err)
+
+	// the root does not depend on the order of updates
+	other := newMemTree(make(memStore))
+	var otherRoot = EmptyRoot
+	for i := 99; i >= 0; i-- {
+		key := fmt.Sprintf("key%d", i)
+		otherRoot, err = other.Update(otherRoot, map[string][]byte{key: kvs[key]})
+		require.NoError(t, err)
+	}
+	require.Equal(t, root, otherRoot)
+
+	// the nodes are read from store by a new tree
+	tree = NewTree(store, nil)
+	for key, value := range kvs {
+		got, err := tree.Get(root, []byte(key))
+		require.NoError(t, err)
+		require.Equal(t, value, got)
+	}
+	got, err := tree.Get(root, []byte("key100"))
+	require.NoError(t, err)
+	require.Nil(t, got)
+
+	// delete all keys except key0
+	var deletes = make(map[string][]byte)
+	for i := 1; i < 100; i++ {
+		deletes[fmt.Sprintf("key%d", i)] = nil
+	}
+	newRoot, err := newMemTree(store).Update(root, deletes)
+	require.NoError(t, err)
+	single, err := newMemTree(make(memStore)).Update(EmptyRoot, map[string][]byte{"key0": kvs["key0"]})
+	require.NoError(t, err)
+	require.Equal(t, single, newRoot)
+	empty, err := newMemTree(store).Update(newRoot, map[string][]byte{"key0": nil, "key1": nil})
+	require.NoError(t, err)
+	require.Equal(t, EmptyRoot, empty)
+
+	// the old root is still readable
+	got, err = tree.Get(root, []byte("key50"))
+	require.NoError(t, err)
+	require.Equal(t, kvs["key50"], got)
+}
+
+func TestProof(t *testing.T) {
+	store := make(memStore)
+	tree := newMemTree(store)
+	var kvs = make(map[string][]byte)
+	for i := 0; i < 50; i++ {
+		value := make([]byte, 32)
+		rand.Read(value)
+		kvs[fmt.Sprintf("slot%d", i)] = value
+	}
+	root, err := tree.Update(EmptyRoot, kvs)
+	require.NoError(t, err)
+
+	for key, value := range kvs {
+		proof, err := tree.Prove(root, []byte(key))
+		require.NoError(t, err)
+		require.NoError(t, proof.Verify(root, []byte(key), value))
+		require.Error(t, proof.Verify(root, []byte(key), []byte("other")))
+		require.Error(t, proof.Verify(root, []byte(key), nil))
+		require.Error(t, proof.Verify(EmptyRoot, []byte(key), value))
+	}
+	for i := 50; i < 100; i++ {
+		key := []byte(fmt.Sprintf("slot%d", i))
+		proof, err := tree.Prove(root, key)
+		require.NoError(t, err)
+		require.NoError(t, proof.Verify(root, key, nil))
+		require.Error(t, proof.Verify(root, key, []byte("value")))
+	}
+
+	// a proof of one key does not prove another
+	proof, err := tree.Prove(root, []byte("slot0"))
+	require.NoError(t, err)
+	require.Error(t, proof.Verify(root, []byte("slot1"), nil))
+	proof.Siblings[0] = EmptyRoot
+	require.Error(t, proof.Verify(root, []byte("slot0"), kvs["slot0"]))
+
+	proof, err = tree.Prove(EmptyRoot, []byte("slot0"))
+	require.NoError(t, err)
+	require.NoError(t, proof.Verify(EmptyRoot, []byte("slot0"), nil))
+}
+
+func TestWalk(t *testing.T) {
+	store := make(memStore)
+	tree := newMemTree(store)
+	var kvs = make(map[string][]byte)
+	for i := 0; i < 20; i++ {
+		kvs[fmt.Sprintf("key%d", i)] = []byte(fmt.Sprintf("value%d", i))
+	}
+	root, err := tree.Update(EmptyRoot, kvs)
+	require.NoError(t, err)
+	var keys = make(map[string][]byte)
+	var nodes = make(map[string]bool)
+	require.NoError(t, tree.Walk(root, func(hash, key []byte) bool {
+		nodes[string(hash)] = true
+		if key != nil {
+			keys[string(key)] = kvs[string(key)]
+		}
+		return true
+	}))
+	require.Equal(t, kvs, keys)
+	require.Len(t, store, len(nodes))
+
+	// the nodes shared with the old root are skipped
+	newRoot, err := tree.Update(root, map[string][]byte{"key0": []byte("new")})
+	require.NoError(t, err)
+	var updated []string
+	require.NoError(t, tree.Walk(newRoot, func(hash, key []byte) bool {
+		if nodes[string(hash)] {
+			return false
+		}
+		if key != nil {
+			updated = append(updated, string(key))
+		}
+		return true
+	}))
+	require.Equal(t, []string{"key0"}, updated)
+	require.NoError(t, tree.Walk(EmptyRoot, func(hash, key []byte) bool {
+		t.Fatal()
+		return false
+	}))
+}
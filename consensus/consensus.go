@@ -25,3 +25,10 @@ type Consensus interface {
 	// GetBlock return the block or error right away if async is false, else return block until the block is created.
 	GetBlock(channelID string, num uint64, async bool) (Block, error)
 }
+
+// Backuper is implemented by the consensus which keeps data on disk
+type Backuper interface {
+	// Backup copies the data of consensus into dir, the data is not changed
+	// while it is copied
+	Backup(dir string) error
+}
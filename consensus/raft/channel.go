@@ -207,14 +207,13 @@ func (c *channel) getBlock(num uint64, async bool) (*eraft.Block, error) {
 }
 
 func getConfChange(tx []byte) *raftpb.ConfChange {
-	var coreTx core.Tx
 	var cfgChange raftpb.ConfChange
-	err := json.Unmarshal(tx, &coreTx)
+	coreTx, err := core.BytesToTx(tx)
 	// Note: The reason return nil because Tx may be just random bytes,
 	// and this is a bad implementation so we should change the way to do this
 	// TODO: Reimplement it
 	if err != nil {
-		log.Errorf("invalid tx format: %v, tx: %s", err, string(tx))
+		log.Errorf("invalid tx format: %v, tx: %x", err, tx)
 		return nil
 	}
 	// get tx type according to recipient
@@ -228,7 +227,7 @@ func getConfChange(tx []byte) *raftpb.ConfChange {
 	}
 	err = json.Unmarshal(coreTx.Data.Payload, &cfgChange)
 	if err != nil {
-		log.Errorf("failed to unmarshal cfgChange tx payload: %v, tx: %s", err, string(tx))
+		log.Errorf("failed to unmarshal cfgChange tx payload: %v, tx: %s", err, coreTx.ID)
 		return nil
 	}
 	return &cfgChange
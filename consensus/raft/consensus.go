@@ -134,6 +134,11 @@ func (c *Consensus) GetBlock(channelID string, num uint64, async bool) (consensu
 	return c.chain.getBlock(channelID, num, async)
 }
 
+// Backup is the implementation of interface
+func (c *Consensus) Backup(dir string) error {
+	return c.chain.raft.Backup(dir)
+}
+
 func (c *Consensus) setLeader(leader uint64) {
 	atomic.StoreUint64(&c.leader, leader)
 }
@@ -61,6 +61,10 @@ type ERaft struct {
 	lock       sync.Mutex
 	stopCh     chan bool
 	stopDoneCh chan bool
+	// pauseCh receives the channel which resumes the serve loop
+	pauseCh chan chan bool
+	// servedCh is closed when the serve loop returns
+	servedCh chan bool
 	// removed contains the ids of removed members in the cluster.
 	// removed id cannot be reused.
 	removed map[types.ID]bool
@@ -91,6 +95,7 @@ func NewERaft(cfg *EraftConfig, app *App) (*ERaft, error) {
 		status:     Stopped,
 		stopCh:     make(chan bool, 1),
 		stopDoneCh: make(chan bool, 1),
+		pauseCh:    make(chan chan bool),
 		removed:    make(map[types.ID]bool),
 	}, nil
 }
@@ -242,7 +247,9 @@ func (e *ERaft) serve() error {
 	}
 	e.state.update(snap.Metadata)
 
+	e.servedCh = make(chan bool)
 	go func() {
+		defer close(e.servedCh)
 		defer e.wal.Close()
 
 		ticker := time.NewTicker(100 * time.Millisecond)
@@ -285,6 +292,8 @@ func (e *ERaft) serve() error {
 				}
 				e.snapshot()
 				e.node.Advance()
+			case resume := <-e.pauseCh:
+				<-resume
 			case <-e.stopCh:
 				e.stopDoneCh <- true
 				return
@@ -296,6 +305,24 @@ func (e *ERaft) serve() error {
 	return nil
 }
 
+// pause blocks the serve loop until the returned function is called, so
+// nothing is written into the wal and snapshots meanwhile
+func (e *ERaft) pause() func() {
+	e.lock.Lock()
+	if atomic.LoadInt32(&e.status) == Stopped {
+		return e.lock.Unlock
+	}
+	resume := make(chan bool)
+	select {
+	case e.pauseCh <- resume:
+	case <-e.servedCh:
+	}
+	return func() {
+		close(resume)
+		e.lock.Unlock()
+	}
+}
+
 // startHTTP start http service
 func (e *ERaft) startHTTP() error {
 	l, err := net.Listen("tcp", e.cfg.getERaftAddress())
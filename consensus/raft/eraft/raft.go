@@ -13,6 +13,8 @@ package eraft
 import (
 	"errors"
 	"fmt"
+	"madledger/common/backup"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 
@@ -187,3 +189,17 @@ func (r *Raft) ProposeConfChange(change *raftpb.ConfChange) error {
 func (r *Raft) Removed(caller uint64) bool {
 	return r.eraft.removed[types.ID(caller)]
 }
+
+// Backup copies the data of raft into dir, the wal and snapshots are copied
+// while the raft is paused, and the db is copied from a snapshot of it
+func (r *Raft) Backup(dir string) error {
+	resume := r.eraft.pause()
+	defer resume()
+	if err := backup.CopyDir(r.cfg.walDir, filepath.Join(dir, "wal")); err != nil {
+		return err
+	}
+	if err := backup.CopyDir(r.cfg.snapDir, filepath.Join(dir, "snap")); err != nil {
+		return err
+	}
+	return backup.SnapshotLevelDB(r.app.db.connect, filepath.Join(dir, "db"))
+}
@@ -310,8 +310,7 @@ func (g *Glue) updateValidator(tx []byte) types.ResponseDeliverTx {
 			Code: code.CodeTypeEncodingError,
 			Log:  fmt.Sprintf("BytesToTx error %s", err)}
 	}
-	var coreTx core.Tx
-	err = json.Unmarshal(tempTx.Data, &coreTx)
+	coreTx, err := core.BytesToTx(tempTx.Data)
 	if err != nil {
 		return types.ResponseDeliverTx{
 			Code: code.CodeTypeEncodingError,
@@ -13,6 +13,7 @@ package tendermint
 import (
 	"errors"
 	"fmt"
+	"madledger/common/backup"
 	"madledger/consensus"
 	"madledger/core"
 	"sync"
@@ -112,6 +113,23 @@ func (c *Consensus) Stop() error {
 	return nil
 }
 
+// Backup is the implementation of interface, the node is stopped while its
+// data is copied and started again after that
+func (c *Consensus) Backup(dir string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.status != consensus.Started {
+		return backup.CopyDir(c.node.conf.RootDir, dir)
+	}
+	c.node.Stop()
+	err := backup.CopyDir(c.node.conf.RootDir, dir)
+	if startErr := c.node.Start(); startErr != nil {
+		return fmt.Errorf("Failed to start node after backup: %v", startErr)
+	}
+	return err
+}
+
 // GetBlock is the implementation of interface
 func (c *Consensus) GetBlock(channelID string, num uint64, async bool) (consensus.Block, error) {
 	return c.app.GetBlock(channelID, num, async)
@@ -58,16 +58,21 @@ func NewBlock(channelID string, num uint64, prevHash []byte, txs []*Tx) *Block {
 
 // Hash return the hash of Block
 func (b *Block) Hash() common.Hash {
-	var buffer bytes.Buffer
-	buffer.Write(util.Int32ToBytes(b.Header.Version))
-	buffer.Write(util.Uint64ToBytes(b.Header.Number))
-	buffer.Write(b.Header.PrevBlock)
-	buffer.Write(b.Header.MerkleRoot)
 	// Time should not be included
 	// Note: Some consensus has same block time while block has same number,
 	// while some consensus may have different block time even block has same number.
 	// So we should set block time to same thing if we want support evm timestamp instruction in consensus which
 	// block time is not consensused.
+	if b.Header.Version >= BinaryVersion {
+		var e encoder
+		e.blockHeader(b.Header, false)
+		return common.BytesToHash(hash.SM3(e.buf))
+	}
+	var buffer bytes.Buffer
+	buffer.Write(util.Int32ToBytes(b.Header.Version))
+	buffer.Write(util.Uint64ToBytes(b.Header.Number))
+	buffer.Write(b.Header.PrevBlock)
+	buffer.Write(b.Header.MerkleRoot)
 	// buffer.Write(util.Int64ToBytes(b.Header.Time))
 	return common.BytesToHash(hash.SM3(buffer.Bytes()))
 }
@@ -76,7 +81,7 @@ func (b *Block) Hash() common.Hash {
 // May support the version of others in the future
 func NewBlockHeader(channelID string, num uint64, prevHash, merkleRootHash []byte) *BlockHeader {
 	return &BlockHeader{
-		Version:    1,
+		Version:    BinaryVersion,
 		ChannelID:  channelID,
 		Number:     num,
 		PrevBlock:  prevHash,
@@ -85,10 +90,9 @@ func NewBlockHeader(channelID string, num uint64, prevHash, merkleRootHash []byt
 	}
 }
 
-// Bytes return the bytes of Block
+// Bytes return the binary encoding of Block
 func (b *Block) Bytes() []byte {
-	bytes, _ := json.Marshal(b)
-	return bytes
+	return encodeBlock(b)
 }
 
 // GetNumber return the number of Block
@@ -101,8 +105,11 @@ func (b *Block) GetMerkleRoot() []byte {
 	return b.Header.MerkleRoot
 }
 
-// UnmarshalBlock unmarshal json-encoded block
+// UnmarshalBlock unmarshal binary or json encoded block
 func UnmarshalBlock(data []byte) (*Block, error) {
+	if !isJSON(data) {
+		return decodeBlock(data)
+	}
 	var block Block
 
 	if err := json.Unmarshal(data, &block); err != nil {
@@ -0,0 +1,248 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package core
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// The binary encoding is deterministic: integers are fixed length big endian,
+// bytes and strings are prefixed by the uint32 length and fields are written in
+// the order of declaration. Empty bytes are decoded as nil, the same as json
+// with omitempty. A binary encoding never starts with '{', so it could be
+// distinguished from the json encoding of history data.
+
+// Here define the versions of TxData and BlockHeader
+const (
+	// JSONVersion hashes TxData in json and BlockHeader by concatenation
+	JSONVersion int32 = 1
+	// BinaryVersion hashes TxData and BlockHeader in binary encoding
+	BinaryVersion int32 = 2
+)
+
+var (
+	errShortBuffer = errors.New("The binary data is too short")
+)
+
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) uint32(v uint32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	e.buf = append(e.buf, b[:]...)
+}
+
+func (e *encoder) uint64(v uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	e.buf = append(e.buf, b[:]...)
+}
+
+func (e *encoder) int32(v int32) {
+	e.uint32(uint32(v))
+}
+
+func (e *encoder) int64(v int64) {
+	e.uint64(uint64(v))
+}
+
+func (e *encoder) bytes(v []byte) {
+	e.uint32(uint32(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *encoder) string(v string) {
+	e.uint32(uint32(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if len(d.data) < n {
+		d.err = errShortBuffer
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *decoder) uint32() uint32 {
+	if b := d.next(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (d *decoder) uint64() uint64 {
+	if b := d.next(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (d *decoder) int32() int32 {
+	return int32(d.uint32())
+}
+
+func (d *decoder) int64() int64 {
+	return int64(d.uint64())
+}
+
+func (d *decoder) bytes() []byte {
+	n := d.uint32()
+	if n == 0 || d.err != nil {
+		return nil
+	}
+	b := d.next(int(n))
+	if b == nil {
+		return nil
+	}
+	return append([]byte(nil), b...)
+}
+
+func (d *decoder) string() string {
+	n := d.uint32()
+	if n == 0 || d.err != nil {
+		return ""
+	}
+	return string(d.next(int(n)))
+}
+
+// finish return error if the data is damaged or has trailing bytes
+func (d *decoder) finish() error {
+	if d.err == nil && len(d.data) != 0 {
+		d.err = fmt.Errorf("There are %d trailing bytes", len(d.data))
+	}
+	return d.err
+}
+
+func isJSON(data []byte) bool {
+	return len(data) != 0 && data[0] == '{'
+}
+
+func (e *encoder) txData(data *TxData, withSig bool) {
+	e.int32(data.Version)
+	e.string(data.ChannelID)
+	e.uint64(data.Nonce)
+	e.bytes(data.Recipient)
+	e.bytes(data.Payload)
+	e.uint64(data.Value)
+	e.string(data.Msg)
+	e.uint64(data.Gas)
+	if withSig {
+		e.bytes(data.Sig.PK)
+		e.bytes(data.Sig.Sig)
+		e.int32(data.Sig.Algo)
+	} else {
+		e.bytes(nil)
+		e.bytes(nil)
+		e.int32(0)
+	}
+}
+
+func (d *decoder) txData(data *TxData) {
+	data.Version = d.int32()
+	data.ChannelID = d.string()
+	data.Nonce = d.uint64()
+	data.Recipient = d.bytes()
+	data.Payload = d.bytes()
+	data.Value = d.uint64()
+	data.Msg = d.string()
+	data.Gas = d.uint64()
+	data.Sig.PK = d.bytes()
+	data.Sig.Sig = d.bytes()
+	data.Sig.Algo = d.int32()
+}
+
+func (e *encoder) tx(tx *Tx) {
+	e.string(tx.ID)
+	e.txData(&tx.Data, true)
+	e.int64(tx.Time)
+}
+
+func (d *decoder) tx(tx *Tx) {
+	tx.ID = d.string()
+	d.txData(&tx.Data)
+	tx.Time = d.int64()
+}
+
+func (e *encoder) blockHeader(header *BlockHeader, withTime bool) {
+	e.int32(header.Version)
+	e.string(header.ChannelID)
+	e.uint64(header.Number)
+	e.bytes(header.PrevBlock)
+	e.bytes(header.MerkleRoot)
+	if withTime {
+		e.int64(header.Time)
+	}
+}
+
+func (d *decoder) blockHeader(header *BlockHeader) {
+	header.Version = d.int32()
+	header.ChannelID = d.string()
+	header.Number = d.uint64()
+	header.PrevBlock = d.bytes()
+	header.MerkleRoot = d.bytes()
+	header.Time = d.int64()
+}
+
+// encodeBlock encodes the header and then the txs, each tx is prefixed by its length
+func encodeBlock(block *Block) []byte {
+	var e encoder
+	e.blockHeader(block.Header, true)
+	e.uint32(uint32(len(block.Transactions)))
+	for _, tx := range block.Transactions {
+		var te encoder
+		te.tx(tx)
+		e.bytes(te.buf)
+	}
+	return e.buf
+}
+
+func decodeBlock(data []byte) (*Block, error) {
+	d := decoder{data: data}
+	var block = Block{Header: new(BlockHeader)}
+	d.blockHeader(block.Header)
+	count := d.uint32()
+	for i := uint32(0); i < count && d.err == nil; i++ {
+		tx, err := decodeTx(d.bytes())
+		if err != nil {
+			return nil, err
+		}
+		block.Transactions = append(block.Transactions, tx)
+	}
+	if err := d.finish(); err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+func decodeTx(data []byte) (*Tx, error) {
+	d := decoder{data: data}
+	var tx Tx
+	d.tx(&tx)
+	if err := d.finish(); err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
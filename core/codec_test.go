@@ -0,0 +1,112 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package core
+
+import (
+	"encoding/json"
+	"madledger/common"
+	"madledger/common/util"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxVersion(t *testing.T) {
+	tx, err := NewTx("test", common.ZeroAddress, []byte("Hello World"), 0, "", getPrivKey())
+	require.NoError(t, err)
+	require.Equal(t, BinaryVersion, tx.Data.Version)
+
+	// a tx of json version is still verifiable
+	privKey := getPrivKey()
+	legacy := &Tx{
+		Data: TxData{
+			ChannelID: "test",
+			Nonce:     1,
+			Recipient: common.ZeroAddress.Bytes(),
+			Payload:   []byte("Hello World"),
+			Version:   JSONVersion,
+			Gas:       GLOBALGASLIMIT,
+		},
+		Time: util.Now(),
+	}
+	sig, err := privKey.Sign(legacy.hashWithoutSig(privKey.Algo()))
+	require.NoError(t, err)
+	legacy.Data.Sig.PK, _ = privKey.PubKey().Bytes()
+	legacy.Data.Sig.Sig, _ = sig.Bytes()
+	legacy.Data.Sig.Algo = privKey.Algo()
+	legacy.ID = util.Hex(legacy.Hash())
+	require.True(t, legacy.Verify())
+	// the version is signed, so it can not be changed
+	legacy.Data.Version = BinaryVersion
+	require.False(t, legacy.Verify())
+	legacy.Data.Version = JSONVersion
+
+	// the json encoding of history is still supported
+	data, err := json.Marshal(legacy)
+	require.NoError(t, err)
+	decoded, err := BytesToTx(data)
+	require.NoError(t, err)
+	require.Equal(t, legacy, decoded)
+	require.True(t, decoded.Verify())
+}
+
+func TestTxEncoding(t *testing.T) {
+	tx, err := NewTx("test", common.ZeroAddress, []byte("Hello World"), 10, "msg", getPrivKey())
+	require.NoError(t, err)
+	data, err := tx.Bytes()
+	require.NoError(t, err)
+	again, err := tx.Bytes()
+	require.NoError(t, err)
+	require.Equal(t, data, again)
+
+	decoded, err := BytesToTx(data)
+	require.NoError(t, err)
+	require.Equal(t, tx, decoded)
+	require.True(t, decoded.Verify())
+
+	_, err = BytesToTx(data[:len(data)-1])
+	require.Error(t, err)
+	_, err = BytesToTx(append(data, 0))
+	require.Error(t, err)
+}
+
+func TestBlockEncoding(t *testing.T) {
+	tx, err := NewTx("test", common.ZeroAddress, []byte("Hello World"), 0, "", getPrivKey())
+	require.NoError(t, err)
+	block := NewBlock("test", 1, nil, []*Tx{tx, NewTxWithoutSig("test", []byte("genesis"), 0)})
+	require.Equal(t, BinaryVersion, block.Header.Version)
+
+	decoded, err := UnmarshalBlock(block.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, block, decoded)
+	require.Equal(t, block.Hash(), decoded.Hash())
+
+	// time is not included in hash
+	decoded.Header.Time++
+	require.Equal(t, block.Hash(), decoded.Hash())
+	decoded.Header.ChannelID = "test1"
+	require.NotEqual(t, block.Hash(), decoded.Hash())
+
+	empty := NewBlock("test", 0, nil, nil)
+	decoded, err = UnmarshalBlock(empty.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, empty, decoded)
+
+	// blocks of json version are still supported
+	block.Header.Version = JSONVersion
+	hash := block.Hash()
+	data, err := json.Marshal(block)
+	require.NoError(t, err)
+	decoded, err = UnmarshalBlock(data)
+	require.NoError(t, err)
+	require.Equal(t, hash, decoded.Hash())
+	require.True(t, decoded.Transactions[0].Verify())
+}
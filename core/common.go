@@ -12,8 +12,8 @@ package core
 
 import (
 	"errors"
+	"fmt"
 	"madledger/common"
-	"strings"
 )
 
 const (
@@ -30,33 +30,83 @@ var (
 	GenesisBlockPrevHash = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
 )
 
-// Defines some native contracts.
+// SystemContract is a contract implemented by the node at a reserved
+// address instead of evm code
+type SystemContract struct {
+	Address common.Address
+	Type    TxType
+	// ChannelID is the system channel whose txs call the contract, it is
+	// empty if the contract is a native contract called in user channels
+	ChannelID string
+}
+
+// systemContracts are the registered system contracts by address
+var systemContracts = make(map[common.Address]*SystemContract)
+
+// registerSystemContract registers the contract at the n-th reserved address,
+// the reserved addresses count down from 0xff..ff
+func registerSystemContract(n byte, txType TxType, channelID string) common.Address {
+	var address common.Address
+	for i := range address {
+		address[i] = 0xff
+	}
+	address[len(address)-1] -= n
+	if _, ok := systemContracts[address]; ok {
+		panic(fmt.Sprintf("System contract %s exists", address.String()))
+	}
+	systemContracts[address] = &SystemContract{
+		Address:   address,
+		Type:      txType,
+		ChannelID: channelID,
+	}
+	return address
+}
+
+// Defines the system contracts.
 var (
 	// Create a channel
-	CreateChannelContractAddress = common.HexToAddress("0xffffffffffffffffffffffffffffffffffffffff")
+	CreateChannelContractAddress = registerSystemContract(0, CREATECHANNEL, CONFIGCHANNELID)
 	// Config consensus cluster
-	CfgConsensusAddress = common.HexToAddress("0xfffffffffffffffffffffffffffffffffffffffe")
+	CfgConsensusAddress = registerSystemContract(1, CONSENSUS, CONFIGCHANNELID)
 	// issue
-	IssueContractAddress = common.HexToAddress("0xfffffffffffffffffffffffffffffffffffffffd")
+	IssueContractAddress = registerSystemContract(2, ISSUE, ASSETCHANNELID)
 	// transfer
-	TransferContractrAddress = common.HexToAddress("0xfffffffffffffffffffffffffffffffffffffffc")
+	TransferContractrAddress = registerSystemContract(3, TRANSFER, ASSETCHANNELID)
 	// exchange token
-	TokenExchangeAddress = common.HexToAddress("0xfffffffffffffffffffffffffffffffffffffffb")
+	TokenExchangeAddress = registerSystemContract(4, TOKEN, ASSETCHANNELID)
+	// redeem token
+	TokenRedeemAddress = registerSystemContract(5, REDEEM, ASSETCHANNELID)
+	// lock asset in escrow
+	EscrowContractAddress = registerSystemContract(6, ESCROW, ASSETCHANNELID)
+	// claim escrow
+	ClaimContractAddress = registerSystemContract(7, CLAIM, ASSETCHANNELID)
+	// refund escrow
+	RefundContractAddress = registerSystemContract(8, REFUND, ASSETCHANNELID)
+	// freeze account
+	FreezeContractAddress = registerSystemContract(9, FREEZE, ASSETCHANNELID)
+	// unfreeze account
+	UnfreezeContractAddress = registerSystemContract(10, UNFREEZE, ASSETCHANNELID)
+	// burn balance of account
+	BurnContractAddress = registerSystemContract(11, BURN, ASSETCHANNELID)
+	// query the info of channels
+	ChannelInfoContractAddress = registerSystemContract(12, NATIVE, "")
+	// query the token of accounts
+	TokenInfoContractAddress = registerSystemContract(13, NATIVE, "")
+	// burn token before redeeming
+	TokenBurnAddress = registerSystemContract(14, BURNTOKEN, "")
 )
 
+// GetSystemContract return the system contract at address, it return
+// false if the address is not reserved
+func GetSystemContract(address common.Address) (*SystemContract, bool) {
+	contract, ok := systemContracts[address]
+	return contract, ok
+}
+
 // GetTxType return tx type
 func GetTxType(recipient string) (TxType, error) {
-	if strings.Compare(recipient, CreateChannelContractAddress.String()) == 0 {
-		return CREATECHANNEL, nil
-	} else if strings.Compare(recipient, CfgConsensusAddress.String()) == 0 {
-		return CONSENSUS, nil
-	} else if strings.Compare(recipient, IssueContractAddress.String()) == 0 {
-		return ISSUE, nil
-	} else if strings.Compare(recipient, TransferContractrAddress.String()) == 0 {
-		return TRANSFER, nil
-	} else if strings.Compare(recipient, TokenExchangeAddress.String()) == 0 {
-		return TOKEN, nil
-	} else {
-		return 0, errors.New("unknown tx type")
+	if contract, ok := GetSystemContract(common.HexToAddress(recipient)); ok {
+		return contract.Type, nil
 	}
+	return 0, errors.New("unknown tx type")
 }
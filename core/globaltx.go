@@ -38,7 +38,7 @@ func NewGlobalTx(channelID string, num uint64, hash common.Hash) *Tx {
 			Nonce:     0,
 			Recipient: common.ZeroAddress.Bytes(),
 			Payload:   payloadBytes,
-			Version:   1,
+			Version:   BinaryVersion,
 		},
 		Time: util.Now(),
 	}
@@ -0,0 +1,49 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package core
+
+import (
+	"madledger/common"
+)
+
+/*
+*  The keys of the state tree of channels, they are the same in any db
+*  1. Account: "account:" + address
+*  2. Storage: address + slot
+*  3. Asset account: "_asset@" + hex of address, only in _asset
+ */
+
+// AccountKey return the key of account in the state tree
+func AccountKey(address common.Address) []byte {
+	return append([]byte("account:"), address.Bytes()...)
+}
+
+// StorageKey return the key of storage slot in the state tree
+func StorageKey(address common.Address, slot common.Word256) []byte {
+	return append(address.Bytes(), slot.Bytes()...)
+}
+
+// AssetAccountKey return the key of asset account in the state tree
+func AssetAccountKey(address common.Address) []byte {
+	return []byte(ASSETCHANNELID + "@" + address.String())
+}
+
+// StateKey return the key of account of address in the state tree of
+// channel, or the key of storage slot if slot is not nil
+func StateKey(channelID string, address common.Address, slot []byte) []byte {
+	if slot != nil {
+		return StorageKey(address, common.LeftPadWord256(slot))
+	}
+	if channelID == ASSETCHANNELID {
+		return AssetAccountKey(address)
+	}
+	return AccountKey(address)
+}
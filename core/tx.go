@@ -51,6 +51,24 @@ const (
 	TRANSFER
 	// TOKEN
 	TOKEN
+	// REDEEM is the redeem token tx
+	REDEEM
+	// ESCROW is the tx that locks asset in escrow
+	ESCROW
+	// CLAIM is the claim escrow tx
+	CLAIM
+	// REFUND is the refund escrow tx
+	REFUND
+	// FREEZE is the freeze account tx
+	FREEZE
+	// UNFREEZE is the unfreeze account tx
+	UNFREEZE
+	// BURN is the burn balance tx
+	BURN
+	// NATIVE is the tx calling a native contract in user channels
+	NATIVE
+	// BURNTOKEN is the tx burning token in user channels before redeeming
+	BURNTOKEN
 )
 
 // TxData is the data of Tx
@@ -78,9 +96,15 @@ type TxSig struct {
 	Algo crypto.Algorithm `json:"algo,omitempty"`
 }
 
-// NewTx is the constructor of Tx
+// NewTx is the constructor of Tx, the gas limit of tx is GLOBALGASLIMIT
 func NewTx(channelID string, recipient common.Address, payload []byte, value uint64, msg string, privKey crypto.PrivateKey) (*Tx, error) {
-	if payload == nil || len(payload) == 0 {
+	return NewTxWithGas(channelID, recipient, payload, value, msg, GLOBALGASLIMIT, privKey)
+}
+
+// NewTxWithGas is the constructor of Tx with the gas limit, only the tx
+// sent to TokenBurnAddress could have an empty payload
+func NewTxWithGas(channelID string, recipient common.Address, payload []byte, value uint64, msg string, gas uint64, privKey crypto.PrivateKey) (*Tx, error) {
+	if len(payload) == 0 && recipient != TokenBurnAddress {
 		return nil, errors.New("The payload can not be empty")
 	}
 	switch privKey.Algo() {
@@ -97,8 +121,8 @@ func NewTx(channelID string, recipient common.Address, payload []byte, value uin
 			Payload:   payload,
 			Value:     value,
 			Msg:       msg,
-			Version:   1,
-			Gas:       GLOBALGASLIMIT,
+			Version:   BinaryVersion,
+			Gas:       gas,
 		},
 		Time: util.Now(),
 	}
@@ -133,7 +157,7 @@ func NewTxWithoutSig(channelID string, payload []byte, nonce uint64) *Tx {
 			Nonce:     nonce,
 			Recipient: common.ZeroAddress.Bytes(),
 			Payload:   payload,
-			Version:   1,
+			Version:   BinaryVersion,
 			Gas:       GLOBALGASLIMIT,
 		},
 		Time: util.Now(),
@@ -214,15 +238,22 @@ func (tx *Tx) hashWithoutSig(algo crypto.Algorithm) []byte {
 
 // hash implementation different hash
 // Note: is algo is not secp256k1, regard it as sm3
+// Note: TxData of JSONVersion is hashed in json to keep the history verifiable
 func (tx *Tx) hash(withSig bool, algo crypto.Algorithm) []byte {
-	var sig = tx.Data.Sig
-	if !withSig {
-		tx.Data.Sig = TxSig{}
+	var bytes []byte
+	if tx.Data.Version < BinaryVersion {
+		var sig = tx.Data.Sig
+		if !withSig {
+			tx.Data.Sig = TxSig{}
+		}
+		bytes, _ = json.Marshal(tx.Data)
+		tx.Data.Sig = sig
+	} else {
+		var e encoder
+		e.txData(&tx.Data, withSig)
+		bytes = e.buf
 	}
 
-	bytes, _ := json.Marshal(tx.Data)
-	tx.Data.Sig = sig
-
 	switch algo {
 	case crypto.KeyAlgoSecp256k1:
 		return hash.SHA256(bytes)
@@ -231,17 +262,22 @@ func (tx *Tx) hash(withSig bool, algo crypto.Algorithm) []byte {
 	}
 }
 
-// Bytes return the bytes of tx, which is the wrapper of json.Marshal
+// Bytes return the binary encoding of tx
 func (tx *Tx) Bytes() ([]byte, error) {
-	return json.Marshal(tx)
+	var e encoder
+	e.tx(tx)
+	return e.buf, nil
 }
 
-// BytesToTx convert bytes to tx, which is the wrapper of json.Unmarshal
+// BytesToTx convert bytes to tx, the json encoding is also supported
 func BytesToTx(data []byte) (*Tx, error) {
-	var tx *Tx
-	err := json.Unmarshal(data, &tx)
-	if err != nil {
-		return nil, err
+	if isJSON(data) {
+		var tx *Tx
+		err := json.Unmarshal(data, &tx)
+		if err != nil {
+			return nil, err
+		}
+		return tx, nil
 	}
-	return tx, nil
+	return decodeTx(data)
 }
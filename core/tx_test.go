@@ -46,6 +46,25 @@ func TestNewTx(t *testing.T) {
 	}
 }
 
+func TestNewTxWithGas(t *testing.T) {
+	tx, err := NewTxWithGas("test", common.ZeroAddress, []byte("Hello World"), 0, "", 21000, getPrivKey())
+	require.NoError(t, err)
+	require.EqualValues(t, 21000, tx.Data.Gas)
+	require.True(t, tx.Verify())
+	tx.Data.Gas = GLOBALGASLIMIT
+	require.False(t, tx.Verify())
+}
+
+func TestNewTxWithoutPayload(t *testing.T) {
+	_, err := NewTx("test", common.ZeroAddress, nil, 0, "", getPrivKey())
+	require.Error(t, err)
+	// the tx burning token has nothing but value
+	tx, err := NewTx("test", TokenBurnAddress, nil, 3, "", getPrivKey())
+	require.NoError(t, err)
+	require.EqualValues(t, 3, tx.Data.Value)
+	require.True(t, tx.Verify())
+}
+
 func TestVerify(t *testing.T) {
 	tx, err := NewTx("test", common.ZeroAddress, []byte("Hello World"), 0, "", getPrivKey())
 	require.NoError(t, err)
@@ -184,3 +203,27 @@ func getPrivKey() crypto.PrivateKey {
 	privKey, _ := crypto.NewPrivateKey(rawPrivKey, crypto.KeyAlgoSecp256k1)
 	return privKey
 }
+
+func TestGetTxType(t *testing.T) {
+	// the addresses are kept the same as before they are registered
+	txType, err := GetTxType("0xffffffffffffffffffffffffffffffffffffffff")
+	require.NoError(t, err)
+	require.Equal(t, CREATECHANNEL, txType)
+	txType, err = GetTxType(BurnContractAddress.String())
+	require.NoError(t, err)
+	require.Equal(t, BURN, txType)
+	require.Equal(t, common.HexToAddress("0xfffffffffffffffffffffffffffffffffffffff2"), TokenInfoContractAddress)
+	_, err = GetTxType(common.ZeroAddress.String())
+	require.Error(t, err)
+
+	contract, ok := GetSystemContract(TokenRedeemAddress)
+	require.True(t, ok)
+	require.Equal(t, ASSETCHANNELID, contract.ChannelID)
+	contract, ok = GetSystemContract(ChannelInfoContractAddress)
+	require.True(t, ok)
+	require.Equal(t, NATIVE, contract.Type)
+	contract, ok = GetSystemContract(TokenBurnAddress)
+	require.True(t, ok)
+	require.Equal(t, BURNTOKEN, contract.Type)
+	require.Empty(t, contract.ChannelID)
+}
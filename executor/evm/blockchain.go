@@ -15,6 +15,9 @@ import (
 	"madledger/peer/db"
 
 	"github.com/thu-arxan/evm"
+	"github.com/thu-arxan/evm/crypto"
+	"github.com/thu-arxan/evm/rlp"
+	"github.com/thu-arxan/evm/util"
 )
 
 // Blockchain is the implementation of blockchain
@@ -49,14 +52,23 @@ func (bc *Blockchain) GetBlockHash(num uint64) []byte {
 	return hash
 }
 
-// CreateAddress is the implementation of interface
+// CreateAddress is the implementation of interface, the address is the last
+// 20 bytes of keccak256(rlp([caller, nonce])) as Ethereum does
 func (bc *Blockchain) CreateAddress(caller evm.Address, nonce uint64) evm.Address {
-	return nil
+	data, err := rlp.EncodeToBytes([]interface{}{util.FixBytesLength(caller.Bytes(), 20), nonce})
+	if err != nil {
+		log.Errorf("Fatal error! Failed to encode the caller %x and nonce %d, err: %v", caller.Bytes(), nonce, err)
+		return nil
+	}
+	return BytesToAddress(crypto.Keccak256(data)[12:])
 }
 
-// Create2Address is the implementation of interface
+// Create2Address is the implementation of interface, the address is the last
+// 20 bytes of keccak256(0xff ++ caller ++ salt ++ keccak256(code)) as
+// EIP-1014 does, and code is the init code
 func (bc *Blockchain) Create2Address(caller evm.Address, salt, code []byte) evm.Address {
-	return nil
+	return BytesToAddress(crypto.Keccak256([]byte{0xff}, util.FixBytesLength(caller.Bytes(), 20),
+		util.FixBytesLength(salt, 32), crypto.Keccak256(code))[12:])
 }
 
 // NewAccount is the implementation of interface
@@ -14,7 +14,18 @@ import "github.com/thu-arxan/evm"
 
 // Cache is the tx cache
 type Cache struct {
-	ctx *DefaultContext
+	ctx state
+}
+
+// state is the accounts and storage that Cache works on, it is either a
+// DefaultContext or a TxContext
+type state interface {
+	exist(address []byte) bool
+	getAccount(address []byte) evm.Account
+	getStorage(addr, key []byte) []byte
+	setStorage(addr, key, value []byte)
+	updateAccount(account evm.Account) error
+	addLog(log *evm.Log)
 }
 
 // NewCache ...
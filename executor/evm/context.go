@@ -12,6 +12,7 @@ package evm
 
 import (
 	"github.com/thu-arxan/evm"
+	"github.com/thu-arxan/evm/precompile"
 )
 
 // Context caches data changed in block, passed to each evm for tx.
@@ -32,4 +33,6 @@ type Context interface {
 	NewBlockchain() evm.Blockchain
 	// NewDatabase creates db for evm.EVM, caches data between txs in block
 	NewDatabase() evm.DB
+	// Precompiles returns the precompiled contracts of MadLedger for evm.EVM
+	Precompiles() map[string]precompile.Contract
 }
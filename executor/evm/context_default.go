@@ -15,11 +15,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	cc "madledger/blockchain/config"
 	"madledger/common"
 	"madledger/core"
 	"madledger/peer/db"
 
 	"github.com/thu-arxan/evm"
+	"github.com/thu-arxan/evm/precompile"
 	"github.com/thu-arxan/evm/util"
 
 	"github.com/syndtr/goleveldb/leveldb"
@@ -37,7 +39,8 @@ type DefaultContext struct {
 	evmCtx *evm.Context
 	logs   []*evm.Log
 
-	accounts map[string]*accountInfo
+	accounts    map[string]*accountInfo
+	precompiles map[string]precompile.Contract
 }
 
 type storageData struct {
@@ -52,8 +55,8 @@ type accountInfo struct {
 }
 
 // NewContext is the constructor of context
-func NewContext(block *core.Block, engine db.DB, wb db.WriteBatch) Context {
-	return &DefaultContext{
+func NewContext(block *core.Block, engine db.DB, wb db.WriteBatch) *DefaultContext {
+	ctx := &DefaultContext{
 		queryEngine: engine,
 		wb:          wb,
 		block:       block,
@@ -68,6 +71,27 @@ func NewContext(block *core.Block, engine db.DB, wb db.WriteBatch) Context {
 		},
 		accounts: make(map[string]*accountInfo),
 	}
+	ctx.SetPrecompileGas(nil)
+	return ctx
+}
+
+// SetPrecompileGas sets the gas of precompiled contracts, it should be set
+// by the profile of channel before running txs. The native contracts are
+// set together, they read the state of queryEngine.
+func (ctx *DefaultContext) SetPrecompileGas(gas *cc.PrecompileGas) {
+	ctx.precompiles = NewPrecompiles(gas)
+	for address, contract := range NewNatives(&NativeState{
+		ChannelID: ctx.channelID,
+		Number:    ctx.block.GetNumber(),
+		DB:        ctx.queryEngine,
+	}) {
+		ctx.precompiles[address] = contract
+	}
+}
+
+// Precompiles is the implementation of Context
+func (ctx *DefaultContext) Precompiles() map[string]precompile.Contract {
+	return ctx.precompiles
 }
 
 // BlockFinalize should be called after RunBlock.
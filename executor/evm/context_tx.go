@@ -0,0 +1,344 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package evm
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"madledger/common"
+	"madledger/peer/db"
+	"sort"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/thu-arxan/evm"
+	"github.com/thu-arxan/evm/precompile"
+)
+
+// TxContext is the Context of one tx on the DefaultContext of block. It
+// keeps the changes of tx until Commit and records what the tx reads, so
+// txs of block could run speculatively in parallel and be committed in
+// order. A tx whose reads are changed by the txs committed before it
+// should run again on a new TxContext.
+//
+// TxContexts of a block could run at the same time, but Commit should not
+// be called while any of them is running.
+type TxContext struct {
+	block  *DefaultContext
+	evmCtx *evm.Context
+	logs   []*evm.Log
+
+	accounts map[string]*txAccountInfo
+	// reads is the accounts of block that tx reads, nil if read from db
+	reads map[string]*Account
+	// storageReads is the storage of block that tx reads, nil if read from db
+	storageReads map[storageKey]*storageData
+	// absent is the accounts that do not exist when tx asks
+	absent map[string]bool
+}
+
+type txAccountInfo struct {
+	accountInfo
+	// loaded is the account read from db, the block caches it when commit
+	loaded *common.Account
+}
+
+type storageKey struct {
+	addr string
+	key  string
+}
+
+// NewTxContext is the constructor of TxContext
+func NewTxContext(block *DefaultContext) *TxContext {
+	evmCtx := *block.evmCtx
+	return &TxContext{
+		block:        block,
+		evmCtx:       &evmCtx,
+		accounts:     make(map[string]*txAccountInfo),
+		reads:        make(map[string]*Account),
+		storageReads: make(map[storageKey]*storageData),
+		absent:       make(map[string]bool),
+	}
+}
+
+// BlockFinalize is the implementation of Context, the changes of tx are
+// synced by Commit instead.
+func (ctx *TxContext) BlockFinalize() error {
+	return errors.New("BlockFinalize should be called on the context of block")
+}
+
+// BlockContext returns evm ctx of the tx
+func (ctx *TxContext) BlockContext() *evm.Context {
+	return ctx.evmCtx
+}
+
+// NewBlockchain creates blockchain for evm.EVM
+func (ctx *TxContext) NewBlockchain() evm.Blockchain {
+	return ctx.block.NewBlockchain()
+}
+
+// NewDatabase creates db for evm.EVM, caches data of the tx
+func (ctx *TxContext) NewDatabase() evm.DB {
+	return &Cache{
+		ctx: ctx,
+	}
+}
+
+// Precompiles returns the precompiled contracts of block
+func (ctx *TxContext) Precompiles() map[string]precompile.Contract {
+	return ctx.block.Precompiles()
+}
+
+// Conflict returns if the accounts or storage that tx reads are changed by
+// the txs committed after tx reads them
+func (ctx *TxContext) Conflict() bool {
+	for addr, seen := range ctx.reads {
+		if acc := ctx.block.accounts[addr]; acc != nil && acc.updated && acc.account != seen {
+			return true
+		}
+	}
+	for key, seen := range ctx.storageReads {
+		acc := ctx.block.accounts[key.addr]
+		if acc == nil {
+			continue
+		}
+		if data := acc.storage[key.key]; data != nil && data.updated && data != seen {
+			return true
+		}
+	}
+	for addr := range ctx.absent {
+		if ctx.block.accounts[addr] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Commit puts the changes of tx into the block, the accounts and storage
+// read from db are cached by the block as well
+func (ctx *TxContext) Commit() {
+	for addr, info := range ctx.accounts {
+		acc := ctx.block.accounts[addr]
+		if acc == nil {
+			acc = &accountInfo{
+				storage: make(map[string]*storageData),
+			}
+			if info.loaded != nil {
+				acc.account = NewAccountFromCommon(info.loaded)
+			} else {
+				acc.account = info.account
+			}
+			ctx.block.accounts[addr] = acc
+		}
+		// the account suicided by evm is not updated since it can not be
+		// updated any more
+		if info.updated || info.account.HasSuicide() {
+			acc.account = info.account
+			acc.updated = true
+		}
+		for key, data := range info.storage {
+			if data.updated {
+				acc.storage[key] = &storageData{
+					value:   data.value,
+					updated: true,
+				}
+			} else if acc.storage[key] == nil {
+				acc.storage[key] = &storageData{
+					value: data.value,
+				}
+			}
+		}
+	}
+	ctx.block.logs = append(ctx.block.logs, ctx.logs...)
+}
+
+// exist records the read of account as getAccount does, so the tx conflicts
+// if the account is changed, for example suicided, by the txs before it
+func (ctx *TxContext) exist(address []byte) bool {
+	addr := string(address)
+	if ctx.accounts[addr] != nil {
+		return true
+	}
+	if acc := ctx.block.accounts[addr]; acc != nil {
+		ctx.read(addr, acc.account)
+		return true
+	}
+	if ctx.block.queryEngine.AccountExist(bytesToCommonAddress(address)) {
+		ctx.read(addr, nil)
+		return true
+	}
+	ctx.absent[addr] = true
+	return false
+}
+
+// read records the account of block that tx reads first, nil if read from db
+func (ctx *TxContext) read(addr string, account *Account) {
+	if _, ok := ctx.reads[addr]; !ok {
+		ctx.reads[addr] = account
+	}
+}
+
+func (ctx *TxContext) getOrSetAccountInfo(address []byte) *txAccountInfo {
+	addr := string(address)
+	if info := ctx.accounts[addr]; info != nil {
+		return info
+	}
+	info := &txAccountInfo{
+		accountInfo: accountInfo{
+			storage: make(map[string]*storageData),
+		},
+	}
+	if acc := ctx.block.accounts[addr]; acc != nil {
+		info.account = NewAccountFromCommon(copyAccount(acc.account.CommonAccount()))
+		ctx.read(addr, acc.account)
+	} else {
+		account, err := ctx.block.queryEngine.GetAccount(bytesToCommonAddress(address))
+		if err != nil {
+			log.Errorf("Fatal! failed to query account for %s, err: %v", hex.EncodeToString(address), err)
+			account = common.NewAccount(bytesToCommonAddress(address))
+		}
+		info.loaded = account
+		info.account = NewAccountFromCommon(copyAccount(account))
+		ctx.read(addr, nil)
+	}
+	ctx.accounts[addr] = info
+	return info
+}
+
+func (ctx *TxContext) getAccount(address []byte) evm.Account {
+	return ctx.getOrSetAccountInfo(address).account
+}
+
+func (ctx *TxContext) getStorage(addr, key []byte) []byte {
+	info := ctx.getOrSetAccountInfo(addr)
+	if data := info.storage[string(key)]; data != nil {
+		return data.value
+	}
+	var value []byte
+	sk := storageKey{addr: string(addr), key: string(key)}
+	if acc := ctx.block.accounts[sk.addr]; acc != nil && acc.storage[sk.key] != nil {
+		data := acc.storage[sk.key]
+		value = data.value
+		ctx.storageReads[sk] = data
+	} else {
+		word, err := ctx.block.queryEngine.GetStorage(bytesToCommonAddress(addr), bytesToCommomWord256(key))
+		if err != nil && err != leveldb.ErrNotFound {
+			log.Errorf("Fatal error! Failed to query value to %s for addr(%s), err: %v", hex.EncodeToString(key), hex.EncodeToString(addr), err)
+		}
+		value = word.Bytes()
+		ctx.storageReads[sk] = nil
+	}
+	info.storage[string(key)] = &storageData{
+		value: value,
+	}
+	return value
+}
+
+func (ctx *TxContext) setStorage(addr, key, value []byte) {
+	info := ctx.getOrSetAccountInfo(addr)
+	if info.account.HasSuicide() {
+		log.Errorf("Fatal error, set storage on a suicide account(%s), key: %s, value: %s", string(addr), string(key), string(value))
+	}
+	info.storage[string(key)] = &storageData{
+		value:   value,
+		updated: true,
+	}
+}
+
+func (ctx *TxContext) updateAccount(account evm.Account) error {
+	addr := account.GetAddress().Bytes()
+	info := ctx.getOrSetAccountInfo(addr)
+	if info.account.HasSuicide() {
+		return fmt.Errorf("Fatal error, UpdateAccount on a suicide account: %s", string(addr))
+	}
+	acc, ok := account.(*Account)
+	if !ok {
+		return errors.New("invalid account type, executor/evm.Account expected")
+	}
+	info.account = acc
+	info.updated = true
+	return nil
+}
+
+func (ctx *TxContext) addLog(log *evm.Log) {
+	ctx.logs = append(ctx.logs, log)
+}
+
+// Logs return the logs emitted by the tx
+func (ctx *TxContext) Logs() []*db.TxLog {
+	var logs []*db.TxLog
+	for _, l := range ctx.logs {
+		txLog := &db.TxLog{
+			Address: bytesToCommonAddress(l.Address.Bytes()),
+			Data:    l.Data,
+		}
+		for _, topic := range l.Topics {
+			txLog.Topics = append(txLog.Topics, bytesToCommomWord256(topic.Bytes()))
+		}
+		logs = append(logs, txLog)
+	}
+	return logs
+}
+
+// StorageChanges return the storage changed by the tx, sorted by address
+// and key. Before is the value in block before the tx.
+func (ctx *TxContext) StorageChanges() []*StorageChange {
+	var changes []*StorageChange
+	for addr, info := range ctx.accounts {
+		for key, data := range info.storage {
+			if !data.updated {
+				continue
+			}
+			change := &StorageChange{
+				Address: bytesToCommonAddress([]byte(addr)),
+				Key:     common.LeftPadWord256([]byte(key)),
+				Before:  common.LeftPadWord256(ctx.blockStorage(addr, key)),
+				After:   common.LeftPadWord256(data.value),
+			}
+			if change.Before != change.After {
+				changes = append(changes, change)
+			}
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if c := bytes.Compare(changes[i].Address.Bytes(), changes[j].Address.Bytes()); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(changes[i].Key.Bytes(), changes[j].Key.Bytes()) < 0
+	})
+	return changes
+}
+
+// blockStorage return the storage in block without recording the read
+func (ctx *TxContext) blockStorage(addr, key string) []byte {
+	if acc := ctx.block.accounts[addr]; acc != nil && acc.storage[key] != nil {
+		return acc.storage[key].value
+	}
+	word, err := ctx.block.queryEngine.GetStorage(bytesToCommonAddress([]byte(addr)), bytesToCommomWord256([]byte(key)))
+	if err != nil && err != leveldb.ErrNotFound {
+		log.Errorf("Fatal error! Failed to query value to %s for addr(%s), err: %v", hex.EncodeToString([]byte(key)), hex.EncodeToString([]byte(addr)), err)
+	}
+	return word.Bytes()
+}
+
+// copyAccount copies account, so the changes of a tx never touch the
+// accounts of block or other txs
+func copyAccount(account *common.Account) *common.Account {
+	copied := *account
+	if account.Assets != nil {
+		copied.Assets = make(map[string]uint64, len(account.Assets))
+		for id, balance := range account.Assets {
+			copied.Assets[id] = balance
+		}
+	}
+	return &copied
+}
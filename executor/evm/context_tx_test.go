@@ -0,0 +1,52 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package evm
+
+import (
+	"madledger/common"
+	"madledger/core"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// suicideRuntime selfdestructs to the caller
+	suicideRuntime = "33ff"
+)
+
+func TestSuicideConflict(t *testing.T) {
+	engine := initDB(t)
+	defer func() {
+		engine.Close()
+		os.RemoveAll(dir)
+	}()
+	sender := common.NewAccount(common.HexToAddress("0x970e8128ab834e8eac17ab8e3812f010678cf791"))
+	wb := engine.NewWriteBatch()
+	ctx := NewContext(&core.Block{Header: &core.BlockHeader{ChannelID: "test", Number: 1}}, engine, wb)
+	stored := deploy(t, ctx, sender, suicideRuntime)
+	require.NoError(t, ctx.BlockFinalize())
+	require.NoError(t, wb.Sync())
+
+	ctx = NewContext(&core.Block{Header: &core.BlockHeader{ChannelID: "test", Number: 2}}, engine, engine.NewWriteBatch())
+	cached := deploy(t, ctx, sender, suicideRuntime)
+	// the contracts are in db and in block, the txs ask if they exist before
+	// the txs suicide them are committed
+	for _, contract := range []common.Address{stored, cached} {
+		tx := NewTxContext(ctx)
+		require.True(t, tx.NewDatabase().Exist(NewAddressFromCommon(contract)))
+		require.False(t, tx.Conflict())
+		call(t, ctx, sender, contract, nil)
+		require.True(t, NewTxContext(ctx).getAccount(contract.Bytes()).HasSuicide())
+		require.True(t, tx.Conflict())
+	}
+}
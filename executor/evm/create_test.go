@@ -0,0 +1,143 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package evm
+
+import (
+	"encoding/hex"
+	"fmt"
+	"madledger/common"
+	"madledger/core"
+	"madledger/peer/db"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	dir = ".db"
+	// childRuntime returns 42
+	childRuntime = "602a60005260206000f3"
+	// childInit stores childRuntime in memory and returns it
+	childInit = "69" + childRuntime + "600052600a6016f3"
+	// factoryRuntime creates the child by CREATE2 with the salt in call
+	// data, and returns the address of child, which is zero if fails
+	factoryRuntime = "72" + childInit + "6000526000356013600d6000f560005260206000f3"
+	// creatorRuntime creates the child by CREATE and returns its address
+	creatorRuntime = "72" + childInit + "6000526013600d6000f060005260206000f3"
+)
+
+// Note: the samples are from https://ethereum.stackexchange.com/questions/760/how-is-the-address-of-an-ethereum-contract-computed
+func TestCreateAddress(t *testing.T) {
+	bc := NewBlockchain(nil, "test")
+	caller := HexToAddress("0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0")
+	require.Equal(t, "cd234a471b72ba2f1ccf0a70fcaba648a5eecd8d", fmt.Sprintf("%x", bc.CreateAddress(caller, 0).Bytes()))
+	require.Equal(t, "343c43a37d37dff08ae8c4a11544c718abb4fcf8", fmt.Sprintf("%x", bc.CreateAddress(caller, 1).Bytes()))
+}
+
+// Note: the samples are from EIP-1014
+func TestCreate2Address(t *testing.T) {
+	bc := NewBlockchain(nil, "test")
+	var samples = []struct {
+		caller, salt, code, address string
+	}{
+		{"0000000000000000000000000000000000000000", "00", "00", "4d1a2e2bb4f88f0250f26ffff098b0b30b26bf38"},
+		{"deadbeef00000000000000000000000000000000", "00", "00", "b928f69bb1d91cd65274e3c79d8986362984fda3"},
+		{"deadbeef00000000000000000000000000000000", "000000000000000000000000feed000000000000000000000000000000000000", "00", "d04116cdd17bebe565eb2422f2497e06cc1c9833"},
+		{"00000000000000000000000000000000deadbeef", "00000000000000000000000000000000000000000000000000000000cafebabe", "deadbeef", "60f3f640a8508fc6a86d45df051962668e1e8ac7"},
+		{"0000000000000000000000000000000000000000", "00", "", "e33c0c7f7df4809055c3eba6c09cfe4baf1bd9e0"},
+	}
+	for _, sample := range samples {
+		salt, _ := hex.DecodeString(sample.salt)
+		code, _ := hex.DecodeString(sample.code)
+		address := bc.Create2Address(HexToAddress(sample.caller), salt, code)
+		require.Equal(t, sample.address, fmt.Sprintf("%x", address.Bytes()))
+	}
+}
+
+func TestFactory(t *testing.T) {
+	engine := initDB(t)
+	defer func() {
+		engine.Close()
+		os.RemoveAll(dir)
+	}()
+	ctx := NewContext(&core.Block{Header: &core.BlockHeader{ChannelID: "test", Number: 1}}, engine, engine.NewWriteBatch())
+	bc := NewBlockchain(engine, "test")
+	sender := common.NewAccount(common.HexToAddress("0x970e8128ab834e8eac17ab8e3812f010678cf791"))
+
+	// the contracts created by sender are derived from the nonce of sender
+	factory := deploy(t, ctx, sender, factoryRuntime)
+	require.Equal(t, bc.CreateAddress(NewAddressFromCommon(sender.GetAddress()), 0).Bytes(), factory.Bytes())
+	creator := deploy(t, ctx, sender, creatorRuntime)
+	require.Equal(t, bc.CreateAddress(NewAddressFromCommon(sender.GetAddress()), 1).Bytes(), creator.Bytes())
+
+	// the child of factory is derived from the salt and the init code
+	init, _ := hex.DecodeString(childInit)
+	var salt = common.Uint64ToWord256(1)
+	output := call(t, ctx, sender, factory, salt.Bytes())
+	child := common.BytesToAddress(output)
+	require.Equal(t, bc.Create2Address(NewAddressFromCommon(factory), salt.Bytes(), init).Bytes(), child.Bytes())
+	require.Equal(t, common.Uint64ToWord256(42).Bytes(), call(t, ctx, sender, child, nil))
+	// the same salt fails since the address is taken, but another salt works
+	require.Equal(t, common.ZeroWord256.Bytes(), call(t, ctx, sender, factory, salt.Bytes()))
+	salt = common.Uint64ToWord256(2)
+	another := common.BytesToAddress(call(t, ctx, sender, factory, salt.Bytes()))
+	require.NotEqual(t, child, another)
+	require.Equal(t, bc.Create2Address(NewAddressFromCommon(factory), salt.Bytes(), init).Bytes(), another.Bytes())
+	require.Equal(t, common.Uint64ToWord256(42).Bytes(), call(t, ctx, sender, another, nil))
+	// the nonce of factory starts from 1 and increases for every CREATE2
+	require.EqualValues(t, 4, getNonce(ctx, factory))
+
+	// the children created by CREATE are derived from the nonce of creator
+	for nonce := uint64(1); nonce <= 2; nonce++ {
+		child = common.BytesToAddress(call(t, ctx, sender, creator, nil))
+		require.Equal(t, bc.CreateAddress(NewAddressFromCommon(creator), nonce).Bytes(), child.Bytes())
+		require.Equal(t, common.Uint64ToWord256(42).Bytes(), call(t, ctx, sender, child, nil))
+	}
+}
+
+func initDB(t *testing.T) db.DB {
+	require.NoError(t, os.RemoveAll(dir))
+	require.NoError(t, os.MkdirAll(dir, 0777))
+	engine, err := db.NewLevelDB(dir)
+	require.NoError(t, err)
+	return engine
+}
+
+// deploy creates a contract with runtime code, it returns the address of
+// the contract
+func deploy(t *testing.T, block *DefaultContext, sender *common.Account, runtime string) common.Address {
+	code, err := hex.DecodeString(runtime)
+	require.NoError(t, err)
+	// the init code copies the runtime code after it into memory and
+	// returns it
+	init := append([]byte{0x60, byte(len(code)), 0x80, 0x60, 0x0b, 0x60, 0x00, 0x39, 0x60, 0x00, 0xf3}, code...)
+	ctx := NewTxContext(block)
+	_, addr, err := NewEVM(ctx, sender.GetAddress(), init, 0, 10000000, block.queryEngine, nil).Create(sender)
+	require.NoError(t, err)
+	ctx.Commit()
+	return addr
+}
+
+// call calls the contract with input, it returns the output
+func call(t *testing.T, block *DefaultContext, sender *common.Account, contract common.Address, input []byte) []byte {
+	ctx := NewTxContext(block)
+	receiver := common.NewAccount(contract)
+	receiver.SetCode(ctx.getAccount(contract.Bytes()).GetCode())
+	output, err := NewEVM(ctx, sender.GetAddress(), input, 0, 10000000, block.queryEngine, nil).Call(sender, receiver, receiver.GetCode())
+	require.NoError(t, err)
+	ctx.Commit()
+	return output
+}
+
+func getNonce(block *DefaultContext, contract common.Address) uint64 {
+	return NewTxContext(block).getAccount(contract.Bytes()).GetNonce()
+}
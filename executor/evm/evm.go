@@ -34,6 +34,8 @@ type EVM interface {
 	// Create create a contract.
 	// MadEVM.Create(caller Address) ([]byte, Address, error)
 	Create(caller *common.Account) ([]byte, common.Address, error)
+	// SetTracer records the ops and calls of evm by tracer
+	SetTracer(tracer *Tracer)
 }
 
 // DefaultEVM ...
@@ -55,9 +57,11 @@ func NewEVM(ctx Context, caller common.Address, payload []byte, value uint64, ga
 	evmCtx.Value = value
 	evmCtx.Gas = &gas
 
+	runner := evm.New(ctx.NewBlockchain(), ctx.NewDatabase(), evmCtx)
+	runner.SetPrecompiles(ctx.Precompiles())
 	return &DefaultEVM{
 		ctx:    ctx,
-		runner: evm.New(ctx.NewBlockchain(), ctx.NewDatabase(), evmCtx),
+		runner: runner,
 	}
 }
 
@@ -74,3 +78,8 @@ func (evm *DefaultEVM) Create(caller *common.Account) ([]byte, common.Address, e
 	}
 	return v, common.BytesToAddress(addr.Bytes()), err
 }
+
+// SetTracer ...
+func (evm *DefaultEVM) SetTracer(tracer *Tracer) {
+	evm.runner.SetTracer(tracer)
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package evm
+
+import (
+	"errors"
+	"fmt"
+	"madledger/common"
+	"madledger/core"
+	"madledger/peer/db"
+	"strings"
+
+	eabi "github.com/thu-arxan/evm/abi"
+	"github.com/thu-arxan/evm/precompile"
+)
+
+// NativeGasPerWord is the gas charged per word of the input of a native
+// contract besides the gas of method
+const NativeGasPerWord = 3
+
+var (
+	errUnknownMethod = errors.New("Unknown method of native contract")
+)
+
+// NativeContract is a contract implemented in go at a reserved address. Its
+// methods are described by the json abi, so it could be called by txs and
+// contracts in evm the same as a solidity contract.
+type NativeContract struct {
+	Address common.Address
+	// ABI is the json abi of the methods
+	ABI string
+	// Methods implement the methods of ABI by name
+	Methods map[string]*NativeMethod
+}
+
+// NativeMethod is a method of NativeContract
+type NativeMethod struct {
+	// Gas is the gas of a call besides the gas of input
+	Gas uint64
+	// Run return the outputs by the inputs, they are the go values of the
+	// types in abi, e.g. uint64 for uint64 and []byte for address
+	Run func(state *NativeState, inputs []interface{}) ([]interface{}, error)
+}
+
+// NativeState is what native contracts could read, the data of DB is the
+// one before the block, so the result does not rely on the order of txs
+type NativeState struct {
+	ChannelID string
+	Number    uint64
+	DB        db.DB
+}
+
+// nativeContract is a registered NativeContract with its abi parsed
+type nativeContract struct {
+	contract *NativeContract
+	abi      eabi.ABI
+}
+
+// natives are the registered native contracts by the bytes of address
+var natives = make(map[string]*nativeContract)
+
+// RegisterNative registers a native contract, it should be called in init
+// because natives are not locked. The address should be reserved as a native
+// system contract in core.
+func RegisterNative(contract *NativeContract) error {
+	if system, ok := core.GetSystemContract(contract.Address); !ok || system.Type != core.NATIVE {
+		return fmt.Errorf("Address %s is not reserved for native contract", contract.Address.String())
+	}
+	address := string(contract.Address.Bytes())
+	if _, ok := natives[address]; ok {
+		return fmt.Errorf("Native contract %s exists", contract.Address.String())
+	}
+	if _, ok := NewPrecompiles(nil)[address]; ok {
+		return fmt.Errorf("Address %s is used by precompiled contract", contract.Address.String())
+	}
+	abi, err := eabi.JSON(strings.NewReader(contract.ABI))
+	if err != nil {
+		return err
+	}
+	for name := range abi.Methods {
+		if contract.Methods[name] == nil {
+			return fmt.Errorf("Method %s of native contract %s is not implemented", name, contract.Address.String())
+		}
+	}
+	natives[address] = &nativeContract{
+		contract: contract,
+		abi:      abi,
+	}
+	return nil
+}
+
+// IsNative return if there is a native contract at address
+func IsNative(address common.Address) bool {
+	_, ok := natives[string(address.Bytes())]
+	return ok
+}
+
+// NewNatives return the native contracts which read state, they are called
+// as precompiled contracts in evm
+func NewNatives(state *NativeState) map[string]precompile.Contract {
+	var contracts = make(map[string]precompile.Contract, len(natives))
+	for address, native := range natives {
+		contracts[address] = &boundNative{
+			native: native,
+			state:  state,
+		}
+	}
+	return contracts
+}
+
+// boundNative is a native contract bound to the state
+type boundNative struct {
+	native *nativeContract
+	state  *NativeState
+}
+
+func (c *boundNative) RequiredGas(input []byte) uint64 {
+	var gas = words(input) * NativeGasPerWord
+	if method, _, err := c.method(input); err == nil {
+		gas += method.Gas
+	}
+	return gas
+}
+
+func (c *boundNative) Run(input []byte) ([]byte, error) {
+	method, abiMethod, err := c.method(input)
+	if err != nil {
+		return nil, err
+	}
+	inputs, err := abiMethod.Inputs.UnpackValues(input[4:])
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := method.Run(c.state, inputs)
+	if err != nil {
+		return nil, err
+	}
+	return abiMethod.Outputs.Pack(outputs...)
+}
+
+// method return the method selected by the first 4 bytes of input
+func (c *boundNative) method(input []byte) (*NativeMethod, *eabi.Method, error) {
+	abiMethod, err := c.native.abi.MethodByID(input)
+	if err != nil {
+		return nil, nil, errUnknownMethod
+	}
+	return c.native.contract.Methods[abiMethod.Name], abiMethod, nil
+}
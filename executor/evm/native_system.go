@@ -0,0 +1,90 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package evm
+
+import (
+	"encoding/binary"
+	"madledger/common"
+	"madledger/core"
+	"madledger/peer/db"
+)
+
+// Here defines the abi of the native contracts of MadLedger
+const (
+	// ChannelInfoABI is the abi of core.ChannelInfoContractAddress
+	ChannelInfoABI = `[
+	{"type":"function","name":"channelID","constant":true,"inputs":[],"outputs":[{"name":"","type":"string"}]},
+	{"type":"function","name":"channelInfo","constant":true,"inputs":[{"name":"channelID","type":"string"}],"outputs":[{"name":"exist","type":"bool"},{"name":"public","type":"bool"},{"name":"gasPrice","type":"uint64"},{"name":"assetTokenRatio","type":"uint64"},{"name":"maxGas","type":"uint64"}]}
+]`
+	// TokenInfoABI is the abi of core.TokenInfoContractAddress
+	TokenInfoABI = `[
+	{"type":"function","name":"balanceOf","constant":true,"inputs":[{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"uint64"}]}
+]`
+)
+
+func init() {
+	for _, contract := range []*NativeContract{
+		{
+			Address: core.ChannelInfoContractAddress,
+			ABI:     ChannelInfoABI,
+			Methods: map[string]*NativeMethod{
+				"channelID":   {Gas: 200, Run: channelID},
+				"channelInfo": {Gas: 800, Run: channelInfo},
+			},
+		},
+		{
+			Address: core.TokenInfoContractAddress,
+			ABI:     TokenInfoABI,
+			Methods: map[string]*NativeMethod{
+				"balanceOf": {Gas: 400, Run: balanceOf},
+			},
+		},
+	} {
+		if err := RegisterNative(contract); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// channelID return the id of channel which the tx belongs to
+func channelID(state *NativeState, inputs []interface{}) ([]interface{}, error) {
+	return []interface{}{state.ChannelID}, nil
+}
+
+// channelInfo return the profile of channel, exist is false if there is no
+// such channel
+func channelInfo(state *NativeState, inputs []interface{}) ([]interface{}, error) {
+	id := inputs[0].(string)
+	if !state.DB.HasChannel(id) {
+		return []interface{}{false, false, uint64(0), uint64(0), uint64(0)}, nil
+	}
+	profile, err := state.DB.GetChannelProfile(id)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{true, profile.Public, profile.GasPrice, profile.AssetTokenRatio, profile.MaxGas}, nil
+}
+
+// balanceOf return the token of owner in the channel which the tx belongs to.
+// Tokens of other channels could not be read, because only the peers running
+// a channel have its tokens, so the result would differ between peers.
+func balanceOf(state *NativeState, inputs []interface{}) ([]interface{}, error) {
+	key := db.GetTokenKey(state.ChannelID, common.BytesToAddress(inputs[0].([]byte)))
+	value, err := state.DB.Get(key, true)
+	if err != nil {
+		return nil, err
+	}
+	var token uint64
+	if len(value) == 8 {
+		token = binary.BigEndian.Uint64(value)
+	}
+	return []interface{}{token}, nil
+}
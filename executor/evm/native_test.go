@@ -0,0 +1,195 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package evm
+
+import (
+	"encoding/binary"
+	"fmt"
+	cc "madledger/blockchain/config"
+	"madledger/common"
+	"madledger/core"
+	"madledger/peer/db"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	eabi "github.com/thu-arxan/evm/abi"
+	"github.com/thu-arxan/evm/crypto"
+)
+
+const (
+	// channelRuntime forwards the call data to the channel info contract and
+	// returns what it returns
+	channelRuntime = "3660006000376000600036600073fffffffffffffffffffffffffffffffffffffff35afa503d600060003e3d6000f3"
+)
+
+func TestRegisterNative(t *testing.T) {
+	var contract = &NativeContract{
+		Address: core.ChannelInfoContractAddress,
+		ABI:     ChannelInfoABI,
+	}
+	require.Error(t, RegisterNative(contract))
+	contract.Address = SM3Address
+	require.Error(t, RegisterNative(contract))
+	// the address is not reserved for native contract
+	contract.Address = core.IssueContractAddress
+	require.Error(t, RegisterNative(contract))
+	contract.Address = common.HexToAddress("0x0000000000000000000000000000000000000200")
+	require.Error(t, RegisterNative(contract))
+	require.False(t, IsNative(contract.Address))
+	require.True(t, IsNative(core.ChannelInfoContractAddress))
+	require.True(t, IsNative(core.TokenInfoContractAddress))
+}
+
+func TestChannelInfo(t *testing.T) {
+	engine := initDB(t)
+	defer func() {
+		engine.Close()
+		os.RemoveAll(dir)
+	}()
+	require.NoError(t, engine.UpdateChannel("test", &cc.Profile{
+		Public:          true,
+		GasPrice:        2,
+		AssetTokenRatio: 3,
+		MaxGas:          1000,
+	}))
+	contract := NewNatives(&NativeState{ChannelID: "test", DB: engine})[string(core.ChannelInfoContractAddress.Bytes())]
+	abi := parseABI(t, ChannelInfoABI)
+
+	input, err := abi.Pack("channelID")
+	require.NoError(t, err)
+	require.EqualValues(t, 200+3, contract.RequiredGas(input))
+	output, err := contract.Run(input)
+	require.NoError(t, err)
+	values, err := abi.Methods["channelID"].Outputs.UnpackValues(output)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"test"}, values)
+
+	input, err = abi.Pack("channelInfo", "test")
+	require.NoError(t, err)
+	require.EqualValues(t, 800+4*3, contract.RequiredGas(input))
+	output, err = contract.Run(input)
+	require.NoError(t, err)
+	values, err = abi.Methods["channelInfo"].Outputs.UnpackValues(output)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{true, true, uint64(2), uint64(3), uint64(1000)}, values)
+
+	input, err = abi.Pack("channelInfo", "unknown")
+	require.NoError(t, err)
+	output, err = contract.Run(input)
+	require.NoError(t, err)
+	values, err = abi.Methods["channelInfo"].Outputs.UnpackValues(output)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{false, false, uint64(0), uint64(0), uint64(0)}, values)
+
+	// unknown method and bad input
+	_, err = contract.Run([]byte{1, 2, 3, 4})
+	require.Error(t, err)
+	_, err = contract.Run(input[:36])
+	require.Error(t, err)
+}
+
+func TestTokenInfo(t *testing.T) {
+	engine := initDB(t)
+	defer func() {
+		engine.Close()
+		os.RemoveAll(dir)
+	}()
+	owner := common.HexToAddress("0x970e8128ab834e8eac17ab8e3812f010678cf791")
+	var token = make([]byte, 8)
+	binary.BigEndian.PutUint64(token, 100)
+	wb := engine.NewWriteBatch()
+	wb.Put(db.GetTokenKey("test", owner), token)
+	require.NoError(t, wb.Sync())
+
+	contract := NewNatives(&NativeState{ChannelID: "test", DB: engine})[string(core.TokenInfoContractAddress.Bytes())]
+	abi := parseABI(t, TokenInfoABI)
+	for _, c := range []struct {
+		method string
+		args   []interface{}
+		token  uint64
+	}{
+		{"balanceOf", []interface{}{owner.Bytes()}, 100},
+		{"balanceOf", []interface{}{common.ZeroAddress.Bytes()}, 0},
+	} {
+		input, err := abi.Pack(c.method, c.args...)
+		require.NoError(t, err)
+		output, err := contract.Run(input)
+		require.NoError(t, err)
+		require.Equal(t, common.Uint64ToWord256(c.token).Bytes(), output)
+	}
+}
+
+// TestTokenInfoPeers runs balanceOf on two peers, the first one runs the
+// private channel too, and they should get the same result
+func TestTokenInfoPeers(t *testing.T) {
+	owner := common.HexToAddress("0x970e8128ab834e8eac17ab8e3812f010678cf791")
+	var engines []db.DB
+	for i, channels := range [][]string{{"test", "private"}, {"test"}} {
+		engine, err := db.NewLevelDB(fmt.Sprintf("%s/%d", dir, i))
+		require.NoError(t, err)
+		defer engine.Close()
+		wb := engine.NewWriteBatch()
+		for j, channelID := range channels {
+			var token = make([]byte, 8)
+			binary.BigEndian.PutUint64(token, uint64(100/(j+1)))
+			wb.Put(db.GetTokenKey(channelID, owner), token)
+		}
+		require.NoError(t, wb.Sync())
+		engines = append(engines, engine)
+	}
+	defer os.RemoveAll(dir)
+
+	abi := parseABI(t, TokenInfoABI)
+	input, err := abi.Pack("balanceOf", owner.Bytes())
+	require.NoError(t, err)
+	// balanceOfChannel(string,address) which reads other channels is unknown
+	other := append(crypto.Keccak256([]byte("balanceOfChannel(string,address)"))[:4], input[4:]...)
+	var outputs [][]byte
+	for _, engine := range engines {
+		contract := NewNatives(&NativeState{ChannelID: "test", DB: engine})[string(core.TokenInfoContractAddress.Bytes())]
+		output, err := contract.Run(input)
+		require.NoError(t, err)
+		outputs = append(outputs, output)
+		_, err = contract.Run(other)
+		require.Error(t, err)
+	}
+	require.Equal(t, common.Uint64ToWord256(100).Bytes(), outputs[0])
+	require.Equal(t, outputs[0], outputs[1])
+}
+
+func TestCallNative(t *testing.T) {
+	engine := initDB(t)
+	defer func() {
+		engine.Close()
+		os.RemoveAll(dir)
+	}()
+	ctx := NewContext(&core.Block{Header: &core.BlockHeader{ChannelID: "test", Number: 1}}, engine, engine.NewWriteBatch())
+	sender := common.NewAccount(common.HexToAddress("0x970e8128ab834e8eac17ab8e3812f010678cf791"))
+	abi := parseABI(t, ChannelInfoABI)
+	input, err := abi.Pack("channelID")
+	require.NoError(t, err)
+	// called by a tx
+	output := call(t, ctx, sender, core.ChannelInfoContractAddress, input)
+	values, err := abi.Methods["channelID"].Outputs.UnpackValues(output)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{"test"}, values)
+	// called by a contract
+	contract := deploy(t, ctx, sender, channelRuntime)
+	require.Equal(t, output, call(t, ctx, sender, contract, input))
+}
+
+func parseABI(t *testing.T, json string) eabi.ABI {
+	abi, err := eabi.JSON(strings.NewReader(json))
+	require.NoError(t, err)
+	return abi
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package evm
+
+import (
+	"errors"
+	cc "madledger/blockchain/config"
+	"madledger/common"
+	"madledger/common/crypto"
+	"madledger/common/crypto/hash"
+	"math/big"
+
+	"github.com/thu-arxan/evm/precompile"
+)
+
+// Here defines the addresses of the precompiled contracts of MadLedger, the
+// addresses of Ethereum are from 0x01 to 0x09
+var (
+	// SM3Address returns the sm3 hash of input
+	SM3Address = common.HexToAddress("0x0000000000000000000000000000000000000100")
+	// SM2VerifyAddress verifies abi.encode(bytes data, bytes pubKey, bytes sig)
+	// and returns 1 if the sm2 signature of data is right, else 0
+	SM2VerifyAddress = common.HexToAddress("0x0000000000000000000000000000000000000101")
+	// PubKeyToAddressAddress returns the address of
+	// abi.encode(uint256 algo, bytes pubKey), algo is 0 for sm2 and 1 for
+	// secp256k1
+	PubKeyToAddressAddress = common.HexToAddress("0x0000000000000000000000000000000000000102")
+)
+
+// DefaultPrecompileGas is used if the channel does not set the gas of
+// precompiled contracts, which is close to sha256 and ecrecover
+var DefaultPrecompileGas = cc.PrecompileGas{
+	SM3Base:             60,
+	SM3PerWord:          12,
+	SM2VerifyBase:       3000,
+	SM2VerifyPerWord:    12,
+	PubKeyToAddressBase: 3000,
+}
+
+var (
+	errInvalidInput = errors.New("Invalid input of precompiled contract")
+)
+
+// NewPrecompiles return the precompiled contracts of MadLedger under gas, the
+// default gas is used if gas is nil
+func NewPrecompiles(gas *cc.PrecompileGas) map[string]precompile.Contract {
+	if gas == nil {
+		gas = &DefaultPrecompileGas
+	}
+	return map[string]precompile.Contract{
+		string(SM3Address.Bytes()):             &sm3Hash{gas: gas},
+		string(SM2VerifyAddress.Bytes()):       &sm2Verify{gas: gas},
+		string(PubKeyToAddressAddress.Bytes()): &pubKeyToAddress{gas: gas},
+	}
+}
+
+type sm3Hash struct {
+	gas *cc.PrecompileGas
+}
+
+func (c *sm3Hash) RequiredGas(input []byte) uint64 {
+	return c.gas.SM3Base + words(input)*c.gas.SM3PerWord
+}
+
+func (c *sm3Hash) Run(input []byte) ([]byte, error) {
+	return hash.SM3(input), nil
+}
+
+type sm2Verify struct {
+	gas *cc.PrecompileGas
+}
+
+func (c *sm2Verify) RequiredGas(input []byte) uint64 {
+	return c.gas.SM2VerifyBase + words(input)*c.gas.SM2VerifyPerWord
+}
+
+func (c *sm2Verify) Run(input []byte) ([]byte, error) {
+	var args = make([][]byte, 3)
+	for i := range args {
+		arg, err := bytesArg(input, i)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = arg
+	}
+	var result = common.ZeroWord256
+	pubKey, err := crypto.NewPublicKey(args[1], crypto.KeyAlgoSM2)
+	if err != nil {
+		return result.Bytes(), nil
+	}
+	sig, err := crypto.NewSignature(args[2], crypto.KeyAlgoSM2)
+	if err != nil {
+		return result.Bytes(), nil
+	}
+	if sig.Verify(args[0], pubKey) {
+		result = common.Uint64ToWord256(1)
+	}
+	return result.Bytes(), nil
+}
+
+type pubKeyToAddress struct {
+	gas *cc.PrecompileGas
+}
+
+func (c *pubKeyToAddress) RequiredGas(input []byte) uint64 {
+	return c.gas.PubKeyToAddressBase
+}
+
+func (c *pubKeyToAddress) Run(input []byte) ([]byte, error) {
+	if len(input) < 64 {
+		return nil, errInvalidInput
+	}
+	algo := new(big.Int).SetBytes(input[:32])
+	if !algo.IsInt64() || (algo.Int64() != int64(crypto.KeyAlgoSM2) && algo.Int64() != int64(crypto.KeyAlgoSecp256k1)) {
+		return nil, errInvalidInput
+	}
+	raw, err := bytesArg(input, 1)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := crypto.NewPublicKey(raw, crypto.Algorithm(algo.Int64()))
+	if err != nil {
+		return nil, err
+	}
+	address, err := pubKey.Address()
+	if err != nil {
+		return nil, err
+	}
+	return common.LeftPadWord256(address.Bytes()).Bytes(), nil
+}
+
+// words return the number of 32 bytes words of input
+func words(input []byte) uint64 {
+	return (uint64(len(input)) + 31) / 32
+}
+
+// bytesArg decodes the i-th argument of the abi encoding as bytes, the head
+// of the argument is the offset of its length
+func bytesArg(input []byte, i int) ([]byte, error) {
+	offset, err := readUint(input, uint64(i)*32)
+	if err != nil {
+		return nil, err
+	}
+	length, err := readUint(input, offset)
+	if err != nil {
+		return nil, err
+	}
+	if offset+32+length > uint64(len(input)) {
+		return nil, errInvalidInput
+	}
+	return input[offset+32 : offset+32+length], nil
+}
+
+// readUint reads the word at offset of input as an uint64
+func readUint(input []byte, offset uint64) (uint64, error) {
+	if offset+32 > uint64(len(input)) || offset+32 < offset {
+		return 0, errInvalidInput
+	}
+	value := new(big.Int).SetBytes(input[offset : offset+32])
+	if !value.IsUint64() || value.Uint64() > uint64(len(input)) {
+		return 0, errInvalidInput
+	}
+	return value.Uint64(), nil
+}
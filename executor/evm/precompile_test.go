@@ -0,0 +1,139 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package evm
+
+import (
+	"encoding/hex"
+	cc "madledger/blockchain/config"
+	"madledger/common"
+	"madledger/common/crypto"
+	"madledger/common/crypto/hash"
+	"madledger/core"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// sm3Runtime returns the sm3 of call data by the precompiled contract
+	sm3Runtime = "366000600037602060003660006101005afa5060206000f3"
+)
+
+func TestSM3(t *testing.T) {
+	contract := NewPrecompiles(nil)[string(SM3Address.Bytes())]
+	data := []byte("Hello World")
+	require.EqualValues(t, 72, contract.RequiredGas(data))
+	output, err := contract.Run(data)
+	require.NoError(t, err)
+	require.Equal(t, hash.SM3(data), output)
+}
+
+func TestSM2Verify(t *testing.T) {
+	contract := NewPrecompiles(nil)[string(SM2VerifyAddress.Bytes())]
+	privKey, err := crypto.GeneratePrivateKey(crypto.KeyAlgoSM2)
+	require.NoError(t, err)
+	pubKey, err := privKey.PubKey().Bytes()
+	require.NoError(t, err)
+	data := []byte("The document signed off-chain")
+	sig, err := privKey.Sign(data)
+	require.NoError(t, err)
+	sigBytes, err := sig.Bytes()
+	require.NoError(t, err)
+
+	input := encodeBytes(data, pubKey, sigBytes)
+	require.Equal(t, DefaultPrecompileGas.SM2VerifyBase+words(input)*DefaultPrecompileGas.SM2VerifyPerWord, contract.RequiredGas(input))
+	output, err := contract.Run(input)
+	require.NoError(t, err)
+	require.Equal(t, common.Uint64ToWord256(1).Bytes(), output)
+	// the signature of other data is wrong
+	output, err = contract.Run(encodeBytes([]byte("Another document"), pubKey, sigBytes))
+	require.NoError(t, err)
+	require.Equal(t, common.ZeroWord256.Bytes(), output)
+	// the public key of other key is wrong
+	other, err := crypto.GeneratePrivateKey(crypto.KeyAlgoSM2)
+	require.NoError(t, err)
+	otherPubKey, err := other.PubKey().Bytes()
+	require.NoError(t, err)
+	output, err = contract.Run(encodeBytes(data, otherPubKey, sigBytes))
+	require.NoError(t, err)
+	require.Equal(t, common.ZeroWord256.Bytes(), output)
+	// the input should be abi encoded
+	_, err = contract.Run(input[:len(input)-64])
+	require.Error(t, err)
+	_, err = contract.Run(data)
+	require.Error(t, err)
+}
+
+func TestPubKeyToAddress(t *testing.T) {
+	contract := NewPrecompiles(nil)[string(PubKeyToAddressAddress.Bytes())]
+	for _, algo := range []crypto.Algorithm{crypto.KeyAlgoSM2, crypto.KeyAlgoSecp256k1} {
+		privKey, err := crypto.GeneratePrivateKey(algo)
+		require.NoError(t, err)
+		pubKey, err := privKey.PubKey().Bytes()
+		require.NoError(t, err)
+		address, err := privKey.PubKey().Address()
+		require.NoError(t, err)
+		output, err := contract.Run(encodePubKey(uint64(algo), pubKey))
+		require.NoError(t, err)
+		require.Equal(t, common.LeftPadWord256(address.Bytes()).Bytes(), output)
+	}
+	_, err := contract.Run(encodePubKey(2, []byte("key")))
+	require.Error(t, err)
+	_, err = contract.Run(encodePubKey(uint64(crypto.KeyAlgoSecp256k1), []byte("key")))
+	require.Error(t, err)
+}
+
+func TestPrecompileGas(t *testing.T) {
+	gas := &cc.PrecompileGas{
+		SM3Base:             1,
+		SM3PerWord:          2,
+		SM2VerifyBase:       3,
+		SM2VerifyPerWord:    4,
+		PubKeyToAddressBase: 5,
+	}
+	contracts := NewPrecompiles(gas)
+	input := make([]byte, 33)
+	require.EqualValues(t, 5, contracts[string(SM3Address.Bytes())].RequiredGas(input))
+	require.EqualValues(t, 11, contracts[string(SM2VerifyAddress.Bytes())].RequiredGas(input))
+	require.EqualValues(t, 5, contracts[string(PubKeyToAddressAddress.Bytes())].RequiredGas(input))
+}
+
+func TestCallPrecompile(t *testing.T) {
+	engine := initDB(t)
+	defer func() {
+		engine.Close()
+		os.RemoveAll(dir)
+	}()
+	ctx := NewContext(&core.Block{Header: &core.BlockHeader{ChannelID: "test", Number: 1}}, engine, engine.NewWriteBatch())
+	sender := common.NewAccount(common.HexToAddress("0x970e8128ab834e8eac17ab8e3812f010678cf791"))
+	contract := deploy(t, ctx, sender, sm3Runtime)
+	data, _ := hex.DecodeString("6d61646c6564676572")
+	require.Equal(t, hash.SM3(data), call(t, ctx, sender, contract, data))
+}
+
+// encodeBytes return the abi encoding of bytes arguments
+func encodeBytes(args ...[]byte) []byte {
+	var head, tail []byte
+	for _, arg := range args {
+		head = append(head, common.Uint64ToWord256(uint64(len(args)*32+len(tail))).Bytes()...)
+		tail = append(tail, common.Uint64ToWord256(uint64(len(arg))).Bytes()...)
+		tail = append(tail, common.RightPadBytes(arg, int(words(arg))*32)...)
+	}
+	return append(head, tail...)
+}
+
+// encodePubKey return the abi encoding of algo and pubKey
+func encodePubKey(algo uint64, pubKey []byte) []byte {
+	input := append(common.Uint64ToWord256(algo).Bytes(), common.Uint64ToWord256(64).Bytes()...)
+	input = append(input, common.Uint64ToWord256(uint64(len(pubKey))).Bytes()...)
+	return append(input, common.RightPadBytes(pubKey, int(words(pubKey))*32)...)
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package evm
+
+import (
+	"fmt"
+	"madledger/common"
+
+	"github.com/thu-arxan/evm"
+)
+
+// Tracer records the ops and the calls of a tx in evm, it is the
+// implementation of evm.Tracer
+type Tracer struct {
+	// Steps is the ops run by evm in order
+	Steps []*Step
+	// Call is the call or create of tx, the calls it makes are its children
+	Call *Call
+	// Err is not nil if the trace exceeds the limits of config, the steps
+	// after it are not recorded
+	Err error
+
+	config TraceConfig
+	// bytes is the size of stack and memory recorded
+	bytes  uint64
+	frames []*traceFrame
+}
+
+// TraceConfig decides what a Tracer records, a zero limit means no limit
+type TraceConfig struct {
+	// DisableStack and DisableMemory skip the stack and memory of steps
+	DisableStack  bool
+	DisableMemory bool
+	// MaxSteps is the max number of steps recorded
+	MaxSteps uint64
+	// MaxBytes is the max size of stack and memory of all steps recorded
+	MaxBytes uint64
+}
+
+// Step is an op run by evm
+type Step struct {
+	// Depth is 1 for the code of tx
+	Depth uint64
+	PC    uint64
+	Op    string
+	// Gas is the gas left before the op runs
+	Gas uint64
+	// GasCost is the gas used by the op, including the gas used by the call
+	// it makes
+	GasCost uint64
+	// Stack is the stack before the op runs, the top is the last one
+	Stack  []common.Word256
+	Memory []byte
+	// Storage is the storage set if the op is SSTORE
+	Storage *StorageChange
+}
+
+// StorageChange is a change of the storage of a contract, Before is unknown
+// if it is the change of a step
+type StorageChange struct {
+	Address common.Address
+	Key     common.Word256
+	Before  common.Word256
+	After   common.Word256
+}
+
+// Call is a call or create in evm
+type Call struct {
+	// Op is the op which starts the call, which is CALL or CREATE for tx
+	Op      string
+	From    common.Address
+	To      common.Address
+	Input   []byte
+	Output  []byte
+	Value   uint64
+	Gas     uint64
+	GasUsed uint64
+	Err     string
+	Calls   []*Call
+}
+
+type traceFrame struct {
+	call *Call
+	// last is the last step of the call, its gas cost is known until the
+	// next step of the call or the exit
+	last *Step
+}
+
+// NewTracer is the constructor of Tracer
+func NewTracer(config TraceConfig) *Tracer {
+	return &Tracer{
+		config: config,
+	}
+}
+
+// CaptureStep is the implementation of evm.Tracer, the size of stack and
+// memory is checked before they are copied
+func (t *Tracer) CaptureStep(depth, pc uint64, op evm.OpCode, gas uint64, stack *evm.Stack, memory evm.Memory) {
+	if t.Err != nil {
+		return
+	}
+	if t.config.MaxSteps != 0 && uint64(len(t.Steps)) >= t.config.MaxSteps {
+		t.Err = fmt.Errorf("The trace has more than %d steps", t.config.MaxSteps)
+		return
+	}
+	var size uint64
+	if !t.config.DisableStack {
+		size += uint64(stack.Len()) * common.Word256Length
+	}
+	if !t.config.DisableMemory {
+		size += memory.Capacity().Uint64()
+	}
+	if t.config.MaxBytes != 0 && t.bytes+size > t.config.MaxBytes {
+		t.Err = fmt.Errorf("The stack and memory of trace are more than %d bytes", t.config.MaxBytes)
+		return
+	}
+	t.bytes += size
+
+	step := &Step{
+		Depth: depth,
+		PC:    pc,
+		Op:    op.String(),
+		Gas:   gas,
+	}
+	if !t.config.DisableMemory {
+		step.Memory = evm.MemoryData(memory)
+	}
+	if !t.config.DisableStack {
+		for _, word := range stack.Data() {
+			step.Stack = append(step.Stack, common.Word256(word))
+		}
+	}
+	if len(t.frames) != 0 {
+		frame := t.frames[len(t.frames)-1]
+		if op == evm.SSTORE && stack.Len() >= 2 {
+			data := stack.Data()
+			step.Storage = &StorageChange{
+				Address: frame.call.To,
+				Key:     common.Word256(data[len(data)-1]),
+				After:   common.Word256(data[len(data)-2]),
+			}
+		}
+		frame.finish(gas)
+		frame.last = step
+	}
+	t.Steps = append(t.Steps, step)
+}
+
+// CaptureEnter is the implementation of evm.Tracer
+func (t *Tracer) CaptureEnter(depth uint64, op evm.OpCode, caller, callee evm.Address, input []byte, value, gas uint64) {
+	call := &Call{
+		Op:    op.String(),
+		From:  bytesToCommonAddress(caller.Bytes()),
+		To:    bytesToCommonAddress(callee.Bytes()),
+		Input: common.CopyBytes(input),
+		Value: value,
+		Gas:   gas,
+	}
+	if len(t.frames) == 0 {
+		t.Call = call
+	} else {
+		parent := t.frames[len(t.frames)-1].call
+		parent.Calls = append(parent.Calls, call)
+	}
+	t.frames = append(t.frames, &traceFrame{call: call})
+}
+
+// CaptureExit is the implementation of evm.Tracer
+func (t *Tracer) CaptureExit(output []byte, gasLeft uint64, err error) {
+	if len(t.frames) == 0 {
+		return
+	}
+	frame := t.frames[len(t.frames)-1]
+	t.frames = t.frames[:len(t.frames)-1]
+	frame.finish(gasLeft)
+	frame.call.Output = common.CopyBytes(output)
+	if frame.call.Gas > gasLeft {
+		frame.call.GasUsed = frame.call.Gas - gasLeft
+	}
+	if err != nil {
+		frame.call.Err = err.Error()
+	}
+}
+
+// finish sets the gas cost of the last step by the gas left after it
+func (f *traceFrame) finish(gasLeft uint64) {
+	if f.last != nil && f.last.Gas > gasLeft {
+		f.last.GasCost = f.last.Gas - gasLeft
+	}
+}
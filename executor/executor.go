@@ -0,0 +1,74 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package executor
+
+import (
+	"madledger/common"
+	"madledger/executor/evm"
+	"madledger/executor/wasm"
+	"madledger/peer/db"
+)
+
+// Executor runs the contracts of a kind of code, the evm and the wasm both
+// implement it
+type Executor interface {
+	// Call runs the code of callee with the payload
+	Call(caller, callee *common.Account, code []byte) ([]byte, error)
+	// Create creates a contract with the payload
+	Create(caller *common.Account) ([]byte, common.Address, error)
+	// SetTracer records the calls by tracer
+	SetTracer(tracer *evm.Tracer)
+}
+
+var (
+	_ Executor = (*evm.DefaultEVM)(nil)
+	_ Executor = (*wasm.Executor)(nil)
+)
+
+// selector chooses the executor for each contract by the prefix of code
+type selector struct {
+	payload []byte
+	evm     Executor
+	wasm    Executor
+}
+
+// New returns an Executor which runs the wasm modules by wasm and others by
+// evm, they share the context, the gas and the state
+func New(ctx evm.Context, caller common.Address, payload []byte, value uint64, gas uint64, engine db.DB, wb db.WriteBatch) Executor {
+	vm := evm.NewEVM(ctx, caller, payload, value, gas, engine, wb)
+	return &selector{
+		payload: payload,
+		evm:     vm,
+		wasm:    wasm.NewExecutor(ctx, payload, value, ctx.BlockContext().Gas),
+	}
+}
+
+// Call calls the callee by wasm if the code is a wasm module
+func (s *selector) Call(caller, callee *common.Account, code []byte) ([]byte, error) {
+	if wasm.IsWASM(code) {
+		return s.wasm.Call(caller, callee, code)
+	}
+	return s.evm.Call(caller, callee, code)
+}
+
+// Create creates the contract by wasm if the payload is a wasm module
+func (s *selector) Create(caller *common.Account) ([]byte, common.Address, error) {
+	if wasm.IsWASM(s.payload) {
+		return s.wasm.Create(caller)
+	}
+	return s.evm.Create(caller)
+}
+
+// SetTracer ...
+func (s *selector) SetTracer(tracer *evm.Tracer) {
+	s.evm.SetTracer(tracer)
+	s.wasm.SetTracer(tracer)
+}
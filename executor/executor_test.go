@@ -0,0 +1,143 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package executor
+
+import (
+	"encoding/hex"
+	"madledger/common"
+	"madledger/core"
+	"madledger/executor/evm"
+	"madledger/peer/db"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/thu-arxan/evm/errors"
+)
+
+const (
+	dir = ".db"
+	// counterWASM increases the last byte of storage at the zero key and
+	// outputs the value
+	counterWASM = "0061736d0100000001090260027f7f0060000002320303656e760b73746f726167655f676574000003656e760b73746f726167655f736574000003656e76066f757470757400000302010105030100010708010463616c6c00030a23012100410041201000413f413f2d000041016a3a00004100412010014120412010020b"
+	// forwarderRuntime forwards the call data to the address at byte 14 by
+	// staticcall and returns what it returns
+	forwarderRuntime = "366000600037600060003660007300000000000000000000000000000000000000005afa503d600060003e3d6000f3"
+)
+
+func TestSelect(t *testing.T) {
+	require.NoError(t, os.RemoveAll(dir))
+	engine, err := db.NewLevelDB(dir)
+	require.NoError(t, err)
+	defer func() {
+		engine.Close()
+		os.RemoveAll(dir)
+	}()
+	ctx := evm.NewContext(&core.Block{Header: &core.BlockHeader{ChannelID: "test", Number: 1}}, engine, engine.NewWriteBatch())
+	bc := evm.NewBlockchain(engine, "test")
+	sender := common.NewAccount(common.HexToAddress("0x970e8128ab834e8eac17ab8e3812f010678cf791"))
+	code, _ := hex.DecodeString(counterWASM)
+
+	// the gas is not enough to store the module
+	_, _, err = New(evm.NewTxContext(ctx), sender.GetAddress(), code, 0, 1000, engine, nil).Create(sender)
+	require.Equal(t, errors.InsufficientGas, err)
+
+	// the module is the code of contract
+	contract := create(t, ctx, sender, code)
+	require.Equal(t, bc.CreateAddress(evm.NewAddressFromCommon(sender.GetAddress()), 0).Bytes(), contract.Bytes())
+	account := evm.NewTxContext(ctx).NewDatabase().GetAccount(evm.NewAddressFromCommon(contract))
+	require.Equal(t, code, account.GetCode())
+	require.EqualValues(t, 1, account.GetNonce())
+
+	for i := uint64(1); i <= 3; i++ {
+		output, err := call(ctx, sender, contract, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, common.Uint64ToWord256(i).Bytes(), output)
+	}
+	require.Equal(t, common.Uint64ToWord256(3).Bytes(), getStorage(ctx, contract))
+
+	// the call is traced, and the changes are dropped if it fails
+	txCtx := evm.NewTxContext(ctx)
+	tracer := evm.NewTracer(evm.TraceConfig{})
+	vm := New(txCtx, sender.GetAddress(), nil, 0, 1000, engine, nil)
+	vm.SetTracer(tracer)
+	_, err = vm.Call(sender, common.NewAccount(contract), code)
+	require.Error(t, err)
+	require.Empty(t, txCtx.StorageChanges())
+	require.Equal(t, "CALL", tracer.Call.Op)
+	require.Equal(t, contract, tracer.Call.To)
+	require.NotEmpty(t, tracer.Call.Err)
+
+	// evm contracts can not call wasm contracts
+	runtime, _ := hex.DecodeString(forwarderRuntime)
+	copy(runtime[14:], contract.Bytes())
+	// the init code copies the runtime code after it into memory and
+	// returns it
+	init := append([]byte{0x60, byte(len(runtime)), 0x80, 0x60, 0x0b, 0x60, 0x00, 0x39, 0x60, 0x00, 0xf3}, runtime...)
+	forwarder := create(t, ctx, sender, init)
+	tracer = evm.NewTracer(evm.TraceConfig{})
+	output, err := call(ctx, sender, forwarder, nil, tracer)
+	require.NoError(t, err)
+	require.Empty(t, output)
+	require.Contains(t, tracer.Call.Calls[0].Err, "wasm contract")
+	require.Equal(t, common.Uint64ToWord256(3).Bytes(), getStorage(ctx, contract))
+
+	// the stack and memory are skipped if disabled, and the trace stops if it
+	// exceeds the limits
+	tracer = evm.NewTracer(evm.TraceConfig{DisableStack: true, DisableMemory: true})
+	_, err = call(ctx, sender, forwarder, nil, tracer)
+	require.NoError(t, err)
+	require.NoError(t, tracer.Err)
+	require.NotEmpty(t, tracer.Steps)
+	for _, step := range tracer.Steps {
+		require.Empty(t, step.Stack)
+		require.Empty(t, step.Memory)
+	}
+	tracer = evm.NewTracer(evm.TraceConfig{MaxSteps: 2})
+	_, err = call(ctx, sender, forwarder, nil, tracer)
+	require.NoError(t, err)
+	require.Error(t, tracer.Err)
+	require.Len(t, tracer.Steps, 2)
+	tracer = evm.NewTracer(evm.TraceConfig{MaxBytes: 64})
+	_, err = call(ctx, sender, forwarder, nil, tracer)
+	require.NoError(t, err)
+	require.Error(t, tracer.Err)
+}
+
+// create creates a contract with payload and commits it, it returns the
+// address of the contract
+func create(t *testing.T, block *evm.DefaultContext, sender *common.Account, payload []byte) common.Address {
+	ctx := evm.NewTxContext(block)
+	_, addr, err := New(ctx, sender.GetAddress(), payload, 0, 10000000, nil, nil).Create(sender)
+	require.NoError(t, err)
+	ctx.Commit()
+	return addr
+}
+
+// call calls the contract with input and commits it if succeeds
+func call(block *evm.DefaultContext, sender *common.Account, contract common.Address, input []byte, tracer *evm.Tracer) ([]byte, error) {
+	ctx := evm.NewTxContext(block)
+	receiver := common.NewAccount(contract)
+	receiver.SetCode(ctx.NewDatabase().GetAccount(evm.NewAddressFromCommon(contract)).GetCode())
+	vm := New(ctx, sender.GetAddress(), input, 0, 10000000, nil, nil)
+	if tracer != nil {
+		vm.SetTracer(tracer)
+	}
+	output, err := vm.Call(sender, receiver, receiver.GetCode())
+	if err == nil {
+		ctx.Commit()
+	}
+	return output, err
+}
+
+func getStorage(block *evm.DefaultContext, contract common.Address) []byte {
+	return evm.NewTxContext(block).NewDatabase().GetStorage(evm.NewAddressFromCommon(contract), common.ZeroWord256.Bytes())
+}
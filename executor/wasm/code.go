@@ -0,0 +1,199 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package wasm
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Here defines the opcodes which are handled specially, the others are
+// named by the comments in the switch of VM.execute
+const (
+	opUnreachable  = 0x00
+	opBlock        = 0x02
+	opLoop         = 0x03
+	opIf           = 0x04
+	opElse         = 0x05
+	opEnd          = 0x0b
+	opBr           = 0x0c
+	opBrIf         = 0x0d
+	opBrTable      = 0x0e
+	opReturn       = 0x0f
+	opCall         = 0x10
+	opCallIndirect = 0x11
+	opLocalGet     = 0x20
+	opGlobalSet    = 0x24
+	opI32Load      = 0x28
+	opI64Store32   = 0x3e
+	opMemorySize   = 0x3f
+	opMemoryGrow   = 0x40
+	opI32Const     = 0x41
+	opI64Const     = 0x42
+)
+
+// instr is an instruction with its immediates decoded
+type instr struct {
+	op byte
+	// imm is the const, the index, the depth of branch or the offset of
+	// memory
+	imm uint64
+	// arity is the number of results of block, loop and if
+	arity int
+	// end is the index of the end of block, loop, if and else, and els is
+	// the index of the else of if, it is 0 if there is no else
+	end, els int
+	// table is the depths of br_table, the last one is the default
+	table []uint32
+}
+
+// control is a block, loop or if in compiling
+type control struct {
+	start int
+	els   int
+}
+
+// compile decodes the body of f into instructions, the branches are checked
+// and the blocks are matched with their ends
+func compile(m *Module, f *function, r *reader) ([]instr, error) {
+	var code []instr
+	var controls []control
+	locals := uint64(len(f.typ.params) + len(f.locals))
+	for r.err == nil {
+		in := instr{op: r.byte()}
+		if r.err != nil {
+			break
+		}
+		switch op := in.op; {
+		case op == opBlock || op == opLoop || op == opIf:
+			switch r.byte() {
+			case 0x40:
+			case byte(i32), byte(i64):
+				in.arity = 1
+			default:
+				return nil, errors.New("Block type is not supported")
+			}
+			controls = append(controls, control{start: len(code)})
+		case op == opElse:
+			if len(controls) == 0 {
+				return nil, errors.New("Else without if")
+			}
+			c := &controls[len(controls)-1]
+			if code[c.start].op != opIf || c.els != 0 {
+				return nil, errors.New("Else without if")
+			}
+			c.els = len(code)
+			code[c.start].els = len(code)
+			in.arity = code[c.start].arity
+		case op == opEnd:
+			if len(controls) == 0 {
+				code = append(code, in)
+				if r.pos != len(r.data) {
+					return nil, errors.New("Instructions after the end of function")
+				}
+				return code, nil
+			}
+			c := controls[len(controls)-1]
+			controls = controls[:len(controls)-1]
+			code[c.start].end = len(code)
+			if c.els != 0 {
+				code[c.els].end = len(code)
+			}
+		case op == opBr || op == opBrIf:
+			in.imm = uint64(r.u32())
+			if in.imm > uint64(len(controls)) {
+				return nil, errors.New("Invalid depth of branch")
+			}
+		case op == opBrTable:
+			for n := r.u32(); n > 0 && r.err == nil; n-- {
+				in.table = append(in.table, r.u32())
+				if len(in.table) > MaxTableSize {
+					return nil, errors.New("The table of br_table is too big")
+				}
+			}
+			in.table = append(in.table, r.u32())
+			for _, depth := range in.table {
+				if depth > uint32(len(controls)) {
+					return nil, errors.New("Invalid depth of branch")
+				}
+			}
+		case op == opCall:
+			in.imm = uint64(r.u32())
+			if in.imm >= uint64(len(m.funcs)) {
+				return nil, fmt.Errorf("Function %d does not exist", in.imm)
+			}
+		case op == opCallIndirect:
+			in.imm = uint64(r.u32())
+			if in.imm >= uint64(len(m.types)) || r.byte() != 0 {
+				return nil, errors.New("Invalid call_indirect")
+			}
+		case op >= opLocalGet && op < opLocalGet+3:
+			in.imm = uint64(r.u32())
+			if in.imm >= locals {
+				return nil, fmt.Errorf("Local %d does not exist", in.imm)
+			}
+		case op == opGlobalSet-1 || op == opGlobalSet:
+			in.imm = uint64(r.u32())
+			if in.imm >= uint64(len(m.globals)) {
+				return nil, fmt.Errorf("Global %d does not exist", in.imm)
+			}
+			if op == opGlobalSet && !m.globals[in.imm].mutable {
+				return nil, fmt.Errorf("Global %d is immutable", in.imm)
+			}
+		case op >= opI32Load && op <= opI64Store32:
+			if op == 0x2a || op == 0x2b || op == 0x38 || op == 0x39 {
+				return nil, fmt.Errorf("Instruction 0x%x is not supported", op)
+			}
+			r.u32()
+			in.imm = uint64(r.u32())
+			if !m.hasMemory {
+				return nil, errors.New("There is no memory")
+			}
+		case op == opMemorySize || op == opMemoryGrow:
+			if r.byte() != 0 || !m.hasMemory {
+				return nil, errors.New("There is no memory")
+			}
+		case op == opI32Const:
+			in.imm = uint64(uint32(r.signed(32)))
+		case op == opI64Const:
+			in.imm = uint64(r.signed(64))
+		case !isSimple(op):
+			return nil, fmt.Errorf("Instruction 0x%x is not supported", op)
+		}
+		code = append(code, in)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return nil, errors.New("The function does not end")
+}
+
+// isSimple return if op is a supported instruction without immediates, the
+// float instructions are not supported
+func isSimple(op byte) bool {
+	switch {
+	case op == opUnreachable || op == 0x01 || op == opReturn || op == 0x1a || op == 0x1b:
+		return true
+	case op >= 0x45 && op <= 0x5a:
+		// compare integers
+		return true
+	case op >= 0x67 && op <= 0x8a:
+		// integer arithmetic
+		return true
+	case op == 0xa7 || op == 0xac || op == 0xad:
+		// wrap and extend
+		return true
+	case op >= 0xc0 && op <= 0xc4:
+		// sign extension
+		return true
+	}
+	return false
+}
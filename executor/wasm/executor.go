@@ -0,0 +1,210 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package wasm
+
+import (
+	"madledger/common"
+	"madledger/executor/evm"
+
+	tevm "github.com/thu-arxan/evm"
+	"github.com/thu-arxan/evm/core"
+	"github.com/thu-arxan/evm/errors"
+	"github.com/thu-arxan/evm/gas"
+)
+
+// Here defines the exports of wasm contract, deploy is optional and called
+// once when the contract is created, call is called by each tx
+const (
+	DeployExport = "deploy"
+	CallExport   = "call"
+)
+
+// Executor runs the wasm contracts on the same state as evm, the code of
+// contract is the module itself
+type Executor struct {
+	ctx    evm.Context
+	input  []byte
+	value  uint64
+	gas    *uint64
+	tracer *evm.Tracer
+}
+
+// NewExecutor is the constructor of Executor, the gas is shared with evm if
+// the context is shared
+func NewExecutor(ctx evm.Context, payload []byte, value uint64, gas *uint64) *Executor {
+	return &Executor{
+		ctx:   ctx,
+		input: payload,
+		value: value,
+		gas:   gas,
+	}
+}
+
+// Call runs the export call of the module code
+func (e *Executor) Call(caller, callee *common.Account, code []byte) (output []byte, err error) {
+	from, to := evm.NewAddressFromCommon(caller.GetAddress()), evm.NewAddressFromCommon(callee.GetAddress())
+	e.enter(tevm.CALL, from, to)
+	defer func() {
+		e.exit(output, err)
+	}()
+	module, err := Decode(code)
+	if err != nil {
+		return nil, err
+	}
+	cache := tevm.NewCache(e.ctx.NewDatabase())
+	if err := transfer(cache, from, to, e.value); err != nil {
+		return nil, err
+	}
+	output, err = e.run(cache, module, CallExport, from, to, true)
+	if err != nil {
+		return output, err
+	}
+	cache.Sync()
+	return output, nil
+}
+
+// Create creates a contract with the module in payload, and runs the export
+// deploy if there is. It return the code of contract as evm does.
+func (e *Executor) Create(caller *common.Account) (output []byte, address common.Address, err error) {
+	if len(e.input) == 0 {
+		return nil, common.ZeroAddress, errors.InvalidContractCode
+	}
+	cache := tevm.NewCache(e.ctx.NewDatabase())
+	from := evm.NewAddressFromCommon(caller.GetAddress())
+	nonce := cache.GetNonce(from)
+	created := e.ctx.NewBlockchain().CreateAddress(from, nonce)
+	if created == nil {
+		return nil, common.ZeroAddress, errors.InvalidAddress
+	}
+	to := evm.BytesToAddress(created.Bytes())
+	e.enter(tevm.CREATE, from, to)
+	defer func() {
+		e.exit(output, err)
+	}()
+	module, err := Decode(e.input)
+	if err != nil {
+		return nil, common.ZeroAddress, err
+	}
+	if useGasNegative(e.gas, uint64(len(e.input))*gas.CreateData) != nil {
+		return nil, common.ZeroAddress, errors.InsufficientGas
+	}
+	if cache.Exist(to) {
+		return nil, common.ZeroAddress, errors.InvalidAddress
+	}
+	callerAccount := cache.GetAccount(from)
+	callerAccount.SetNonce(nonce + 1)
+	if err := cache.UpdateAccount(callerAccount); err != nil {
+		return nil, common.ZeroAddress, err
+	}
+	contract := evm.NewAccount(to)
+	contract.SetNonce(1)
+	contract.SetCode(e.input)
+	if err := cache.UpdateAccount(contract); err != nil {
+		return nil, common.ZeroAddress, err
+	}
+	if err := transfer(cache, from, to, e.value); err != nil {
+		return nil, common.ZeroAddress, err
+	}
+	if _, err := e.run(cache, module, DeployExport, from, to, false); err != nil {
+		return nil, common.ZeroAddress, err
+	}
+	cache.Sync()
+	return e.input, common.BytesToAddress(to.Bytes()), nil
+}
+
+// SetTracer records the call of wasm by tracer, the instructions are not
+// recorded
+func (e *Executor) SetTracer(tracer *evm.Tracer) {
+	e.tracer = tracer
+}
+
+// run runs the export name of module, the export is required if must
+func (e *Executor) run(cache *tevm.Cache, module *Module, name string, caller, address *evm.Address, must bool) ([]byte, error) {
+	block := e.ctx.BlockContext()
+	vm, err := New(module, &Context{
+		Host:        &wasmHost{cache: cache, address: address},
+		Caller:      caller.Bytes(),
+		Address:     address.Bytes(),
+		Input:       e.input,
+		Value:       e.value,
+		BlockNumber: block.BlockHeight,
+		BlockTime:   block.BlockTime,
+	}, e.gas)
+	if err != nil {
+		return nil, err
+	}
+	if !must && !vm.Has(name) {
+		return nil, nil
+	}
+	return vm.Run(name)
+}
+
+func (e *Executor) enter(op tevm.OpCode, caller, callee tevm.Address) {
+	if e.tracer != nil {
+		e.tracer.CaptureEnter(1, op, caller, callee, e.input, e.value, *e.gas)
+	}
+}
+
+func (e *Executor) exit(output []byte, err error) {
+	if e.tracer != nil {
+		e.tracer.CaptureExit(output, *e.gas, err)
+	}
+}
+
+// wasmHost is the storage and logs of a wasm contract on the cache
+type wasmHost struct {
+	cache   *tevm.Cache
+	address *evm.Address
+}
+
+func (h *wasmHost) GetStorage(key []byte) []byte {
+	return h.cache.GetStorage(h.address, core.BytesToWord256(key))
+}
+
+func (h *wasmHost) SetStorage(key, value []byte) {
+	h.cache.SetStorage(h.address, core.BytesToWord256(key), value)
+}
+
+func (h *wasmHost) AddLog(topics [][]byte, data []byte) {
+	var log = &tevm.Log{
+		Address: h.address,
+		Data:    data,
+	}
+	for i := range topics {
+		log.Topics = append(log.Topics, core.BytesToWord256(topics[i]))
+	}
+	h.cache.AddLog(log)
+}
+
+func transfer(cache *tevm.Cache, from, to tevm.Address, value uint64) error {
+	if value == 0 {
+		return nil
+	}
+	sender, receiver := cache.GetAccount(from), cache.GetAccount(to)
+	if err := sender.SubBalance(value); err != nil {
+		return err
+	}
+	if err := receiver.AddBalance(value); err != nil {
+		return err
+	}
+	if err := cache.UpdateAccount(sender); err != nil {
+		return err
+	}
+	return cache.UpdateAccount(receiver)
+}
+
+func useGasNegative(gasLeft *uint64, gasToUse uint64) error {
+	if *gasLeft < gasToUse {
+		return errors.InsufficientGas
+	}
+	*gasLeft -= gasToUse
+	return nil
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package wasm
+
+import (
+	"bytes"
+	"errors"
+)
+
+// Host is the state of the contract, keys and values of storage are 32 bytes
+type Host interface {
+	GetStorage(key []byte) []byte
+	SetStorage(key, value []byte)
+	// AddLog adds a log with at most 4 topics of 32 bytes
+	AddLog(topics [][]byte, data []byte)
+}
+
+// Here defines the gas of host functions, it is the same as evm
+const (
+	GasPerWord        = 3
+	GasStorageGet     = 200
+	GasStorageSet     = 5000
+	GasStorageCreate  = 20000
+	GasLog            = 375
+	GasLogTopic       = 375
+	GasLogData        = 8
+	maxTopics         = 4
+	wordSize          = 32
+	addressSize       = 20
+	gasHostFuncAccess = 2
+)
+
+var (
+	errTooManyTopics = errors.New("There are more than 4 topics")
+)
+
+type hostFunc struct {
+	typ *funcType
+	gas uint64
+	// call return the result if the function has one
+	call func(vm *VM, args []uint64) uint64
+}
+
+// hostFuncs are the functions that contracts import from module env, the
+// pointers and lengths are i32
+var hostFuncs = map[string]*hostFunc{
+	// input_size() -> i32 return the size of input
+	"input_size": {
+		typ: &funcType{results: []valueType{i32}},
+		gas: gasHostFuncAccess,
+		call: func(vm *VM, args []uint64) uint64 {
+			return uint64(len(vm.ctx.Input))
+		},
+	},
+	// input_copy(dst, offset, len) copies input[offset:offset+len] to dst
+	"input_copy": {
+		typ: &funcType{params: []valueType{i32, i32, i32}},
+		gas: GasPerWord,
+		call: func(vm *VM, args []uint64) uint64 {
+			offset, size := uint64(uint32(args[1])), uint64(uint32(args[2]))
+			vm.useWords(size)
+			if offset+size > uint64(len(vm.ctx.Input)) {
+				vm.trap(errMemoryOutOfBounds)
+			}
+			copy(vm.mem(uint32(args[0]), 0, size), vm.ctx.Input[offset:])
+			return 0
+		},
+	},
+	// output(ptr, len) sets the output of call
+	"output": {
+		typ: &funcType{params: []valueType{i32, i32}},
+		gas: GasPerWord,
+		call: func(vm *VM, args []uint64) uint64 {
+			vm.output = vm.read(args[0], 0, uint64(uint32(args[1])))
+			return 0
+		},
+	},
+	// revert(ptr, len) sets the output and reverts the call
+	"revert": {
+		typ: &funcType{params: []valueType{i32, i32}},
+		gas: GasPerWord,
+		call: func(vm *VM, args []uint64) uint64 {
+			vm.output = vm.read(args[0], 0, uint64(uint32(args[1])))
+			vm.trap(ErrRevert)
+			return 0
+		},
+	},
+	// caller(dst) writes the 20 bytes address of caller to dst
+	"caller": {
+		typ: &funcType{params: []valueType{i32}},
+		gas: gasHostFuncAccess,
+		call: func(vm *VM, args []uint64) uint64 {
+			copy(vm.mem(uint32(args[0]), 0, addressSize), vm.ctx.Caller)
+			return 0
+		},
+	},
+	// address(dst) writes the 20 bytes address of contract to dst
+	"address": {
+		typ: &funcType{params: []valueType{i32}},
+		gas: gasHostFuncAccess,
+		call: func(vm *VM, args []uint64) uint64 {
+			copy(vm.mem(uint32(args[0]), 0, addressSize), vm.ctx.Address)
+			return 0
+		},
+	},
+	// value() -> i64 return the value transferred
+	"value": {
+		typ: &funcType{results: []valueType{i64}},
+		gas: gasHostFuncAccess,
+		call: func(vm *VM, args []uint64) uint64 {
+			return vm.ctx.Value
+		},
+	},
+	// block_number() -> i64 return the number of block
+	"block_number": {
+		typ: &funcType{results: []valueType{i64}},
+		gas: gasHostFuncAccess,
+		call: func(vm *VM, args []uint64) uint64 {
+			return vm.ctx.BlockNumber
+		},
+	},
+	// block_time() -> i64 return the time of block
+	"block_time": {
+		typ: &funcType{results: []valueType{i64}},
+		gas: gasHostFuncAccess,
+		call: func(vm *VM, args []uint64) uint64 {
+			return uint64(vm.ctx.BlockTime)
+		},
+	},
+	// gas_left() -> i64 return the gas left
+	"gas_left": {
+		typ: &funcType{results: []valueType{i64}},
+		gas: gasHostFuncAccess,
+		call: func(vm *VM, args []uint64) uint64 {
+			return *vm.gas
+		},
+	},
+	// storage_get(key, dst) writes the 32 bytes value of the 32 bytes key to
+	// dst
+	"storage_get": {
+		typ: &funcType{params: []valueType{i32, i32}},
+		gas: GasStorageGet,
+		call: func(vm *VM, args []uint64) uint64 {
+			value := vm.ctx.Host.GetStorage(vm.read(args[0], 0, wordSize))
+			copy(vm.mem(uint32(args[1]), 0, wordSize), leftPad(value))
+			return 0
+		},
+	},
+	// storage_set(key, value) sets the 32 bytes value of the 32 bytes key
+	"storage_set": {
+		typ: &funcType{params: []valueType{i32, i32}},
+		call: func(vm *VM, args []uint64) uint64 {
+			key, value := vm.read(args[0], 0, wordSize), vm.read(args[1], 0, wordSize)
+			var gas uint64 = GasStorageSet
+			if isZero(vm.ctx.Host.GetStorage(key)) && !isZero(value) {
+				gas = GasStorageCreate
+			}
+			if err := vm.useGas(gas); err != nil {
+				vm.trap(err)
+			}
+			vm.ctx.Host.SetStorage(key, value)
+			return 0
+		},
+	},
+	// log(data, len, topics, count) adds a log with count topics of 32
+	// bytes at topics
+	"log": {
+		typ: &funcType{params: []valueType{i32, i32, i32, i32}},
+		gas: GasLog,
+		call: func(vm *VM, args []uint64) uint64 {
+			count := uint64(uint32(args[3]))
+			if count > maxTopics {
+				vm.trap(errTooManyTopics)
+			}
+			if err := vm.useGas(count*GasLogTopic + uint64(uint32(args[1]))*GasLogData); err != nil {
+				vm.trap(err)
+			}
+			var topics [][]byte
+			for i := uint64(0); i < count; i++ {
+				topics = append(topics, vm.read(args[2], i*wordSize, wordSize))
+			}
+			vm.ctx.Host.AddLog(topics, vm.read(args[0], 0, uint64(uint32(args[1]))))
+			return 0
+		},
+	},
+}
+
+// read copies size bytes of memory at ptr plus offset, the gas of words is
+// charged
+func (vm *VM) read(ptr uint64, offset, size uint64) []byte {
+	vm.useWords(size)
+	return append([]byte(nil), vm.mem(uint32(ptr), offset, size)...)
+}
+
+func (vm *VM) useWords(size uint64) {
+	if err := vm.useGas((size + wordSize - 1) / wordSize * GasPerWord); err != nil {
+		vm.trap(err)
+	}
+}
+
+func leftPad(value []byte) []byte {
+	if len(value) >= wordSize {
+		return value[len(value)-wordSize:]
+	}
+	return append(make([]byte, wordSize-len(value)), value...)
+}
+
+func isZero(value []byte) bool {
+	return len(bytes.Trim(value, "\x00")) == 0
+}
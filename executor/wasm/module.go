@@ -0,0 +1,535 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package wasm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Here defines the deterministic limits of wasm contracts
+const (
+	// MaxCodeSize is the max size of a module
+	MaxCodeSize = 256 * 1024
+	// MaxPages is the max pages of memory, a page is 64KB
+	MaxPages = 16
+	// MaxTableSize is the max size of table
+	MaxTableSize = 1024
+	// MaxLocals is the max locals of a function including the params
+	MaxLocals = 1024
+	// MaxStackHeight is the max values on stack of all calls
+	MaxStackHeight = 64 * 1024
+	// MaxCallDepth is the max depth of calls in a module
+	MaxCallDepth = 256
+
+	pageSize = 64 * 1024
+)
+
+// Magic is the prefix of wasm modules, the code starts with it is run by the
+// wasm executor
+var Magic = []byte{0x00, 0x61, 0x73, 0x6d}
+
+// IsWASM return if code is a wasm module
+func IsWASM(code []byte) bool {
+	return bytes.HasPrefix(code, Magic)
+}
+
+type valueType byte
+
+const (
+	i32 valueType = 0x7f
+	i64 valueType = 0x7e
+)
+
+type funcType struct {
+	params  []valueType
+	results []valueType
+}
+
+func (t *funcType) equal(other *funcType) bool {
+	return bytes.Equal(valueTypes(t.params), valueTypes(other.params)) &&
+		bytes.Equal(valueTypes(t.results), valueTypes(other.results))
+}
+
+func valueTypes(types []valueType) []byte {
+	var bs = make([]byte, len(types))
+	for i := range types {
+		bs[i] = byte(types[i])
+	}
+	return bs
+}
+
+type function struct {
+	typ *funcType
+	// host is not nil if the function is imported
+	host   *hostFunc
+	locals []valueType
+	code   []instr
+}
+
+type global struct {
+	typ     valueType
+	mutable bool
+	init    uint64
+}
+
+type segment struct {
+	offset uint32
+	data   []byte
+}
+
+type element struct {
+	offset uint32
+	funcs  []uint32
+}
+
+// Module is a decoded wasm module, only integer instructions are supported
+// so that the result is deterministic
+type Module struct {
+	types     []*funcType
+	funcs     []*function
+	imports   int
+	tableSize uint32
+	hasMemory bool
+	memMin    uint32
+	memMax    uint32
+	globals   []*global
+	exports   map[string]uint32
+	elements  []*element
+	data      []*segment
+}
+
+// Decode decodes and validates the wasm module
+func Decode(code []byte) (*Module, error) {
+	if len(code) > MaxCodeSize {
+		return nil, fmt.Errorf("The size of module %d is bigger than %d", len(code), MaxCodeSize)
+	}
+	r := &reader{data: code}
+	if !bytes.Equal(r.bytes(4), Magic) || binary.LittleEndian.Uint32(r.bytes(4)) != 1 {
+		return nil, errors.New("Not a wasm module of version 1")
+	}
+	m := &Module{
+		exports: make(map[string]uint32),
+	}
+	var last byte
+	var funcTypes []uint32
+	for r.err == nil && r.pos < len(r.data) {
+		id := r.byte()
+		body := &reader{data: r.bytes(int(r.u32()))}
+		if r.err != nil {
+			break
+		}
+		if id == 0 {
+			continue
+		}
+		if sectionOrder(id) <= last {
+			return nil, fmt.Errorf("Section %d is out of order", id)
+		}
+		last = sectionOrder(id)
+		var err error
+		switch id {
+		case 1:
+			err = m.decodeTypes(body)
+		case 2:
+			err = m.decodeImports(body)
+		case 3:
+			funcTypes, err = m.decodeFunctions(body)
+		case 4:
+			err = m.decodeTable(body)
+		case 5:
+			err = m.decodeMemory(body)
+		case 6:
+			err = m.decodeGlobals(body)
+		case 7:
+			err = m.decodeExports(body)
+		case 9:
+			err = m.decodeElements(body)
+		case 10:
+			err = m.decodeCode(body, funcTypes)
+			funcTypes = nil
+		case 11:
+			err = m.decodeData(body)
+		case 12:
+			body.u32()
+		default:
+			err = fmt.Errorf("Section %d is not supported", id)
+		}
+		if err == nil {
+			err = body.finish()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(funcTypes) != 0 {
+		return nil, errors.New("The code of functions is missing")
+	}
+	for name, index := range m.exports {
+		if int(index) >= len(m.funcs) {
+			return nil, fmt.Errorf("Export %s is not a function", name)
+		}
+	}
+	return m, nil
+}
+
+// sectionOrder return the order of section id, the data count section is
+// between the element section and the code section
+func sectionOrder(id byte) byte {
+	if id == 12 {
+		return 2*9 + 1
+	}
+	return 2 * id
+}
+
+func (m *Module) decodeTypes(r *reader) error {
+	for n := r.u32(); n > 0 && r.err == nil; n-- {
+		if r.byte() != 0x60 {
+			return errors.New("Invalid function type")
+		}
+		var typ funcType
+		var err error
+		if typ.params, err = r.valueTypes(); err != nil {
+			return err
+		}
+		if typ.results, err = r.valueTypes(); err != nil {
+			return err
+		}
+		if len(typ.results) > 1 {
+			return errors.New("Multiple results are not supported")
+		}
+		m.types = append(m.types, &typ)
+	}
+	return r.err
+}
+
+func (m *Module) decodeImports(r *reader) error {
+	for n := r.u32(); n > 0 && r.err == nil; n-- {
+		module, name := r.name(), r.name()
+		if kind := r.byte(); kind != 0 {
+			return fmt.Errorf("Import %s.%s is not a function", module, name)
+		}
+		typ, err := m.typeAt(r.u32())
+		if err != nil {
+			return err
+		}
+		host, ok := hostFuncs[name]
+		if module != "env" || !ok {
+			return fmt.Errorf("Unknown import %s.%s", module, name)
+		}
+		if !host.typ.equal(typ) {
+			return fmt.Errorf("Import %s.%s has wrong type", module, name)
+		}
+		m.funcs = append(m.funcs, &function{typ: typ, host: host})
+		m.imports++
+	}
+	return r.err
+}
+
+func (m *Module) decodeFunctions(r *reader) ([]uint32, error) {
+	var types []uint32
+	for n := r.u32(); n > 0 && r.err == nil; n-- {
+		index := r.u32()
+		typ, err := m.typeAt(index)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, index)
+		m.funcs = append(m.funcs, &function{typ: typ})
+	}
+	return types, r.err
+}
+
+func (m *Module) decodeTable(r *reader) error {
+	if r.u32() != 1 || r.byte() != 0x70 {
+		return errors.New("Only one funcref table is supported")
+	}
+	min, _ := r.limits()
+	if min > MaxTableSize {
+		return fmt.Errorf("The size of table %d is bigger than %d", min, MaxTableSize)
+	}
+	m.tableSize = min
+	return r.err
+}
+
+func (m *Module) decodeMemory(r *reader) error {
+	if r.u32() != 1 {
+		return errors.New("Only one memory is supported")
+	}
+	min, max := r.limits()
+	if min > MaxPages {
+		return fmt.Errorf("The pages of memory %d is more than %d", min, MaxPages)
+	}
+	if max > MaxPages {
+		max = MaxPages
+	}
+	m.hasMemory, m.memMin, m.memMax = true, min, max
+	return r.err
+}
+
+func (m *Module) decodeGlobals(r *reader) error {
+	for n := r.u32(); n > 0 && r.err == nil; n-- {
+		typ, err := r.valueType()
+		if err != nil {
+			return err
+		}
+		g := &global{typ: typ, mutable: r.byte() == 1}
+		if g.init, err = r.constExpr(typ); err != nil {
+			return err
+		}
+		m.globals = append(m.globals, g)
+	}
+	return r.err
+}
+
+func (m *Module) decodeExports(r *reader) error {
+	for n := r.u32(); n > 0 && r.err == nil; n-- {
+		name, kind, index := r.name(), r.byte(), r.u32()
+		// only functions are called by name, others are ignored
+		if kind == 0 {
+			m.exports[name] = index
+		}
+	}
+	return r.err
+}
+
+func (m *Module) decodeElements(r *reader) error {
+	for n := r.u32(); n > 0 && r.err == nil; n-- {
+		if r.u32() != 0 {
+			return errors.New("Only active elements of table 0 are supported")
+		}
+		offset, err := r.constExpr(i32)
+		if err != nil {
+			return err
+		}
+		e := &element{offset: uint32(offset)}
+		for count := r.u32(); count > 0 && r.err == nil; count-- {
+			index := r.u32()
+			if int(index) >= len(m.funcs) {
+				return fmt.Errorf("Function %d does not exist", index)
+			}
+			e.funcs = append(e.funcs, index)
+		}
+		if uint64(e.offset)+uint64(len(e.funcs)) > uint64(m.tableSize) {
+			return errors.New("Elements are out of table")
+		}
+		m.elements = append(m.elements, e)
+	}
+	return r.err
+}
+
+func (m *Module) decodeCode(r *reader, types []uint32) error {
+	if int(r.u32()) != len(types) {
+		return errors.New("The count of code and functions mismatch")
+	}
+	for i := range types {
+		f := m.funcs[m.imports+i]
+		body := &reader{data: r.bytes(int(r.u32()))}
+		if r.err != nil {
+			return r.err
+		}
+		var count = len(f.typ.params)
+		for n := body.u32(); n > 0 && body.err == nil; n-- {
+			num := body.u32()
+			typ, err := body.valueType()
+			if err != nil {
+				return err
+			}
+			count += int(num)
+			if count > MaxLocals {
+				return fmt.Errorf("The locals of function are more than %d", MaxLocals)
+			}
+			for j := uint32(0); j < num; j++ {
+				f.locals = append(f.locals, typ)
+			}
+		}
+		code, err := compile(m, f, body)
+		if err != nil {
+			return fmt.Errorf("Function %d: %v", m.imports+i, err)
+		}
+		f.code = code
+	}
+	return r.err
+}
+
+func (m *Module) decodeData(r *reader) error {
+	for n := r.u32(); n > 0 && r.err == nil; n-- {
+		if r.u32() != 0 {
+			return errors.New("Only active data of memory 0 are supported")
+		}
+		offset, err := r.constExpr(i32)
+		if err != nil {
+			return err
+		}
+		s := &segment{offset: uint32(offset), data: r.bytes(int(r.u32()))}
+		if !m.hasMemory || uint64(s.offset)+uint64(len(s.data)) > uint64(m.memMin)*pageSize {
+			return errors.New("Data is out of memory")
+		}
+		m.data = append(m.data, s)
+	}
+	return r.err
+}
+
+func (m *Module) typeAt(index uint32) (*funcType, error) {
+	if int(index) >= len(m.types) {
+		return nil, fmt.Errorf("Type %d does not exist", index)
+	}
+	return m.types[index], nil
+}
+
+var (
+	errShortBuffer = errors.New("The module is too short")
+	errOverflow    = errors.New("The integer of module overflows")
+)
+
+// reader reads the binary encoding of wasm, the first error is kept in err
+type reader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *reader) byte() byte {
+	if b := r.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || len(r.data)-r.pos < n {
+		r.err = errShortBuffer
+		return nil
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+// u32 reads an unsigned leb128 integer of 32 bits
+func (r *reader) u32() uint32 {
+	var result uint32
+	for shift := uint(0); ; shift += 7 {
+		b := r.byte()
+		if r.err != nil {
+			return 0
+		}
+		if shift == 28 && b > 0x0f {
+			r.err = errOverflow
+			return 0
+		}
+		result |= uint32(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return result
+		}
+	}
+}
+
+// signed reads a signed leb128 integer of n bits
+func (r *reader) signed(n uint) int64 {
+	var result int64
+	var shift uint
+	for {
+		b := r.byte()
+		if r.err != nil {
+			return 0
+		}
+		if shift >= n {
+			r.err = errOverflow
+			return 0
+		}
+		result |= int64(b&0x7f) << shift
+		shift += 7
+		if b&0x80 == 0 {
+			if shift < 64 && b&0x40 != 0 {
+				result |= -1 << shift
+			}
+			return result
+		}
+	}
+}
+
+func (r *reader) name() string {
+	return string(r.bytes(int(r.u32())))
+}
+
+func (r *reader) valueType() (valueType, error) {
+	switch typ := valueType(r.byte()); typ {
+	case i32, i64:
+		return typ, nil
+	default:
+		if r.err != nil {
+			return 0, r.err
+		}
+		return 0, fmt.Errorf("Value type 0x%x is not supported", byte(typ))
+	}
+}
+
+func (r *reader) valueTypes() ([]valueType, error) {
+	var types []valueType
+	for n := r.u32(); n > 0 && r.err == nil; n-- {
+		typ, err := r.valueType()
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, typ)
+	}
+	return types, r.err
+}
+
+func (r *reader) limits() (uint32, uint32) {
+	switch r.byte() {
+	case 0:
+		return r.u32(), math.MaxUint32
+	case 1:
+		min, max := r.u32(), r.u32()
+		if max < min {
+			r.err = errors.New("The max of limits is less than min")
+		}
+		return min, max
+	default:
+		r.err = errors.New("Invalid limits")
+		return 0, 0
+	}
+}
+
+// constExpr reads a const instruction of typ and then the end
+func (r *reader) constExpr(typ valueType) (uint64, error) {
+	var value uint64
+	switch op := r.byte(); {
+	case op == 0x41 && typ == i32:
+		value = uint64(uint32(r.signed(32)))
+	case op == 0x42 && typ == i64:
+		value = uint64(r.signed(64))
+	default:
+		return 0, errors.New("Only const instructions are supported in init expressions")
+	}
+	if r.byte() != 0x0b {
+		return 0, errors.New("Invalid init expression")
+	}
+	return value, r.err
+}
+
+// finish return error if the data is damaged or has trailing bytes
+func (r *reader) finish() error {
+	if r.err == nil && r.pos != len(r.data) {
+		r.err = fmt.Errorf("There are %d trailing bytes", len(r.data)-r.pos)
+	}
+	return r.err
+}
@@ -0,0 +1,681 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package wasm
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// Here defines the gas of wasm, it is close to the gas of evm
+const (
+	// GasPerInstruction is the gas of most instructions
+	GasPerInstruction = 1
+	// GasPerMemoryAccess is the gas of load and store
+	GasPerMemoryAccess = 3
+	// GasPerCall is the gas of call and call_indirect
+	GasPerCall = 20
+	// GasPerPage is the gas of a page of memory, which is 3 per word
+	GasPerPage = 3 * pageSize / 32
+)
+
+var gasCosts [256]uint64
+
+func init() {
+	for op := range gasCosts {
+		gasCosts[op] = GasPerInstruction
+	}
+	for op := opI32Load; op <= opI64Store32; op++ {
+		gasCosts[op] = GasPerMemoryAccess
+	}
+	gasCosts[opCall] = GasPerCall
+	gasCosts[opCallIndirect] = GasPerCall
+}
+
+// Here defines the errors of running wasm
+var (
+	// ErrOutOfGas means that the gas is not enough
+	ErrOutOfGas = errors.New("Out of gas")
+	// ErrRevert means that the contract calls revert
+	ErrRevert = errors.New("Execution reverted")
+
+	errUnreachable        = errors.New("Unreachable")
+	errStackOverflow      = errors.New("Stack overflow")
+	errStackUnderflow     = errors.New("Stack underflow")
+	errCallStackExhausted = errors.New("Call stack exhausted")
+	errMemoryOutOfBounds  = errors.New("Memory access out of bounds")
+	errDivideByZero       = errors.New("Integer divide by zero")
+	errIntegerOverflow    = errors.New("Integer overflow")
+	errUndefinedElement   = errors.New("Undefined element")
+	errIndirectCallType   = errors.New("Indirect call type mismatch")
+)
+
+// Context is what a wasm contract runs with
+type Context struct {
+	Host        Host
+	Caller      []byte
+	Address     []byte
+	Input       []byte
+	Value       uint64
+	BlockNumber uint64
+	BlockTime   int64
+}
+
+// VM is an instance of a module
+type VM struct {
+	module  *Module
+	ctx     *Context
+	gas     *uint64
+	memory  []byte
+	globals []uint64
+	// table is the function indexes, it is -1 if the element is null
+	table  []int64
+	stack  []uint64
+	base   int
+	depth  int
+	output []byte
+}
+
+// trap aborts the running of VM with err
+type trap struct {
+	err error
+}
+
+// New instantiates the module with ctx, the memory is charged from gas
+func New(module *Module, ctx *Context, gas *uint64) (*VM, error) {
+	vm := &VM{
+		module: module,
+		ctx:    ctx,
+		gas:    gas,
+		table:  make([]int64, module.tableSize),
+	}
+	if err := vm.useGas(uint64(module.memMin) * GasPerPage); err != nil {
+		return nil, err
+	}
+	vm.memory = make([]byte, int(module.memMin)*pageSize)
+	for _, s := range module.data {
+		copy(vm.memory[s.offset:], s.data)
+	}
+	for _, g := range module.globals {
+		vm.globals = append(vm.globals, g.init)
+	}
+	for i := range vm.table {
+		vm.table[i] = -1
+	}
+	for _, e := range module.elements {
+		for i, index := range e.funcs {
+			vm.table[int(e.offset)+i] = int64(index)
+		}
+	}
+	return vm, nil
+}
+
+// Has return if the module exports a function named name
+func (vm *VM) Has(name string) bool {
+	_, ok := vm.module.exports[name]
+	return ok
+}
+
+// Run calls the exported function name, which has no params and results.
+// It returns the output set by the contract.
+func (vm *VM) Run(name string) (output []byte, err error) {
+	index, ok := vm.module.exports[name]
+	if !ok {
+		return nil, fmt.Errorf("Function %s is not exported", name)
+	}
+	f := vm.module.funcs[index]
+	if len(f.typ.params) != 0 || len(f.typ.results) != 0 {
+		return nil, fmt.Errorf("Function %s should have no params and results", name)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			// a runtime error is also deterministic, it should not crash
+			// the peer
+			if t, ok := r.(trap); ok {
+				output, err = vm.output, t.err
+			} else {
+				output, err = vm.output, fmt.Errorf("Invalid wasm: %v", r)
+			}
+		}
+	}()
+	vm.call(index)
+	return vm.output, nil
+}
+
+func (vm *VM) trap(err error) {
+	panic(trap{err: err})
+}
+
+func (vm *VM) useGas(gas uint64) error {
+	if *vm.gas < gas {
+		*vm.gas = 0
+		return ErrOutOfGas
+	}
+	*vm.gas -= gas
+	return nil
+}
+
+func (vm *VM) push(v uint64) {
+	if len(vm.stack) >= MaxStackHeight {
+		vm.trap(errStackOverflow)
+	}
+	vm.stack = append(vm.stack, v)
+}
+
+func (vm *VM) pop() uint64 {
+	if len(vm.stack) <= vm.base {
+		vm.trap(errStackUnderflow)
+	}
+	v := vm.stack[len(vm.stack)-1]
+	vm.stack = vm.stack[:len(vm.stack)-1]
+	return v
+}
+
+func (vm *VM) pushBool(b bool) {
+	if b {
+		vm.push(1)
+	} else {
+		vm.push(0)
+	}
+}
+
+// mem return size bytes of memory at addr plus offset
+func (vm *VM) mem(addr uint32, offset uint64, size uint64) []byte {
+	start := uint64(addr) + offset
+	if start+size > uint64(len(vm.memory)) {
+		vm.trap(errMemoryOutOfBounds)
+	}
+	return vm.memory[start : start+size]
+}
+
+// call calls the function at index, the params are popped from the stack
+// and the results are pushed
+func (vm *VM) call(index uint32) {
+	f := vm.module.funcs[index]
+	n := len(f.typ.params)
+	if len(vm.stack)-vm.base < n {
+		vm.trap(errStackUnderflow)
+	}
+	args := make([]uint64, n, n+len(f.locals))
+	copy(args, vm.stack[len(vm.stack)-n:])
+	vm.stack = vm.stack[:len(vm.stack)-n]
+	if f.host != nil {
+		if err := vm.useGas(f.host.gas); err != nil {
+			vm.trap(err)
+		}
+		result := f.host.call(vm, args)
+		if len(f.typ.results) != 0 {
+			vm.push(result)
+		}
+		return
+	}
+	if vm.depth >= MaxCallDepth {
+		vm.trap(errCallStackExhausted)
+	}
+	base := vm.base
+	vm.depth++
+	vm.base = len(vm.stack)
+	vm.execute(f, append(args, make([]uint64, len(f.locals))...))
+	vm.base = base
+	vm.depth--
+}
+
+// label is the target of branches
+type label struct {
+	// cont is the index of instruction to continue after branch
+	cont   int
+	height int
+	arity  int
+}
+
+// frame is a running function
+type frame struct {
+	vm     *VM
+	locals []uint64
+	labels []label
+}
+
+// branch unwinds the stack and labels to the label at depth, and return the
+// index of instruction to continue
+func (f *frame) branch(depth uint64) int {
+	target := f.labels[len(f.labels)-1-int(depth)]
+	stack := f.vm.stack
+	if len(stack) < target.height+target.arity {
+		f.vm.trap(errStackUnderflow)
+	}
+	copy(stack[target.height:], stack[len(stack)-target.arity:])
+	f.vm.stack = stack[:target.height+target.arity]
+	f.labels = f.labels[:len(f.labels)-1-int(depth)]
+	return target.cont
+}
+
+func (vm *VM) execute(fn *function, locals []uint64) {
+	f := &frame{
+		vm:     vm,
+		locals: locals,
+		labels: []label{{cont: len(fn.code), height: vm.base, arity: len(fn.typ.results)}},
+	}
+	code := fn.code
+	for pc := 0; pc < len(code); pc++ {
+		in := &code[pc]
+		if err := vm.useGas(gasCosts[in.op]); err != nil {
+			vm.trap(err)
+		}
+		switch in.op {
+		case opUnreachable:
+			vm.trap(errUnreachable)
+		case 0x01:
+			// nop
+		case opBlock:
+			f.labels = append(f.labels, label{cont: in.end + 1, height: len(vm.stack), arity: in.arity})
+		case opLoop:
+			f.labels = append(f.labels, label{cont: pc, height: len(vm.stack)})
+		case opIf:
+			cond := uint32(vm.pop())
+			f.labels = append(f.labels, label{cont: in.end + 1, height: len(vm.stack), arity: in.arity})
+			if cond == 0 {
+				if in.els != 0 {
+					pc = in.els
+				} else {
+					pc = in.end - 1
+				}
+			}
+		case opElse:
+			// the end of then
+			pc = f.branch(0) - 1
+		case opEnd:
+			f.labels = f.labels[:len(f.labels)-1]
+		case opBr:
+			pc = f.branch(in.imm) - 1
+		case opBrIf:
+			if uint32(vm.pop()) != 0 {
+				pc = f.branch(in.imm) - 1
+			}
+		case opBrTable:
+			i := uint64(uint32(vm.pop()))
+			if i >= uint64(len(in.table)) {
+				i = uint64(len(in.table) - 1)
+			}
+			pc = f.branch(uint64(in.table[i])) - 1
+		case opReturn:
+			pc = f.branch(uint64(len(f.labels)-1)) - 1
+		case opCall:
+			vm.call(uint32(in.imm))
+		case opCallIndirect:
+			i := uint64(uint32(vm.pop()))
+			if i >= uint64(len(vm.table)) || vm.table[i] < 0 {
+				vm.trap(errUndefinedElement)
+			}
+			index := uint32(vm.table[i])
+			if !vm.module.funcs[index].typ.equal(vm.module.types[in.imm]) {
+				vm.trap(errIndirectCallType)
+			}
+			vm.call(index)
+		case 0x1a:
+			// drop
+			vm.pop()
+		case 0x1b:
+			// select
+			cond, b, a := uint32(vm.pop()), vm.pop(), vm.pop()
+			if cond != 0 {
+				vm.push(a)
+			} else {
+				vm.push(b)
+			}
+		case opLocalGet:
+			vm.push(f.locals[in.imm])
+		case 0x21:
+			// local.set
+			f.locals[in.imm] = vm.pop()
+		case 0x22:
+			// local.tee
+			v := vm.pop()
+			f.locals[in.imm] = v
+			vm.push(v)
+		case 0x23:
+			// global.get
+			vm.push(vm.globals[in.imm])
+		case opGlobalSet:
+			vm.globals[in.imm] = vm.pop()
+		case opMemorySize:
+			vm.push(uint64(len(vm.memory) / pageSize))
+		case opMemoryGrow:
+			delta := uint64(uint32(vm.pop()))
+			pages := uint64(len(vm.memory) / pageSize)
+			if pages+delta > uint64(vm.module.memMax) {
+				vm.push(uint64(math.MaxUint32))
+				break
+			}
+			if err := vm.useGas(delta * GasPerPage); err != nil {
+				vm.trap(err)
+			}
+			vm.memory = append(vm.memory, make([]byte, int(delta)*pageSize)...)
+			vm.push(pages)
+		case opI32Const, opI64Const:
+			vm.push(in.imm)
+		default:
+			if in.op >= opI32Load && in.op <= opI64Store32 {
+				vm.memoryOp(in)
+			} else {
+				vm.numericOp(in.op)
+			}
+		}
+	}
+	// keep the results only
+	arity := len(fn.typ.results)
+	if len(vm.stack)-vm.base < arity {
+		vm.trap(errStackUnderflow)
+	}
+	copy(vm.stack[vm.base:], vm.stack[len(vm.stack)-arity:])
+	vm.stack = vm.stack[:vm.base+arity]
+}
+
+// memoryOp runs load and store
+func (vm *VM) memoryOp(in *instr) {
+	switch in.op {
+	case 0x28:
+		// i32.load
+		vm.push(uint64(binary.LittleEndian.Uint32(vm.mem(uint32(vm.pop()), in.imm, 4))))
+	case 0x29:
+		// i64.load
+		vm.push(binary.LittleEndian.Uint64(vm.mem(uint32(vm.pop()), in.imm, 8)))
+	case 0x2c:
+		// i32.load8_s
+		vm.push(uint64(uint32(int32(int8(vm.mem(uint32(vm.pop()), in.imm, 1)[0])))))
+	case 0x2d:
+		// i32.load8_u
+		vm.push(uint64(vm.mem(uint32(vm.pop()), in.imm, 1)[0]))
+	case 0x2e:
+		// i32.load16_s
+		vm.push(uint64(uint32(int32(int16(binary.LittleEndian.Uint16(vm.mem(uint32(vm.pop()), in.imm, 2)))))))
+	case 0x2f:
+		// i32.load16_u
+		vm.push(uint64(binary.LittleEndian.Uint16(vm.mem(uint32(vm.pop()), in.imm, 2))))
+	case 0x30:
+		// i64.load8_s
+		vm.push(uint64(int64(int8(vm.mem(uint32(vm.pop()), in.imm, 1)[0]))))
+	case 0x31:
+		// i64.load8_u
+		vm.push(uint64(vm.mem(uint32(vm.pop()), in.imm, 1)[0]))
+	case 0x32:
+		// i64.load16_s
+		vm.push(uint64(int64(int16(binary.LittleEndian.Uint16(vm.mem(uint32(vm.pop()), in.imm, 2))))))
+	case 0x33:
+		// i64.load16_u
+		vm.push(uint64(binary.LittleEndian.Uint16(vm.mem(uint32(vm.pop()), in.imm, 2))))
+	case 0x34:
+		// i64.load32_s
+		vm.push(uint64(int64(int32(binary.LittleEndian.Uint32(vm.mem(uint32(vm.pop()), in.imm, 4))))))
+	case 0x35:
+		// i64.load32_u
+		vm.push(uint64(binary.LittleEndian.Uint32(vm.mem(uint32(vm.pop()), in.imm, 4))))
+	case 0x36:
+		// i32.store
+		v := vm.pop()
+		binary.LittleEndian.PutUint32(vm.mem(uint32(vm.pop()), in.imm, 4), uint32(v))
+	case 0x37:
+		// i64.store
+		v := vm.pop()
+		binary.LittleEndian.PutUint64(vm.mem(uint32(vm.pop()), in.imm, 8), v)
+	case 0x3a, 0x3c:
+		// i32.store8 and i64.store8
+		v := vm.pop()
+		vm.mem(uint32(vm.pop()), in.imm, 1)[0] = byte(v)
+	case 0x3b, 0x3d:
+		// i32.store16 and i64.store16
+		v := vm.pop()
+		binary.LittleEndian.PutUint16(vm.mem(uint32(vm.pop()), in.imm, 2), uint16(v))
+	case 0x3e:
+		// i64.store32
+		v := vm.pop()
+		binary.LittleEndian.PutUint32(vm.mem(uint32(vm.pop()), in.imm, 4), uint32(v))
+	}
+}
+
+// numericOp runs the instructions of integers
+func (vm *VM) numericOp(op byte) {
+	switch {
+	case op == 0x45:
+		// i32.eqz
+		vm.pushBool(uint32(vm.pop()) == 0)
+	case op >= 0x46 && op <= 0x4f:
+		b, a := uint32(vm.pop()), uint32(vm.pop())
+		vm.pushBool(compare32(op-0x46, a, b))
+	case op == 0x50:
+		// i64.eqz
+		vm.pushBool(vm.pop() == 0)
+	case op >= 0x51 && op <= 0x5a:
+		b, a := vm.pop(), vm.pop()
+		vm.pushBool(compare64(op-0x51, a, b))
+	case op >= 0x67 && op <= 0x69:
+		vm.push(uint64(unary32(op-0x67, uint32(vm.pop()))))
+	case op >= 0x6a && op <= 0x78:
+		b, a := uint32(vm.pop()), uint32(vm.pop())
+		vm.push(uint64(vm.binary32(op-0x6a, a, b)))
+	case op >= 0x79 && op <= 0x7b:
+		vm.push(unary64(op-0x79, vm.pop()))
+	case op >= 0x7c && op <= 0x8a:
+		b, a := vm.pop(), vm.pop()
+		vm.push(vm.binary64(op-0x7c, a, b))
+	case op == 0xa7:
+		// i32.wrap_i64
+		vm.push(uint64(uint32(vm.pop())))
+	case op == 0xac:
+		// i64.extend_i32_s
+		vm.push(uint64(int64(int32(uint32(vm.pop())))))
+	case op == 0xad:
+		// i64.extend_i32_u
+		vm.push(uint64(uint32(vm.pop())))
+	case op == 0xc0:
+		// i32.extend8_s
+		vm.push(uint64(uint32(int32(int8(vm.pop())))))
+	case op == 0xc1:
+		// i32.extend16_s
+		vm.push(uint64(uint32(int32(int16(vm.pop())))))
+	case op == 0xc2:
+		// i64.extend8_s
+		vm.push(uint64(int64(int8(vm.pop()))))
+	case op == 0xc3:
+		// i64.extend16_s
+		vm.push(uint64(int64(int16(vm.pop()))))
+	case op == 0xc4:
+		// i64.extend32_s
+		vm.push(uint64(int64(int32(vm.pop()))))
+	}
+}
+
+// compare32 runs eq, ne, lt_s, lt_u, gt_s, gt_u, le_s, le_u, ge_s and ge_u
+func compare32(i byte, a, b uint32) bool {
+	switch i {
+	case 0:
+		return a == b
+	case 1:
+		return a != b
+	case 2:
+		return int32(a) < int32(b)
+	case 3:
+		return a < b
+	case 4:
+		return int32(a) > int32(b)
+	case 5:
+		return a > b
+	case 6:
+		return int32(a) <= int32(b)
+	case 7:
+		return a <= b
+	case 8:
+		return int32(a) >= int32(b)
+	default:
+		return a >= b
+	}
+}
+
+// compare64 is compare32 of i64
+func compare64(i byte, a, b uint64) bool {
+	switch i {
+	case 0:
+		return a == b
+	case 1:
+		return a != b
+	case 2:
+		return int64(a) < int64(b)
+	case 3:
+		return a < b
+	case 4:
+		return int64(a) > int64(b)
+	case 5:
+		return a > b
+	case 6:
+		return int64(a) <= int64(b)
+	case 7:
+		return a <= b
+	case 8:
+		return int64(a) >= int64(b)
+	default:
+		return a >= b
+	}
+}
+
+// unary32 runs clz, ctz and popcnt
+func unary32(i byte, a uint32) uint32 {
+	switch i {
+	case 0:
+		return uint32(bits.LeadingZeros32(a))
+	case 1:
+		return uint32(bits.TrailingZeros32(a))
+	default:
+		return uint32(bits.OnesCount32(a))
+	}
+}
+
+// unary64 is unary32 of i64
+func unary64(i byte, a uint64) uint64 {
+	switch i {
+	case 0:
+		return uint64(bits.LeadingZeros64(a))
+	case 1:
+		return uint64(bits.TrailingZeros64(a))
+	default:
+		return uint64(bits.OnesCount64(a))
+	}
+}
+
+// binary32 runs add, sub, mul, div_s, div_u, rem_s, rem_u, and, or, xor,
+// shl, shr_s, shr_u, rotl and rotr
+func (vm *VM) binary32(i byte, a, b uint32) uint32 {
+	switch i {
+	case 0:
+		return a + b
+	case 1:
+		return a - b
+	case 2:
+		return a * b
+	case 3:
+		if b == 0 {
+			vm.trap(errDivideByZero)
+		}
+		if int32(a) == math.MinInt32 && int32(b) == -1 {
+			vm.trap(errIntegerOverflow)
+		}
+		return uint32(int32(a) / int32(b))
+	case 4:
+		if b == 0 {
+			vm.trap(errDivideByZero)
+		}
+		return a / b
+	case 5:
+		if b == 0 {
+			vm.trap(errDivideByZero)
+		}
+		if int32(b) == -1 {
+			return 0
+		}
+		return uint32(int32(a) % int32(b))
+	case 6:
+		if b == 0 {
+			vm.trap(errDivideByZero)
+		}
+		return a % b
+	case 7:
+		return a & b
+	case 8:
+		return a | b
+	case 9:
+		return a ^ b
+	case 10:
+		return a << (b % 32)
+	case 11:
+		return uint32(int32(a) >> (b % 32))
+	case 12:
+		return a >> (b % 32)
+	case 13:
+		return bits.RotateLeft32(a, int(b%32))
+	default:
+		return bits.RotateLeft32(a, -int(b%32))
+	}
+}
+
+// binary64 is binary32 of i64
+func (vm *VM) binary64(i byte, a, b uint64) uint64 {
+	switch i {
+	case 0:
+		return a + b
+	case 1:
+		return a - b
+	case 2:
+		return a * b
+	case 3:
+		if b == 0 {
+			vm.trap(errDivideByZero)
+		}
+		if int64(a) == math.MinInt64 && int64(b) == -1 {
+			vm.trap(errIntegerOverflow)
+		}
+		return uint64(int64(a) / int64(b))
+	case 4:
+		if b == 0 {
+			vm.trap(errDivideByZero)
+		}
+		return a / b
+	case 5:
+		if b == 0 {
+			vm.trap(errDivideByZero)
+		}
+		if int64(b) == -1 {
+			return 0
+		}
+		return uint64(int64(a) % int64(b))
+	case 6:
+		if b == 0 {
+			vm.trap(errDivideByZero)
+		}
+		return a % b
+	case 7:
+		return a & b
+	case 8:
+		return a | b
+	case 9:
+		return a ^ b
+	case 10:
+		return a << (b % 64)
+	case 11:
+		return uint64(int64(a) >> (b % 64))
+	case 12:
+		return a >> (b % 64)
+	case 13:
+		return bits.RotateLeft64(a, int(b%64))
+	default:
+		return bits.RotateLeft64(a, -int(b%64))
+	}
+}
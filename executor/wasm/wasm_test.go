@@ -0,0 +1,337 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package wasm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	// the types of functions
+	typeVoid   = []byte{0x60, 0, 0}
+	typeI32I32 = []byte{0x60, 2, 0x7f, 0x7f, 0}
+	typeI64I64 = []byte{0x60, 1, 0x7e, 1, 0x7e}
+	typeLog    = []byte{0x60, 4, 0x7f, 0x7f, 0x7f, 0x7f, 0}
+)
+
+// builder builds a module of the sections, the functions of the module are
+// of type 0 unless funcs is set
+type builder struct {
+	types   [][]byte
+	imports [][]byte
+	funcs   []uint32
+	memory  bool
+	exports [][]byte
+	codes   [][]byte
+	data    [][]byte
+}
+
+func (b *builder) bytes() []byte {
+	var buf = append([]byte{}, Magic...)
+	buf = append(buf, 1, 0, 0, 0)
+	buf = append(buf, section(1, b.types...)...)
+	if len(b.imports) != 0 {
+		buf = append(buf, section(2, b.imports...)...)
+	}
+	var funcs [][]byte
+	for i := range b.codes {
+		if len(b.funcs) != 0 {
+			funcs = append(funcs, leb(b.funcs[i]))
+		} else {
+			funcs = append(funcs, []byte{0})
+		}
+	}
+	buf = append(buf, section(3, funcs...)...)
+	if b.memory {
+		buf = append(buf, section(5, []byte{0, 1})...)
+	}
+	buf = append(buf, section(7, b.exports...)...)
+	var codes [][]byte
+	for _, code := range b.codes {
+		codes = append(codes, append(leb(uint32(len(code))), code...))
+	}
+	buf = append(buf, section(10, codes...)...)
+	if len(b.data) != 0 {
+		buf = append(buf, section(11, b.data...)...)
+	}
+	return buf
+}
+
+func leb(v uint32) []byte {
+	var buf []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(buf, b)
+		}
+		buf = append(buf, b|0x80)
+	}
+}
+
+func section(id byte, items ...[]byte) []byte {
+	content := leb(uint32(len(items)))
+	for _, item := range items {
+		content = append(content, item...)
+	}
+	return append(append([]byte{id}, leb(uint32(len(content)))...), content...)
+}
+
+func name(s string) []byte {
+	return append(leb(uint32(len(s))), s...)
+}
+
+func importFunc(field string, typ uint32) []byte {
+	return append(append(append(name("env"), name(field)...), 0), leb(typ)...)
+}
+
+func export(field string, index uint32) []byte {
+	return append(append(name(field), 0), leb(index)...)
+}
+
+func data(offset byte, bs []byte) []byte {
+	return append(append([]byte{0, 0x41, offset, 0x0b}, leb(uint32(len(bs)))...), bs...)
+}
+
+// counter increases the last byte of storage at the zero key, and outputs
+// the value
+func counter() []byte {
+	b := &builder{
+		types:   [][]byte{typeI32I32, typeVoid},
+		imports: [][]byte{importFunc("storage_get", 0), importFunc("storage_set", 0), importFunc("output", 0)},
+		funcs:   []uint32{1},
+		memory:  true,
+		exports: [][]byte{export("call", 3)},
+		codes: [][]byte{{0,
+			0x41, 0, 0x41, 32, 0x10, 0,
+			0x41, 63, 0x41, 63, 0x2d, 0, 0, 0x41, 1, 0x6a, 0x3a, 0, 0,
+			0x41, 0, 0x41, 32, 0x10, 1,
+			0x41, 32, 0x41, 32, 0x10, 2,
+			0x0b}},
+	}
+	return b.bytes()
+}
+
+// outputs builds a module whose call runs code and then outputs 8 bytes at
+// memory 0, the functions after call are of type (i64) -> i64
+func outputs(code []byte, others ...[]byte) []byte {
+	b := &builder{
+		types:   [][]byte{typeI32I32, typeVoid, typeI64I64},
+		imports: [][]byte{importFunc("output", 0)},
+		funcs:   []uint32{1},
+		memory:  true,
+		exports: [][]byte{export("call", 1)},
+		codes:   [][]byte{append(append([]byte{}, code...), 0x41, 0, 0x41, 8, 0x10, 0, 0x0b)},
+	}
+	for _, other := range others {
+		b.funcs = append(b.funcs, 2)
+		b.codes = append(b.codes, other)
+	}
+	return b.bytes()
+}
+
+// void builds a module whose call is code
+func void(code ...byte) []byte {
+	b := &builder{
+		types:   [][]byte{typeVoid},
+		memory:  true,
+		exports: [][]byte{export("call", 0)},
+		codes:   [][]byte{code},
+	}
+	return b.bytes()
+}
+
+type host struct {
+	storage map[string][]byte
+	topics  [][]byte
+	logs    [][]byte
+}
+
+func newHost() *host {
+	return &host{storage: make(map[string][]byte)}
+}
+
+func (h *host) GetStorage(key []byte) []byte {
+	return h.storage[string(key)]
+}
+
+func (h *host) SetStorage(key, value []byte) {
+	h.storage[string(key)] = value
+}
+
+func (h *host) AddLog(topics [][]byte, data []byte) {
+	h.topics = append(h.topics, topics...)
+	h.logs = append(h.logs, data)
+}
+
+func run(t *testing.T, code []byte, h Host, gas uint64) ([]byte, uint64, error) {
+	module, err := Decode(code)
+	require.NoError(t, err)
+	vm, err := New(module, &Context{Host: h}, &gas)
+	require.NoError(t, err)
+	output, err := vm.Run("call")
+	return output, gas, err
+}
+
+func TestDecode(t *testing.T) {
+	require.True(t, IsWASM(counter()))
+	require.False(t, IsWASM([]byte{0x60, 0x80}))
+	for _, code := range [][]byte{
+		// not a module
+		{0x60, 0x80, 0x60, 0x40},
+		// version 2
+		append(append([]byte{}, Magic...), 2, 0, 0, 0),
+		// the function does not end
+		void(0, 0x01),
+		// float is not supported
+		void(0, 0x43, 0, 0, 0, 0, 0x1a, 0x0b),
+		// branch out of function
+		void(0, 0x0c, 1, 0x0b),
+		// local does not exist
+		void(0, 0x20, 0, 0x1a, 0x0b),
+		// function does not exist
+		void(0, 0x10, 1, 0x0b),
+		// unknown import
+		(&builder{
+			types:   [][]byte{typeVoid},
+			imports: [][]byte{importFunc("selfdestruct", 0)},
+			exports: [][]byte{export("call", 1)},
+			codes:   [][]byte{{0, 0x0b}},
+		}).bytes(),
+		// import of wrong type
+		(&builder{
+			types:   [][]byte{typeVoid},
+			imports: [][]byte{importFunc("output", 0)},
+			exports: [][]byte{export("call", 1)},
+			codes:   [][]byte{{0, 0x0b}},
+		}).bytes(),
+		// too big
+		append(counter(), make([]byte, MaxCodeSize)...),
+	} {
+		_, err := Decode(code)
+		require.Error(t, err)
+	}
+}
+
+func TestCounter(t *testing.T) {
+	h := newHost()
+	for i := byte(1); i <= 3; i++ {
+		output, gas, err := run(t, counter(), h, 100000)
+		require.NoError(t, err)
+		require.Equal(t, append(make([]byte, 31), i), output)
+		require.True(t, gas < 100000)
+	}
+	require.Len(t, h.storage, 1)
+	// the gas of memory and storage is charged
+	_, gas, err := run(t, counter(), h, GasPerPage+GasStorageGet)
+	require.Equal(t, ErrOutOfGas, err)
+	require.Zero(t, gas)
+	require.Equal(t, byte(3), h.storage[string(make([]byte, 32))][31])
+}
+
+func TestNumeric(t *testing.T) {
+	for _, c := range []struct {
+		code   []byte
+		others [][]byte
+		result uint64
+	}{
+		// 100 + 99 + ... + 1 by loop
+		{[]byte{1, 2, 0x7f,
+			0x41, 0xe4, 0, 0x21, 0,
+			0x02, 0x40, 0x03, 0x40,
+			0x20, 0, 0x45, 0x0d, 1,
+			0x20, 1, 0x20, 0, 0x6a, 0x21, 1,
+			0x20, 0, 0x41, 1, 0x6b, 0x21, 0,
+			0x0c, 0, 0x0b, 0x0b,
+			0x41, 0, 0x20, 1, 0x36, 2, 0}, nil, 5050},
+		// factorial of 20 by recursion
+		{[]byte{0, 0x41, 0, 0x42, 20, 0x10, 2, 0x37, 3, 0}, [][]byte{{0,
+			0x20, 0, 0x50, 0x04, 0x7e,
+			0x42, 1,
+			0x05,
+			0x20, 0, 0x20, 0, 0x42, 1, 0x7d, 0x10, 2, 0x7e,
+			0x0b, 0x0b}}, 2432902008176640000},
+		// -7 / 2 and the remainder of -7 / 2
+		{[]byte{0, 0x41, 0, 0x41, 0x79, 0x41, 2, 0x6d, 0x36, 2, 0,
+			0x41, 4, 0x41, 0x79, 0x41, 2, 0x6f, 0x36, 2, 0}, nil, 0xfffffffffffffffd},
+		// select and clz
+		{[]byte{0, 0x41, 0, 0x42, 1, 0x42, 2, 0x41, 0, 0x1b, 0x79, 0x37, 3, 0}, nil, 62},
+	} {
+		output, _, err := run(t, outputs(c.code, c.others...), nil, 100000)
+		require.NoError(t, err)
+		require.Equal(t, c.result, binary.LittleEndian.Uint64(output))
+	}
+}
+
+func TestTrap(t *testing.T) {
+	for _, c := range []struct {
+		code []byte
+		err  error
+	}{
+		{[]byte{0, 0x00, 0x0b}, errUnreachable},
+		{[]byte{0, 0x41, 1, 0x41, 0, 0x6e, 0x1a, 0x0b}, errDivideByZero},
+		{[]byte{0, 0x41, 0x80, 0x80, 0x04, 0x28, 2, 0, 0x1a, 0x0b}, errMemoryOutOfBounds},
+		{[]byte{0, 0x10, 0, 0x0b}, errCallStackExhausted},
+		{[]byte{0, 0x03, 0x40, 0x0c, 0, 0x0b, 0x0b}, ErrOutOfGas},
+		{[]byte{0, 0x1a, 0x0b}, errStackUnderflow},
+		{[]byte{0, 0x41, 1, 0x40, 0x00, 0x1a, 0x41, 0, 0x40, 0x00, 0x1a, 0x3f, 0x00, 0x41, 16, 0x40, 0x00, 0x0b}, nil},
+	} {
+		_, _, err := run(t, void(c.code...), nil, 10000000)
+		require.Equal(t, c.err, err)
+	}
+	// memory can not grow over the max pages
+	output, _, err := run(t, outputs([]byte{0, 0x41, 0, 0x41, MaxPages, 0x40, 0, 0x36, 2, 0}), nil, 100000)
+	require.NoError(t, err)
+	require.EqualValues(t, 0xffffffff, binary.LittleEndian.Uint32(output))
+}
+
+func TestHost(t *testing.T) {
+	// revert with output
+	b := &builder{
+		types:   [][]byte{typeI32I32, typeVoid},
+		imports: [][]byte{importFunc("revert", 0)},
+		funcs:   []uint32{1},
+		memory:  true,
+		exports: [][]byte{export("call", 1)},
+		codes:   [][]byte{{0, 0x41, 0, 0x41, 2, 0x10, 0, 0x0b}},
+		data:    [][]byte{data(0, []byte("no"))},
+	}
+	output, _, err := run(t, b.bytes(), nil, 100000)
+	require.Equal(t, ErrRevert, err)
+	require.Equal(t, []byte("no"), output)
+
+	// log with a topic
+	topic := bytes.Repeat([]byte{1}, 32)
+	b = &builder{
+		types:   [][]byte{typeLog, typeVoid},
+		imports: [][]byte{importFunc("log", 0)},
+		funcs:   []uint32{1},
+		memory:  true,
+		exports: [][]byte{export("call", 1)},
+		codes:   [][]byte{{0, 0x41, 32, 0x41, 2, 0x41, 0, 0x41, 1, 0x10, 0, 0x0b}},
+		data:    [][]byte{data(0, topic), data(32, []byte("ok"))},
+	}
+	h := newHost()
+	_, gas, err := run(t, b.bytes(), h, 100000)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{topic}, h.topics)
+	require.Equal(t, [][]byte{[]byte("ok")}, h.logs)
+	require.True(t, gas <= 100000-GasPerPage-GasLog-GasLogTopic-2*GasLogData)
+
+	// too many topics
+	b.codes = [][]byte{{0, 0x41, 32, 0x41, 2, 0x41, 0, 0x41, 5, 0x10, 0, 0x0b}}
+	_, _, err = run(t, b.bytes(), newHost(), 100000)
+	require.Equal(t, errTooManyTopics, err)
+}
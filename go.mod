@@ -17,7 +17,7 @@ require (
 	github.com/go-kit/kit v0.10.1-0.20200322194522-cb67d82b1869 // indirect
 	github.com/gogo/protobuf v1.3.1 // indirect
 	github.com/golang/protobuf v1.3.5
-	github.com/golang/snappy v0.0.2-0.20190904063534-ff6b7dc882cf // indirect
+	github.com/golang/snappy v0.0.2-0.20190904063534-ff6b7dc882cf
 	github.com/gorilla/websocket v1.4.2 // indirect
 	github.com/hashicorp/hcl v1.0.1-0.20191016231534-914dc3f8dd7c // indirect
 	github.com/jmhodges/levigo v1.0.0 // indirect
@@ -59,3 +59,6 @@ require (
 	gopkg.in/ini.v1 v1.55.0 // indirect
 	gopkg.in/yaml.v2 v2.2.8
 )
+
+// The root package of evm is patched for MadLedger, see third_party/evm/MADLEDGER.md
+replace github.com/thu-arxan/evm => ./third_party/evm
@@ -0,0 +1,182 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package channel
+
+import (
+	"madledger/common/event"
+	"madledger/consensus/raft"
+	"madledger/core"
+	"sync"
+)
+
+// maxAnchorBatch is the max number of anchors submitted in a batch
+const maxAnchorBatch = 64
+
+// anchorer submits the anchors of blocks into _global in batches, so the
+// channels store their blocks without waiting for _global. The channels of
+// a batch are submitted in parallel, but the anchors of a channel are
+// submitted in order, so only the tail of a channel could be unanchored
+// after the orderer stops.
+type anchorer struct {
+	coordinator *Coordinator
+
+	lock    sync.Mutex
+	pending []anchor
+	signal  chan bool
+	stop    chan bool
+	once    sync.Once
+	// hub is done with the id of anchor after it is recorded if sync is
+	// true, otherwise nobody waits for the anchor
+	sync bool
+	hub  *event.Hub
+}
+
+// anchor is the global tx of a block of the channel
+type anchor struct {
+	channelID string
+	tx        *core.Tx
+}
+
+func newAnchorer(coordinator *Coordinator) *anchorer {
+	return &anchorer{
+		coordinator: coordinator,
+		signal:      make(chan bool, 1),
+		stop:        make(chan bool),
+		sync:        coordinator.chainCfg.SyncAnchor,
+		hub:         event.NewHub(),
+	}
+}
+
+// add queues the anchor of block and return it
+func (a *anchorer) add(block *core.Block) *core.Tx {
+	tx := core.NewGlobalTx(block.Header.ChannelID, block.Header.Number, block.Hash())
+	log.Debugf("Channel %s add tx %s to global channel, num: %d", block.Header.ChannelID, tx.ID, block.Header.Number)
+	a.lock.Lock()
+	a.pending = append(a.pending, anchor{channelID: block.Header.ChannelID, tx: tx})
+	a.lock.Unlock()
+	select {
+	case a.signal <- true:
+	default:
+	}
+	return tx
+}
+
+// wait blocks until the anchor is recorded in _global, it should be called
+// once for each anchor and only if sync is true
+func (a *anchorer) wait(tx *core.Tx) {
+	a.hub.Watch(tx.ID, nil)
+	a.hub.Forget(tx.ID)
+}
+
+// recover queues the anchors of blocks which are stored but not recorded in
+// _global because the orderer stopped before. Anchors of a channel are
+// recorded in order, so the scan stops at the first anchored block.
+func (a *anchorer) recover(manager *Manager) {
+	var blocks []*core.Block
+	// genesis blocks are not anchored
+	for num := manager.GetBlockSize(); num > 1; num-- {
+		block, err := manager.GetBlock(num - 1)
+		if err != nil {
+			log.Fatalf("Channel %s failed to load block %d: %v", manager.ID, num-1, err)
+			return
+		}
+		if manager.db.HasTx(core.NewGlobalTx(manager.ID, block.Header.Number, block.Hash())) {
+			break
+		}
+		blocks = append(blocks, block)
+	}
+	for i := len(blocks) - 1; i >= 0; i-- {
+		log.Infof("Channel %s anchors block %d again", manager.ID, blocks[i].Header.Number)
+		a.add(blocks[i])
+	}
+}
+
+// start submits the pending anchors until stopped, a batch is submitted
+// after the last one is recorded
+func (a *anchorer) start() {
+	for {
+		select {
+		case <-a.signal:
+		case <-a.stop:
+			return
+		}
+		for {
+			a.lock.Lock()
+			anchors := a.pending
+			if len(anchors) > maxAnchorBatch {
+				anchors = anchors[:maxAnchorBatch]
+			}
+			a.pending = a.pending[len(anchors):]
+			a.lock.Unlock()
+			if len(anchors) == 0 || !a.submit(anchors) {
+				break
+			}
+		}
+	}
+}
+
+// submit adds the anchors into _global and waits until they are recorded,
+// the anchor of a block is added after the anchors of blocks before it are
+// recorded. It return false if the anchorer is stopped.
+func (a *anchorer) submit(anchors []anchor) bool {
+	var channels = make(map[string][]*core.Tx)
+	for _, anchor := range anchors {
+		channels[anchor.channelID] = append(channels[anchor.channelID], anchor.tx)
+	}
+	var wg sync.WaitGroup
+	for _, txs := range channels {
+		wg.Add(1)
+		go func(txs []*core.Tx) {
+			defer wg.Done()
+			for _, tx := range txs {
+				if !a.addTx(tx) {
+					return
+				}
+			}
+		}(txs)
+	}
+	wg.Wait()
+	select {
+	case <-a.stop:
+		return false
+	default:
+		return true
+	}
+}
+
+// addTx adds the anchor into _global and waits until it is recorded,
+// it return false if the anchorer is stopped
+func (a *anchorer) addTx(tx *core.Tx) bool {
+	if err := a.coordinator.GM.AddTx(tx); err != nil {
+		// todo: This is temporary fix
+		if err.Error() != "The tx exist in the blockchain aleardy" && raft.GetError(err) != raft.TxInPool {
+			select {
+			case <-a.stop:
+				return false
+			default:
+			}
+			log.Fatalf("Failed to add tx %s into global channel because %s", tx.ID, err)
+			return false
+		}
+	}
+	if a.sync {
+		a.hub.Done(tx.ID, nil)
+	}
+	return true
+}
+
+// close stops submitting anchors, the pending ones are anchored again by
+// recover after restart. It could be called more than once.
+func (a *anchorer) close() {
+	a.once.Do(func() {
+		close(a.stop)
+	})
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package channel
+
+import (
+	"madledger/common/backup"
+	"madledger/consensus"
+)
+
+// Backup copies the blocks of all channels into blocksDir and the db into
+// dbDir, channels are frozen while copying, so they are consistent. The data
+// of consensus is copied into consensusDir if it is not empty after that, so
+// the consensus is never behind the blocks
+func (c *Coordinator) Backup(blocksDir, dbDir, consensusDir string) error {
+	if err := c.backupChannels(blocksDir, dbDir); err != nil {
+		return err
+	}
+	if consensusDir == "" {
+		return nil
+	}
+	if b, ok := c.Consensus.(consensus.Backuper); ok {
+		return b.Backup(consensusDir)
+	}
+	return nil
+}
+
+func (c *Coordinator) backupChannels(blocksDir, dbDir string) error {
+	c.commitLock.Lock()
+	defer c.commitLock.Unlock()
+
+	if err := backup.CopyDir(c.chainCfg.Path, blocksDir); err != nil {
+		return err
+	}
+	return c.db.Snapshot(dbDir)
+}
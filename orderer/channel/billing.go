@@ -0,0 +1,145 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package channel
+
+import (
+	"fmt"
+	"madledger/common"
+	"madledger/core"
+	"madledger/orderer/db"
+)
+
+// BillingTopic is the topic of billing events
+const BillingTopic = "billing"
+
+// BillingEvent is broadcast when a user channel is halted because the
+// balance can not pay the block price, or woken after the due is paid off
+type BillingEvent struct {
+	ChannelID string
+	Halted    bool
+	// Due is the due of channel when it is halted
+	Due uint64
+}
+
+// Billing is the billing statement of a user channel
+type Billing struct {
+	ChannelID  string
+	Balance    uint64
+	Due        uint64
+	Halted     bool
+	BlockPrice uint64
+	// Records are the latest charges in descending order of block number
+	Records []db.BillingRecord
+}
+
+// chargeBlock charges the block price against the channel account, and the
+// part that balance can not pay is added to due and the channel is halted
+func (manager *Manager) chargeBlock(block *core.Block, price uint64) error {
+	acc, err := manager.db.GetOrCreateAccount(common.AddressFromChannelID(manager.ID))
+	if err != nil {
+		return err
+	}
+	size := uint64(len(block.Bytes()))
+	record := db.BillingRecord{
+		BlockNumber: block.Header.Number,
+		Size:        size,
+		Fee:         size * price,
+		Time:        block.Header.Time,
+	}
+	balance := acc.GetBalance()
+	if balance < record.Fee {
+		record.Paid = balance
+		record.Due = record.Fee - balance
+	} else {
+		record.Paid = record.Fee
+	}
+	if err := acc.SubBalance(record.Paid); err != nil {
+		return err
+	}
+	if err := acc.AddDue(record.Due); err != nil {
+		return err
+	}
+
+	wb := manager.db.NewWriteBatch()
+	if err := wb.UpdateAccounts(acc); err != nil {
+		return err
+	}
+	if err := wb.AddBillingRecord(manager.ID, &record); err != nil {
+		return err
+	}
+	if err := wb.Sync(); err != nil {
+		return err
+	}
+
+	if record.Due != 0 {
+		manager.lock.Lock()
+		halted := manager.insufficientBalance
+		manager.insufficientBalance = true
+		manager.lock.Unlock()
+		if !halted {
+			log.Warnf("Channel %s is halted because of due %d", manager.ID, acc.GetDue())
+			manager.coordinator.broadcastBilling(manager.ID, true, acc.GetDue())
+		}
+	}
+	return nil
+}
+
+// RegisterBilling register the billing events of all user channels, the
+// channel is closed if the receiver falls behind. The token should be passed
+// to UnregisterBilling when the events are not needed.
+func (c *Coordinator) RegisterBilling() (ch chan interface{}, token int) {
+	return c.hub.Register(BillingTopic)
+}
+
+// UnregisterBilling unregister the billing events registered with token
+func (c *Coordinator) UnregisterBilling(token int) {
+	c.hub.UnRegister(BillingTopic, token)
+}
+
+// broadcastBilling never blocks the block adding, so the slow receivers are dropped
+func (c *Coordinator) broadcastBilling(channelID string, halted bool, due uint64) {
+	c.hub.Publish(BillingTopic, BillingEvent{
+		ChannelID: channelID,
+		Halted:    halted,
+		Due:       due,
+	})
+}
+
+// GetChannelBilling return the billing statement of user channel with the latest count charges,
+// count <= 0 means all charges
+func (c *Coordinator) GetChannelBilling(channelID string, count int) (*Billing, error) {
+	c.managerLock.RLock()
+	manager, ok := c.Managers[channelID]
+	c.managerLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("channel %s is not a user channel", channelID)
+	}
+	profile, err := c.db.GetChannelProfile(channelID)
+	if err != nil {
+		return nil, err
+	}
+	acc, err := c.db.GetOrCreateAccount(common.AddressFromChannelID(channelID))
+	if err != nil {
+		return nil, err
+	}
+	records, err := c.db.ListBillingRecords(channelID, count)
+	if err != nil {
+		return nil, err
+	}
+	return &Billing{
+		ChannelID:  channelID,
+		Balance:    acc.GetBalance(),
+		Due:        acc.GetDue(),
+		Halted:     manager.IsHalted(),
+		BlockPrice: profile.BlockPrice,
+		Records:    records,
+	}, nil
+}
@@ -2,8 +2,10 @@ package channel
 
 import (
 	"errors"
+	cc "madledger/blockchain/config"
 	"madledger/common"
 	"madledger/common/crypto"
+	"madledger/core"
 	"madledger/orderer/db"
 	"reflect"
 )
@@ -60,12 +62,23 @@ func (cache *Cache) IsAssetAdmin(pk crypto.PublicKey, pkAlgo crypto.Algorithm) b
 // GetOrCreateAccount returns default account if not exist
 func (cache *Cache) GetOrCreateAccount(address common.Address) (common.Account, error) {
 	if account, ok := cache.accounts[address]; ok {
+		// copy assets so that a failed op won't pollute the cache
+		assets := make(map[string]uint64, len(account.Assets))
+		for id, balance := range account.Assets {
+			assets[id] = balance
+		}
+		account.Assets = assets
 		return account, nil
 	}
 	account, err := cache.db.GetOrCreateAccount(address)
 	return account, err
 }
 
+// SetTxStatus store the status of tx
+func (cache *Cache) SetTxStatus(tx *core.Tx, status *db.TxStatus) error {
+	return cache.wb.SetTxStatus(tx, status)
+}
+
 // UpdateAccounts update account info
 func (cache *Cache) UpdateAccounts(accs ...common.Account) error {
 	for _, acc := range accs {
@@ -74,6 +87,11 @@ func (cache *Cache) UpdateAccounts(accs ...common.Account) error {
 	return cache.wb.UpdateAccounts(accs...)
 }
 
+// GetChannelProfile return the profile of channel
+func (cache *Cache) GetChannelProfile(id string) (*cc.Profile, error) {
+	return cache.db.GetChannelProfile(id)
+}
+
 // SetAssetAdmin only works when it is first called
 func (cache *Cache) SetAssetAdmin(pk crypto.PublicKey, pkAlgo crypto.Algorithm) error {
 	if cache.adminPK != nil {
@@ -52,10 +52,15 @@ type Coordinator struct {
 	AM *Manager
 
 	Consensus consensus.Consensus
+	anchorer  *anchorer
 
 	hub       *event.Hub
 	stateLock sync.RWMutex
 	states    map[string]*State
+
+	// commitLock is held by channels while committing blocks, and it is
+	// locked to freeze the blocks and db while backing up
+	commitLock sync.RWMutex
 }
 
 // StateCode represent the code of state
@@ -84,6 +89,7 @@ func NewCoordinator(dbDir string, chainCfg *config.BlockChainConfig, consensusCf
 	c.states = make(map[string]*State)
 	c.Managers = make(map[string]*Manager)
 	c.chainCfg = chainCfg
+	c.anchorer = newAnchorer(c)
 	// set db
 	c.db, err = db.NewLevelDB(dbDir)
 	if err != nil {
@@ -165,6 +171,14 @@ func (c *Coordinator) Start() error {
 
 	go c.GM.Start()
 	time.Sleep(100 * time.Millisecond)
+	c.anchorer.recover(c.CM)
+	c.anchorer.recover(c.AM)
+	c.managerLock.RLock()
+	for _, channelManager := range c.Managers {
+		c.anchorer.recover(channelManager)
+	}
+	c.managerLock.RUnlock()
+	go c.anchorer.start()
 	go c.CM.Start()
 	time.Sleep(100 * time.Millisecond)
 	go c.AM.Start()
@@ -184,6 +198,7 @@ func (c *Coordinator) Start() error {
 // Stop will stop the consensus
 func (c *Coordinator) Stop() error {
 	defer c.db.Close()
+	c.anchorer.close()
 	return c.Consensus.Stop()
 }
 
@@ -247,11 +262,17 @@ func (c *Coordinator) ListChannels(req *pb.ListChannelsRequest) (*pb.ChannelInfo
 			if channelManager.IsAdmin(member) {
 				identity = pb.Identity_ADMIN
 			}
-			infos.Channels = append(infos.Channels, &pb.ChannelInfo{
-				ChannelID:       channel,
-				BlockSize:       channelManager.GetBlockSize(),
-				Identity:        identity,
-			})
+			info := &pb.ChannelInfo{
+				ChannelID: channel,
+				BlockSize: channelManager.GetBlockSize(),
+				Identity:  identity,
+			}
+			if profile, err := c.db.GetChannelProfile(channel); err == nil {
+				info.MaxGas = profile.MaxGas
+				info.GasPrice = profile.GasPrice
+				info.AssetTokenRatio = profile.AssetTokenRatio
+			}
+			infos.Channels = append(infos.Channels, info)
 		}
 	}
 	return infos, nil
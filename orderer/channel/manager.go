@@ -11,16 +11,18 @@
 package channel
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"madledger/blockchain"
+	ac "madledger/blockchain/asset"
 	"madledger/common"
 	"madledger/common/event"
 	"madledger/common/util"
 	"madledger/consensus"
-	"madledger/consensus/raft"
 	"madledger/core"
 	"madledger/orderer/db"
+	"strconv"
 	"sync"
 	"time"
 
@@ -52,7 +54,7 @@ type Manager struct {
 
 // NewManager is the constructor of Manager
 func NewManager(id string, coordinator *Coordinator) (*Manager, error) {
-	cm, err := blockchain.NewManager(id, fmt.Sprintf("%s/%s", coordinator.chainCfg.Path, id))
+	cm, err := blockchain.NewManager(id, fmt.Sprintf("%s/%s", coordinator.chainCfg.Path, id), coordinator.chainCfg.Compression)
 	if err != nil {
 		return nil, err
 	}
@@ -90,25 +92,23 @@ func (manager *Manager) Start() {
 					block = core.NewBlock(manager.ID, prevBlock.Header.Number+1, prevBlock.Hash().Bytes(), txs)
 					log.Debugf("Channel %s create new block %d, hash is %s", manager.ID, prevBlock.Header.Number+1, util.Hex(block.Hash().Bytes()))
 				}
-				// If the channel is not the global channel, it should send a tx to the global channel
+				// If the channel is not the global channel, its block should be anchored in the global channel
 				if manager.ID != core.GLOBALCHANNELID {
-					tx := core.NewGlobalTx(manager.ID, block.Header.Number, block.Hash())
-					// 打印非config通道向global通道中添加的tx信息
-					log.Debugf("Channel %s add tx %s to global channel, num: %d", manager.ID, tx.ID, block.Header.Number)
-					if err := manager.coordinator.GM.AddTx(tx); err != nil {
-						// todo: This is temporary fix
-						if err.Error() != "The tx exist in the blockchain aleardy" && raft.GetError(err) != raft.TxInPool {
-							log.Fatalf("Channel %s failed to add tx into global channel because %s", manager.ID, err)
-							return
-						}
+					tx := manager.coordinator.anchorer.add(block)
+					if manager.coordinator.anchorer.sync {
+						manager.coordinator.anchorer.wait(tx)
 					}
 				}
-				if err := manager.AddBlock(block); err != nil {
+				manager.waitBlock(block)
+				manager.coordinator.commitLock.RLock()
+				err := manager.addBlock(block)
+				manager.coordinator.commitLock.RUnlock()
+				if err != nil {
 					log.Fatalf("Channel %s failed to run because of %s", manager.ID, err)
 					return
 				}
 				log.Debugf("Channel %s has %d block now", manager.ID, block.Header.Number)
-				manager.hub.Done(string(block.Header.Number), nil)
+				manager.hub.Done(strconv.FormatUint(block.Header.Number, 10), nil)
 				for _, tx := range block.Transactions {
 					manager.hub.Done(util.Hex(tx.Hash()), nil)
 				}
@@ -142,6 +142,22 @@ func (manager *Manager) GetBlock(num uint64) (*core.Block, error) {
 
 // AddBlock add a block
 func (manager *Manager) AddBlock(block *core.Block) error {
+	manager.waitBlock(block)
+	return manager.addBlock(block)
+}
+
+// waitBlock waits until the block could be run, it should not be called
+// while holding the commit lock, otherwise _global may never unlock the block
+func (manager *Manager) waitBlock(block *core.Block) {
+	switch manager.ID {
+	case core.CONFIGCHANNELID, core.ASSETCHANNELID:
+		if !isGenesisBlock(block) && !manager.coordinator.CanRun(block.Header.ChannelID, block.Header.Number) {
+			manager.coordinator.Watch(block.Header.ChannelID, block.Header.Number)
+		}
+	}
+}
+
+func (manager *Manager) addBlock(block *core.Block) error {
 	log.Infof("start adding block %d in channel %v", block.GetNumber(), manager.ID)
 	// first update db
 	if err := manager.db.AddBlock(block); err != nil {
@@ -149,11 +165,18 @@ func (manager *Manager) AddBlock(block *core.Block) error {
 			manager.ID, block.Header.Number, err.Error())
 		return err
 	}
-	if err := manager.cm.AddBlock(block); err != nil {
-		log.Infof("manager.cm.AddBlock error: %s add block %d, %s",
-			manager.ID, block.Header.Number, err.Error())
+	// the block is stored already while reindexing
+	stored, err := manager.cm.HasBlock(block)
+	if err != nil {
 		return err
 	}
+	if !stored {
+		if err := manager.cm.AddBlock(block); err != nil {
+			log.Infof("manager.cm.AddBlock error: %s add block %d, %s",
+				manager.ID, block.Header.Number, err.Error())
+			return err
+		}
+	}
 
 	if isUserChannel(manager.ID) && !isGenesisBlock(block) {
 		profile, err := manager.db.GetChannelProfile(manager.ID)
@@ -163,26 +186,8 @@ func (manager *Manager) AddBlock(block *core.Block) error {
 			return err
 		}
 		if profile.BlockPrice != 0 {
-			acc, err := manager.db.GetOrCreateAccount(common.AddressFromChannelID(manager.ID))
-			if err != nil {
-				return err
-			}
-			balance := acc.GetBalance()
-
-			storagePrice := uint64(len(block.Bytes())) * profile.BlockPrice
-			if balance < storagePrice {
-				manager.lock.Lock()
-				manager.insufficientBalance = true
-				manager.lock.Unlock()
-				acc.SubBalance(balance)
-				acc.AddDue(storagePrice - balance)
-				// zhq todo: am i doing it correct?
-			} else {
-				acc.SubBalance(storagePrice)
-			}
-			err = manager.db.SetAccount(acc)
-			if err != nil {
-				log.Infof("manager.db cannot set account: %s add block %d, %s",
+			if err := manager.chargeBlock(block, profile.BlockPrice); err != nil {
+				log.Infof("manager cannot charge: %s add block %d, %s",
 					manager.ID, block.Header.Number, err.Error())
 				return err
 			}
@@ -196,16 +201,10 @@ func (manager *Manager) AddBlock(block *core.Block) error {
 	// check is there is any need to update local state of orderer
 	switch manager.ID {
 	case core.CONFIGCHANNELID:
-		if !isGenesisBlock(block) && !manager.coordinator.CanRun(block.Header.ChannelID, block.Header.Number) {
-			manager.coordinator.Watch(block.Header.ChannelID, block.Header.Number)
-		}
 		return manager.AddConfigBlock(block)
 	case core.GLOBALCHANNELID:
 		return manager.AddGlobalBlock(block)
 	case core.ASSETCHANNELID:
-		if !isGenesisBlock(block) && !manager.coordinator.CanRun(block.Header.ChannelID, block.Header.Number) {
-			manager.coordinator.Watch(block.Header.ChannelID, block.Header.Number)
-		}
 		return manager.AddAssetBlock(block)
 	default:
 		return nil
@@ -273,17 +272,54 @@ func (manager *Manager) GetAccount(address common.Address) (common.Account, erro
 	return manager.db.GetOrCreateAccount(address)
 }
 
+// GetAsset return the named asset
+func (manager *Manager) GetAsset(id string) (*ac.Asset, error) {
+	data, err := manager.db.Get(ac.GetAssetKey(id), true)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, fmt.Errorf("asset %s is not exist", id)
+	}
+	var asset ac.Asset
+	err = json.Unmarshal(data, &asset)
+	return &asset, err
+}
+
+// GetComplianceHistory return the compliance records of address
+func (manager *Manager) GetComplianceHistory(address common.Address) ([]ac.ComplianceRecord, error) {
+	var records []ac.ComplianceRecord
+	data, err := manager.db.Get(ac.GetComplianceKey(address), true)
+	if err != nil || data == nil {
+		return records, err
+	}
+	err = json.Unmarshal(data, &records)
+	return records, err
+}
+
+// WakeFromSufficientBalance wake the channel after its due is paid off
 func (manager *Manager) WakeFromSufficientBalance() {
 	manager.lock.Lock()
+	halted := manager.insufficientBalance
 	manager.insufficientBalance = false
 	manager.lock.Unlock()
+	if halted {
+		manager.coordinator.broadcastBilling(manager.ID, false, 0)
+	}
+}
+
+// IsHalted return if the channel refuses txs because of due
+func (manager *Manager) IsHalted() bool {
+	manager.lock.RLock()
+	defer manager.lock.RUnlock()
+	return manager.insufficientBalance
 }
 
 // FetchBlockAsync will fetch book async.
 // TODO: fix the thread unsafety
 func (manager *Manager) FetchBlockAsync(num uint64) (*core.Block, error) {
 	if manager.cm.GetExpect() <= num {
-		manager.hub.Watch(string(num), nil)
+		manager.hub.Watch(strconv.FormatUint(num, 10), nil)
 	}
 
 	block, err := manager.cm.GetBlock(num)
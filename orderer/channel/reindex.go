@@ -0,0 +1,174 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package channel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	cc "madledger/blockchain/config"
+	"madledger/common/event"
+	"madledger/core"
+	"madledger/orderer/config"
+	"madledger/orderer/db"
+	"sort"
+)
+
+// Progress is called after a block of channel is replayed, height is the
+// number of blocks stored in the channel
+type Progress func(channelID string, num, height uint64)
+
+// Reindex rebuilds the db at dbDir from the blocks stored in chainCfg.Path,
+// dbDir should be empty. Blocks are replayed in the order recorded in
+// _global, then the blocks which are not recorded yet.
+func Reindex(dbDir string, chainCfg *config.BlockChainConfig, progress Progress) error {
+	var err error
+
+	c := new(Coordinator)
+	c.hub = event.NewHub()
+	c.states = make(map[string]*State)
+	c.Managers = make(map[string]*Manager)
+	c.chainCfg = chainCfg
+	c.db, err = db.NewLevelDB(dbDir)
+	if err != nil {
+		return fmt.Errorf("Failed to load db at %s because %s", dbDir, err.Error())
+	}
+	defer c.db.Close()
+
+	r := &reindexer{
+		c:        c,
+		next:     make(map[string]uint64),
+		progress: progress,
+	}
+	defer r.close()
+	if c.CM, err = NewManager(core.CONFIGCHANNELID, c); err != nil {
+		return err
+	}
+	if c.GM, err = NewManager(core.GLOBALCHANNELID, c); err != nil {
+		return err
+	}
+	if c.AM, err = NewManager(core.ASSETCHANNELID, c); err != nil {
+		return err
+	}
+	if !c.CM.HasGenesisBlock() || !c.GM.HasGenesisBlock() || !c.AM.HasGenesisBlock() {
+		return errors.New("The system channels have no genesis block")
+	}
+	if err := r.loadSystemAdmin(); err != nil {
+		return err
+	}
+
+	for _, manager := range []*Manager{c.CM, c.GM, c.AM} {
+		if err := r.replay(manager, 0); err != nil {
+			return err
+		}
+	}
+	for num := uint64(1); num < c.GM.GetBlockSize(); num++ {
+		if err := r.replay(c.GM, num); err != nil {
+			return err
+		}
+		block, err := c.GM.GetBlock(num)
+		if err != nil {
+			return err
+		}
+		for _, tx := range block.Transactions {
+			payload, err := tx.GetGlobalTxPayload()
+			if err != nil {
+				return err
+			}
+			manager, err := c.getChannelManager(payload.ChannelID)
+			if err != nil {
+				return fmt.Errorf("Block %d of _global: %v", num, err)
+			}
+			if err := r.replay(manager, payload.Num); err != nil {
+				return err
+			}
+		}
+	}
+
+	// the blocks which are stored while _global is not
+	tail := []*Manager{c.CM, c.AM}
+	var channels []string
+	for channelID := range c.Managers {
+		channels = append(channels, channelID)
+	}
+	sort.Strings(channels)
+	for _, channelID := range channels {
+		tail = append(tail, c.Managers[channelID])
+	}
+	for _, manager := range tail {
+		if err := r.replay(manager, manager.GetBlockSize()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type reindexer struct {
+	c *Coordinator
+	// next is the next block to replay of each channel
+	next     map[string]uint64
+	progress Progress
+}
+
+// loadSystemAdmin restores the system admin which is recorded in the
+// genesis block of _config
+func (r *reindexer) loadSystemAdmin() error {
+	block, err := r.c.CM.GetBlock(0)
+	if err != nil {
+		return err
+	}
+	for _, tx := range block.Transactions {
+		var payload cc.Payload
+		if err := json.Unmarshal(tx.Data.Payload, &payload); err != nil {
+			return err
+		}
+		if payload.ChannelID == "" && payload.Profile != nil {
+			return r.c.db.UpdateSystemAdmin(payload.Profile)
+		}
+	}
+	return errors.New("There is no system admin in the genesis block of _config")
+}
+
+// replay replays the blocks of channel until num
+func (r *reindexer) replay(manager *Manager, num uint64) error {
+	height := manager.GetBlockSize()
+	// the block may be recorded in _global while it is not stored
+	if num >= height {
+		if height == 0 {
+			return nil
+		}
+		num = height - 1
+	}
+	for ; r.next[manager.ID] <= num; r.next[manager.ID]++ {
+		block, err := manager.GetBlock(r.next[manager.ID])
+		if err != nil {
+			return err
+		}
+		if err := manager.addBlock(block); err != nil {
+			return fmt.Errorf("Failed to replay block %d of channel %s: %v", block.Header.Number, manager.ID, err)
+		}
+		if r.progress != nil {
+			r.progress(manager.ID, block.Header.Number, height)
+		}
+	}
+	return nil
+}
+
+func (r *reindexer) close() {
+	for _, manager := range []*Manager{r.c.CM, r.c.GM, r.c.AM} {
+		if manager != nil {
+			manager.cm.Close()
+		}
+	}
+	for _, manager := range r.c.Managers {
+		manager.cm.Close()
+	}
+}
@@ -12,15 +12,12 @@ package channel
 
 import (
 	"encoding/json"
-	"errors"
-	"fmt"
 	ac "madledger/blockchain/asset"
 	cc "madledger/blockchain/config"
 	"madledger/common"
-	"madledger/common/crypto"
 	"madledger/consensus"
 	"madledger/core"
-	"reflect"
+	"madledger/orderer/db"
 )
 
 // AddConfigBlock add a config block
@@ -42,30 +39,37 @@ func (manager *Manager) AddConfigBlock(block *core.Block) error {
 		// This is a create channel tx,从leveldb中查询是否已经存在channelID
 		// 这里并没有对channelID已经存在做出响应,而是在coordinator的createChannel做出响应
 		if !manager.db.HasChannel(channelID) {
-			// then start the consensus
-			err := manager.coordinator.Consensus.AddChannel(channelID, consensus.Config{
-				Timeout: manager.coordinator.chainCfg.BatchTimeout,
-				MaxSize: manager.coordinator.chainCfg.BatchSize,
-				Number:  1,
-				Resume:  false,
-			})
+			// there is no consensus while reindexing
+			if manager.coordinator.Consensus != nil {
+				// then start the consensus
+				manager.coordinator.Consensus.AddChannel(channelID, consensus.Config{
+					Timeout: manager.coordinator.chainCfg.BatchTimeout,
+					MaxSize: manager.coordinator.chainCfg.BatchSize,
+					Number:  1,
+					Resume:  false,
+				})
+			}
 			channel, err := NewManager(channelID, manager.coordinator)
 			if err != nil {
 				return err
 			}
-			// create genesis block here
+			// create genesis block here, the stored one is replayed while reindexing
 			// Note: the genesis block will contain no tx
-			genesisBlock := core.NewBlock(channelID, 0, core.GenesisBlockPrevHash, []*core.Tx{})
+			if !channel.HasGenesisBlock() {
+				genesisBlock := core.NewBlock(channelID, 0, core.GenesisBlockPrevHash, []*core.Tx{})
 
-			err = channel.AddBlock(genesisBlock)
-			if err != nil {
-				return err
+				err = channel.AddBlock(genesisBlock)
+				if err != nil {
+					return err
+				}
 			}
 			// then start the channel
-			go func() {
-				log.Infof("system/AddConfigBlock: start channel %s", channelID)
-				channel.Start()
-			}()
+			if manager.coordinator.Consensus != nil {
+				go func() {
+					log.Infof("system/AddConfigBlock: start channel %s", channelID)
+					channel.Start()
+				}()
+			}
 			// 更新coordinator.Managers(map类型)
 			manager.coordinator.setChannel(channelID, channel)
 			nums[payload.ChannelID] = []uint64{0}
@@ -108,119 +112,40 @@ func (manager *Manager) AddAssetBlock(block *core.Block) error {
 		return nil
 	}
 	cache := NewCache(manager.db)
-	var err error
-
-	for _, tx := range block.Transactions {
-		receiver := tx.GetReceiver()
-		var payload ac.Payload
-		err = json.Unmarshal(tx.Data.Payload, &payload)
-		if err != nil {
-			log.Infof("wrong tx format: %v", err)
-			continue
-		}
-		sender, err := tx.GetSender()
-		if err != nil {
-			log.Infof("wrong sender address %v", sender)
-			continue
+	machine := ac.NewMachine(&cache, &assetListener{coordinator: manager.coordinator})
+	for i, tx := range block.Transactions {
+		status := &db.TxStatus{
+			BlockNumber:     block.Header.Number,
+			BlockIndex:      i,
+			ContractAddress: tx.GetReceiver().String(),
 		}
-		//if receiver is not set, issue or transfer money to a channel
-		value := tx.Data.Value
-		recipient := payload.Address
-		if recipient == common.ZeroAddress {
-			recipient = common.AddressFromChannelID(payload.ChannelID)
+		if err := machine.Execute(tx, block.Header.Number, block.Header.Time); err != nil {
+			log.Infof("failed to execute tx %s in _asset: %v", tx.ID, err)
+			status.Err = err.Error()
 		}
-		switch receiver {
-		case core.IssueContractAddress:
-			err = manager.issue(cache, tx.Data.Sig.PK, tx.Data.Sig.Algo, recipient, value, payload.ChannelID)
-		case core.TransferContractrAddress:
-			err = manager.transfer(cache, sender, recipient, value, payload.ChannelID)
-		case core.TokenExchangeAddress:
-			err = manager.exchangeToken(cache, sender, recipient, value, payload.ChannelID)
-		default:
-			err = errors.New("Contract not support in _asset")
+		if err := cache.SetTxStatus(tx, status); err != nil {
+			return err
 		}
 	}
 	return cache.Sync()
 }
 
-func (manager *Manager) issue(cache Cache, senderPKBytes []byte, pkAlgo crypto.Algorithm, receiver common.Address, value uint64, channelID string) error {
-	pk, err := crypto.NewPublicKey(senderPKBytes, pkAlgo)
-	if !cache.IsAssetAdmin(pk, pkAlgo) && cache.SetAssetAdmin(pk, pkAlgo) != nil {
-		return fmt.Errorf("issue authentication failed: %v", err)
-	}
-	if value == 0 {
-		return nil
-	}
-
-	receiverAccount, err := cache.GetOrCreateAccount(receiver)
-	if err != nil {
-		return err
-	}
-
-	valueLeft, err := manager.payDueAndTryWakeChannel(receiverAccount, value, channelID)
-	if err != nil {
-		return err
-	}
-
-	err = receiverAccount.AddBalance(valueLeft)
-	if err != nil {
-		return err
-	}
-	return cache.UpdateAccounts(receiverAccount)
+// assetListener wakes the channel when its due is paid off,
+// orderer can't modify token because they don't know the exact value of token in every channel
+type assetListener struct {
+	coordinator *Coordinator
 }
 
-func (manager *Manager) transfer(cache Cache, sender, receiver common.Address, value uint64, channelID string) error {
-
-	if value == 0 || reflect.DeepEqual(sender, receiver){
-		return nil
-	}
-
-
-	senderAccount, err := cache.GetOrCreateAccount(sender)
-	if err != nil {
-		return err
-	}
-	if err = senderAccount.SubBalance(value); err != nil {
-		return err
-	}
-
-	receiverAccount, err := cache.GetOrCreateAccount(receiver)
-	if err != nil {
-		return err
-	}
-
-	valueLeft, err := manager.payDueAndTryWakeChannel(receiverAccount, value, channelID)
-	if err != nil {
-		return err
-	}
-
-	if err = receiverAccount.AddBalance(valueLeft); err != nil {
-		return err
+// DuePaid is the implementation of asset.Listener
+func (l *assetListener) DuePaid(channelID string) {
+	if err := l.coordinator.WakeDueChannel(channelID); err != nil {
+		log.Warnf("channel awake error: %v", err)
+		return
 	}
-
-	return cache.UpdateAccounts(senderAccount, receiverAccount)
+	log.Infof("wake channel %v", channelID)
 }
 
-func (manager *Manager) exchangeToken(cache Cache, sender, receiver common.Address, value uint64, channelID string) error {
-	if err := manager.transfer(cache, sender, receiver, value, channelID); err != nil {
-		return err
-	}
-
-	// orderer can't modify token because they don't know the exact value of token in every channel!!
+// TokenExchanged is the implementation of asset.Listener
+func (l *assetListener) TokenExchanged(channelID string, sender common.Address, value uint64) error {
 	return nil
 }
-
-func (manager *Manager) payDueAndTryWakeChannel(acc common.Account, value uint64, channelID string) (uint64, error) {
-	due := acc.GetDue()
-	if due == 0 {
-		return value, nil
-	}
-	if value < due {
-		return 0, acc.SubDue(value)
-	}
-	if err := manager.coordinator.WakeDueChannel(channelID); err != nil {
-		log.Warnf("channel awake error: %v", err)
-	}
-	log.Infof("wake channel %v", channelID)
-	return value - due, acc.SubDue(due)
-}
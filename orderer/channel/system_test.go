@@ -0,0 +1,418 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package channel
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	ac "madledger/blockchain/asset"
+	cc "madledger/blockchain/config"
+	"madledger/common"
+	"madledger/common/crypto"
+	"madledger/core"
+	"madledger/orderer/db"
+	pc "madledger/peer/channel"
+	pdb "madledger/peer/db"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	ordererDir = ".orderer"
+	peerDir    = ".peer"
+)
+
+// TestAssetConsistency adds random blocks into _asset of orderer and peer,
+// and the peer runs random blocks of test which spend token as gas and burn
+// token, the burned token is redeemed by the admin of test in the next block
+// of _asset. They should get the same status of every tx, the same accounts
+// and escrows, and the token of peer should be the redeemable asset without
+// the token spent and burned but not redeemed.
+func TestAssetConsistency(t *testing.T) {
+	os.RemoveAll(ordererDir)
+	os.RemoveAll(peerDir)
+	defer os.RemoveAll(ordererDir)
+	defer os.RemoveAll(peerDir)
+	ordererDB, err := db.NewLevelDB(ordererDir)
+	require.NoError(t, err)
+	defer ordererDB.Close()
+	peerDB, err := pdb.NewLevelDB(filepath.Join(peerDir, "leveldb"))
+	require.NoError(t, err)
+	defer peerDB.Close()
+	adminKey, err := crypto.GeneratePrivateKey(crypto.KeyAlgoSecp256k1)
+	require.NoError(t, err)
+	admin, err := core.NewMember(adminKey.PubKey(), "admin")
+	require.NoError(t, err)
+	profile := &cc.Profile{
+		Admins:          []*core.Member{admin},
+		GasPrice:        1,
+		AssetTokenRatio: 3000,
+		MaxGas:          10000000,
+	}
+	require.NoError(t, ordererDB.UpdateChannel("test", profile))
+	require.NoError(t, peerDB.UpdateChannel("test", profile))
+
+	orderer := &Manager{
+		ID:          core.ASSETCHANNELID,
+		db:          ordererDB,
+		coordinator: &Coordinator{Managers: make(map[string]*Manager)},
+	}
+	peer, err := pc.NewManager(core.ASSETCHANNELID, filepath.Join(peerDir, "blocks"), "", nil, peerDB, nil, pc.NewCoordinator())
+	require.NoError(t, err)
+	channel, err := pc.NewManager("test", filepath.Join(peerDir, "test"), "", nil, peerDB, nil, pc.NewCoordinator())
+	require.NoError(t, err)
+
+	var keys []crypto.PrivateKey
+	var addresses []common.Address
+	for i := 0; i < 5; i++ {
+		key, err := crypto.GeneratePrivateKey(crypto.KeyAlgoSecp256k1)
+		require.NoError(t, err)
+		address, err := key.PubKey().Address()
+		require.NoError(t, err)
+		keys = append(keys, key)
+		addresses = append(addresses, address)
+	}
+
+	r := rand.New(rand.NewSource(2020))
+	var escrows []string
+	// burns are the successful burning txs not redeemed yet
+	var burns []*core.Tx
+	var spent = make(map[common.Address]uint64)
+	var burned = make(map[common.Address]uint64)
+	var redeemed = make(map[common.Address]uint64)
+	prevHash := core.GenesisBlockPrevHash
+	for number := uint64(1); number <= 30; number++ {
+		var txs []*core.Tx
+		for i := 0; i < 20; i++ {
+			tx := randomTx(r, keys, escrows)
+			if tx.GetReceiver() == core.EscrowContractAddress {
+				escrows = append(escrows, tx.ID)
+			}
+			txs = append(txs, tx)
+		}
+		// the first block makes keys[0] the asset admin
+		if number == 1 {
+			txs[0] = newTx(core.IssueContractAddress, ac.Payload{Address: addresses[0]}, 100, keys[0])
+		}
+		// the admin of test redeems the burned token, and sometimes redeems
+		// the same burning tx twice
+		var redeems = make(map[string]*core.Tx)
+		var done = make(map[string]bool)
+		for _, burn := range burns {
+			sender, _ := burn.GetSender()
+			payload := ac.Payload{Address: sender, ChannelID: "test", BurnID: burn.ID}
+			redeem := newTx(core.TokenRedeemAddress, payload, burn.Data.Value, adminKey)
+			redeems[redeem.ID] = burn
+			txs = append(txs, redeem)
+			if r.Intn(4) == 0 {
+				txs = append(txs, newTx(core.TokenRedeemAddress, payload, burn.Data.Value, adminKey))
+			}
+		}
+		block := core.NewBlock(core.ASSETCHANNELID, number, prevHash, txs)
+		prevHash = block.Hash().Bytes()
+		require.NoError(t, orderer.AddAssetBlock(block))
+		require.NoError(t, peer.AddAssetBlock(block))
+
+		for _, tx := range txs {
+			ordererStatus, err := ordererDB.GetTxStatus(core.ASSETCHANNELID, tx.ID)
+			require.NoError(t, err)
+			peerStatus, err := peerDB.GetTxStatus(core.ASSETCHANNELID, tx.ID)
+			require.NoError(t, err)
+			require.Equal(t, ordererStatus.Err, peerStatus.Err, "tx %s in block %d", tx.ID, number)
+			require.Equal(t, ordererStatus.BlockIndex, peerStatus.BlockIndex)
+			if burn, ok := redeems[tx.ID]; ok && ordererStatus.Err == "" {
+				sender, _ := burn.GetSender()
+				redeemed[sender] += burn.Data.Value
+				done[burn.ID] = true
+			}
+		}
+		// the burns failed to redeem, e.g. the owner is frozen, are redeemed later
+		var left []*core.Tx
+		for _, burn := range burns {
+			if !done[burn.ID] {
+				left = append(left, burn)
+			}
+		}
+		burns = left
+		for _, address := range append(addresses, common.AddressFromChannelID("test")) {
+			ordererAccount, err := ordererDB.GetOrCreateAccount(address)
+			require.NoError(t, err)
+			peerAccount, err := peerDB.GetOrCreateAccount(address)
+			require.NoError(t, err)
+			require.Equal(t, ordererAccount, peerAccount, "account %s in block %d", address.String(), number)
+		}
+		for _, id := range escrows {
+			ordererEscrow, err := ordererDB.Get(ac.GetEscrowKey(id), true)
+			require.NoError(t, err)
+			peerEscrow, err := peerDB.Get(ac.GetEscrowKey(id), true)
+			require.NoError(t, err)
+			require.Equal(t, ordererEscrow, peerEscrow, "escrow %s in block %d", id, number)
+		}
+
+		// spend token as gas and burn token in test
+		txs = nil
+		for i := 0; i < 10; i++ {
+			key := keys[r.Intn(len(keys))]
+			var tx *core.Tx
+			if r.Intn(2) == 0 {
+				// the init code stores 1 at slot 0
+				tx, err = core.NewTxWithGas("test", common.ZeroAddress, []byte{0x60, 0x01, 0x60, 0x00, 0x55, 0x00}, 0, "", 30000, key)
+			} else {
+				// the burning tx has no payload, and burns 1 asset at least
+				tx, err = core.NewTx("test", core.TokenBurnAddress, nil, uint64(r.Intn(20)+1), "", key)
+			}
+			require.NoError(t, err)
+			txs = append(txs, tx)
+		}
+		wb, err := channel.RunBlock(core.NewBlock("test", number, nil, txs))
+		require.NoError(t, err)
+		require.NoError(t, wb.Sync())
+		for _, tx := range txs {
+			status, err := peerDB.GetTxStatus("test", tx.ID)
+			require.NoError(t, err)
+			sender, _ := tx.GetSender()
+			if tx.GetReceiver() != core.TokenBurnAddress {
+				spent[sender] += status.Tokens
+			} else if status.Code == pdb.TxSuccess {
+				burned[sender] += tx.Data.Value
+				burns = append(burns, tx)
+			}
+		}
+
+		cache := pc.NewCache(peerDB)
+		for _, address := range addresses {
+			data, err := ordererDB.Get(ac.GetRedeemableKey("test", address), true)
+			require.NoError(t, err)
+			var redeemable uint64
+			if data != nil {
+				redeemable = binary.BigEndian.Uint64(data)
+			}
+			token, err := cache.GetToken("test", address)
+			require.NoError(t, err)
+			unredeemed := profile.AssetTokenRatio * (burned[address] - redeemed[address])
+			require.Equal(t, profile.AssetTokenRatio*redeemable, token+spent[address]+unredeemed, "token of %s in block %d", address.String(), number)
+		}
+	}
+	require.NotEmpty(t, spent)
+	require.NotEmpty(t, burned)
+	require.NotEmpty(t, redeemed)
+}
+
+// TestRedeemConsistency spends token in channel before redeeming, and the
+// orderer has paid block price with the balance of channel. The orderer, the
+// peer running the channel and the peer not running it should get the same
+// status of every tx and the same accounts of owner.
+func TestRedeemConsistency(t *testing.T) {
+	dir, err := ioutil.TempDir("", "redeem")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	ordererDB, err := db.NewLevelDB(filepath.Join(dir, "orderer"))
+	require.NoError(t, err)
+	defer ordererDB.Close()
+	var peerDBs []pdb.DB
+	var peers []*pc.Manager
+	for i := 0; i < 2; i++ {
+		peerDB, err := pdb.NewLevelDB(filepath.Join(dir, fmt.Sprintf("peer%d", i), "leveldb"))
+		require.NoError(t, err)
+		defer peerDB.Close()
+		peer, err := pc.NewManager(core.ASSETCHANNELID, filepath.Join(dir, fmt.Sprintf("peer%d", i), "blocks"), "", nil, peerDB, nil, pc.NewCoordinator())
+		require.NoError(t, err)
+		peerDBs = append(peerDBs, peerDB)
+		peers = append(peers, peer)
+	}
+	orderer := &Manager{
+		ID:          core.ASSETCHANNELID,
+		db:          ordererDB,
+		coordinator: &Coordinator{Managers: make(map[string]*Manager)},
+	}
+
+	ownerKey, err := crypto.GeneratePrivateKey(crypto.KeyAlgoSecp256k1)
+	require.NoError(t, err)
+	owner, err := ownerKey.PubKey().Address()
+	require.NoError(t, err)
+	adminKey, err := crypto.GeneratePrivateKey(crypto.KeyAlgoSecp256k1)
+	require.NoError(t, err)
+	admin, err := core.NewMember(adminKey.PubKey(), "admin")
+	require.NoError(t, err)
+	profile := &cc.Profile{
+		Admins:          []*core.Member{admin},
+		GasPrice:        1,
+		AssetTokenRatio: 100000,
+		MaxGas:          10000000,
+	}
+	require.NoError(t, ordererDB.UpdateChannel("test", profile))
+	for _, peerDB := range peerDBs {
+		require.NoError(t, peerDB.UpdateChannel("test", profile))
+	}
+	// only the first peer runs test
+	channel, err := pc.NewManager("test", filepath.Join(dir, "peer0", "test"), "", nil, peerDBs[0], nil, pc.NewCoordinator())
+	require.NoError(t, err)
+
+	prevHash := core.GenesisBlockPrevHash
+	addBlock := func(number uint64, txs ...*core.Tx) {
+		block := core.NewBlock(core.ASSETCHANNELID, number, prevHash, txs)
+		prevHash = block.Hash().Bytes()
+		require.NoError(t, orderer.AddAssetBlock(block))
+		for _, peer := range peers {
+			require.NoError(t, peer.AddAssetBlock(block))
+		}
+		for _, tx := range txs {
+			ordererStatus, err := ordererDB.GetTxStatus(core.ASSETCHANNELID, tx.ID)
+			require.NoError(t, err)
+			for _, peerDB := range peerDBs {
+				peerStatus, err := peerDB.GetTxStatus(core.ASSETCHANNELID, tx.ID)
+				require.NoError(t, err)
+				require.Equal(t, ordererStatus.Err, peerStatus.Err, "tx %s in block %d", tx.ID, number)
+			}
+		}
+		ordererAccount, err := ordererDB.GetOrCreateAccount(owner)
+		require.NoError(t, err)
+		for _, peerDB := range peerDBs {
+			peerAccount, err := peerDB.GetOrCreateAccount(owner)
+			require.NoError(t, err)
+			require.Equal(t, ordererAccount, peerAccount, "block %d", number)
+		}
+	}
+	addBlock(1,
+		newTx(core.IssueContractAddress, ac.Payload{Address: owner}, 10, ownerKey),
+		newTx(core.TokenExchangeAddress, ac.Payload{ChannelID: "test"}, 5, ownerKey),
+	)
+
+	// the token is spent as gas, so the token of 5 could not be burned, the
+	// init code stores 1 at slot 0
+	create, err := core.NewTxWithGas("test", common.ZeroAddress, []byte{0x60, 0x01, 0x60, 0x00, 0x55, 0x00}, 0, "", 300000, ownerKey)
+	require.NoError(t, err)
+	spent, err := core.NewTx("test", core.TokenBurnAddress, nil, 5, "", ownerKey)
+	require.NoError(t, err)
+	burn, err := core.NewTx("test", core.TokenBurnAddress, nil, 2, "", ownerKey)
+	require.NoError(t, err)
+	wb, err := channel.RunBlock(core.NewBlock("test", 1, nil, []*core.Tx{create, spent, burn}))
+	require.NoError(t, err)
+	require.NoError(t, wb.Sync())
+	status, err := peerDBs[0].GetTxStatus("test", spent.ID)
+	require.NoError(t, err)
+	require.Equal(t, pdb.TxFailed, status.Code)
+	status, err = peerDBs[0].GetTxStatus("test", burn.ID)
+	require.NoError(t, err)
+	require.Equal(t, pdb.TxSuccess, status.Code)
+
+	// the orderer paid block price with the balance of channel
+	account, err := ordererDB.GetOrCreateAccount(common.AddressFromChannelID("test"))
+	require.NoError(t, err)
+	require.NoError(t, account.SubBalance(4))
+	ordererWB := ordererDB.NewWriteBatch()
+	require.NoError(t, ordererWB.UpdateAccounts(account))
+	require.NoError(t, ordererWB.Sync())
+
+	redeem := ac.Payload{Address: owner, ChannelID: "test", BurnID: burn.ID}
+	more := ac.Payload{Address: owner, ChannelID: "test", BurnID: spent.ID}
+	addBlock(2,
+		// the owner could not redeem by itself
+		newTx(core.TokenRedeemAddress, redeem, 2, ownerKey),
+		newTx(core.TokenRedeemAddress, redeem, 2, adminKey),
+		// the burning tx is redeemed only once
+		newTx(core.TokenRedeemAddress, redeem, 2, adminKey),
+		// more than the redeemable asset
+		newTx(core.TokenRedeemAddress, more, 5, adminKey),
+	)
+	account, err = ordererDB.GetOrCreateAccount(owner)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), account.GetBalance())
+	// the part that balance of channel could not pay becomes due in orderer
+	account, err = ordererDB.GetOrCreateAccount(common.AddressFromChannelID("test"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), account.GetBalance())
+	require.Equal(t, uint64(1), account.GetDue())
+	for _, peerDB := range peerDBs {
+		account, err = peerDB.GetOrCreateAccount(common.AddressFromChannelID("test"))
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), account.GetBalance())
+		require.Equal(t, uint64(0), account.GetDue())
+	}
+}
+
+func randomTx(r *rand.Rand, keys []crypto.PrivateKey, escrows []string) *core.Tx {
+	assets := []string{"", "", "GOLD", "SILVER"}
+	// some contracts appear more than once so that they are called more often
+	contracts := []common.Address{
+		core.IssueContractAddress,
+		core.IssueContractAddress,
+		core.TransferContractrAddress,
+		core.TransferContractrAddress,
+		core.TransferContractrAddress,
+		core.TokenExchangeAddress,
+		core.TokenRedeemAddress,
+		core.EscrowContractAddress,
+		core.EscrowContractAddress,
+		core.ClaimContractAddress,
+		core.ClaimContractAddress,
+		core.RefundContractAddress,
+		core.FreezeContractAddress,
+		core.UnfreezeContractAddress,
+		core.UnfreezeContractAddress,
+		core.BurnContractAddress,
+	}
+	preimage := []byte("madledger")
+	hashLock := sha256.Sum256(preimage)
+
+	key := keys[r.Intn(len(keys))]
+	receiver, _ := keys[r.Intn(len(keys))].PubKey().Address()
+	payload := ac.Payload{
+		Address: receiver,
+		AssetID: assets[r.Intn(len(assets))],
+	}
+	contract := contracts[r.Intn(len(contracts))]
+	switch contract {
+	case core.IssueContractAddress:
+		// only the admin could issue the native asset
+		if payload.AssetID == "" || r.Intn(2) == 0 {
+			key = keys[0]
+		}
+		payload.Cap = uint64(r.Intn(500))
+		return newTx(contract, payload, uint64(r.Intn(100)), key)
+	case core.TokenExchangeAddress, core.TokenRedeemAddress:
+		payload.Address = common.ZeroAddress
+		payload.ChannelID = "test"
+		if r.Intn(4) != 0 {
+			payload.AssetID = ""
+		}
+	case core.EscrowContractAddress:
+		if r.Intn(2) == 0 {
+			payload.HashLock = hashLock[:]
+		}
+		payload.ExpireHeight = uint64(r.Intn(40))
+	case core.ClaimContractAddress, core.RefundContractAddress:
+		if len(escrows) != 0 {
+			payload.EscrowID = escrows[r.Intn(len(escrows))]
+		}
+		payload.Preimage = preimage
+	case core.FreezeContractAddress, core.UnfreezeContractAddress, core.BurnContractAddress:
+		// most of them are sent by the admin
+		if r.Intn(4) != 0 {
+			key = keys[0]
+		}
+		payload.Reason = "test"
+	}
+	return newTx(contract, payload, uint64(r.Intn(30)), key)
+}
+
+func newTx(contract common.Address, payload ac.Payload, value uint64, key crypto.PrivateKey) *core.Tx {
+	data, _ := json.Marshal(payload)
+	tx, _ := core.NewTx(core.ASSETCHANNELID, contract, data, value, "", key)
+	return tx
+}
@@ -45,6 +45,11 @@ BlockChain:
   Path: <<<BlockChainPath>>>
   # If verify the rightness of blocks (default: false)
   Verify: false
+  # Compression of blocks stored, none or snappy (default: none)
+  Compression: none
+  # If blocks are stored only after anchored in _global, it makes channels
+  # wait for _global and is only for comparison (default: false)
+  SyncAnchor: false
 
 # Consensus mechanism configuration
 Consensus:
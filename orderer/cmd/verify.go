@@ -0,0 +1,59 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package cmd
+
+import (
+	"errors"
+	"madledger/blockchain"
+	"madledger/common/util"
+	"madledger/orderer/config"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	verifyCmd = &cobra.Command{
+		Use: "verify",
+	}
+	verifyViper = viper.New()
+)
+
+func init() {
+	verifyCmd.RunE = runVerify
+	verifyCmd.Flags().StringP("config", "c", "orderer.yaml", "The config file of blockchain")
+	verifyViper.BindPFlag("config", verifyCmd.Flags().Lookup("config"))
+	verifyCmd.Flags().StringSliceP("channel", "n", nil, "The channels to verify, all channels if not provided")
+	verifyViper.BindPFlag("channel", verifyCmd.Flags().Lookup("channel"))
+	rootCmd.AddCommand(verifyCmd)
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	cfgFile := verifyViper.GetString("config")
+	if cfgFile == "" {
+		return errors.New("Please provide the config file")
+	}
+	cfgAbsPath, err := util.MakeFileAbs(cfgFile, homeDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadConfig(cfgAbsPath)
+	if err != nil {
+		return err
+	}
+	setLog(cfg.Debug)
+	chainCfg, err := cfg.GetBlockChainConfig()
+	if err != nil {
+		return err
+	}
+	return blockchain.VerifyAndPrint(os.Stdout, chainCfg.Path, verifyViper.GetStringSlice("channel")...)
+}
@@ -16,6 +16,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"madledger/common/compress"
 	"madledger/common/util"
 	"os"
 	"regexp"
@@ -135,6 +136,11 @@ type BlockChainConfig struct {
 	BatchSize    int    `yaml:"BatchSize"`
 	Path         string `yaml:"Path"`
 	Verify       bool   `yaml:"Verify"`
+	// Compression is the compression of blocks stored, none or snappy
+	Compression string `yaml:"Compression"`
+	// SyncAnchor makes channels store blocks only after they are anchored in
+	// _global, blocks are anchored asynchronously by default
+	SyncAnchor bool `yaml:"SyncAnchor"`
 }
 
 type TLSConfig struct {
@@ -203,11 +209,16 @@ func (cfg *Config) GetBlockChainConfig() (*BlockChainConfig, error) {
 	if cfg.BlockChain.BatchSize <= 0 {
 		return nil, fmt.Errorf("The batch size can not be %d", cfg.BlockChain.BatchSize)
 	}
+	if err := compress.Check(cfg.BlockChain.Compression); err != nil {
+		return nil, err
+	}
 	return &BlockChainConfig{
 		BatchTimeout: cfg.BlockChain.BatchTimeout,
 		BatchSize:    cfg.BlockChain.BatchSize,
 		Path:         storePath,
 		Verify:       cfg.BlockChain.Verify,
+		Compression:  cfg.BlockChain.Compression,
+		SyncAnchor:   cfg.BlockChain.SyncAnchor,
 	}, nil
 }
 
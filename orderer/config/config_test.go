@@ -58,6 +58,7 @@ func TestGetBlockChainConfig(t *testing.T) {
 	require.Equal(t, chainCfg.BatchTimeout, 1000)
 	require.Equal(t, chainCfg.BatchSize, 100)
 	require.NotEqual(t, chainCfg.Path, "")
+	require.False(t, chainCfg.SyncAnchor)
 	// then change the value of cfg
 	// check batch timeout
 	cfg.BlockChain.BatchTimeout = 0
@@ -17,7 +17,8 @@ import (
 	"madledger/core"
 )
 
-// TxStatus return the status of tx
+// TxStatus return the status of tx, only the txs of _asset are recorded
+// because they are executed by the orderer
 type TxStatus struct {
 	Err             string
 	BlockNumber     uint64
@@ -26,12 +27,30 @@ type TxStatus struct {
 	ContractAddress string
 }
 
+// BillingRecord is the storage charge of a block in user channel
+type BillingRecord struct {
+	BlockNumber uint64
+	// Size is the length of block bytes
+	Size uint64
+	// Fee is Size * BlockPrice
+	Fee uint64
+	// Paid is the part of fee paid by the balance of channel
+	Paid uint64
+	// Due is the part of fee added to the due of channel
+	Due  uint64
+	Time int64
+}
+
 // WriteBatch ...
 type WriteBatch interface {
 	UpdateAccounts(accounts ...common.Account) error
 	//SetAssetAdmin only succeed at the first time it is called
 	SetAssetAdmin(pk crypto.PublicKey) error
 	Put(key, value []byte)
+	// AddBillingRecord records the storage charge of a block
+	AddBillingRecord(channelID string, record *BillingRecord) error
+	// SetTxStatus records the status of tx
+	SetTxStatus(tx *core.Tx, status *TxStatus) error
 	Sync() error
 }
 
@@ -44,12 +63,16 @@ type DB interface {
 	// AddBlock will records all txs in the block to get rid of duplicated txs
 	AddBlock(block *core.Block) error
 	HasTx(tx *core.Tx) bool
+	// GetTxStatus return the status of tx recorded by SetTxStatus
+	GetTxStatus(channelID, txID string) (*TxStatus, error)
 	IsMember(channelID string, member *core.Member) bool
 	IsAdmin(channelID string, member *core.Member) bool
 	// WatchChannel provide a way to spy channel change. Now it mainly used to
 	// spy channel create operation.
 	WatchChannel(channelID string)
 	Close() error
+	// Snapshot writes a consistent copy of the db into dir
+	Snapshot(dir string) error
 	UpdateSystemAdmin(profile *cc.Profile) error
 	IsSystemAdmin(member *core.Member) bool
 
@@ -63,6 +86,9 @@ type DB interface {
 	GetOrCreateAccount(address common.Address) (common.Account, error)
 	//SetAccount can only be called when atomicity is at one account level
 	SetAccount(account common.Account) error
+	// ListBillingRecords return the latest count billing records of channel
+	// in descending order of block number, count <= 0 means all
+	ListBillingRecords(channelID string, count int) ([]BillingRecord, error)
 	//NewWriteBatch new a write batch
 	NewWriteBatch() WriteBatch
 }
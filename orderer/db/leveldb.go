@@ -16,18 +16,22 @@ import (
 	"madledger/common"
 
 	cc "madledger/blockchain/config"
+	"madledger/common/backup"
 	"madledger/common/crypto"
 	"madledger/common/event"
 	"madledger/common/util"
 	"madledger/core"
 
 	"github.com/syndtr/goleveldb/leveldb"
+	lutil "github.com/syndtr/goleveldb/leveldb/util"
 )
 
 /*
 *  1. Channel profile: key is []byte("_config@" + channelID), value is the json.Marshal(profile)
 *  2. All channel ids: key is []byte("_config"), value is json.Marshl([]string{id1, id2, ...})
 *  3. Tx: key is combine of []byte(channelID) and []byte(txID), value is []byte("true")
+*  4. Billing record: key is []byte("_billing@" + channelID + "@" + number), value is the json.Marshal(record)
+*  5. Tx status: key is []byte("_status@" + channelID + "@" + txID), value is the json.Marshal(status)
  */
 
 // LevelDB is the implementation of DB on orderer/data/leveldb
@@ -227,6 +231,11 @@ func (db *LevelDB) Close() error {
 	return db.connect.Close()
 }
 
+// Snapshot is the implementation of interface
+func (db *LevelDB) Snapshot(dir string) error {
+	return backup.SnapshotLevelDB(db.connect, dir)
+}
+
 // addChannel add a record into key core.CONFIGCHANNELID
 func (db *LevelDB) addChannel(id string) error {
 	var key = []byte(core.CONFIGCHANNELID)
@@ -308,11 +317,42 @@ func (db *LevelDB) Get(key []byte, couldBeEmpty bool) ([]byte, error) {
 	return val, err
 }
 
+// GetTxStatus is the implementation of DB
+func (db *LevelDB) GetTxStatus(channelID, txID string) (*TxStatus, error) {
+	value, err := db.connect.Get(getTxStatusKey(channelID, txID), nil)
+	if err != nil {
+		return nil, err
+	}
+	var status TxStatus
+	if err = json.Unmarshal(value, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
 // Put put the kv pair
 func (db *LevelDB) Put(key, val []byte) error {
 	return db.connect.Put(key, val, nil)
 }
 
+// ListBillingRecords is the implementation of DB
+func (db *LevelDB) ListBillingRecords(channelID string, count int) ([]BillingRecord, error) {
+	var records []BillingRecord
+	iter := db.connect.NewIterator(lutil.BytesPrefix(getBillingPrefix(channelID)), nil)
+	defer iter.Release()
+	for ok := iter.Last(); ok; ok = iter.Prev() {
+		if count > 0 && len(records) >= count {
+			break
+		}
+		var record BillingRecord
+		if err := json.Unmarshal(iter.Value(), &record); err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+	return records, iter.Error()
+}
+
 // NewWriteBatch implement the interface, WriteBatch is a wrapper of leveldb.Batch
 func (db *LevelDB) NewWriteBatch() WriteBatch {
 	batch := new(leveldb.Batch)
@@ -338,6 +378,26 @@ func (wb *WriteBatchWrapper) Sync() error {
 	return wb.db.connect.Write(wb.batch, nil)
 }
 
+// AddBillingRecord add billing record in writebatch
+func (wb *WriteBatchWrapper) AddBillingRecord(channelID string, record *BillingRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	wb.Put(getBillingKey(channelID, record.BlockNumber), data)
+	return nil
+}
+
+// SetTxStatus add tx status in writebatch
+func (wb *WriteBatchWrapper) SetTxStatus(tx *core.Tx, status *TxStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	wb.Put(getTxStatusKey(tx.Data.ChannelID, tx.ID), data)
+	return nil
+}
+
 //UpdateAccounts update asset
 func (wb *WriteBatchWrapper) UpdateAccounts(accounts ...common.Account) error {
 	for _, acc := range accounts {
@@ -373,3 +433,16 @@ func getAccountKey(address common.Address) []byte {
 func getAssetAdminKey() []byte {
 	return []byte("_asset_admin")
 }
+
+func getTxStatusKey(channelID, txID string) []byte {
+	return []byte(fmt.Sprintf("_status@%s@%s", channelID, txID))
+}
+
+func getBillingPrefix(channelID string) []byte {
+	return []byte(fmt.Sprintf("_billing@%s@", channelID))
+}
+
+// getBillingKey pads the number so that records are sorted by number
+func getBillingKey(channelID string, number uint64) []byte {
+	return []byte(fmt.Sprintf("_billing@%s@%020d", channelID, number))
+}
@@ -162,6 +162,34 @@ func TestGetAndPut(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestBillingRecords(t *testing.T) {
+	wb := db.NewWriteBatch()
+	for i := uint64(1); i <= 10; i++ {
+		require.NoError(t, wb.AddBillingRecord("billing", &BillingRecord{
+			BlockNumber: i,
+			Size:        100,
+			Fee:         100,
+			Paid:        100,
+		}))
+	}
+	require.NoError(t, wb.AddBillingRecord("billing1", &BillingRecord{BlockNumber: 1}))
+	require.NoError(t, wb.Sync())
+
+	records, err := db.ListBillingRecords("billing", 3)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	require.Equal(t, uint64(10), records[0].BlockNumber)
+	require.Equal(t, uint64(8), records[2].BlockNumber)
+
+	records, err = db.ListBillingRecords("billing", 0)
+	require.NoError(t, err)
+	require.Len(t, records, 10)
+
+	records, err = db.ListBillingRecords("nobilling", 0)
+	require.NoError(t, err)
+	require.Len(t, records, 0)
+}
+
 func TestEnd(t *testing.T) {
 	os.RemoveAll(dir)
 }
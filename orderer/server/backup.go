@@ -0,0 +1,116 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package server
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"madledger/common/backup"
+	"madledger/orderer/config"
+	"madledger/orderer/db"
+	pb "madledger/protos"
+	"os"
+	"path/filepath"
+)
+
+// backupSections return the dirs of sections in the backup of orderer
+func backupSections(cfg *config.Config) (map[string]string, error) {
+	chainCfg, err := cfg.GetBlockChainConfig()
+	if err != nil {
+		return nil, err
+	}
+	dbCfg, err := cfg.GetDBConfig()
+	if err != nil {
+		return nil, err
+	}
+	consensusCfg, err := cfg.GetConsensusConfig()
+	if err != nil {
+		return nil, err
+	}
+	var sections = map[string]string{
+		backup.BlocksSection: chainCfg.Path,
+		backup.DBSection:     dbCfg.LevelDB.Path,
+	}
+	switch consensusCfg.Type {
+	case config.RAFT:
+		sections[backup.ConsensusSection] = consensusCfg.Raft.Path
+	case config.BFT:
+		sections[backup.ConsensusSection] = consensusCfg.BFT.Path
+	}
+	return sections, nil
+}
+
+// Export writes the backup of a stopped orderer into w
+func Export(cfg *config.Config, w io.Writer) error {
+	sections, err := backupSections(cfg)
+	if err != nil {
+		return err
+	}
+	// the db is locked until exported, so the orderer could not start
+	ldb, err := db.NewLevelDB(sections[backup.DBSection])
+	if err != nil {
+		return fmt.Errorf("Failed to open db, the orderer may be running: %v", err)
+	}
+	defer ldb.Close()
+	tmp, err := ioutil.TempDir("", "orderer-backup")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	sections[backup.DBSection] = filepath.Join(tmp, backup.DBSection)
+	if err := ldb.Snapshot(sections[backup.DBSection]); err != nil {
+		return err
+	}
+	return backup.Write(w, "orderer", false, sections)
+}
+
+// Import restores the backup into the dirs of orderer, which should be empty
+func Import(cfg *config.Config, file string) (*backup.Manifest, error) {
+	sections, err := backupSections(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return backup.Restore(file, "orderer", sections)
+}
+
+// Backup is the implementation of protos, the blocks and db are copied
+// while channels are frozen, and the data of consensus is copied while the
+// consensus is paused
+func (s *Server) Backup(req *pb.BackupRequest, stream pb.Orderer_BackupServer) error {
+	if err := backup.CheckLocal(stream.Context()); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempDir("", "orderer-backup")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	var sections = map[string]string{
+		backup.BlocksSection: filepath.Join(tmp, backup.BlocksSection),
+		backup.DBSection:     filepath.Join(tmp, backup.DBSection),
+	}
+	var consensusDir string
+	if _, ok := s.sections[backup.ConsensusSection]; ok {
+		consensusDir = filepath.Join(tmp, backup.ConsensusSection)
+		sections[backup.ConsensusSection] = consensusDir
+	}
+	if err := s.cc.Backup(sections[backup.BlocksSection], sections[backup.DBSection], consensusDir); err != nil {
+		return err
+	}
+	w := backup.NewStreamWriter(func(data []byte) error {
+		return stream.Send(&pb.BackupChunk{Data: data})
+	})
+	if err := backup.Write(w, "orderer", true, sections); err != nil {
+		return err
+	}
+	return w.Flush()
+}
@@ -2,13 +2,13 @@ package server
 
 import (
 	"encoding/hex"
-	"encoding/json"
 	"madledger/common"
 	"madledger/common/crypto"
 	"madledger/core"
 	pb "madledger/protos"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -58,17 +58,20 @@ func (hs *Server) CreateChannelByHTTP(c *gin.Context) {
 		return
 	}
 
-	var coreTx core.Tx
-	json.Unmarshal([]byte(j.Tx), &coreTx)
+	coreTx, err := decodeTx(j.Tx)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if !coreTx.Verify() {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "The tx is not a valid tx"})
 		return
 	}
-	if coreTx.GetReceiver().String() != core.CreateChannelContractAddress.String() {
+	if txType, _ := core.GetTxType(coreTx.GetReceiver().String()); txType != core.CREATECHANNEL {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "The receiver of the tx is not the valid contract address"})
 		return
 	}
-	_, err := hs.cc.CreateChannel(&coreTx)
+	_, err = hs.cc.CreateChannel(coreTx)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -77,6 +80,19 @@ func (hs *Server) CreateChannelByHTTP(c *gin.Context) {
 	return
 }
 
+// decodeTx decodes the tx of request, which is the hex of the binary encoding
+// of tx, and the json of tx sent by the old clients is also supported
+func decodeTx(tx string) (*core.Tx, error) {
+	if strings.HasPrefix(tx, "{") {
+		return core.BytesToTx([]byte(tx))
+	}
+	data, err := hex.DecodeString(tx)
+	if err != nil {
+		return nil, err
+	}
+	return core.BytesToTx(data)
+}
+
 // AddTxReq ...
 type AddTxReq struct {
 	Tx string `json:"tx"`
@@ -91,8 +107,11 @@ func (hs *Server) AddTxByHTTP(c *gin.Context) {
 	}
 	var status pb.TxStatus
 
-	var coreTx core.Tx
-	json.Unmarshal([]byte(j.Tx), &coreTx)
+	coreTx, err := decodeTx(j.Tx)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	txType, err := core.GetTxType(common.BytesToAddress(coreTx.Data.Recipient).String())
 	if err == nil && txType == core.CONSENSUS {
@@ -112,7 +131,7 @@ func (hs *Server) AddTxByHTTP(c *gin.Context) {
 			return
 		}
 	}
-	err = hs.cc.AddTx(&coreTx)
+	err = hs.cc.AddTx(coreTx)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -123,7 +142,8 @@ func (hs *Server) AddTxByHTTP(c *gin.Context) {
 
 // AccountInfoReq ...
 type AccountInfoReq struct {
-	Addr string `json:"address"`
+	Addr    string `json:"address"`
+	AssetID string `json:"asset"`
 }
 
 // GetAccountInfoByHTTP get account info by http
@@ -141,7 +161,67 @@ func (hs *Server) GetAccountInfoByHTTP(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	accountInfo.Balance = account.GetBalance()
+	accountInfo.AssetID = j.AssetID
+	accountInfo.Balance = account.GetAssetBalance(j.AssetID)
+	accountInfo.Frozen = account.Frozen
+	if j.AssetID != "" {
+		asset, err := hs.cc.AM.GetAsset(j.AssetID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		accountInfo.Decimals = uint32(asset.Decimals)
+		accountInfo.Cap = asset.Cap
+		accountInfo.Supply = asset.Supply
+	}
 	c.JSON(http.StatusOK, gin.H{"accountinfo": accountInfo})
 	return
 }
+
+// ComplianceHistoryReq ...
+type ComplianceHistoryReq struct {
+	Addr string `json:"address"`
+}
+
+// GetComplianceHistoryByHTTP get compliance history by http
+func (hs *Server) GetComplianceHistoryByHTTP(c *gin.Context) {
+	var j ComplianceHistoryReq
+	if err := c.ShouldBindJSON(&j); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	addr, err := hex.DecodeString(j.Addr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	records, err := hs.cc.AM.GetComplianceHistory(common.BytesToAddress(addr))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"compliancehistory": newComplianceHistory(records)})
+	return
+}
+
+// ChannelBillingReq ...
+type ChannelBillingReq struct {
+	ChannelID string `json:"channelID"`
+	Count     uint32 `json:"count"`
+}
+
+// GetChannelBillingByHTTP get channel billing by http
+func (hs *Server) GetChannelBillingByHTTP(c *gin.Context) {
+	var j ChannelBillingReq
+	if err := c.ShouldBindJSON(&j); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	billing, err := hs.cc.GetChannelBilling(j.ChannelID, int(j.Count))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"channelbilling": newChannelBilling(billing)})
+	return
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package server
+
+import (
+	"fmt"
+	"madledger/orderer/channel"
+	"madledger/orderer/config"
+	"madledger/orderer/db"
+	"os"
+
+	lerrors "github.com/syndtr/goleveldb/leveldb/errors"
+)
+
+// Reindex rebuilds the db of a stopped orderer from its blocks, the db is
+// rebuilt aside and replaces the old one only if it succeeds
+func Reindex(cfg *config.Config, progress channel.Progress) error {
+	chainCfg, err := cfg.GetBlockChainConfig()
+	if err != nil {
+		return err
+	}
+	dbCfg, err := cfg.GetDBConfig()
+	if err != nil {
+		return err
+	}
+	dir := dbCfg.LevelDB.Path
+	// a corrupted db is the common reason to reindex, but a locked one is not
+	if ldb, err := db.NewLevelDB(dir); err == nil {
+		ldb.Close()
+	} else if !lerrors.IsCorrupted(err) {
+		return fmt.Errorf("Failed to open db, the orderer may be running: %v", err)
+	}
+	staging := dir + ".reindex"
+	if err := os.RemoveAll(staging); err != nil {
+		return err
+	}
+	if err := channel.Reindex(staging, chainCfg, progress); err != nil {
+		os.RemoveAll(staging)
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	return os.Rename(staging, dir)
+}
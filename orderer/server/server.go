@@ -14,6 +14,8 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	// register the grpc compressors, so blocks could be fetched in snappy
+	_ "madledger/common/compress"
 	"madledger/orderer/channel"
 	"madledger/orderer/config"
 	pb "madledger/protos"
@@ -42,10 +44,12 @@ const (
 // These define api for gin
 const (
 	// ActionFetchBlock     = "fetchblock"
-	ActionListChannels   = "listchannels"
-	ActionCreateChannel  = "createchannel"
-	ActionAddTx          = "addtx"
-	ActionGetAccountInfo = "getaccountinfo"
+	ActionListChannels         = "listchannels"
+	ActionCreateChannel        = "createchannel"
+	ActionAddTx                = "addtx"
+	ActionGetAccountInfo       = "getaccountinfo"
+	ActionGetComplianceHistory = "getcompliancehistory"
+	ActionGetChannelBilling    = "getchannelbilling"
 )
 
 // Server provide the serve of orderer
@@ -57,6 +61,8 @@ type Server struct {
 	cc        *channel.Coordinator
 	ln        net.Listener
 	engine    *gin.Engine
+	// sections are the dirs backed up
+	sections map[string]string
 }
 
 // NewServer is the constructor of server
@@ -89,6 +95,10 @@ func NewServer(cfg *config.Config) (*Server, error) {
 		return nil, err
 	}
 	server.cc = cc
+	server.sections, err = backupSections(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	server.engine = gin.New()
 	server.engine.Use(gin.Recovery())
@@ -108,6 +118,8 @@ func (s *Server) initServer(engine *gin.Engine) error {
 		v1.POST(ActionCreateChannel, s.CreateChannelByHTTP)
 		v1.POST(ActionAddTx, s.AddTxByHTTP)
 		v1.POST(ActionGetAccountInfo, s.GetAccountInfoByHTTP)
+		v1.POST(ActionGetComplianceHistory, s.GetComplianceHistoryByHTTP)
+		v1.POST(ActionGetChannelBilling, s.GetChannelBillingByHTTP)
 	}
 	return nil
 }
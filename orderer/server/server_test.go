@@ -11,16 +11,20 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"madledger/blockchain/asset"
 	cc "madledger/blockchain/config"
 	"madledger/common"
+	"madledger/common/backup"
 	"madledger/common/crypto"
 	"madledger/common/util"
 	"madledger/core"
+	"madledger/orderer/channel"
 	"madledger/orderer/config"
 	pb "madledger/protos"
 	"os"
@@ -31,6 +35,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/syndtr/goleveldb/leveldb"
 
 	"google.golang.org/grpc"
 )
@@ -49,6 +54,10 @@ var (
 var (
 	server            *Server
 	genesisBlocksHash = make(map[string]common.Hash)
+	// assetReceiverKey is used to redeem token after restart
+	assetReceiverKey crypto.PrivateKey
+	// assetBurnID is the id of burning tx redeemed before restart
+	assetBurnID string
 )
 
 func TestNewServer(t *testing.T) {
@@ -494,6 +503,45 @@ func TestAsset(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, uint64(15), acc.GetBalance())
 
+	//test redeem token, the token is burned in channel first, and orderer
+	//only orders the burning tx
+	pbTx, burnID := getBurnTx("test", uint64(2), receiverKey)
+	_, err = client.AddTx(context.Background(), &pb.AddTxRequest{
+		Tx: pbTx,
+	})
+	require.NoError(t, err)
+
+	//only the admin of channel could redeem with the burning tx
+	redeem := asset.Payload{Address: receiver, ChannelID: "test", BurnID: burnID}
+	pbTx = getAssetPayloadTx(core.TokenRedeemAddress, redeem, uint64(2), receiverKey)
+	_, err = client.AddTx(context.Background(), &pb.AddTxRequest{
+		Tx: pbTx,
+	})
+	require.NoError(t, err)
+	acc, err = client.GetAccountInfo(context.Background(), &pb.GetAccountInfoRequest{
+		Address: receiver.Bytes(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), acc.GetBalance())
+
+	pbTx = getAssetPayloadTx(core.TokenRedeemAddress, redeem, uint64(2), privKey)
+	_, err = client.AddTx(context.Background(), &pb.AddTxRequest{
+		Tx: pbTx,
+	})
+	require.NoError(t, err)
+	acc, err = client.GetAccountInfo(context.Background(), &pb.GetAccountInfoRequest{
+		Address: receiver.Bytes(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), acc.GetBalance())
+	acc, err = client.GetAccountInfo(context.Background(), &pb.GetAccountInfoRequest{
+		Address: common.AddressFromChannelID("test").Bytes(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(13), acc.GetBalance())
+	assetBurnID = burnID
+	assetReceiverKey = receiverKey
+
 	//test Block Price
 	privKey, _ := crypto.NewPrivateKey(rawPrivKey, crypto.KeyAlgoSecp256k1)
 
@@ -506,12 +554,17 @@ func TestAsset(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	//change BlockPrice of test channel's
-
+	//change BlockPrice of test channel's, the admin is kept to redeem token
+	admin, err := core.NewMember(privKey.PubKey(), "admin")
+	require.NoError(t, err)
 	payload, err := json.Marshal(cc.Payload{
 		ChannelID: "test",
 		Profile: &cc.Profile{
-			BlockPrice: 100,
+			Public:          true,
+			Admins:          []*core.Member{admin},
+			AssetTokenRatio: 1,
+			MaxGas:          10000000,
+			BlockPrice:      100,
 		},
 	})
 	require.NoError(t, err)
@@ -524,6 +577,8 @@ func TestAsset(t *testing.T) {
 	require.NoError(t, err)
 
 	//now add tx that cause due
+	billingEvents, token := server.cc.RegisterBilling()
+	defer server.cc.UnregisterBilling(token)
 	coreTx, err = core.NewTx("test", common.ZeroAddress, []byte("cause due but pass"), 0, "", privKey)
 	require.NoError(t, err)
 	pbTx, err = pb.NewTx(coreTx)
@@ -543,12 +598,39 @@ func TestAsset(t *testing.T) {
 	})
 	require.Error(t, err)
 
+	billing, err := client.GetChannelBilling(context.Background(), &pb.GetChannelBillingRequest{
+		ChannelID: "test",
+	})
+	require.NoError(t, err)
+	require.True(t, billing.Halted)
+	require.Equal(t, uint64(0), billing.Balance)
+	require.Equal(t, uint64(100), billing.BlockPrice)
+	require.Len(t, billing.Records, 1)
+	require.Equal(t, uint64(13), billing.Records[0].Paid)
+	require.Equal(t, billing.Records[0].Fee, billing.Records[0].Paid+billing.Records[0].Due)
+	require.Equal(t, billing.Records[0].Due, billing.Due)
+	event := (<-billingEvents).(channel.BillingEvent)
+	require.Equal(t, channel.BillingEvent{ChannelID: "test", Halted: true, Due: billing.Due}, event)
+
 	//now issue money to channel account to wake it
 	pbTx = getAssetChannelTx(core.IssueContractAddress, common.ZeroAddress, "test", uint64(1000000), issuerKey)
 	_, err = client.AddTx(context.Background(), &pb.AddTxRequest{
 		Tx: pbTx,
 	})
 	require.NoError(t, err)
+	event = (<-billingEvents).(channel.BillingEvent)
+	require.Equal(t, channel.BillingEvent{ChannelID: "test"}, event)
+	billing, err = client.GetChannelBilling(context.Background(), &pb.GetChannelBillingRequest{
+		ChannelID: "test",
+	})
+	require.NoError(t, err)
+	require.False(t, billing.Halted)
+	require.Equal(t, uint64(0), billing.Due)
+
+	_, err = client.GetChannelBilling(context.Background(), &pb.GetChannelBillingRequest{
+		ChannelID: core.ASSETCHANNELID,
+	})
+	require.Error(t, err)
 
 	coreTx, err = core.NewTx("test", common.ZeroAddress, []byte("success again"), 0, "", privKey)
 	require.NoError(t, err)
@@ -562,11 +644,154 @@ func TestAsset(t *testing.T) {
 	server.Stop()
 }
 
+func TestRedeemAfterRestart(t *testing.T) {
+	var err error
+	server, err = NewServer(getTestConfig())
+	require.NoError(t, err)
+
+	go func() {
+		require.NoError(t, server.Start())
+	}()
+	time.Sleep(500 * time.Millisecond)
+
+	client, _ := getClient()
+	receiver, err := assetReceiverKey.PubKey().Address()
+	require.NoError(t, err)
+
+	//the burning tx redeemed before restart could not be redeemed again
+	redeem := asset.Payload{Address: receiver, ChannelID: "test", BurnID: assetBurnID}
+	pbTx := getAssetPayloadTx(core.TokenRedeemAddress, redeem, uint64(2), privKey)
+	_, err = client.AddTx(context.Background(), &pb.AddTxRequest{
+		Tx: pbTx,
+	})
+	require.NoError(t, err)
+	acc, err := client.GetAccountInfo(context.Background(), &pb.GetAccountInfoRequest{
+		Address: receiver.Bytes(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), acc.GetBalance())
+
+	//only 3 is left to redeem
+	for _, value := range []uint64{4, 3} {
+		pbTx, burnID := getBurnTx("test", value, assetReceiverKey)
+		_, err = client.AddTx(context.Background(), &pb.AddTxRequest{
+			Tx: pbTx,
+		})
+		require.NoError(t, err)
+		redeem = asset.Payload{Address: receiver, ChannelID: "test", BurnID: burnID}
+		pbTx = getAssetPayloadTx(core.TokenRedeemAddress, redeem, value, privKey)
+		_, err = client.AddTx(context.Background(), &pb.AddTxRequest{
+			Tx: pbTx,
+		})
+		require.NoError(t, err)
+	}
+	acc, err = client.GetAccountInfo(context.Background(), &pb.GetAccountInfoRequest{
+		Address: receiver.Bytes(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), acc.GetBalance())
+
+	server.Stop()
+}
+
+func TestBackup(t *testing.T) {
+	var err error
+	server, err = NewServer(getTestConfig())
+	require.NoError(t, err)
+
+	go func() {
+		require.NoError(t, server.Start())
+	}()
+	time.Sleep(500 * time.Millisecond)
+
+	// online
+	client, _ := getClient()
+	stream, err := client.Backup(context.Background(), &pb.BackupRequest{})
+	require.NoError(t, err)
+	var online bytes.Buffer
+	require.NoError(t, backup.Receive(&online, func() ([]byte, error) {
+		chunk, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		return chunk.Data, nil
+	}))
+	// the db is locked by the running orderer
+	require.Error(t, Export(getTestConfig(), ioutil.Discard))
+	server.Stop()
+
+	// offline
+	var offline bytes.Buffer
+	require.NoError(t, Export(getTestConfig(), &offline))
+
+	initTestEnvironment(".data1")
+	for i, data := range [][]byte{online.Bytes(), offline.Bytes()} {
+		file := fmt.Sprintf("%s/%d.backup", getTestBackupPath(), i)
+		require.NoError(t, os.MkdirAll(getTestBackupPath(), 0755))
+		require.NoError(t, ioutil.WriteFile(file, data, 0644))
+		cfg := getTestConfig()
+		cfg.BlockChain.Path = fmt.Sprintf("%s/%d/blocks", getTestBackupPath(), i)
+		cfg.DB.LevelDB.Path = fmt.Sprintf("%s/%d/leveldb", getTestBackupPath(), i)
+		manifest, err := Import(cfg, file)
+		require.NoError(t, err)
+		require.Equal(t, i == 0, manifest.Online)
+		require.NotZero(t, manifest.Heights["test"])
+		// the orderer is not empty now
+		_, err = Import(cfg, file)
+		require.Error(t, err)
+		// the restored orderer has the same channels
+		server, err = NewServer(cfg)
+		require.NoError(t, err)
+		go func() {
+			require.NoError(t, server.Start())
+		}()
+		time.Sleep(500 * time.Millisecond)
+		client, _ := getClient()
+		infos, err := client.ListChannels(context.Background(), &pb.ListChannelsRequest{
+			System: true,
+			PK:     pubKeyBytes,
+			Algo:   privKey.PubKey().Algo(),
+		})
+		require.NoError(t, err)
+		for _, info := range infos.Channels {
+			require.Equal(t, manifest.Heights[info.ChannelID], info.BlockSize)
+		}
+		server.Stop()
+	}
+}
+
+func TestReindex(t *testing.T) {
+	before := dumpTestDB(t)
+	// the db is lost
+	require.NoError(t, os.RemoveAll(getTestDBPath()))
+	var heights = make(map[string]uint64)
+	require.NoError(t, Reindex(getTestConfig(), func(channelID string, num, height uint64) {
+		require.Equal(t, heights[channelID], num)
+		heights[channelID] = num + 1
+	}))
+	require.NotZero(t, heights["test"])
+	require.Equal(t, before, dumpTestDB(t))
+}
+
 func TestEnd(t *testing.T) {
 	initTestEnvironment(".data")
 	initTestEnvironment(".data1")
 }
 
+// dumpTestDB return all the records in db
+func dumpTestDB(t *testing.T) map[string]string {
+	ldb, err := leveldb.OpenFile(getTestDBPath(), nil)
+	require.NoError(t, err)
+	defer ldb.Close()
+	var records = make(map[string]string)
+	iter := ldb.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		records[string(iter.Key())] = string(iter.Value())
+	}
+	return records
+}
+
 func getClient() (pb.OrdererClient, error) {
 	var conn *grpc.ClientConn
 	var err error
@@ -599,15 +824,26 @@ func getCreateChannelTx(channelID string) *pb.Tx {
 }
 
 func getAssetChannelTx(contract, addressInPayload common.Address, channelInPayload string, value uint64, privKey crypto.PrivateKey) *pb.Tx {
-	payload, _ := json.Marshal(asset.Payload{
+	return getAssetPayloadTx(contract, asset.Payload{
 		Address:   addressInPayload,
 		ChannelID: channelInPayload,
-	})
-	coreTx, _ := core.NewTx(core.ASSETCHANNELID, contract, payload, value, "", privKey)
+	}, value, privKey)
+}
+
+func getAssetPayloadTx(contract common.Address, payload asset.Payload, value uint64, privKey crypto.PrivateKey) *pb.Tx {
+	data, _ := json.Marshal(payload)
+	coreTx, _ := core.NewTx(core.ASSETCHANNELID, contract, data, value, "", privKey)
 	pbTx, _ := pb.NewTx(coreTx)
 	return pbTx
 }
 
+// getBurnTx return the tx burning token of value asset in channel and its id
+func getBurnTx(channelID string, value uint64, privKey crypto.PrivateKey) (*pb.Tx, string) {
+	coreTx, _ := core.NewTx(channelID, core.TokenBurnAddress, nil, value, "", privKey)
+	pbTx, _ := pb.NewTx(coreTx)
+	return pbTx, coreTx.ID
+}
+
 func getTestConfig() *config.Config {
 	cfg, _ := config.LoadConfig(getTestConfigFilePath())
 	cfg.BlockChain.Path = getTestChainPath()
@@ -627,6 +863,12 @@ func getTestDBPath() string {
 	return dbPath
 }
 
+func getTestBackupPath() string {
+	gopath := os.Getenv("GOPATH")
+	backupPath, _ := util.MakeFileAbs("src/madledger/orderer/server/.data1/backup", gopath)
+	return backupPath
+}
+
 func getTestConfigFilePath() string {
 	gopath := os.Getenv("GOPATH")
 	cfgFilePath, _ := util.MakeFileAbs("src/madledger/orderer/config/.orderer.yaml", gopath)
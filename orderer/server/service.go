@@ -12,12 +12,15 @@ package server
 
 import (
 	"errors"
+	ac "madledger/blockchain/asset"
 	"madledger/common"
 	"madledger/common/crypto"
 	"madledger/core"
+	"madledger/orderer/channel"
 	pb "madledger/protos"
 
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
 )
 
 // FetchBlock is the implementation of protos
@@ -43,7 +46,7 @@ func (s *Server) CreateChannel(ctx context.Context, req *pb.CreateChannelRequest
 	if !tx.Verify() {
 		return nil, errors.New("The tx is not a valid tx")
 	}
-	if tx.GetReceiver().String() != core.CreateChannelContractAddress.String() {
+	if txType, _ := core.GetTxType(tx.GetReceiver().String()); txType != core.CREATECHANNEL {
 		return nil, errors.New("The receiver of the tx is not the valid contract address")
 	}
 	_, err = s.cc.CreateChannel(tx)
@@ -64,7 +67,7 @@ func (s *Server) AddTx(ctx context.Context, req *pb.AddTxRequest) (*pb.TxStatus,
 	// get tx type according to recipient
 	txType, err := core.GetTxType(common.BytesToAddress(tx.Data.Recipient).String())
 	if err == nil && txType == core.CONSENSUS {
-		pk, err := crypto.NewPublicKey(req.Tx.Data.Sig.PK, req.Tx.Data.Sig.Algo)
+		pk, err := crypto.NewPublicKey(tx.Data.Sig.PK, tx.Data.Sig.Algo)
 		if err != nil {
 			return &status, err
 		}
@@ -90,6 +93,102 @@ func (s *Server) GetAccountInfo(ctx context.Context, req *pb.GetAccountInfoReque
 	if err != nil {
 		return &info, err
 	}
-	info.Balance = account.GetBalance()
-	return &info, err
+	info.AssetID = req.AssetID
+	info.Balance = account.GetAssetBalance(req.AssetID)
+	info.Frozen = account.Frozen
+	if req.AssetID != "" {
+		asset, err := s.cc.AM.GetAsset(req.AssetID)
+		if err != nil {
+			return &info, err
+		}
+		info.Decimals = uint32(asset.Decimals)
+		info.Cap = asset.Cap
+		info.Supply = asset.Supply
+	}
+	return &info, nil
+}
+
+// GetComplianceHistory is the implementation of protos
+func (s *Server) GetComplianceHistory(ctx context.Context, req *pb.GetComplianceHistoryRequest) (*pb.ComplianceHistory, error) {
+	records, err := s.cc.AM.GetComplianceHistory(common.BytesToAddress(req.Address))
+	if err != nil {
+		return &pb.ComplianceHistory{}, err
+	}
+	return newComplianceHistory(records), nil
+}
+
+func newComplianceHistory(records []ac.ComplianceRecord) *pb.ComplianceHistory {
+	var history pb.ComplianceHistory
+	for _, record := range records {
+		history.Records = append(history.Records, &pb.ComplianceRecord{
+			TxID:        record.TxID,
+			Action:      record.Action,
+			AssetID:     record.AssetID,
+			Value:       record.Value,
+			Reason:      record.Reason,
+			BlockNumber: record.BlockNumber,
+		})
+	}
+	return &history
+}
+
+// GetChannelBilling is the implementation of protos
+func (s *Server) GetChannelBilling(ctx context.Context, req *pb.GetChannelBillingRequest) (*pb.ChannelBilling, error) {
+	billing, err := s.cc.GetChannelBilling(req.ChannelID, int(req.Count))
+	if err != nil {
+		return &pb.ChannelBilling{}, err
+	}
+	return newChannelBilling(billing), nil
+}
+
+// WatchBilling is the implementation of protos
+func (s *Server) WatchBilling(req *pb.WatchBillingRequest, stream pb.Orderer_WatchBillingServer) error {
+	events, token := s.cc.RegisterBilling()
+	defer s.cc.UnregisterBilling(token)
+	// the header tells the watcher that the events after it will be sent
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case msg, ok := <-events:
+			if !ok {
+				return errors.New("The watcher falls behind the billing events")
+			}
+			event := msg.(channel.BillingEvent)
+			if req.ChannelID != "" && req.ChannelID != event.ChannelID {
+				continue
+			}
+			if err := stream.Send(&pb.BillingEvent{
+				ChannelID: event.ChannelID,
+				Halted:    event.Halted,
+				Due:       event.Due,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func newChannelBilling(billing *channel.Billing) *pb.ChannelBilling {
+	var pbBilling = pb.ChannelBilling{
+		ChannelID:  billing.ChannelID,
+		Balance:    billing.Balance,
+		Due:        billing.Due,
+		Halted:     billing.Halted,
+		BlockPrice: billing.BlockPrice,
+	}
+	for _, record := range billing.Records {
+		pbBilling.Records = append(pbBilling.Records, &pb.BillingRecord{
+			BlockNumber: record.BlockNumber,
+			Size:        record.Size,
+			Fee:         record.Fee,
+			Paid:        record.Paid,
+			Due:         record.Due,
+			Time:        record.Time,
+		})
+	}
+	return &pbBilling
 }
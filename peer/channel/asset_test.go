@@ -0,0 +1,118 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package channel
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	ac "madledger/blockchain/asset"
+	cc "madledger/blockchain/config"
+	"madledger/common"
+	"madledger/common/crypto"
+	"madledger/core"
+	"madledger/peer/db"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestBurnToken spends token as gas, then the token left is not enough to
+// burn for all the asset exchanged, the asset of token burned is redeemed by
+// the admin of channel
+func TestBurnToken(t *testing.T) {
+	dir, err := ioutil.TempDir("", "asset")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	ldb, err := db.NewLevelDB(dir)
+	require.NoError(t, err)
+
+	privKey, err := crypto.GeneratePrivateKey(crypto.KeyAlgoSecp256k1)
+	require.NoError(t, err)
+	sender, err := privKey.PubKey().Address()
+	require.NoError(t, err)
+	adminKey, err := crypto.GeneratePrivateKey(crypto.KeyAlgoSecp256k1)
+	require.NoError(t, err)
+	admin, err := core.NewMember(adminKey.PubKey(), "admin")
+	require.NoError(t, err)
+	require.NoError(t, ldb.UpdateChannel("test", &cc.Profile{
+		Admins:          []*core.Member{admin},
+		GasPrice:        1,
+		AssetTokenRatio: 100000,
+		MaxGas:          10000000,
+	}))
+	manager := &Manager{id: core.ASSETCHANNELID, db: ldb}
+	channel := &Manager{id: "test", db: ldb}
+	newTx := func(key crypto.PrivateKey, contract common.Address, payload ac.Payload, value uint64) *core.Tx {
+		data, _ := json.Marshal(payload)
+		tx, err := core.NewTx(core.ASSETCHANNELID, contract, data, value, "", key)
+		require.NoError(t, err)
+		return tx
+	}
+	runBlock := func(block *core.Block) {
+		wb, err := channel.RunBlock(block)
+		require.NoError(t, err)
+		require.NoError(t, wb.Sync())
+	}
+
+	// get 500000 token of test by exchanging 5
+	block := core.NewBlock(core.ASSETCHANNELID, 1, core.GenesisBlockPrevHash, []*core.Tx{
+		newTx(privKey, core.IssueContractAddress, ac.Payload{Address: sender}, 10),
+		newTx(privKey, core.TokenExchangeAddress, ac.Payload{ChannelID: "test"}, 5),
+	})
+	require.NoError(t, manager.AddAssetBlock(block))
+
+	// spend token as gas, so the token of 5 could not be burned
+	create, err := core.NewTxWithGas("test", common.ZeroAddress, payload, 0, "", 300000, privKey)
+	require.NoError(t, err)
+	spent, err := core.NewTx("test", core.TokenBurnAddress, nil, 5, "", privKey)
+	require.NoError(t, err)
+	burn, err := core.NewTx("test", core.TokenBurnAddress, nil, 2, "", privKey)
+	require.NoError(t, err)
+	runBlock(core.NewBlock("test", 1, nil, []*core.Tx{create, spent, burn}))
+	status, err := ldb.GetTxStatus("test", create.ID)
+	require.NoError(t, err)
+	require.NotZero(t, status.Tokens)
+	gasTokens := status.Tokens
+	status, err = ldb.GetTxStatus("test", spent.ID)
+	require.NoError(t, err)
+	require.Equal(t, db.TxFailed, status.Code)
+	status, err = ldb.GetTxStatus("test", burn.ID)
+	require.NoError(t, err)
+	require.Equal(t, db.TxSuccess, status.Code)
+	require.Equal(t, uint64(200000), status.Tokens)
+	cache := NewCache(ldb)
+	token, err := cache.GetToken("test", sender)
+	require.NoError(t, err)
+	require.Equal(t, 300000-gasTokens, token)
+
+	// only the admin redeems, and the burning tx is redeemed only once
+	redeem := ac.Payload{Address: sender, ChannelID: "test", BurnID: burn.ID}
+	txs := []*core.Tx{
+		newTx(privKey, core.TokenRedeemAddress, redeem, 2),
+		newTx(adminKey, core.TokenRedeemAddress, redeem, 2),
+		newTx(adminKey, core.TokenRedeemAddress, redeem, 2),
+	}
+	block = core.NewBlock(core.ASSETCHANNELID, 2, block.Hash().Bytes(), txs)
+	require.NoError(t, manager.AddAssetBlock(block))
+	for i, code := range []int{db.TxFailed, db.TxSuccess, db.TxFailed} {
+		status, err := ldb.GetTxStatus(core.ASSETCHANNELID, txs[i].ID)
+		require.NoError(t, err)
+		require.Equal(t, code, status.Code)
+	}
+	account, err := ldb.GetOrCreateAccount(sender)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), account.GetBalance())
+	account, err = ldb.GetOrCreateAccount(common.AddressFromChannelID("test"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), account.GetBalance())
+	require.Equal(t, uint64(0), account.GetDue())
+}
@@ -49,7 +49,7 @@ func TestRunBlock(t *testing.T) {
 	}
 	db, err := db.NewLevelDB(".benchmark")
 	require.NoError(t, err)
-	manager, err := NewManager("benchmark", ".benchmark", nil, db, nil, nil)
+	manager, err := NewManager("benchmark", ".benchmark", "", nil, db, nil, nil)
 	require.NoError(t, err)
 	var begin = time.Now()
 	for i := range blocks {
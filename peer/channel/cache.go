@@ -3,9 +3,9 @@ package channel
 import (
 	"encoding/binary"
 	"errors"
+	cc "madledger/blockchain/config"
 	"madledger/common"
 	"madledger/common/crypto"
-	"madledger/common/util"
 	"madledger/core"
 	"madledger/peer/db"
 	"reflect"
@@ -46,21 +46,22 @@ func (cache *Cache) Get(key []byte, couldBeEmpty bool) ([]byte, error) {
 
 // GetToken return token sender has of channel
 func (cache *Cache) GetToken(channelID string, sender common.Address) (uint64, error) {
-	tokenKey := util.BytesCombine([]byte(channelID), []byte("token"), sender.Bytes())
-	var tokenBytes []byte
-	if _, ok := cache.kvs[string(tokenKey)]; !ok {
-		tokenBytes, err := cache.db.Get(tokenKey, true)
+	return cache.getUint64(db.GetTokenKey(channelID, sender))
+}
+
+func (cache *Cache) getUint64(key []byte) (uint64, error) {
+	if _, ok := cache.kvs[string(key)]; !ok {
+		valBytes, err := cache.db.Get(key, true)
 		if err != nil {
 			return 0, err
 		}
-		if tokenBytes == nil {
-			tokenBytes = make([]byte, 8)
-			binary.BigEndian.PutUint64(tokenBytes, 0)
+		if valBytes == nil {
+			valBytes = make([]byte, 8)
+			binary.BigEndian.PutUint64(valBytes, 0)
 		}
-		cache.kvs[string(tokenKey)] = tokenBytes
+		cache.kvs[string(key)] = valBytes
 	}
-	tokenBytes = cache.kvs[string(tokenKey)]
-	return binary.BigEndian.Uint64(tokenBytes), nil
+	return binary.BigEndian.Uint64(cache.kvs[string(key)]), nil
 }
 
 // IsAssetAdmin decides whether a pk is the admin public key of _asset
@@ -82,6 +83,12 @@ func (cache *Cache) IsAssetAdmin(pk crypto.PublicKey, pkAlgo crypto.Algorithm) b
 // GetOrCreateAccount returns default account if not exist
 func (cache *Cache) GetOrCreateAccount(address common.Address) (common.Account, error) {
 	if account, ok := cache.accounts[address]; ok {
+		// copy assets so that a failed op won't pollute the cache
+		assets := make(map[string]uint64, len(account.Assets))
+		for id, balance := range account.Assets {
+			assets[id] = balance
+		}
+		account.Assets = assets
 		return account, nil
 	}
 	account, err := cache.db.GetOrCreateAccount(address)
@@ -96,6 +103,11 @@ func (cache *Cache) UpdateAccounts(accs ...common.Account) error {
 	return cache.wb.UpdateAccounts(accs...)
 }
 
+// GetChannelProfile return the profile of channel
+func (cache *Cache) GetChannelProfile(id string) (*cc.Profile, error) {
+	return cache.db.GetChannelProfile(id)
+}
+
 // SetAssetAdmin only works when it is first called
 func (cache *Cache) SetAssetAdmin(pk crypto.PublicKey, pkAlgo crypto.Algorithm) error {
 	if cache.adminPK != nil {
@@ -117,11 +129,13 @@ func (cache *Cache) SetTxStatus(tx *core.Tx, status *db.TxStatus) error {
 
 // SetToken set token to db
 func (cache *Cache) SetToken(channelID string, sender common.Address, token uint64) {
-	tokenKey := util.BytesCombine([]byte(channelID), []byte("token"), sender.Bytes())
-	tokenBytes := make([]byte, 8)
-	binary.BigEndian.PutUint64(tokenBytes, token)
-	cache.kvs[string(tokenKey)] = tokenBytes
-	cache.wb.Put(tokenKey, tokenBytes)
+	cache.putUint64(db.GetTokenKey(channelID, sender), token)
+}
+
+func (cache *Cache) putUint64(key []byte, value uint64) {
+	valBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(valBytes, value)
+	cache.Put(key, valBytes)
 }
 
 // PutBlock only used by addAssetBlock
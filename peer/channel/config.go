@@ -28,15 +28,21 @@ func (m *Manager) AddConfigBlock(block *core.Block) error {
 			BlockNumber: block.Header.Number,
 			BlockIndex:  i,
 			Output:      nil,
+			Code:        db.TxSuccess,
 		}
+		if sender, err := tx.GetSender(); err == nil {
+			status.Sender = sender.String()
+		}
+		txType, _ := core.GetTxType(tx.GetReceiver().String())
 		// this kind of tx will have different payload than regular _config tx
-		if tx.GetReceiver() == core.CfgConsensusAddress {
+		if txType == core.CONSENSUS {
 			wb.SetTxStatus(tx, status)
 			continue
 		}
 		payload, err := getConfigPayload(tx)
 		if err != nil {
 			status.Err = err.Error()
+			status.Code = db.TxFailed
 			wb.SetTxStatus(tx, status)
 			continue
 		}
@@ -46,7 +52,7 @@ func (m *Manager) AddConfigBlock(block *core.Block) error {
 		}
 
 		channelID := payload.ChannelID
-		if tx.GetReceiver().String() == core.CreateChannelContractAddress.String() {
+		if txType == core.CREATECHANNEL {
 
 			if payload.Profile.Public {
 				wb.AddChannel(channelID)
@@ -82,6 +88,7 @@ func (m *Manager) AddConfigBlock(block *core.Block) error {
 		err = m.db.UpdateChannel(channelID, payload.Profile)
 		if err != nil {
 			status.Err = err.Error()
+			status.Code = db.TxFailed
 		}
 		wb.SetTxStatus(tx, status)
 	}
@@ -22,6 +22,10 @@ type Coordinator struct {
 	lock   sync.Mutex
 	states map[string]*State
 	hub    *event.Hub
+
+	// commitLock is held by channels while committing blocks, and it is
+	// locked to freeze the blocks and db while backing up
+	commitLock sync.RWMutex
 }
 
 // StateCode represent the code of state
@@ -57,6 +61,12 @@ func NewCoordinator() *Coordinator {
 	return c
 }
 
+// Freeze stops channels committing blocks until unfreeze is called
+func (c *Coordinator) Freeze() (unfreeze func()) {
+	c.commitLock.Lock()
+	return c.commitLock.Unlock
+}
+
 // CanRun return runable
 func (c *Coordinator) CanRun(channelID string, num uint64) bool {
 	c.lock.Lock()
@@ -112,6 +122,20 @@ func (c *Coordinator) Unlocks(channelNums map[string][]uint64) {
 	}
 }
 
+// Unlocked return the largest block of each channel which is unlocked
+func (c *Coordinator) Unlocked() map[string]uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	var nums = make(map[string]uint64)
+	for channel, state := range c.states {
+		if state.code == Runable {
+			nums[channel] = state.num
+		}
+	}
+	return nums
+}
+
 // Update is the channel update info
 type Update struct {
 	ID     string
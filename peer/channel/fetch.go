@@ -0,0 +1,206 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package channel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"madledger/blockchain"
+	"madledger/common/util"
+	"madledger/core"
+	"madledger/peer/orderer"
+	"math"
+)
+
+// fetchResult is the result of fetching a block
+type fetchResult struct {
+	num   uint64
+	head  bool
+	block *core.Block
+	err   error
+}
+
+// fetcher fetches the blocks of channel ahead of the block running within a
+// window, and hands them out in order. The block expected is fetched from
+// all orderers and waits until it is ready, the blocks after it are fetched
+// from the orderers in turn and fail if they are not ready, so a channel far
+// behind catches up without waiting a round trip for each block. Blocks are
+// verified while fetching, and linked to the prev block when handed out.
+type fetcher struct {
+	m       *Manager
+	results chan *fetchResult
+	// stop is closed when the manager stops, so the fetching goroutines
+	// never block on results
+	stop chan bool
+	// fetching is the blocks being fetched
+	fetching map[uint64]bool
+	// blocks is the blocks fetched and verified
+	blocks map[uint64]*core.Block
+	// limit is the first block which was not ready when fetched ahead, the
+	// blocks from it are not fetched ahead until it is handed out
+	limit uint64
+	// lastNum and lastHash are of the last block handed out
+	lastNum  uint64
+	lastHash []byte
+}
+
+func newFetcher(m *Manager) *fetcher {
+	window := m.window
+	if window < 1 {
+		window = 1
+	}
+	return &fetcher{
+		m:        m,
+		results:  make(chan *fetchResult, window),
+		stop:     make(chan bool),
+		fetching: make(map[uint64]bool),
+		blocks:   make(map[uint64]*core.Block),
+		limit:    math.MaxUint64,
+	}
+}
+
+// next return the block expected by the chain
+func (f *fetcher) next() (*core.Block, error) {
+	for {
+		expect := f.m.cm.GetExpect()
+		for num := range f.blocks {
+			if num < expect {
+				delete(f.blocks, num)
+			}
+		}
+		if block := f.blocks[expect]; block != nil {
+			delete(f.blocks, expect)
+			if err := f.link(expect, block); err != nil {
+				// the blocks fetched ahead may be on the same wrong chain
+				f.blocks = make(map[uint64]*core.Block)
+				return nil, err
+			}
+			if expect >= f.limit {
+				f.limit = math.MaxUint64
+			}
+			return block, nil
+		}
+		f.fill(expect)
+		select {
+		case r := <-f.results:
+			delete(f.fetching, r.num)
+			if r.err != nil {
+				if r.head {
+					return nil, r.err
+				}
+				if r.num < f.limit {
+					f.limit = r.num
+				}
+			} else if r.num >= expect {
+				f.blocks[r.num] = r.block
+			}
+		case <-f.m.signalCh:
+			close(f.stop)
+			return nil, errors.New("Stop")
+		}
+	}
+}
+
+// fill fetches the blocks in the window which are not fetched yet
+func (f *fetcher) fill(expect uint64) {
+	if len(f.m.clients) == 0 {
+		return
+	}
+	if !f.fetching[expect] {
+		f.fetch(expect, true)
+	}
+	for num := expect + 1; num < expect+uint64(f.m.window) && num < f.limit; num++ {
+		if !f.fetching[num] && f.blocks[num] == nil {
+			f.fetch(num, false)
+		}
+	}
+}
+
+func (f *fetcher) fetch(num uint64, head bool) {
+	f.fetching[num] = true
+	go func() {
+		var block *core.Block
+		var err error
+		if head {
+			block, err = f.m.fetchBlock(num)
+		} else {
+			block, err = f.m.fetchBlockFrom(f.m.clients[num%uint64(len(f.m.clients))], num, false)
+		}
+		select {
+		case f.results <- &fetchResult{
+			num:   num,
+			head:  head,
+			block: block,
+			err:   err,
+		}:
+		case <-f.stop:
+		}
+	}()
+}
+
+// link checks if the block links to the last block of chain
+func (f *fetcher) link(num uint64, block *core.Block) error {
+	var prevHash = core.GenesisBlockPrevHash
+	if f.lastHash != nil && f.lastNum+1 == num {
+		prevHash = f.lastHash
+	} else if num != 0 {
+		hash, err := f.m.cm.GetLastHash()
+		if err != nil {
+			return err
+		}
+		prevHash = hash.Bytes()
+	}
+	if !bytes.Equal(block.Header.PrevBlock, prevHash) {
+		return fmt.Errorf("Block %d of channel %s links to %s rather than %s", num, f.m.id, util.Hex(block.Header.PrevBlock), util.Hex(prevHash))
+	}
+	f.lastNum = num
+	f.lastHash = block.Hash().Bytes()
+	return nil
+}
+
+// fetchBlock fetches block num from all orderers and waits until it is
+// ready, the first one verified is returned
+// todo: here we should support evil orderer
+func (m *Manager) fetchBlock(num uint64) (*core.Block, error) {
+	var results = make(chan *fetchResult, len(m.clients))
+	for i := range m.clients {
+		go func(client *orderer.Client) {
+			block, err := m.fetchBlockFrom(client, num, true)
+			results <- &fetchResult{
+				num:   num,
+				block: block,
+				err:   err,
+			}
+		}(m.clients[i])
+	}
+	var err = errors.New("There is no orderer")
+	for range m.clients {
+		r := <-results
+		if r.err == nil {
+			return r.block, nil
+		}
+		err = r.err
+	}
+	return nil, err
+}
+
+// fetchBlockFrom fetches block num from the orderer and verifies it
+func (m *Manager) fetchBlockFrom(client *orderer.Client, num uint64, async bool) (*core.Block, error) {
+	block, err := client.FetchBlock(m.id, num, async)
+	if err != nil {
+		return nil, err
+	}
+	if err := blockchain.VerifyBlock(m.id, num, block); err != nil {
+		return nil, fmt.Errorf("Block %d of channel %s is corrupted: %v", num, m.id, err)
+	}
+	return block, nil
+}
@@ -12,10 +12,13 @@ package channel
 
 import (
 	"errors"
+	"fmt"
 	"madledger/blockchain"
+	cc "madledger/blockchain/config"
 	"madledger/common"
 	"madledger/core"
 
+	"madledger/executor"
 	"madledger/executor/evm"
 	"madledger/peer/db"
 	"madledger/peer/orderer"
@@ -43,11 +46,16 @@ type Manager struct {
 	cm          *blockchain.Manager
 	clients     []*orderer.Client
 	coordinator *Coordinator
+	// parallel is the number of txs run at the same time in RunBlock
+	parallel int
+	// window is the number of blocks fetched ahead of the block running
+	window int
 }
 
-// NewManager is the constructor of Manager
-func NewManager(id, dir string, identity *core.Member, db db.DB, clients []*orderer.Client, coordinator *Coordinator) (*Manager, error) {
-	cm, err := blockchain.NewManager(id, dir)
+// NewManager is the constructor of Manager, compression is the compression
+// of blocks stored
+func NewManager(id, dir, compression string, identity *core.Member, db db.DB, clients []*orderer.Client, coordinator *Coordinator) (*Manager, error) {
+	cm, err := blockchain.NewManager(id, dir, compression)
 	if err != nil {
 		return nil, err
 	}
@@ -60,17 +68,36 @@ func NewManager(id, dir string, identity *core.Member, db db.DB, clients []*orde
 		cm:          cm,
 		clients:     clients,
 		coordinator: coordinator,
+		parallel:    runtime.NumCPU(),
+		window:      1,
 	}, nil
 }
 
+// SetParallel sets the number of txs run at the same time in RunBlock, txs
+// run one by one if n is less than 2
+func (m *Manager) SetParallel(n int) {
+	m.parallel = n
+}
+
+// SetWindow sets the number of blocks fetched ahead of the block running,
+// blocks are fetched one by one if n is less than 2. It should be called
+// before Start.
+func (m *Manager) SetWindow(n int) {
+	m.window = n
+}
+
 // Start start the manager.
 func (m *Manager) Start() {
 	log.Infof("channel %s is starting...", m.id)
+	fetcher := newFetcher(m)
 	for {
-		block, err := m.fetchBlock()
+		block, err := fetcher.next()
 		if err == nil {
 			// fmt.Println("Succeed to fetch block", m.id, ":", block.Header.Number)
-			m.AddBlock(block)
+			m.waitBlock(block)
+			m.coordinator.commitLock.RLock()
+			m.addBlock(block)
+			m.coordinator.commitLock.RUnlock()
 		} else if err.Error() == "Stop" {
 			m.stopCh <- true
 			return
@@ -87,35 +114,75 @@ func (m *Manager) Stop() {
 	<-m.stopCh
 }
 
+// GetBlock return the block of num
+func (m *Manager) GetBlock(num uint64) (*core.Block, error) {
+	return m.cm.GetBlock(num)
+}
+
+// GetBlockSize return the size of blocks
+func (m *Manager) GetBlockSize() uint64 {
+	return m.cm.GetExpect()
+}
+
+// GetBase return the first block stored
+func (m *Manager) GetBase() uint64 {
+	return m.cm.GetBase()
+}
+
+// GetLastHash return the hash of the last block
+func (m *Manager) GetLastHash() (common.Hash, error) {
+	return m.cm.GetLastHash()
+}
+
 // AddBlock add a block
 func (m *Manager) AddBlock(block *core.Block) error {
-	// add into the blockchain
-	err := m.cm.AddBlock(block)
+	m.waitBlock(block)
+	return m.addBlock(block)
+}
+
+// waitBlock waits until the block could be run, it should not be called
+// while holding the commit lock, otherwise _global may never unlock the block
+func (m *Manager) waitBlock(block *core.Block) {
+	switch block.Header.ChannelID {
+	case core.GLOBALCHANNELID:
+		return
+	case core.CONFIGCHANNELID, core.ASSETCHANNELID:
+		if isGenesisBlock(block) {
+			return
+		}
+	}
+	if !m.coordinator.CanRun(block.Header.ChannelID, block.Header.Number) {
+		m.coordinator.Watch(block.Header.ChannelID, block.Header.Number)
+	}
+}
+
+func (m *Manager) addBlock(block *core.Block) error {
+	// add into the blockchain, the block is stored already while reindexing
+	stored, err := m.cm.HasBlock(block)
 	if err != nil {
 		return err
 	}
+	if !stored {
+		if err := m.cm.AddBlock(block); err != nil {
+			return err
+		}
+	}
 	switch block.Header.ChannelID {
 	case core.GLOBALCHANNELID:
 		return m.AddGlobalBlock(block)
 	case core.CONFIGCHANNELID:
-		if !isGenesisBlock(block) && !m.coordinator.CanRun(block.Header.ChannelID, block.Header.Number) {
-			m.coordinator.Watch(block.Header.ChannelID, block.Header.Number)
-		}
 		return m.AddConfigBlock(block)
 	case core.ASSETCHANNELID:
-		if !isGenesisBlock(block) && !m.coordinator.CanRun(block.Header.ChannelID, block.Header.Number) {
-			m.coordinator.Watch(block.Header.ChannelID, block.Header.Number)
-		}
 		return m.AddAssetBlock(block)
 	default:
-		if !m.coordinator.CanRun(block.Header.ChannelID, block.Header.Number) {
-			m.coordinator.Watch(block.Header.ChannelID, block.Header.Number)
-		}
 		log.Infof("Run block %s: %d", m.id, block.Header.Number)
 		wb, err := m.RunBlock(block)
 		if err != nil {
 			return err
 		}
+		if _, err := wb.UpdateStateRoot(m.id, block.Header.Number); err != nil {
+			return err
+		}
 
 		wb.PutBlock(block)
 		return wb.Sync()
@@ -129,55 +196,54 @@ func isGenesisBlock(block *core.Block) bool {
 // RunBlock will carry out all txs in the block.
 // It will return after the block is runned.
 // In the future, this will contains chains which rely on something or nothing
+//
+// Txs run speculatively in parallel on their own TxContext first, then they
+// are committed in order, and a tx whose reads are changed by the txs before
+// it runs again, so the result is the same as running them one by one.
 func (m *Manager) RunBlock(block *core.Block) (db.WriteBatch, error) {
 	cache := NewCache(m.db)
 	context := evm.NewContext(block, cache.db, cache.wb)
 	defer context.BlockFinalize()
-	// first parallel get sender to speed up
-	threadSize := runtime.NumCPU()
-	if threadSize < 2 {
-		threadSize = 2
-	}
-	var ch = make(chan bool, threadSize)
-	var wg sync.WaitGroup
-	for i := range block.Transactions {
-		wg.Add(1)
-		tx := block.Transactions[i]
-		ch <- true
-		go func() {
-			defer func() {
-				<-ch
-				wg.Done()
-			}()
-			tx.GetSender()
-		}()
-	}
-	wg.Wait()
 
 	profile, err := m.db.GetChannelProfile(m.id)
 	if err != nil {
 		return nil, err
 	}
+	context.SetPrecompileGas(profile.PrecompileGas)
+
+	var results = make([]*txResult, len(block.Transactions))
+	if m.parallel > 1 {
+		var ch = make(chan bool, m.parallel)
+		var wg sync.WaitGroup
+		for i := range block.Transactions {
+			wg.Add(1)
+			ch <- true
+			go func(i int) {
+				defer func() {
+					<-ch
+					wg.Done()
+				}()
+				result := m.prepareTx(block, i, profile)
+				if result.run && !result.burn {
+					m.runTx(context, block.Transactions[i], result)
+				}
+				results[i] = result
+			}(i)
+		}
+		wg.Wait()
+	}
 
 	for i, tx := range block.Transactions {
-		senderAddress, err := tx.GetSender()
-		status := &db.TxStatus{
-			Err:         "",
-			BlockNumber: block.Header.Number,
-			BlockIndex:  i,
-			Output:      nil,
+		result := results[i]
+		if result == nil {
+			result = m.prepareTx(block, i, profile)
 		}
-		if err != nil {
-			status.Err = err.Error()
-			cache.SetTxStatus(tx, status)
+		if !result.run {
+			cache.SetTxStatus(tx, result.status)
 			continue
 		}
-		receiverAddress := tx.GetReceiver()
-
-		sender, err := m.db.GetAccount(senderAddress)
-		if err != nil {
-			status.Err = err.Error()
-			cache.SetTxStatus(tx, status)
+		if result.burn {
+			cache.SetTxStatus(tx, m.burnToken(&cache, result, tx.Data.Value, profile))
 			continue
 		}
 
@@ -188,108 +254,278 @@ func (m *Manager) RunBlock(block *core.Block) (db.WriteBatch, error) {
 		// 记录进入evm前的gas limit
 		// 用出来之后用前减后可得到具体消耗了多少gas
 		// 然后将token -= gas * gas price，存到cache中
+		tokenLeft, err := cache.GetToken(m.id, result.sender.GetAddress())
+		if err != nil {
+			cache.SetTxStatus(tx, result.fail(err.Error()))
+			continue
+		}
+		if tokenLeft < result.gasLimit*profile.GasPrice {
+			cache.SetTxStatus(tx, result.fail("Not enough token"))
+			continue
+		}
 
-		gasLimit := profile.MaxGas
-		if gasLimit > tx.Data.Gas {
-			gasLimit = tx.Data.Gas
+		if result.ctx == nil || result.ctx.Conflict() {
+			m.runTx(context, tx, result)
 		}
-		tokenLeft, err := cache.GetToken(m.id, senderAddress)
+		if result.ctx == nil {
+			cache.SetTxStatus(tx, result.status)
+			continue
+		}
+		result.status.Tokens = result.status.GasUsed * profile.GasPrice
+		cache.SetTxStatus(tx, result.status)
+		result.ctx.Commit()
+		tokenLeft -= result.status.Tokens
+		cache.SetToken(m.id, result.sender.GetAddress(), tokenLeft)
+	}
+	return cache.wb, nil
+}
+
+// txResult is the result of a tx in RunBlock
+type txResult struct {
+	status *db.TxStatus
+	// run is false if tx fails before checking the token of sender
+	run bool
+	// burn is true if tx burns token instead of running in evm
+	burn     bool
+	sender   *common.Account
+	gasLimit uint64
+	// ctx holds the changes of tx, it is nil if tx does not run in evm
+	ctx *evm.TxContext
+}
+
+// fail drops the result of running tx if it has run, and return the status
+// with err
+func (r *txResult) fail(err string) *db.TxStatus {
+	r.status = &db.TxStatus{
+		Err:         err,
+		BlockNumber: r.status.BlockNumber,
+		BlockIndex:  r.status.BlockIndex,
+		Code:        db.TxFailed,
+		Sender:      r.status.Sender,
+		GasLimit:    r.gasLimit,
+	}
+	return r.status
+}
+
+// prepareTx checks the tx before checking the token of sender
+func (m *Manager) prepareTx(block *core.Block, i int, profile *cc.Profile) *txResult {
+	tx := block.Transactions[i]
+	result := &txResult{
+		status: &db.TxStatus{
+			Err:         "",
+			BlockNumber: block.Header.Number,
+			BlockIndex:  i,
+			Output:      nil,
+			Code:        db.TxFailed,
+		},
+	}
+	senderAddress, err := tx.GetSender()
+	if err != nil {
+		result.status.Err = err.Error()
+		return result
+	}
+	result.status.Sender = senderAddress.String()
+	sender, err := m.db.GetAccount(senderAddress)
+	if err != nil {
+		result.status.Err = err.Error()
+		return result
+	}
+	result.run = true
+	result.burn = tx.GetReceiver() == core.TokenBurnAddress
+	result.sender = sender
+	result.gasLimit = profile.MaxGas
+	if result.gasLimit > tx.Data.Gas {
+		result.gasLimit = tx.Data.Gas
+	}
+	result.status.GasLimit = result.gasLimit
+	return result
+}
+
+// burnToken burns the token of sender that value of asset is exchanged for,
+// so that an admin of channel could redeem the asset with the id of tx
+func (m *Manager) burnToken(cache *Cache, result *txResult, value uint64, profile *cc.Profile) *db.TxStatus {
+	tokenLeft, err := cache.GetToken(m.id, result.sender.GetAddress())
+	if err != nil {
+		return result.fail(err.Error())
+	}
+	burned := profile.AssetTokenRatio * value
+	if profile.AssetTokenRatio != 0 && burned/profile.AssetTokenRatio != value {
+		return result.fail("Overflow")
+	}
+	if tokenLeft < burned {
+		return result.fail(fmt.Sprintf("token %d is less than %d", tokenLeft, burned))
+	}
+	cache.SetToken(m.id, result.sender.GetAddress(), tokenLeft-burned)
+	result.status.Code = db.TxSuccess
+	result.status.Tokens = burned
+	return result.status
+}
+
+// runTx runs the tx in evm on a new TxContext of block
+func (m *Manager) runTx(context *evm.DefaultContext, tx *core.Tx, result *txResult) {
+	status := &db.TxStatus{
+		Err:         "",
+		BlockNumber: result.status.BlockNumber,
+		BlockIndex:  result.status.BlockIndex,
+		Output:      nil,
+		Code:        db.TxFailed,
+		Sender:      result.status.Sender,
+		GasLimit:    result.gasLimit,
+	}
+	result.status = status
+	result.ctx = nil
+	ctx := evm.NewTxContext(context)
+	if execTx(ctx, m.db, tx, result.sender, result.gasLimit, status, nil) {
+		result.ctx = ctx
+	}
+}
+
+// execTx runs tx from sender in evm on ctx and fills status, the receiver
+// is read from state. The ops are recorded by tracer if it is not nil. It
+// return false if tx fails before running in evm.
+func execTx(ctx *evm.TxContext, state db.DB, tx *core.Tx, sender *common.Account, gasLimit uint64, status *db.TxStatus, tracer *evm.Tracer) bool {
+	vm := executor.New(ctx, sender.GetAddress(), tx.Data.Payload, tx.Data.Value, gasLimit, state, nil)
+	if tracer != nil {
+		vm.SetTracer(tracer)
+	}
+
+	receiverAddress := tx.GetReceiver()
+	if receiverAddress.String() != common.ZeroAddress.String() {
+		// if the length of payload is not zero, this is a contract call
+		if len(tx.Data.Payload) != 0 && !state.AccountExist(receiverAddress) && !evm.IsNative(receiverAddress) {
+			status.Err = "Invalid Address"
+			return false
+		}
+
+		receiver, err := state.GetAccount(receiverAddress)
 		if err != nil {
 			status.Err = err.Error()
-			cache.SetTxStatus(tx, status)
-			continue
+			return false
 		}
-		if tokenLeft < gasLimit*profile.GasPrice {
-			status.Err = "Not enough token"
-			cache.SetTxStatus(tx, status)
-			continue
+		output, err := vm.Call(sender, receiver, receiver.GetCode())
+		status.Output = output
+		if err != nil {
+			status.Err = err.Error()
+		}
+	} else {
+		output, addr, err := vm.Create(sender)
+		status.Output = output
+		status.ContractAddress = addr.String()
+		if err != nil {
+			status.Err = err.Error()
 		}
+	}
+	status.Code = db.TxSuccess
+	if status.Err != "" {
+		status.Code = db.TxReverted
+	}
+	status.Logs = ctx.Logs()
+	status.GasUsed = gasLimit - *ctx.BlockContext().Gas
+	return true
+}
 
-		evm := evm.NewEVM(context, senderAddress, tx.Data.Payload, tx.Data.Value, gasLimit, cache.db, cache.wb)
-
-		if receiverAddress.String() != common.ZeroAddress.String() {
-			// if the length of payload is not zero, this is a contract call
-			if len(tx.Data.Payload) != 0 && !m.db.AccountExist(receiverAddress) {
-				status.Err = "Invalid Address"
-				cache.SetTxStatus(tx, status)
-				continue
-			}
-
-			receiver, err := m.db.GetAccount(receiverAddress)
-			if err != nil {
-				status.Err = err.Error()
-				cache.SetTxStatus(tx, status)
-				continue
-			}
-			output, err := evm.Call(sender, receiver, receiver.GetCode())
-			status.Output = output
-			if err != nil {
-				status.Err = err.Error()
-			}
-			cache.SetTxStatus(tx, status)
+// Call calls the contract on the state after block num without a tx, the
+// changes of state are dropped. It return the output and the gas used.
+func (m *Manager) Call(num uint64, caller, callee common.Address, payload []byte, gas uint64) ([]byte, uint64, error) {
+	state, block, profile, err := m.stateAt(num)
+	if err != nil {
+		return nil, 0, err
+	}
+	gasLimit := profile.MaxGas
+	if gas != 0 && gasLimit > gas {
+		gasLimit = gas
+	}
+	if !state.AccountExist(callee) && !evm.IsNative(callee) {
+		return nil, 0, errors.New("Invalid Address")
+	}
+	return dryRun(state, block, profile, caller, callee, payload, 0, gasLimit)
+}
+
+// GasEstimate is the least gas limit a tx succeeds with
+type GasEstimate struct {
+	Gas     uint64
+	GasUsed uint64
+	// Tokens is the tokens charged for GasUsed under the gas price
+	Tokens uint64
+}
+
+// EstimateGas dry-runs a tx on the state after block num, and searches the
+// least gas limit that the tx succeeds with by binary search. The receiver
+// is the zero address if the tx creates a contract.
+func (m *Manager) EstimateGas(num uint64, caller, receiver common.Address, payload []byte, value uint64) (*GasEstimate, error) {
+	state, block, profile, err := m.stateAt(num)
+	if err != nil {
+		return nil, err
+	}
+	if receiver != common.ZeroAddress && len(payload) != 0 && !state.AccountExist(receiver) && !evm.IsNative(receiver) {
+		return nil, errors.New("Invalid Address")
+	}
+	// the tx never succeeds if it fails with the max gas of channel
+	_, gasUsed, err := dryRun(state, block, profile, caller, receiver, payload, value, profile.MaxGas)
+	if err != nil {
+		return nil, err
+	}
+	// the tx fails with lo and succeeds with hi
+	var lo, hi = uint64(0), profile.MaxGas
+	if gasUsed > 0 {
+		lo = gasUsed - 1
+	}
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+		_, used, err := dryRun(state, block, profile, caller, receiver, payload, value, mid)
+		if err != nil {
+			lo = mid
 		} else {
-			output, addr, err := evm.Create(sender)
-			status.Output = output
-			status.ContractAddress = addr.String()
-			if err != nil {
-				status.Err = err.Error()
-			}
-			cache.SetTxStatus(tx, status)
+			hi, gasUsed = mid, used
 		}
-		gasUsed := gasLimit - *context.BlockContext().Gas
-		tokenLeft -= gasUsed * profile.GasPrice
-		cache.SetToken(m.id, senderAddress, tokenLeft)
 	}
-	return cache.wb, nil
+	return &GasEstimate{
+		Gas:     hi,
+		GasUsed: gasUsed,
+		Tokens:  gasUsed * profile.GasPrice,
+	}, nil
 }
 
-// todo: here we should support evil orderer
-func (m *Manager) fetchBlock() (*core.Block, error) {
-	var lock sync.RWMutex
-	var errs = make(chan error, len(m.clients))
-	var blocks = make(chan *core.Block, len(m.clients))
-	closed := false
-	id := m.id
-	expect := m.cm.GetExpect()
-	for i := range m.clients {
-		go func(i int) {
-			block, err := m.clients[i].FetchBlock(id, expect, true)
-			lock.RLock()
-			defer lock.RUnlock()
-			if closed {
-				return
-			}
-			if err != nil {
-				errs <- err
-			} else {
-				blocks <- block
-			}
-		}(i)
-	}
-
-	fails := 0
+// stateAt return the state after block num, the block and the profile of
+// channel
+func (m *Manager) stateAt(num uint64) (db.DB, *core.Block, *cc.Profile, error) {
+	state, err := db.StateAt(m.db, m.id, num)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	block, err := m.cm.GetBlock(num)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	profile, err := m.db.GetChannelProfile(m.id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return state, block, profile, nil
+}
 
-	for {
-		defer func() {
-			lock.Lock()
-			if !closed {
-				close(errs)
-				close(blocks)
-				closed = true
-			}
-			lock.Unlock()
-		}()
-		// log.Infof("get %s %d, closed: %t, fail: %d, %d", id, except, closed, fails, len(m.clients))
-		select {
-		case block := <-blocks:
-			return block, nil
-		case err := <-errs:
-			fails++
-			if fails == len(m.clients) {
-				return nil, err
-			}
-		case <-m.signalCh:
-			return nil, errors.New("Stop")
+// dryRun runs payload from caller in the context of block on state, and
+// drops the changes. The contract is created if receiver is the zero
+// address. It return the output and the gas used.
+func dryRun(state db.DB, block *core.Block, profile *cc.Profile, caller, receiver common.Address, payload []byte, value, gas uint64) ([]byte, uint64, error) {
+	sender, err := state.GetAccount(caller)
+	if err != nil {
+		return nil, 0, err
+	}
+	wb := state.NewWriteBatch()
+	context := evm.NewContext(block, state, wb)
+	context.SetPrecompileGas(profile.PrecompileGas)
+	vm := executor.New(context, caller, payload, value, gas, state, wb)
+	var output []byte
+	if receiver == common.ZeroAddress {
+		output, _, err = vm.Create(sender)
+	} else {
+		var account *common.Account
+		account, err = state.GetAccount(receiver)
+		if err != nil {
+			return nil, 0, err
 		}
+		output, err = vm.Call(sender, account, account.GetCode())
 	}
+	return output, gas - *context.BlockContext().Gas, err
 }
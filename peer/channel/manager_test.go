@@ -56,9 +56,9 @@ var (
 	leveldb, _       = db.NewLevelDB(".data/leveldb")
 	cfg, _           = getPeerConfig()
 	client, _        = orderer.NewClient("localhost:9999", cfg)
-	globalManager, _ = NewManager(core.GLOBALCHANNELID, ".data/blocks/"+core.GLOBALCHANNELID, nil, leveldb, []*orderer.Client{client}, coordinator)
-	configManager, _ = NewManager(core.CONFIGCHANNELID, ".data/blocks/"+core.CONFIGCHANNELID, nil, leveldb, []*orderer.Client{client}, coordinator)
-	testManager, _   = NewManager("test", ".data/blocks/test", nil, leveldb, []*orderer.Client{client}, coordinator)
+	globalManager, _ = NewManager(core.GLOBALCHANNELID, ".data/blocks/"+core.GLOBALCHANNELID, "", nil, leveldb, []*orderer.Client{client}, coordinator)
+	configManager, _ = NewManager(core.CONFIGCHANNELID, ".data/blocks/"+core.CONFIGCHANNELID, "", nil, leveldb, []*orderer.Client{client}, coordinator)
+	testManager, _   = NewManager("test", ".data/blocks/test", "", nil, leveldb, []*orderer.Client{client}, coordinator)
 	globalBlocks     = make(map[int]*core.Block)
 	configBlocks     = make(map[int]*core.Block)
 	testBlocks       = make(map[int]*core.Block)
@@ -217,6 +217,22 @@ func (o *fakeOrderer) GetAccountInfo(ctx context.Context, req *pb.GetAccountInfo
 	return nil, nil
 }
 
+func (o *fakeOrderer) GetComplianceHistory(ctx context.Context, req *pb.GetComplianceHistoryRequest) (*pb.ComplianceHistory, error) {
+	return nil, nil
+}
+
+func (o *fakeOrderer) GetChannelBilling(ctx context.Context, req *pb.GetChannelBillingRequest) (*pb.ChannelBilling, error) {
+	return nil, nil
+}
+
+func (o *fakeOrderer) WatchBilling(req *pb.WatchBillingRequest, stream pb.Orderer_WatchBillingServer) error {
+	return nil
+}
+
+func (o *fakeOrderer) Backup(req *pb.BackupRequest, stream pb.Orderer_BackupServer) error {
+	return nil
+}
+
 func (o *fakeOrderer) GetTxStatus(ctx context.Context, req *pb.GetTxStatusRequest) (*pb.TxStatus, error) {
 	return nil, nil
 }
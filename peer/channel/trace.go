@@ -0,0 +1,127 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package channel
+
+import (
+	"errors"
+	"fmt"
+	cc "madledger/blockchain/config"
+	"madledger/core"
+	"madledger/executor/evm"
+	"madledger/peer/db"
+)
+
+// TxTrace is the trace of a tx which runs again in the context of its block
+type TxTrace struct {
+	// Status is the status of the tx when it runs again
+	Status  *db.TxStatus
+	Steps   []*evm.Step
+	Call    *evm.Call
+	Storage []*evm.StorageChange
+}
+
+// TraceTx runs the tx again with a tracer on the state before it. The txs
+// before it in the block run first on the state before the block, with the
+// gas limits in their receipts, and the changes are dropped at last.
+func (m *Manager) TraceTx(txID string, config evm.TraceConfig) (*TxTrace, error) {
+	status, err := m.db.GetTxStatus(m.id, txID)
+	if err != nil {
+		return nil, err
+	}
+	if status.Code == db.TxFailed {
+		return nil, fmt.Errorf("The tx %s did not run in evm: %s", txID, status.Err)
+	}
+	if status.BlockNumber == 0 {
+		return nil, errors.New("The txs in genesis block could not be traced")
+	}
+	block, err := m.cm.GetBlock(status.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if status.BlockIndex >= len(block.Transactions) || block.Transactions[status.BlockIndex].ID != txID {
+		return nil, fmt.Errorf("The tx %s is not in block %d", txID, status.BlockNumber)
+	}
+	profile, err := m.db.GetChannelProfile(m.id)
+	if err != nil {
+		return nil, err
+	}
+	state, err := db.StateAt(m.db, m.id, status.BlockNumber-1)
+	if err != nil {
+		return nil, err
+	}
+	context := evm.NewContext(block, state, state.NewWriteBatch())
+	context.SetPrecompileGas(profile.PrecompileGas)
+	for _, tx := range block.Transactions[:status.BlockIndex] {
+		prev, err := m.db.GetTxStatus(m.id, tx.ID)
+		if err != nil {
+			return nil, err
+		}
+		// the receipts before the code is recorded have no code, and the
+		// reverted txs change nothing
+		if prev.Code == db.TxFailed || (prev.Code == db.TxUnknown && prev.Err != "") {
+			continue
+		}
+		ctx := evm.NewTxContext(context)
+		if _, err := replayTx(ctx, state, tx, traceGasLimit(prev, tx, profile), nil); err != nil {
+			return nil, err
+		}
+		ctx.Commit()
+	}
+
+	tx := block.Transactions[status.BlockIndex]
+	ctx := evm.NewTxContext(context)
+	tracer := evm.NewTracer(config)
+	replayed, err := replayTx(ctx, state, tx, traceGasLimit(status, tx, profile), tracer)
+	if err != nil {
+		return nil, err
+	}
+	if tracer.Err != nil {
+		return nil, tracer.Err
+	}
+	replayed.BlockNumber, replayed.BlockIndex = status.BlockNumber, status.BlockIndex
+	return &TxTrace{
+		Status:  replayed,
+		Steps:   tracer.Steps,
+		Call:    tracer.Call,
+		Storage: ctx.StorageChanges(),
+	}, nil
+}
+
+// replayTx runs tx on ctx again and return the status
+func replayTx(ctx *evm.TxContext, state db.DB, tx *core.Tx, gasLimit uint64, tracer *evm.Tracer) (*db.TxStatus, error) {
+	senderAddress, err := tx.GetSender()
+	if err != nil {
+		return nil, err
+	}
+	sender, err := state.GetAccount(senderAddress)
+	if err != nil {
+		return nil, err
+	}
+	status := &db.TxStatus{
+		Code:     db.TxFailed,
+		Sender:   senderAddress.String(),
+		GasLimit: gasLimit,
+	}
+	execTx(ctx, state, tx, sender, gasLimit, status, tracer)
+	return status, nil
+}
+
+// traceGasLimit return the gas limit of tx in status, or the gas limit under
+// profile if status does not record it
+func traceGasLimit(status *db.TxStatus, tx *core.Tx, profile *cc.Profile) uint64 {
+	if status.GasLimit != 0 {
+		return status.GasLimit
+	}
+	if profile.MaxGas > tx.Data.Gas {
+		return tx.Data.Gas
+	}
+	return profile.MaxGas
+}
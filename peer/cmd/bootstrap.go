@@ -0,0 +1,132 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"madledger/common/backup"
+	"madledger/common/crypto"
+	"madledger/common/util"
+	"madledger/peer/config"
+	"madledger/peer/server"
+	"madledger/peer/snapshot"
+	pb "madledger/protos"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	bootstrapCmd = &cobra.Command{
+		Use: "bootstrap",
+	}
+	bootstrapViper = viper.New()
+)
+
+func init() {
+	bootstrapCmd.RunE = runBootstrap
+	bootstrapCmd.Flags().StringP("config", "c", "peer.yaml", "The config file of blockchain")
+	bootstrapViper.BindPFlag("config", bootstrapCmd.Flags().Lookup("config"))
+	bootstrapCmd.Flags().StringP("input", "i", "", "The snapshot file")
+	bootstrapViper.BindPFlag("input", bootstrapCmd.Flags().Lookup("input"))
+	bootstrapCmd.Flags().StringP("peer", "p", "", "The address of peer to fetch the snapshot from")
+	bootstrapViper.BindPFlag("peer", bootstrapCmd.Flags().Lookup("peer"))
+	rootCmd.AddCommand(bootstrapCmd)
+}
+
+func runBootstrap(cmd *cobra.Command, args []string) error {
+	cfgFile := bootstrapViper.GetString("config")
+	if cfgFile == "" {
+		return errors.New("Please provide the config file")
+	}
+	input := bootstrapViper.GetString("input")
+	address := bootstrapViper.GetString("peer")
+	if (input == "") == (address == "") {
+		return errors.New("Please provide either the snapshot file or the peer")
+	}
+	cfgAbsPath, err := util.MakeFileAbs(cfgFile, homeDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadConfig(cfgAbsPath)
+	if err != nil {
+		return err
+	}
+	setLog(cfg.Debug)
+
+	if address != "" {
+		if input, err = fetchSnapshot(cfg, address); err != nil {
+			return err
+		}
+		defer os.Remove(input)
+	}
+	manifest, err := server.Bootstrap(cfg, input)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Bootstrap from snapshot %s\n", manifest.Hash)
+	var channels []string
+	for id := range manifest.Channels {
+		channels = append(channels, id)
+	}
+	sort.Strings(channels)
+	for _, id := range channels {
+		fmt.Printf("channel %s: %d blocks\n", id, manifest.Channels[id].Height)
+	}
+	return nil
+}
+
+// fetchSnapshot receives the latest snapshot of peer into a temp file
+func fetchSnapshot(cfg *config.Config, address string) (string, error) {
+	conn, err := server.Dial(cfg, address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	// the request is signed by the key of peer, so only the channels the peer
+	// belongs to are sent
+	key, err := crypto.LoadPrivateKeyFromFile(cfg.KeyStore.Key)
+	if err != nil {
+		return "", err
+	}
+	req, err := server.NewFetchSnapshotRequest(key)
+	if err != nil {
+		return "", err
+	}
+	stream, err := pb.NewPeerClient(conn).FetchSnapshot(context.Background(), req)
+	if err != nil {
+		return "", err
+	}
+	f, err := ioutil.TempFile("", "peer-*"+snapshot.Ext)
+	if err != nil {
+		return "", err
+	}
+	err = backup.Receive(f, func() ([]byte, error) {
+		chunk, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		return chunk.Data, nil
+	})
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
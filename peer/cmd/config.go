@@ -41,11 +41,17 @@ BlockChain:
   # default: $GOPATH/src/madledger/peer/data/blocks
   # But in the production environment, you must provide a path
   Path: 
+  # Compression of blocks stored, none or snappy (default: none)
+  Compression: none
 
 # Address of orderers
 Orderer:
   Address:
     - localhost:12345
+  # Compression asked for when fetching blocks, none or snappy (default: none)
+  Compression: none
+  # Number of blocks fetched ahead of the block running, 1 disables it (default: 16)
+  Window: 16
 
 # DB only support leveldb now
 DB:
@@ -59,5 +65,22 @@ DB:
 # KeyStore manage some private keys
 KeyStore:
   Key: <<<KEYFILE>>>
+
+# Configure for the state snapshots
+Snapshot:
+  # Take a snapshot every Interval blocks of _global, 0 disables it (default: 0)
+  Interval: 0
+  # default: $GOPATH/src/madledger/peer/data/snapshots
+  # But in the production environment, you must provide a path if Interval is not 0
+  Path: 
+  # The number of latest snapshots kept (default: 2)
+  Keep: 2
+
+# Compare the state roots of channels with other peers, a mismatch is logged as error
+StateRoot:
+  # The addresses of peers, empty disables it
+  Peers: []
+  # Compare every Interval seconds (default: 10)
+  Interval: 10
 `
 )
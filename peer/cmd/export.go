@@ -0,0 +1,126 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"madledger/common/backup"
+	"madledger/common/util"
+	"madledger/peer/config"
+	"madledger/peer/server"
+	pb "madledger/protos"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	exportCmd = &cobra.Command{
+		Use: "export",
+	}
+	exportViper = viper.New()
+)
+
+func init() {
+	exportCmd.RunE = runExport
+	exportCmd.Flags().StringP("config", "c", "peer.yaml", "The config file of blockchain")
+	exportViper.BindPFlag("config", exportCmd.Flags().Lookup("config"))
+	exportCmd.Flags().StringP("output", "o", "peer.backup", "The file of backup")
+	exportViper.BindPFlag("output", exportCmd.Flags().Lookup("output"))
+	exportCmd.Flags().Bool("online", false, "Export from the running peer")
+	exportViper.BindPFlag("online", exportCmd.Flags().Lookup("online"))
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	cfgFile := exportViper.GetString("config")
+	if cfgFile == "" {
+		return errors.New("Please provide the config file")
+	}
+	output := exportViper.GetString("output")
+	if output == "" {
+		return errors.New("Please provide the output file")
+	}
+	cfgAbsPath, err := util.MakeFileAbs(cfgFile, homeDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadConfig(cfgAbsPath)
+	if err != nil {
+		return err
+	}
+	setLog(cfg.Debug)
+
+	tmp := output + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	if exportViper.GetBool("online") {
+		err = exportOnline(cfg, f)
+	} else {
+		err = server.Export(cfg, f)
+	}
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	manifest, err := backup.Verify(tmp)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, output); err != nil {
+		return err
+	}
+	fmt.Printf("Export to %s\n", output)
+	printHeights(manifest)
+	return nil
+}
+
+// exportOnline receives the backup from the peer running on this machine
+func exportOnline(cfg *config.Config, w io.Writer) error {
+	conn, err := server.Dial(cfg, fmt.Sprintf("localhost:%d", cfg.Port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stream, err := pb.NewPeerClient(conn).Backup(context.Background(), &pb.BackupRequest{})
+	if err != nil {
+		return err
+	}
+	return backup.Receive(w, func() ([]byte, error) {
+		chunk, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		return chunk.Data, nil
+	})
+}
+
+func printHeights(manifest *backup.Manifest) {
+	var channels []string
+	for id := range manifest.Heights {
+		channels = append(channels, id)
+	}
+	sort.Strings(channels)
+	for _, id := range channels {
+		fmt.Printf("channel %s: %d blocks\n", id, manifest.Heights[id])
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"madledger/common/util"
+	"madledger/peer/config"
+	"madledger/peer/server"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	importCmd = &cobra.Command{
+		Use: "import",
+	}
+	importViper = viper.New()
+)
+
+func init() {
+	importCmd.RunE = runImport
+	importCmd.Flags().StringP("config", "c", "peer.yaml", "The config file of blockchain")
+	importViper.BindPFlag("config", importCmd.Flags().Lookup("config"))
+	importCmd.Flags().StringP("input", "i", "peer.backup", "The file of backup")
+	importViper.BindPFlag("input", importCmd.Flags().Lookup("input"))
+	rootCmd.AddCommand(importCmd)
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	cfgFile := importViper.GetString("config")
+	if cfgFile == "" {
+		return errors.New("Please provide the config file")
+	}
+	input := importViper.GetString("input")
+	if input == "" {
+		return errors.New("Please provide the input file")
+	}
+	cfgAbsPath, err := util.MakeFileAbs(cfgFile, homeDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadConfig(cfgAbsPath)
+	if err != nil {
+		return err
+	}
+	setLog(cfg.Debug)
+
+	manifest, err := server.Import(cfg, input)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Import from %s\n", input)
+	printHeights(manifest)
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"madledger/common/util"
+	"madledger/peer/config"
+	"madledger/peer/server"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	reindexCmd = &cobra.Command{
+		Use: "reindex",
+	}
+	reindexViper = viper.New()
+)
+
+func init() {
+	reindexCmd.RunE = runReindex
+	reindexCmd.Flags().StringP("config", "c", "peer.yaml", "The config file of blockchain")
+	reindexViper.BindPFlag("config", reindexCmd.Flags().Lookup("config"))
+	rootCmd.AddCommand(reindexCmd)
+}
+
+func runReindex(cmd *cobra.Command, args []string) error {
+	cfgFile := reindexViper.GetString("config")
+	if cfgFile == "" {
+		return errors.New("Please provide the config file")
+	}
+	cfgAbsPath, err := util.MakeFileAbs(cfgFile, homeDir)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.LoadConfig(cfgAbsPath)
+	if err != nil {
+		return err
+	}
+	setLog(cfg.Debug)
+
+	if err := server.Reindex(cfg, printProgress); err != nil {
+		return err
+	}
+	fmt.Println("Reindex succeed")
+	return nil
+}
+
+// printProgress print the progress every 1000 blocks and at the last block
+func printProgress(channelID string, num, height uint64) {
+	if num%1000 == 0 || num+1 == height {
+		fmt.Printf("Channel %s: %d/%d blocks\n", channelID, num+1, height)
+	}
+}
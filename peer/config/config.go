@@ -16,6 +16,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"madledger/common/compress"
 	"madledger/common/crypto"
 	"madledger/common/util"
 	"madledger/core"
@@ -47,6 +48,8 @@ type Config struct {
 	KeyStore struct {
 		Key string `yaml:"Key"`
 	} `yaml:"KeyStore"`
+	Snapshot  SnapshotConfig  `yaml:"Snapshot"`
+	StateRoot StateRootConfig `yaml:"StateRoot"`
 }
 
 // TLSConfig ...
@@ -102,6 +105,12 @@ func LoadConfig(cfgFile string) (*Config, error) {
 	if err = cfg.loadDBConfig(); err != nil {
 		return nil, err
 	}
+	if err = cfg.loadSnapshotConfig(); err != nil {
+		return nil, err
+	}
+	if cfg.StateRoot.Interval <= 0 {
+		cfg.StateRoot.Interval = 10
+	}
 	return &cfg, nil
 }
 
@@ -141,6 +150,10 @@ func (cfg *Config) loadTLSConfig() error {
 // OrdererConfig is the config of orderer
 type OrdererConfig struct {
 	Address []string `yaml:"Address"`
+	// Compression is the grpc compression asked for, none or snappy
+	Compression string `yaml:"Compression"`
+	// Window is the number of blocks fetched ahead of the block running
+	Window int `yaml:"Window"`
 }
 
 // loadOrdererConfig check the orderer config and set necessary things
@@ -148,12 +161,17 @@ func (cfg *Config) loadOrdererConfig() error {
 	if len(cfg.Orderer.Address) == 0 {
 		return errors.New("orderer address is not setted")
 	}
-	return nil
+	if cfg.Orderer.Window <= 0 {
+		cfg.Orderer.Window = 16
+	}
+	return compress.Check(cfg.Orderer.Compression)
 }
 
 // BlockChainConfig is the config of blockchain
 type BlockChainConfig struct {
 	Path string `yaml:"Path"`
+	// Compression is the compression of blocks stored, none or snappy
+	Compression string `yaml:"Compression"`
 }
 
 // loadBlockChainConfig check the blockchain config and set necessary things
@@ -165,7 +183,7 @@ func (cfg *Config) loadBlockChainConfig() error {
 			return errors.New("The path of blockchain is not provided")
 		}
 	}
-	return nil
+	return compress.Check(cfg.BlockChain.Compression)
 }
 
 // DBType is the type of DB
@@ -205,6 +223,42 @@ func (cfg *Config) loadDBConfig() error {
 	return nil
 }
 
+// SnapshotConfig is the config of state snapshots
+type SnapshotConfig struct {
+	// Interval is the number of _global blocks between snapshots, 0 means
+	// no snapshot is taken
+	Interval uint64 `yaml:"Interval"`
+	Path     string `yaml:"Path"`
+	// Keep is the number of latest snapshots kept
+	Keep int `yaml:"Keep"`
+}
+
+// StateRootConfig is the config of comparing state roots with other peers
+type StateRootConfig struct {
+	// Peers are the addresses of peers to compare with, empty means the
+	// state roots are not compared
+	Peers []string `yaml:"Peers"`
+	// Interval is the seconds between comparisons
+	Interval int `yaml:"Interval"`
+}
+
+// loadSnapshotConfig check the snapshot config and set necessary things
+func (cfg *Config) loadSnapshotConfig() error {
+	if cfg.Snapshot.Interval == 0 {
+		return nil
+	}
+	if cfg.Snapshot.Path == "" {
+		if !cfg.Debug {
+			return errors.New("The path of snapshots is not provided")
+		}
+		cfg.Snapshot.Path = getDefaultSnapshotPath()
+	}
+	if cfg.Snapshot.Keep <= 0 {
+		cfg.Snapshot.Keep = 2
+	}
+	return nil
+}
+
 // GetIdentity return the identity of peer
 func (cfg *Config) GetIdentity() (*core.Member, error) {
 	if cfg.KeyStore.Key == "" {
@@ -228,6 +282,11 @@ func getDefaultLevelDBPath() string {
 	return storePath
 }
 
+func getDefaultSnapshotPath() string {
+	storePath, _ := util.MakeFileAbs("src/madledger/peer/data/snapshots", gopath)
+	return storePath
+}
+
 func getDefaultChainPath() string {
 	storePath, _ := util.MakeFileAbs("src/madledger/peer/data/blocks", gopath)
 	return storePath
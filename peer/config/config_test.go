@@ -25,6 +25,7 @@ func TestLoadConfig(t *testing.T) {
 	}
 	require.True(t, cfg.Debug)
 	require.False(t, cfg.TLS.Enable)
+	require.Equal(t, 16, cfg.Orderer.Window)
 }
 
 func TestGetIdentity(t *testing.T) {
@@ -14,16 +14,43 @@ import (
 	cc "madledger/blockchain/config"
 	"madledger/common"
 	"madledger/common/crypto"
+	"madledger/common/smt"
+	"madledger/common/util"
 	"madledger/core"
 )
 
-// TxStatus return the status of tx
+// Codes of TxStatus, the statuses stored before codes are TxUnknown
+const (
+	TxUnknown = iota
+	TxSuccess
+	// TxFailed is the code of tx rejected before running, nothing is charged
+	TxFailed
+	// TxReverted is the code of tx failed in the evm, the gas used is charged
+	TxReverted
+)
+
+// TxStatus return the status of tx, it is the receipt of tx
 type TxStatus struct {
 	Err             string
 	BlockNumber     uint64
 	BlockIndex      int
 	Output          []byte
 	ContractAddress string
+	Code            int
+	Sender          string
+	GasLimit        uint64
+	GasUsed         uint64
+	// Tokens is the tokens charged for the gas used, or burned by the tx
+	// sent to core.TokenBurnAddress
+	Tokens uint64
+	Logs   []*TxLog
+}
+
+// TxLog is the log emitted by the tx
+type TxLog struct {
+	Address common.Address
+	Topics  []common.Word256
+	Data    []byte
 }
 
 // WriteBatch define a write batch interface
@@ -36,10 +63,17 @@ type WriteBatch interface {
 	PutBlock(block *core.Block) error
 	// Put stores (key, value) into batch, the caller is responsible to avoid duplicate key
 	Put(key, value []byte)
+	// PutRecord stores a record returned by DB.Iterate
+	PutRecord(key, value []byte)
+	// DeleteRecord deletes a record returned by DB.Iterate
+	DeleteRecord(key []byte)
 	RemoveAccountStorage(address common.Address)
 	AddChannel(channelID string)
 	DeleteChannel(channelID string)
 	// UpdateChannel()
+	// UpdateStateRoot puts the accounts and storage set by the batch into the
+	// state tree of channel, and stores the new root as the root after block num
+	UpdateStateRoot(channelID string, num uint64) ([]byte, error)
 	Sync() error
 
 	UpdateAccounts(accounts ...common.Account) error
@@ -67,6 +101,16 @@ type DB interface {
 	// GetBlock gets block by block.num from db
 	GetBlock(channelID string, num uint64) (*core.Block, error)
 	Close()
+	// Snapshot writes a consistent copy of the db into dir
+	Snapshot(dir string) error
+	// Iterate calls fn with each record of state in the order of keys until
+	// fn return an error, blocks stored by WriteBatch.PutBlock are skipped,
+	// and records could be stored by WriteBatch.PutRecord
+	Iterate(fn func(key, value []byte) error) error
+	// ExcludeChannels return the filter of records returned by Iterate which
+	// drops the records of excluded channels, heights are the number of
+	// blocks of channels in db
+	ExcludeChannels(heights map[string]uint64, excluded []string) (RecordFilter, error)
 
 	Get(key []byte, couldBeEmpty bool) ([]byte, error)
 	//GetAssetAdminPKBytes return nil is not exist
@@ -77,4 +121,14 @@ type DB interface {
 	IsSystemAdmin(member *core.Member) bool
 
 	GetChannelProfile(id string) (*cc.Profile, error)
+	// GetStateRoot return the root of state tree of channel after block num
+	GetStateRoot(channelID string, num uint64) ([]byte, error)
+	// GetStateProof return the value of key in the state tree of channel
+	// after block num and its proof, the value is nil if key does not exist
+	GetStateProof(channelID string, num uint64, key []byte) ([]byte, *smt.Proof, error)
+}
+
+// GetTokenKey return the key of the token of address in channel
+func GetTokenKey(channelID string, address common.Address) []byte {
+	return util.BytesCombine(common.AddressFromChannelID(channelID).Bytes(), []byte("token"), address.Bytes())
 }
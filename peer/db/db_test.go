@@ -19,6 +19,7 @@ import (
 	"madledger/core"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -46,6 +47,16 @@ var (
 		BlockIndex:      1,
 		Output:          []byte("tx1"),
 		ContractAddress: "",
+		Code:            TxSuccess,
+		Sender:          common.ZeroAddress.String(),
+		GasLimit:        100,
+		GasUsed:         21,
+		Tokens:          42,
+		Logs: []*TxLog{{
+			Address: common.ZeroAddress,
+			Topics:  []common.Word256{common.ZeroWord256},
+			Data:    []byte("log"),
+		}},
 	}
 	tx2, _    = core.NewTx("test", common.ZeroAddress, []byte("2"), 0, "", privKey)
 	tx2Status = &TxStatus{
@@ -64,6 +75,9 @@ func TestDB(t *testing.T) {
 		testStorage(t)
 		testTxStatus(t)
 		testHistory(t)
+		testStateRoot(t)
+		testStateAt(t)
+		testExcludeChannels(t)
 		db.Close()
 		os.RemoveAll(dir)
 	}
@@ -235,6 +249,166 @@ func testBenchmark(t *testing.T) {
 	fmt.Printf("get %d accounts cost %v\n", size, time.Since(begin))
 }
 
+func testStateRoot(t *testing.T) {
+	account := newAccount()
+	account.AddBalance(100)
+	address := account.GetAddress()
+	slot, value := common.LeftPadWord256([]byte("slot")), common.LeftPadWord256([]byte("value"))
+	wb := db.NewWriteBatch()
+	require.NoError(t, wb.SetAccount(account))
+	require.NoError(t, wb.SetStorage(address, slot, value))
+	root0, err := wb.UpdateStateRoot("state", 0)
+	require.NoError(t, err)
+	require.NoError(t, wb.Sync())
+	root, err := db.GetStateRoot("state", 0)
+	require.NoError(t, err)
+	require.Equal(t, root0, root)
+	_, err = db.GetStateRoot("state", 1)
+	require.Error(t, err)
+
+	accountValue, err := account.Bytes()
+	require.NoError(t, err)
+	for key, expect := range map[string][]byte{
+		string(core.AccountKey(address)):             accountValue,
+		string(core.StorageKey(address, slot)):       value.Bytes(),
+		string(core.StorageKey(address, value)):      nil,
+		string(core.AssetAccountKey(address)):        nil,
+		string(core.StateKey("state", address, nil)): accountValue,
+	} {
+		got, proof, err := db.GetStateProof("state", 0, []byte(key))
+		require.NoError(t, err)
+		require.Equal(t, expect, got)
+		require.NoError(t, proof.Verify(root0, []byte(key), expect))
+	}
+
+	// the root of block 1 changes, but the state after block 0 is kept
+	wb = db.NewWriteBatch()
+	require.NoError(t, wb.RemoveAccount(address))
+	root1, err := wb.UpdateStateRoot("state", 1)
+	require.NoError(t, err)
+	require.NoError(t, wb.Sync())
+	require.NotEqual(t, root0, root1)
+	got, _, err := db.GetStateProof("state", 1, core.AccountKey(address))
+	require.NoError(t, err)
+	require.Nil(t, got)
+	got, _, err = db.GetStateProof("state", 0, core.AccountKey(address))
+	require.NoError(t, err)
+	require.Equal(t, accountValue, got)
+
+	// a block which changes nothing keeps the root
+	wb = db.NewWriteBatch()
+	root2, err := wb.UpdateStateRoot("state", 2)
+	require.NoError(t, err)
+	require.Equal(t, root1, root2)
+}
+
+func testStateAt(t *testing.T) {
+	account := newAccount()
+	account.AddBalance(100)
+	address := account.GetAddress()
+	slot := common.LeftPadWord256([]byte("slot"))
+	wb := db.NewWriteBatch()
+	require.NoError(t, wb.SetAccount(account))
+	require.NoError(t, wb.SetStorage(address, slot, common.LeftPadWord256([]byte("old"))))
+	_, err := wb.UpdateStateRoot("history", 0)
+	require.NoError(t, err)
+	require.NoError(t, wb.Sync())
+
+	account.AddBalance(50)
+	wb = db.NewWriteBatch()
+	require.NoError(t, wb.SetAccount(account))
+	require.NoError(t, wb.SetStorage(address, slot, common.LeftPadWord256([]byte("new"))))
+	_, err = wb.UpdateStateRoot("history", 1)
+	require.NoError(t, err)
+	require.NoError(t, wb.Sync())
+
+	for num, expect := range map[uint64]struct {
+		balance uint64
+		value   string
+	}{0: {100, "old"}, 1: {150, "new"}} {
+		state, err := StateAt(db, "history", num)
+		require.NoError(t, err)
+		require.True(t, state.AccountExist(address))
+		got, err := state.GetAccount(address)
+		require.NoError(t, err)
+		require.Equal(t, expect.balance, got.GetBalance())
+		value, err := state.GetStorage(address, slot)
+		require.NoError(t, err)
+		require.Equal(t, common.LeftPadWord256([]byte(expect.value)), value)
+	}
+	// the latest state is not changed by the views
+	got, err := db.GetAccount(address)
+	require.NoError(t, err)
+	require.Equal(t, uint64(150), got.GetBalance())
+
+	state, err := StateAt(db, "history", 0)
+	require.NoError(t, err)
+	other := newAccount().GetAddress()
+	require.False(t, state.AccountExist(other))
+	_, err = state.GetStorage(other, slot)
+	require.Error(t, err)
+	_, err = StateAt(db, "history", 2)
+	require.Error(t, err)
+}
+
+func testExcludeChannels(t *testing.T) {
+	shared, secret := newAccount(), newAccount()
+	slot := common.LeftPadWord256([]byte("slot"))
+	tx, err := core.NewTx("secret", common.ZeroAddress, []byte("secret"), 0, "", privKey)
+	require.NoError(t, err)
+	wb := db.NewWriteBatch()
+	wb.AddChannel("shared")
+	wb.AddChannel("secret")
+	require.NoError(t, wb.SetAccount(shared))
+	_, err = wb.UpdateStateRoot("shared", 0)
+	require.NoError(t, err)
+	require.NoError(t, wb.Sync())
+	wb = db.NewWriteBatch()
+	require.NoError(t, wb.SetAccount(shared))
+	require.NoError(t, wb.SetAccount(secret))
+	require.NoError(t, wb.SetStorage(secret.GetAddress(), slot, slot))
+	require.NoError(t, wb.SetTxStatus(tx, tx1Status))
+	wb.Put(GetTokenKey("secret", secret.GetAddress()), []byte("token"))
+	secretRoot, err := wb.UpdateStateRoot("secret", 0)
+	require.NoError(t, err)
+	require.NoError(t, wb.Sync())
+
+	filter, err := db.ExcludeChannels(map[string]uint64{"shared": 1, "secret": 1}, []string{"secret"})
+	require.NoError(t, err)
+	var kept = make(map[string][]byte)
+	require.NoError(t, db.Iterate(func(key, value []byte) error {
+		if value, ok := filter(key, value); ok {
+			kept[string(key)] = value
+		}
+		return nil
+	}))
+	for _, key := range [][]byte{
+		secret.GetAddress().Bytes(),
+		core.StorageKey(secret.GetAddress(), slot),
+		[]byte("secret" + tx.ID),
+		GetTokenKey("secret", secret.GetAddress()),
+		getStateRootKey("secret", 0),
+		getStateNodeKey(secretRoot),
+	} {
+		for record := range kept {
+			require.NotContains(t, record, string(key))
+		}
+	}
+	var found bool
+	for record := range kept {
+		if strings.HasSuffix(record, string(shared.GetAddress().Bytes())) {
+			found = true
+		}
+		if strings.HasSuffix(record, "channels") {
+			var channels []string
+			require.NoError(t, json.Unmarshal(kept[record], &channels))
+			require.Contains(t, channels, "shared")
+			require.NotContains(t, channels, "secret")
+		}
+	}
+	require.True(t, found)
+}
+
 func newAccount() *common.Account {
 	priv, _ := crypto.GeneratePrivateKey()
 	addr, _ := priv.PubKey().Address()
@@ -0,0 +1,172 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"madledger/common"
+	"madledger/common/smt"
+	"sort"
+	"strconv"
+)
+
+// RecordFilter return the value of a record returned by DB.Iterate to keep,
+// or false if the record should be dropped
+type RecordFilter func(key, value []byte) ([]byte, bool)
+
+// excluder finds the records of the excluded channels
+//  1. Records of channel: tx status, token, state root and block
+//  2. Accounts and storage: those only in the state trees of the excluded
+//     channels, the ones set before state trees exist could not be told
+//     apart, so they are kept
+//  3. Nodes of state trees: those only in the trees of the excluded channels
+//  4. History and the channels of peer: the excluded channels are removed
+type excluder struct {
+	excluded map[string]bool
+	// keys are the keys of accounts and storage to drop
+	keys map[string]bool
+	// nodes are the hashes of nodes to drop
+	nodes map[string]bool
+}
+
+// newExcluder walks the state trees of channels after each block, heights
+// are the number of blocks of channels. The channels kept are walked first,
+// so the nodes and keys they share with the excluded ones are kept.
+func newExcluder(get getter, heights map[string]uint64, excluded []string) (*excluder, error) {
+	e := &excluder{
+		excluded: make(map[string]bool),
+		keys:     make(map[string]bool),
+		nodes:    make(map[string]bool),
+	}
+	for _, id := range excluded {
+		e.excluded[id] = true
+	}
+	var ids []string
+	for id := range heights {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if e.excluded[ids[i]] != e.excluded[ids[j]] {
+			return !e.excluded[ids[i]]
+		}
+		return ids[i] < ids[j]
+	})
+	tree := smt.NewTree(nodeStore(get), nil)
+	var visited = make(map[string]bool)
+	var kept = make(map[string]bool)
+	for _, id := range ids {
+		drop := e.excluded[id]
+		for num := uint64(0); num < heights[id]; num++ {
+			root, err := get(getStateRootKey(id, num))
+			if err != nil {
+				return nil, err
+			}
+			if root == nil {
+				continue
+			}
+			err = tree.Walk(root, func(hash, key []byte) bool {
+				if key != nil && !drop {
+					kept[string(key)] = true
+				} else if key != nil && !kept[string(key)] {
+					e.keys[string(key)] = true
+				}
+				if visited[string(hash)] {
+					return false
+				}
+				visited[string(hash)] = true
+				if drop {
+					e.nodes[string(hash)] = true
+				}
+				return true
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return e, nil
+}
+
+// keep is the RecordFilter of the records stored by WriteBatch.Put and the
+// records of state which are stored by their keys of state tree
+func (e *excluder) keep(key, value []byte) ([]byte, bool) {
+	if bytes.HasPrefix(key, []byte("smt_")) {
+		return value, !e.nodes[string(key[len("smt_"):])]
+	}
+	if e.keys[string(key)] {
+		return value, false
+	}
+	if string(key) == "channels" {
+		var channels []string
+		if err := json.Unmarshal(value, &channels); err != nil {
+			return value, true
+		}
+		var kept = make([]string, 0, len(channels))
+		for _, id := range channels {
+			if !e.excluded[id] {
+				kept = append(kept, id)
+			}
+		}
+		value, _ = json.Marshal(kept)
+		return value, true
+	}
+	for id := range e.excluded {
+		if isTxStatusKey(key, id) ||
+			bytes.HasPrefix(key, GetTokenKey(id, common.ZeroAddress)[:common.AddressLength+len("token")]) ||
+			isNumberKey(key, "state_root_"+id+"_") ||
+			isNumberKey(key, "bc_data_"+id+"_") {
+			return value, false
+		}
+	}
+	return value, true
+}
+
+// keepHistory removes the excluded channels from the history of address
+func (e *excluder) keepHistory(value []byte) ([]byte, bool) {
+	var txs map[string][]string
+	if err := json.Unmarshal(value, &txs); err != nil {
+		return value, true
+	}
+	for id := range txs {
+		if e.excluded[id] {
+			delete(txs, id)
+		}
+	}
+	if len(txs) == 0 {
+		return nil, false
+	}
+	value, _ = json.Marshal(txs)
+	return value, true
+}
+
+// isTxStatusKey return if key is channelID + txID, the id of tx is the hex
+// of its hash
+func isTxStatusKey(key []byte, channelID string) bool {
+	if len(key) != len(channelID)+64 || !bytes.HasPrefix(key, []byte(channelID)) {
+		return false
+	}
+	for _, c := range key[len(channelID):] {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// isNumberKey return if key is prefix + num
+func isNumberKey(key []byte, prefix string) bool {
+	if !bytes.HasPrefix(key, []byte(prefix)) {
+		return false
+	}
+	_, err := strconv.ParseUint(string(key[len(prefix):]), 10, 64)
+	return err == nil
+}
@@ -11,13 +11,16 @@
 package db
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	cc "madledger/blockchain/config"
 	"madledger/common"
+	"madledger/common/backup"
 	"madledger/common/crypto"
 	"madledger/common/event"
+	"madledger/common/smt"
 	"madledger/common/util"
 	"madledger/core"
 	"sync"
@@ -287,6 +290,7 @@ func (db *LevelDB) NewWriteBatch() WriteBatch {
 		batch:     batch,
 		db:        db,
 		histories: make(map[string]map[string][]string),
+		states:    make(stateChanges),
 	}
 }
 
@@ -307,6 +311,41 @@ func (db *LevelDB) Close() {
 	}
 }
 
+// Snapshot writes a consistent copy of the db into dir
+func (db *LevelDB) Snapshot(dir string) error {
+	return backup.SnapshotLevelDB(db.connect, dir)
+}
+
+// Iterate is the implementation of interface
+func (db *LevelDB) Iterate(fn func(key, value []byte) error) error {
+	iter := db.connect.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		if isBlockKey(iter.Key()) {
+			continue
+		}
+		if err := fn(iter.Key(), iter.Value()); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+// ExcludeChannels is the implementation of interface
+func (db *LevelDB) ExcludeChannels(heights map[string]uint64, excluded []string) (RecordFilter, error) {
+	e, err := newExcluder(db.get, heights, excluded)
+	if err != nil {
+		return nil, err
+	}
+	return func(key, value []byte) ([]byte, bool) {
+		// the history of address is stored by the bytes of address
+		if len(key) == common.AddressLength {
+			return e.keepHistory(value)
+		}
+		return e.keep(key, value)
+	}, nil
+}
+
 // Get get the value by key
 func (db *LevelDB) Get(key []byte, couldBeEmpty bool) ([]byte, error) {
 	val, err := db.connect.Get(key, nil)
@@ -316,6 +355,21 @@ func (db *LevelDB) Get(key []byte, couldBeEmpty bool) ([]byte, error) {
 	return val, err
 }
 
+// get return nil if key does not exist
+func (db *LevelDB) get(key []byte) ([]byte, error) {
+	return db.Get(key, true)
+}
+
+// GetStateRoot is the implementation of interface
+func (db *LevelDB) GetStateRoot(channelID string, num uint64) ([]byte, error) {
+	return getStateRoot(db.get, channelID, num)
+}
+
+// GetStateProof is the implementation of interface
+func (db *LevelDB) GetStateProof(channelID string, num uint64, key []byte) ([]byte, *smt.Proof, error) {
+	return getStateProof(db.get, channelID, num, key)
+}
+
 // GetAssetAdminPKBytes returns public key bytes of _asset admin or nil if not exists
 func (db *LevelDB) GetAssetAdminPKBytes() []byte {
 	var key = getAssetAdminKey()
@@ -363,6 +417,7 @@ type WriteBatchWrapper struct {
 
 	histories map[string]map[string][]string
 	channels  []string
+	states    stateChanges
 }
 
 // SetAccount is the implementation of interface
@@ -374,7 +429,7 @@ func (wb *WriteBatchWrapper) SetAccount(account *common.Account) error {
 	}
 	// value := MarshalAccount(account)
 	wb.batch.Put(key, value)
-	return nil
+	return wb.states.setAccount(account)
 
 }
 
@@ -382,6 +437,7 @@ func (wb *WriteBatchWrapper) SetAccount(account *common.Account) error {
 func (wb *WriteBatchWrapper) RemoveAccount(address common.Address) error {
 	var key = util.BytesCombine([]byte("account:"), address.Bytes())
 	wb.batch.Delete(key)
+	wb.states.removeAccount(address)
 	return nil
 }
 
@@ -395,6 +451,9 @@ func (wb *WriteBatchWrapper) RemoveAccountStorage(address common.Address) {
 	for ; iter.Valid(); iter.Next() {
 		key := iter.Key()
 		wb.batch.Delete(key)
+		if len(key) == len(addr)+common.Word256Length && bytes.HasPrefix(key, addr) {
+			wb.states.removeStorage(address, common.LeftPadWord256(key[len(addr):]))
+		}
 	}
 }
 
@@ -402,6 +461,7 @@ func (wb *WriteBatchWrapper) RemoveAccountStorage(address common.Address) {
 func (wb *WriteBatchWrapper) SetStorage(address common.Address, key common.Word256, value common.Word256) error {
 	storageKey := util.BytesCombine(address.Bytes(), key.Bytes())
 	wb.batch.Put(storageKey, value.Bytes())
+	wb.states.setStorage(address, key, value)
 	return nil
 }
 
@@ -458,6 +518,21 @@ func (wb *WriteBatchWrapper) Put(key, value []byte) {
 	wb.batch.Put(key, value)
 }
 
+// isBlockKey return if the key is stored by PutBlock
+func isBlockKey(key []byte) bool {
+	return bytes.HasPrefix(key, []byte("bc_data_"))
+}
+
+// PutRecord is the implementation of interface
+func (wb *WriteBatchWrapper) PutRecord(key, value []byte) {
+	wb.batch.Put(key, value)
+}
+
+// DeleteRecord is the implementation of interface
+func (wb *WriteBatchWrapper) DeleteRecord(key []byte) {
+	wb.batch.Delete(key)
+}
+
 // PutBlock stores block into db
 func (wb *WriteBatchWrapper) PutBlock(block *core.Block) error {
 	data := block.Bytes()
@@ -493,6 +568,11 @@ func (wb *WriteBatchWrapper) DeleteChannel(channelID string) {
 	wb.updateChannels()
 }
 
+// UpdateStateRoot is the implementation of interface
+func (wb *WriteBatchWrapper) UpdateStateRoot(channelID string, num uint64) ([]byte, error) {
+	return updateStateRoot(wb.db.get, wb.batch.Put, wb.states, channelID, num)
+}
+
 // Sync sync batch to database
 func (wb *WriteBatchWrapper) Sync() error {
 	return wb.db.connect.Write(wb.batch, nil)
@@ -514,7 +594,7 @@ func (wb *WriteBatchWrapper) UpdateAccounts(accounts ...common.Account) error {
 		}
 		wb.Put(key, data)
 	}
-	return nil
+	return wb.states.updateAssetAccounts(accounts...)
 }
 
 //SetAssetAdmin only succeed at the first time it is called
@@ -533,7 +613,7 @@ func (wb *WriteBatchWrapper) SetAssetAdmin(pk crypto.PublicKey) error {
 }
 
 func getAccountKey(address common.Address) []byte {
-	return []byte(fmt.Sprintf("%s@%s", core.ASSETCHANNELID, address.String()))
+	return core.AssetAccountKey(address)
 }
 
 func getAssetAdminKey() []byte {
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"madledger/common"
 	"madledger/common/event"
+	"madledger/common/smt"
 	"madledger/common/util"
 	"madledger/core"
 	"os"
@@ -228,6 +229,94 @@ func (db *RocksDB) GetBlock(channelID string, num uint64) (*core.Block, error) {
 	return core.UnmarshalBlock(data.Data())
 }
 
+// get return nil if key does not exist in the default column family
+func (db *RocksDB) get(key []byte) ([]byte, error) {
+	data, err := db.connect.Get(db.ro, key)
+	if err != nil {
+		return nil, err
+	}
+	defer data.Free()
+	if !data.Exists() {
+		return nil, nil
+	}
+	return append([]byte{}, data.Data()...), nil
+}
+
+// GetStateRoot is the implementation of interface
+func (db *RocksDB) GetStateRoot(channelID string, num uint64) ([]byte, error) {
+	return getStateRoot(db.get, channelID, num)
+}
+
+// GetStateProof is the implementation of interface
+func (db *RocksDB) GetStateProof(channelID string, num uint64, key []byte) ([]byte, *smt.Proof, error) {
+	return getStateProof(db.get, channelID, num, key)
+}
+
+// columnFamilies return the column families, the index of column family is
+// the first byte of keys of records returned by Iterate
+func (db *RocksDB) columnFamilies() []*gorocksdb.ColumnFamilyHandle {
+	return []*gorocksdb.ColumnFamilyHandle{nil, db.accountCFHdl, db.storageCFHdl, db.historyCFHdl}
+}
+
+// Iterate is the implementation of interface
+func (db *RocksDB) Iterate(fn func(key, value []byte) error) error {
+	for i, cf := range db.columnFamilies() {
+		var iter *gorocksdb.Iterator
+		if cf == nil {
+			iter = db.connect.NewIterator(db.ro)
+		} else {
+			iter = db.connect.NewIteratorCF(db.ro, cf)
+		}
+		for iter.SeekToFirst(); iter.Valid(); iter.Next() {
+			key, value := iter.Key(), iter.Value()
+			var err error
+			if cf != nil || !isBlockKey(key.Data()) {
+				err = fn(append([]byte{byte(i)}, key.Data()...), value.Data())
+			}
+			key.Free()
+			value.Free()
+			if err != nil {
+				iter.Close()
+				return err
+			}
+		}
+		err := iter.Err()
+		iter.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExcludeChannels is the implementation of interface
+func (db *RocksDB) ExcludeChannels(heights map[string]uint64, excluded []string) (RecordFilter, error) {
+	e, err := newExcluder(db.get, heights, excluded)
+	if err != nil {
+		return nil, err
+	}
+	return func(key, value []byte) ([]byte, bool) {
+		if len(key) == 0 {
+			return value, true
+		}
+		// the first byte is the index of column family
+		switch key[0] {
+		case 1:
+			return e.keep(core.AccountKey(common.BytesToAddress(key[1:])), value)
+		case 2:
+			return value, !e.keys[string(key[1:])]
+		case 3:
+			// the history of address in channel is stored by address + channelID
+			if len(key) > 1+common.AddressLength && e.excluded[string(key[1+common.AddressLength:])] {
+				return value, false
+			}
+			return value, true
+		default:
+			return e.keep(key[1:], value)
+		}
+	}, nil
+}
+
 // Close close the rocksdb
 func (db *RocksDB) Close() {
 	if db.connect != nil {
@@ -235,6 +324,16 @@ func (db *RocksDB) Close() {
 	}
 }
 
+// Snapshot creates a checkpoint of the rocksdb in dir
+func (db *RocksDB) Snapshot(dir string) error {
+	checkpoint, err := db.connect.NewCheckpoint()
+	if err != nil {
+		return err
+	}
+	defer checkpoint.Destroy()
+	return checkpoint.CreateCheckpoint(dir, 0)
+}
+
 // NewWriteBatch implement the interface, WriteBatch is a wrapper of gorocks.WriteBatch
 func (db *RocksDB) NewWriteBatch() WriteBatch {
 	batch := gorocksdb.NewWriteBatch()
@@ -242,6 +341,7 @@ func (db *RocksDB) NewWriteBatch() WriteBatch {
 		batch:     batch,
 		db:        db,
 		histories: make(map[string][]string),
+		states:    make(stateChanges),
 	}
 }
 
@@ -252,6 +352,7 @@ type RocksDBWriteBatchWrapper struct {
 
 	histories map[string][]string
 	channels  []string
+	states    stateChanges
 }
 
 // SetAccount is the implementation of interface
@@ -263,7 +364,7 @@ func (wb *RocksDBWriteBatchWrapper) SetAccount(account *common.Account) error {
 	}
 	// value := MarshalAccount(account)
 	wb.batch.PutCF(wb.db.accountCFHdl, key, value)
-	return nil
+	return wb.states.setAccount(account)
 
 }
 
@@ -271,6 +372,7 @@ func (wb *RocksDBWriteBatchWrapper) SetAccount(account *common.Account) error {
 func (wb *RocksDBWriteBatchWrapper) RemoveAccount(address common.Address) error {
 	var key = address.Bytes()
 	wb.batch.DeleteCF(wb.db.accountCFHdl, key)
+	wb.states.removeAccount(address)
 	return nil
 }
 
@@ -284,6 +386,9 @@ func (wb *RocksDBWriteBatchWrapper) RemoveAccountStorage(address common.Address)
 	for ; iter.Valid() && iter.ValidForPrefix(prefix); iter.Next() {
 		key := iter.Key().Data()
 		wb.batch.DeleteCF(wb.db.storageCFHdl, key)
+		if len(key) == len(prefix)+common.Word256Length {
+			wb.states.removeStorage(address, common.LeftPadWord256(key[len(prefix):]))
+		}
 		iter.Key().Free()
 	}
 }
@@ -292,6 +397,7 @@ func (wb *RocksDBWriteBatchWrapper) RemoveAccountStorage(address common.Address)
 func (wb *RocksDBWriteBatchWrapper) SetStorage(address common.Address, key common.Word256, value common.Word256) error {
 	storageKey := util.BytesCombine(address.Bytes(), key.Bytes())
 	wb.batch.PutCF(wb.db.storageCFHdl, storageKey, value.Bytes())
+	wb.states.setStorage(address, key, value)
 	return nil
 }
 
@@ -342,6 +448,35 @@ func (wb *RocksDBWriteBatchWrapper) Put(key, value []byte) {
 	wb.batch.Put(key, value)
 }
 
+// PutRecord is the implementation of interface
+func (wb *RocksDBWriteBatchWrapper) PutRecord(key, value []byte) {
+	if len(key) == 0 || int(key[0]) >= len(wb.db.columnFamilies()) {
+		return
+	}
+	if cf := wb.db.columnFamilies()[key[0]]; cf != nil {
+		wb.batch.PutCF(cf, key[1:], value)
+	} else {
+		wb.batch.Put(key[1:], value)
+	}
+}
+
+// DeleteRecord is the implementation of interface
+func (wb *RocksDBWriteBatchWrapper) DeleteRecord(key []byte) {
+	if len(key) == 0 || int(key[0]) >= len(wb.db.columnFamilies()) {
+		return
+	}
+	if cf := wb.db.columnFamilies()[key[0]]; cf != nil {
+		wb.batch.DeleteCF(cf, key[1:])
+	} else {
+		wb.batch.Delete(key[1:])
+	}
+}
+
+// UpdateStateRoot is the implementation of interface
+func (wb *RocksDBWriteBatchWrapper) UpdateStateRoot(channelID string, num uint64) ([]byte, error) {
+	return updateStateRoot(wb.db.get, wb.batch.Put, wb.states, channelID, num)
+}
+
 // PutBlock stores block into db
 func (wb *RocksDBWriteBatchWrapper) PutBlock(block *core.Block) error {
 	data := block.Bytes()
@@ -0,0 +1,203 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"madledger/common"
+	"madledger/common/smt"
+	"madledger/core"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+/*
+*  The state tree of a channel records the accounts and storage set by the
+*  blocks of the channel, the keys are core.StateKey.
+*  1. Node: key = "smt_" + hash
+*  2. Root: key = "state_root_" + channelID + "_" + num
+ */
+
+// stateChanges are the values of state keys set by a write batch, a nil
+// value means the key is deleted
+type stateChanges map[string][]byte
+
+func (c stateChanges) setAccount(account *common.Account) error {
+	value, err := account.Bytes()
+	if err != nil {
+		return err
+	}
+	c[string(core.AccountKey(account.GetAddress()))] = value
+	return nil
+}
+
+func (c stateChanges) removeAccount(address common.Address) {
+	c[string(core.AccountKey(address))] = nil
+}
+
+func (c stateChanges) setStorage(address common.Address, key, value common.Word256) {
+	c[string(core.StorageKey(address, key))] = value.Bytes()
+}
+
+func (c stateChanges) removeStorage(address common.Address, key common.Word256) {
+	c[string(core.StorageKey(address, key))] = nil
+}
+
+func (c stateChanges) updateAssetAccounts(accounts ...common.Account) error {
+	for _, account := range accounts {
+		value, err := json.Marshal(account)
+		if err != nil {
+			return err
+		}
+		c[string(core.AssetAccountKey(account.GetAddress()))] = value
+	}
+	return nil
+}
+
+// getter return the value of key in db, or nil if key does not exist
+type getter func(key []byte) ([]byte, error)
+
+// nodeStore provides the nodes of state trees
+type nodeStore getter
+
+func (get nodeStore) Get(hash []byte) ([]byte, error) {
+	node, err := get(getStateNodeKey(hash))
+	if err == nil && node == nil {
+		err = fmt.Errorf("The node %x of state tree is missing", hash)
+	}
+	return node, err
+}
+
+// updateStateRoot puts changes into the state tree of channel after block
+// num-1, and stores the new root as the root after block num
+func updateStateRoot(get getter, put func(key, value []byte), changes stateChanges, channelID string, num uint64) ([]byte, error) {
+	var root = smt.EmptyRoot
+	if num > 0 {
+		prev, err := get(getStateRootKey(channelID, num-1))
+		if err != nil {
+			return nil, err
+		}
+		// the peer may run blocks before state roots exist
+		if prev != nil {
+			root = prev
+		}
+	}
+	tree := smt.NewTree(nodeStore(get), func(hash, node []byte) {
+		put(getStateNodeKey(hash), node)
+	})
+	root, err := tree.Update(root, changes)
+	if err != nil {
+		return nil, err
+	}
+	put(getStateRootKey(channelID, num), root)
+	return root, nil
+}
+
+func getStateRoot(get getter, channelID string, num uint64) ([]byte, error) {
+	root, err := get(getStateRootKey(channelID, num))
+	if err == nil && root == nil {
+		err = fmt.Errorf("There is no state root of block %d of channel %s", num, channelID)
+	}
+	return root, err
+}
+
+func getStateProof(get getter, channelID string, num uint64, key []byte) ([]byte, *smt.Proof, error) {
+	root, err := getStateRoot(get, channelID, num)
+	if err != nil {
+		return nil, nil, err
+	}
+	tree := smt.NewTree(nodeStore(get), nil)
+	proof, err := tree.Prove(root, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof.Value(), proof, nil
+}
+
+func getStateNodeKey(hash []byte) []byte {
+	return append([]byte("smt_"), hash...)
+}
+
+func getStateRootKey(channelID string, num uint64) []byte {
+	return []byte(fmt.Sprintf("state_root_%s_%d", channelID, num))
+}
+
+// historyDB is a view of db whose accounts and storage are read from the
+// state tree of a channel after a block, so only the state set by the
+// blocks of the channel could be read
+type historyDB struct {
+	DB
+	tree *smt.Tree
+	root []byte
+}
+
+// StateAt return a view of db whose accounts and storage are the state of
+// channel after block num, the write batches of the view should never sync
+func StateAt(db DB, channelID string, num uint64) (DB, error) {
+	root, err := db.GetStateRoot(channelID, num)
+	if err != nil {
+		return nil, err
+	}
+	get := func(key []byte) ([]byte, error) {
+		return db.Get(key, true)
+	}
+	return &historyDB{
+		DB:   db,
+		tree: smt.NewTree(nodeStore(get), nil),
+		root: root,
+	}, nil
+}
+
+// AccountExist is the implementation of interface
+func (db *historyDB) AccountExist(address common.Address) bool {
+	value, err := db.tree.Get(db.root, core.AccountKey(address))
+	return err == nil && value != nil
+}
+
+// GetAccount is the implementation of interface
+func (db *historyDB) GetAccount(address common.Address) (*common.Account, error) {
+	value, err := db.tree.Get(db.root, core.AccountKey(address))
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return common.NewAccount(address), nil
+	}
+	var account common.Account
+	if err := json.Unmarshal(value, &account); err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// GetStorage is the implementation of interface
+func (db *historyDB) GetStorage(address common.Address, key common.Word256) (common.Word256, error) {
+	value, err := db.tree.Get(db.root, core.StorageKey(address, key))
+	if err != nil {
+		return common.ZeroWord256, err
+	}
+	if value == nil {
+		return common.ZeroWord256, leveldb.ErrNotFound
+	}
+	return common.BytesToWord256(value)
+}
+
+// GetOrCreateAccount is the implementation of interface
+func (db *historyDB) GetOrCreateAccount(address common.Address) (common.Account, error) {
+	account := common.NewAccount(address)
+	value, err := db.tree.Get(db.root, core.AssetAccountKey(address))
+	if err != nil || value == nil {
+		return *account, err
+	}
+	err = json.Unmarshal(value, account)
+	return *account, err
+}
@@ -13,10 +13,14 @@ package orderer
 import (
 	"context"
 	"crypto/tls"
+	"madledger/common/compress"
 	"madledger/peer/config"
+	"sync"
 	"time"
 
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
 
 	"madledger/core"
 	pb "madledger/protos"
@@ -27,6 +31,11 @@ import (
 // Client is the client of orderer
 type Client struct {
 	ordererClient pb.OrdererClient
+	// compression is the compression asked for when fetching blocks,
+	// and it is dropped if the orderer does not support it. Blocks are
+	// fetched in parallel, so it is guarded by lock.
+	lock        sync.RWMutex
+	compression string
 }
 
 // NewClient is the constructor of Client
@@ -54,6 +63,7 @@ func NewClient(addr string, cfg *config.Config) (*Client, error) {
 	ordererClient := pb.NewOrdererClient(conn)
 	return &Client{
 		ordererClient: ordererClient,
+		compression:   cfg.Orderer.Compression,
 	}, nil
 }
 
@@ -63,11 +73,26 @@ func (c *Client) FetchBlock(channelID string, num uint64, async bool) (*core.Blo
 	if async {
 		behavior = pb.Behavior_RETURN_UNTIL_READY
 	}
-	pbBlock, err := c.ordererClient.FetchBlock(context.Background(), &pb.FetchBlockRequest{
+	req := &pb.FetchBlockRequest{
 		ChannelID: channelID,
 		Number:    num,
 		Behavior:  behavior,
-	})
+	}
+	var opts []grpc.CallOption
+	c.lock.RLock()
+	compression := c.compression
+	c.lock.RUnlock()
+	if compress.Enabled(compression) {
+		opts = append(opts, grpc.UseCompressor(compression))
+	}
+	pbBlock, err := c.ordererClient.FetchBlock(context.Background(), req, opts...)
+	if len(opts) != 0 && status.Code(err) == codes.Unimplemented {
+		// the orderer does not register the compressor
+		c.lock.Lock()
+		c.compression = compress.None
+		c.lock.Unlock()
+		pbBlock, err = c.ordererClient.FetchBlock(context.Background(), req)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,94 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package server
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"madledger/common/backup"
+	"madledger/peer/config"
+	pb "madledger/protos"
+	"os"
+	"path/filepath"
+)
+
+// backupSections return the dirs of sections in the backup of peer
+func backupSections(cfg *config.Config) map[string]string {
+	return map[string]string{
+		backup.BlocksSection: cfg.BlockChain.Path,
+		backup.DBSection:     cfg.DB.LevelDB.Dir,
+	}
+}
+
+// Export writes the backup of a stopped peer into w
+func Export(cfg *config.Config, w io.Writer) error {
+	sections := backupSections(cfg)
+	// the db is locked until exported, so the peer could not start
+	db, err := newDB(sections[backup.DBSection])
+	if err != nil {
+		return fmt.Errorf("Failed to open db, the peer may be running: %v", err)
+	}
+	defer db.Close()
+	tmp, err := ioutil.TempDir("", "peer-backup")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	sections[backup.DBSection] = filepath.Join(tmp, backup.DBSection)
+	if err := db.Snapshot(sections[backup.DBSection]); err != nil {
+		return err
+	}
+	return backup.Write(w, "peer", false, sections)
+}
+
+// Import restores the backup into the dirs of peer, which should be empty
+func Import(cfg *config.Config, file string) (*backup.Manifest, error) {
+	return backup.Restore(file, "peer", backupSections(cfg))
+}
+
+// Backup copies the blocks of all channels into blocksDir and the db into
+// dbDir, channels are frozen while copying, so they are consistent
+func (m *ChannelManager) Backup(blocksDir, dbDir string) error {
+	unfreeze := m.coordinator.Freeze()
+	defer unfreeze()
+
+	if err := backup.CopyDir(m.path, blocksDir); err != nil {
+		return err
+	}
+	return m.db.Snapshot(dbDir)
+}
+
+// Backup is the implementation of protos
+func (s *Server) Backup(req *pb.BackupRequest, stream pb.Peer_BackupServer) error {
+	if err := backup.CheckLocal(stream.Context()); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempDir("", "peer-backup")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	var sections = map[string]string{
+		backup.BlocksSection: filepath.Join(tmp, backup.BlocksSection),
+		backup.DBSection:     filepath.Join(tmp, backup.DBSection),
+	}
+	if err := s.cm.Backup(sections[backup.BlocksSection], sections[backup.DBSection]); err != nil {
+		return err
+	}
+	w := backup.NewStreamWriter(func(data []byte) error {
+		return stream.Send(&pb.BackupChunk{Data: data})
+	})
+	if err := backup.Write(w, "peer", true, sections); err != nil {
+		return err
+	}
+	return w.Flush()
+}
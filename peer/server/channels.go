@@ -28,6 +28,10 @@ type ChannelManager struct {
 	db       db.DB
 	identity *core.Member
 	path     string
+	// compression is the compression of blocks stored
+	compression string
+	// window is the number of blocks fetched ahead
+	window int
 
 	// signalCh receive stop signal
 	signalCh chan bool
@@ -38,12 +42,22 @@ type ChannelManager struct {
 	// ConfigChannel is the config channel manager
 	ConfigChannel *channel.Manager
 	// AssetChannel is the asset channel manager
-	AssetChannel   *channel.Manager
+	AssetChannel *channel.Manager
 	// Channels manager all user channels
 	Channels map[string]*channel.Manager
 
 	coordinator    *channel.Coordinator
 	ordererClients []*orderer.Client
+
+	snapshot config.SnapshotConfig
+	// nextSnapshot is the height of _global to take the next snapshot
+	nextSnapshot uint64
+	snapshotting int32
+
+	// peers are the peers to compare state roots with
+	peers         []*statePeer
+	stateInterval time.Duration
+	comparing     int32
 }
 
 // NewChannelManager is the constructor of ChannelManager
@@ -65,6 +79,8 @@ func NewChannelManager(cfg *config.Config) (*ChannelManager, error) {
 	m.db = db
 	// set path
 	m.path = cfg.BlockChain.Path
+	m.compression = cfg.BlockChain.Compression
+	m.window = cfg.Orderer.Window
 	// set order clients
 	if m.ordererClients, err = getOrdererClients(cfg); err != nil {
 		return nil, err
@@ -73,6 +89,17 @@ func NewChannelManager(cfg *config.Config) (*ChannelManager, error) {
 	if err := m.loadChannels(); err != nil {
 		return nil, err
 	}
+	if err := m.loadBootstrap(); err != nil {
+		return nil, err
+	}
+	m.snapshot = cfg.Snapshot
+	if m.peers, err = getStatePeers(cfg); err != nil {
+		return nil, err
+	}
+	m.stateInterval = time.Duration(cfg.StateRoot.Interval) * time.Second
+	if m.snapshot.Interval != 0 {
+		m.nextSnapshot = (m.GlobalChannel.GetBlockSize()/m.snapshot.Interval + 1) * m.snapshot.Interval
+	}
 
 	return m, nil
 }
@@ -100,8 +127,25 @@ func (m *ChannelManager) start() error {
 	}
 
 	go func() {
+		// snapshotCh is nil if snapshots are disabled, so it never fires
+		var snapshotCh <-chan time.Time
+		if m.snapshot.Interval != 0 {
+			ticker := time.NewTicker(time.Second)
+			defer ticker.Stop()
+			snapshotCh = ticker.C
+		}
+		var compareCh <-chan time.Time
+		if len(m.peers) != 0 {
+			ticker := time.NewTicker(m.stateInterval)
+			defer ticker.Stop()
+			compareCh = ticker.C
+		}
 		for {
 			select {
+			case <-snapshotCh:
+				m.autoSnapshot()
+			case <-compareCh:
+				m.compareStateRoots()
 			case msg := <-updateCh:
 				// todo: support channel remove.
 				update := msg.(channel.Update)
@@ -143,6 +187,9 @@ func (m *ChannelManager) stop() {
 	for _, manager := range m.Channels {
 		manager.Stop()
 	}
+	for _, peer := range m.peers {
+		peer.conn.Close()
+	}
 }
 
 // hasChannel return if a channel exist
@@ -158,15 +205,15 @@ func (m *ChannelManager) hasChannel(channelID string) bool {
 // load system channels and user channels
 func (m *ChannelManager) loadChannels() error {
 	// set global channel manager
-	globalManager, err := channel.NewManager(core.GLOBALCHANNELID, fmt.Sprintf("%s/%s", m.path, core.GLOBALCHANNELID), m.identity, m.db, m.ordererClients, m.coordinator)
+	globalManager, err := m.newManager(core.GLOBALCHANNELID)
 	if err != nil {
 		return err
 	}
-	configManager, err := channel.NewManager(core.CONFIGCHANNELID, fmt.Sprintf("%s/%s", m.path, core.CONFIGCHANNELID), m.identity, m.db, m.ordererClients, m.coordinator)
+	configManager, err := m.newManager(core.CONFIGCHANNELID)
 	if err != nil {
 		return err
 	}
-	assetManager, err := channel.NewManager(core.ASSETCHANNELID, fmt.Sprintf("%s/%s", m.path, core.ASSETCHANNELID), m.identity, m.db, m.ordererClients, m.coordinator)
+	assetManager, err := m.newManager(core.ASSETCHANNELID)
 	if err != nil {
 		return err
 	}
@@ -183,6 +230,16 @@ func (m *ChannelManager) loadChannels() error {
 	return nil
 }
 
+// newManager return the manager of channel
+func (m *ChannelManager) newManager(channelID string) (*channel.Manager, error) {
+	manager, err := channel.NewManager(channelID, fmt.Sprintf("%s/%s", m.path, channelID), m.compression, m.identity, m.db, m.ordererClients, m.coordinator)
+	if err != nil {
+		return nil, err
+	}
+	manager.SetWindow(m.window)
+	return manager, nil
+}
+
 // loadChannel load a channel
 func (m *ChannelManager) loadChannel(channelID string) (*channel.Manager, error) {
 	m.lock.Lock()
@@ -190,7 +247,7 @@ func (m *ChannelManager) loadChannel(channelID string) (*channel.Manager, error)
 	if util.Contain(m.Channels, channelID) {
 		return m.Channels[channelID], nil
 	}
-	manager, err := channel.NewManager(channelID, fmt.Sprintf("%s/%s", m.path, channelID), m.identity, m.db, m.ordererClients, m.coordinator)
+	manager, err := m.newManager(channelID)
 	if err != nil {
 		return nil, err
 	}
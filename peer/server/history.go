@@ -0,0 +1,78 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"madledger/common"
+	"madledger/peer/db"
+	pb "madledger/protos"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// GetAccountAt is the implementation of protos, it returns the account in
+// the state of channel after block Num
+func (s *Server) GetAccountAt(ctx context.Context, req *pb.GetAccountAtRequest) (*pb.StateAccount, error) {
+	state, err := db.StateAt(s.cm.db, req.ChannelID, req.Num)
+	if err != nil {
+		return nil, err
+	}
+	address := common.BytesToAddress(req.Address)
+	account, err := state.GetAccount(address)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.StateAccount{
+		Num:     req.Num,
+		Exist:   state.AccountExist(address),
+		Balance: account.GetBalance(),
+		Code:    account.GetCode(),
+		Nonce:   account.GetNonce(),
+	}, nil
+}
+
+// GetStorageAt is the implementation of protos, it returns the storage in
+// the state of channel after block Num
+func (s *Server) GetStorageAt(ctx context.Context, req *pb.GetStorageAtRequest) (*pb.StateStorage, error) {
+	state, err := db.StateAt(s.cm.db, req.ChannelID, req.Num)
+	if err != nil {
+		return nil, err
+	}
+	value, err := state.GetStorage(common.BytesToAddress(req.Address), common.LeftPadWord256(req.Key))
+	if err != nil && err != leveldb.ErrNotFound {
+		return nil, err
+	}
+	return &pb.StateStorage{
+		Num:   req.Num,
+		Value: value.Bytes(),
+	}, nil
+}
+
+// CallAt is the implementation of protos, it calls the contract on the
+// state of channel after block Num and drops the changes
+func (s *Server) CallAt(ctx context.Context, req *pb.CallAtRequest) (*pb.CallResult, error) {
+	manager, ok := s.cm.managers()[req.ChannelID]
+	if !ok {
+		return nil, fmt.Errorf("Channel %s is not exist", req.ChannelID)
+	}
+	output, gasUsed, err := manager.Call(req.Num, common.BytesToAddress(req.Caller),
+		common.BytesToAddress(req.Contract), req.Payload, req.Gas)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CallResult{
+		Num:     req.Num,
+		Output:  output,
+		GasUsed: gasUsed,
+	}, nil
+}
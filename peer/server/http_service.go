@@ -4,7 +4,7 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"madledger/common"
-	"madledger/common/util"
+	"madledger/peer/db"
 	pb "madledger/protos"
 	"net/http"
 	"strconv"
@@ -34,14 +34,7 @@ func (hs *Server) GetTxStatusByHTTP(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	result := &pb.TxStatus{
-		Err:             status.Err,
-		BlockNumber:     status.BlockNumber,
-		BlockIndex:      int32(status.BlockIndex),
-		Output:          status.Output,
-		ContractAddress: status.ContractAddress,
-	}
-	c.JSON(http.StatusOK, gin.H{"status": result})
+	c.JSON(http.StatusOK, gin.H{"status": newTxStatus(status)})
 	return
 }
 
@@ -79,6 +72,7 @@ func (hs *Server) ListTxHistoryByHTTP(c *gin.Context) {
 type GetTokenInfoReq struct {
 	Addr      string `json:"address"`
 	ChannelID string `json:"channelid"`
+	AssetID   string `json:"asset"`
 }
 
 // GetTokenInfoByHTTP Get Token Info By HTTP
@@ -90,18 +84,27 @@ func (hs *Server) GetTokenInfoByHTTP(c *gin.Context) {
 	}
 	channelID := j.ChannelID
 	addr, err := hex.DecodeString(j.Addr)
-	key := util.BytesCombine(common.AddressFromChannelID(channelID).Bytes(), []byte("token"), addr)
-	tokenBytes, err := hs.cm.db.Get(key, false)
+	var token uint64
+	// token is only queried if channel is specified
+	if channelID != "" {
+		key := db.GetTokenKey(channelID, common.BytesToAddress(addr))
+		tokenBytes, err := hs.cm.db.Get(key, false)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if tokenBytes != nil {
+			token = uint64(binary.BigEndian.Uint64(tokenBytes))
+		}
+	}
+	account, err := hs.cm.db.GetOrCreateAccount(common.BytesToAddress(addr))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	var token uint64
-	if tokenBytes != nil {
-		token = uint64(binary.BigEndian.Uint64(tokenBytes))
-	}
 	info := &pb.TokenInfo{
-		Balance: token,
+		Balance:      token,
+		AssetBalance: account.GetAssetBalance(j.AssetID),
 	}
 	c.JSON(http.StatusOK, gin.H{"tokeninfo": info})
 	return
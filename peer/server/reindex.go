@@ -0,0 +1,187 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"madledger/core"
+	"madledger/peer/channel"
+	"madledger/peer/config"
+	"os"
+	"sort"
+
+	lerrors "github.com/syndtr/goleveldb/leveldb/errors"
+)
+
+// Progress is called after a block of channel is replayed, height is the
+// number of blocks stored in the channel
+type Progress func(channelID string, num, height uint64)
+
+// Reindex rebuilds the db of a stopped peer by running its blocks again,
+// the db is rebuilt aside and replaces the old one only if it succeeds
+func Reindex(cfg *config.Config, progress Progress) error {
+	dir := cfg.DB.LevelDB.Dir
+	// a corrupted db is the common reason to reindex, but a locked one is not
+	if db, err := newDB(dir); err == nil {
+		db.Close()
+	} else if !lerrors.IsCorrupted(err) {
+		return fmt.Errorf("Failed to open db, the peer may be running: %v", err)
+	}
+	staging := dir + ".reindex"
+	if err := os.RemoveAll(staging); err != nil {
+		return err
+	}
+	if err := reindex(cfg, staging, progress); err != nil {
+		os.RemoveAll(staging)
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	return os.Rename(staging, dir)
+}
+
+// reindex runs the blocks into the db at dbDir in the order recorded in
+// _global, then the blocks which are not recorded yet
+func reindex(cfg *config.Config, dbDir string, progress Progress) error {
+	m := new(ChannelManager)
+	m.Channels = make(map[string]*channel.Manager)
+	var err error
+	if m.identity, err = cfg.GetIdentity(); err != nil {
+		return err
+	}
+	if m.db, err = newDB(dbDir); err != nil {
+		return err
+	}
+	defer m.db.Close()
+	m.path = cfg.BlockChain.Path
+	m.compression = cfg.BlockChain.Compression
+	m.coordinator = channel.NewCoordinator()
+	if err := m.loadChannels(); err != nil {
+		return err
+	}
+	if m.GlobalChannel.GetBlockSize() == 0 {
+		return errors.New("There is no block to reindex")
+	}
+	if m.GlobalChannel.GetBase() != 0 {
+		return errors.New("The peer bootstrapped from a snapshot does not store the early blocks to reindex")
+	}
+
+	r := &reindexer{
+		m:        m,
+		next:     make(map[string]uint64),
+		progress: progress,
+	}
+	for _, channelID := range []string{core.CONFIGCHANNELID, core.GLOBALCHANNELID, core.ASSETCHANNELID} {
+		if err := r.replay(channelID, 0); err != nil {
+			return err
+		}
+	}
+	for num := uint64(1); num < m.GlobalChannel.GetBlockSize(); num++ {
+		if err := r.replay(core.GLOBALCHANNELID, num); err != nil {
+			return err
+		}
+		block, err := m.GlobalChannel.GetBlock(num)
+		if err != nil {
+			return err
+		}
+		for _, tx := range block.Transactions {
+			payload, err := tx.GetGlobalTxPayload()
+			if err != nil {
+				return err
+			}
+			if err := r.replay(payload.ChannelID, payload.Num); err != nil {
+				return err
+			}
+		}
+	}
+
+	// the blocks which are stored while _global is not, they are unlocked
+	// because _global will never record them
+	tail := []string{core.CONFIGCHANNELID, core.ASSETCHANNELID}
+	var channels []string
+	for _, channelID := range m.db.GetChannels() {
+		switch channelID {
+		case core.GLOBALCHANNELID, core.CONFIGCHANNELID, core.ASSETCHANNELID:
+		default:
+			channels = append(channels, channelID)
+		}
+	}
+	sort.Strings(channels)
+	for _, channelID := range append(tail, channels...) {
+		manager, err := r.manager(channelID)
+		if err != nil {
+			return err
+		}
+		if manager == nil || manager.GetBlockSize() == 0 {
+			continue
+		}
+		m.coordinator.Unlocks(map[string][]uint64{channelID: []uint64{manager.GetBlockSize() - 1}})
+		if err := r.replay(channelID, manager.GetBlockSize()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type reindexer struct {
+	m *ChannelManager
+	// next is the next block to replay of each channel
+	next     map[string]uint64
+	progress Progress
+}
+
+// manager return the manager of channel, or nil if the peer does not
+// belong to the channel
+func (r *reindexer) manager(channelID string) (*channel.Manager, error) {
+	switch channelID {
+	case core.GLOBALCHANNELID:
+		return r.m.GlobalChannel, nil
+	case core.CONFIGCHANNELID:
+		return r.m.ConfigChannel, nil
+	case core.ASSETCHANNELID:
+		return r.m.AssetChannel, nil
+	}
+	if !r.m.db.BelongChannel(channelID) {
+		return nil, nil
+	}
+	return r.m.loadChannel(channelID)
+}
+
+// replay runs the blocks of channel until num
+func (r *reindexer) replay(channelID string, num uint64) error {
+	manager, err := r.manager(channelID)
+	if err != nil || manager == nil {
+		return err
+	}
+	height := manager.GetBlockSize()
+	// the block may be recorded in _global while it is not stored
+	if num >= height {
+		if height == 0 {
+			return nil
+		}
+		num = height - 1
+	}
+	for ; r.next[channelID] <= num; r.next[channelID]++ {
+		block, err := manager.GetBlock(r.next[channelID])
+		if err != nil {
+			return err
+		}
+		if err := manager.AddBlock(block); err != nil {
+			return fmt.Errorf("Failed to replay block %d of channel %s: %v", block.Header.Number, channelID, err)
+		}
+		if r.progress != nil {
+			r.progress(channelID, block.Header.Number, height)
+		}
+	}
+	return nil
+}
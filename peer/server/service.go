@@ -14,7 +14,7 @@ import (
 	"context"
 	"encoding/binary"
 	"madledger/common"
-	"madledger/common/util"
+	"madledger/peer/db"
 	pb "madledger/protos"
 )
 
@@ -24,14 +24,34 @@ func (s *Server) GetTxStatus(ctx context.Context, req *pb.GetTxStatusRequest) (*
 	if err != nil {
 		return &pb.TxStatus{}, err
 	}
+	return newTxStatus(status), nil
+}
+
+// newTxStatus converts the status in db into protos
+func newTxStatus(status *db.TxStatus) *pb.TxStatus {
 	result := &pb.TxStatus{
 		Err:             status.Err,
 		BlockNumber:     status.BlockNumber,
 		BlockIndex:      int32(status.BlockIndex),
 		Output:          status.Output,
 		ContractAddress: status.ContractAddress,
+		Code:            pb.TxCode(status.Code),
+		Sender:          status.Sender,
+		GasLimit:        status.GasLimit,
+		GasUsed:         status.GasUsed,
+		Tokens:          status.Tokens,
+	}
+	for _, txLog := range status.Logs {
+		pbLog := &pb.TxLog{
+			Address: txLog.Address.Bytes(),
+			Data:    txLog.Data,
+		}
+		for _, topic := range txLog.Topics {
+			pbLog.Topics = append(pbLog.Topics, topic.Bytes())
+		}
+		result.Logs = append(result.Logs, pbLog)
 	}
-	return result, nil
+	return result
 }
 
 // ListTxHistory is the implementation of protos
@@ -55,16 +75,46 @@ func (s *Server) ListTxHistory(ctx context.Context, req *pb.ListTxHistoryRequest
 func (s *Server) GetTokenInfo(ctx context.Context, req *pb.GetTokenInfoRequest) (*pb.TokenInfo, error) {
 	var info pb.TokenInfo
 
+	// token is only queried if channel is specified
 	channelID := string(req.GetChannelID())
-	key := util.BytesCombine(common.AddressFromChannelID(channelID).Bytes(), []byte("token"), req.GetAddress())
-	tokenBytes, err := s.cm.db.Get(key, false)
+	if channelID != "" {
+		key := db.GetTokenKey(channelID, common.BytesToAddress(req.GetAddress()))
+		tokenBytes, err := s.cm.db.Get(key, false)
+		if err != nil {
+			return &info, err
+		}
+		var token uint64
+		if tokenBytes != nil {
+			token = uint64(binary.BigEndian.Uint64(tokenBytes))
+		}
+		info.Balance = token
+	}
+	account, err := s.cm.db.GetOrCreateAccount(common.BytesToAddress(req.GetAddress()))
 	if err != nil {
 		return &info, err
 	}
-	var token uint64
-	if tokenBytes != nil {
-		token = uint64(binary.BigEndian.Uint64(tokenBytes))
-	}
-	info.Balance = token
+	info.AssetBalance = account.GetAssetBalance(req.GetAssetID())
 	return &info, nil
 }
+
+// EstimateGas is the implementation of protos, it dry-runs the tx on the
+// state of channel after the latest block and return the least gas limit
+// the tx succeeds with
+func (s *Server) EstimateGas(ctx context.Context, req *pb.EstimateGasRequest) (*pb.GasEstimate, error) {
+	num, err := s.cm.stateNum(req.ChannelID, 0, true)
+	if err != nil {
+		return nil, err
+	}
+	manager := s.cm.managers()[req.ChannelID]
+	estimate, err := manager.EstimateGas(num, common.BytesToAddress(req.Caller),
+		common.BytesToAddress(req.Receiver), req.Payload, req.Value)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GasEstimate{
+		Num:     num,
+		Gas:     estimate.Gas,
+		GasUsed: estimate.GasUsed,
+		Tokens:  estimate.Tokens,
+	}, nil
+}
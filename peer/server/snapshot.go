@@ -0,0 +1,420 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"madledger/blockchain"
+	cc "madledger/blockchain/config"
+	"madledger/common"
+	"madledger/common/backup"
+	"madledger/common/crypto"
+	"madledger/common/crypto/hash"
+	"madledger/common/util"
+	"madledger/core"
+	"madledger/peer/config"
+	"madledger/peer/db"
+	"madledger/peer/snapshot"
+	pb "madledger/protos"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// bootstrapFile is the file in the dir of blocks which records the manifest
+// of snapshot the peer bootstraps from
+const bootstrapFile = ".snapshot"
+
+// Snapshot takes a snapshot of state into dir and return the file
+func (m *ChannelManager) Snapshot(dir string) (string, *snapshot.Manifest, error) {
+	tmp, err := ioutil.TempDir("", "peer-snapshot")
+	if err != nil {
+		return "", nil, err
+	}
+	defer os.RemoveAll(tmp)
+	manifest, err := m.freezeState(tmp)
+	if err != nil {
+		return "", nil, err
+	}
+	state, err := newDB(tmp)
+	if err != nil {
+		return "", nil, err
+	}
+	defer state.Close()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", nil, err
+	}
+	file := filepath.Join(dir, snapshot.FileName(manifest.Channels[core.GLOBALCHANNELID].Height))
+	f, err := os.Create(file + ".tmp")
+	if err != nil {
+		return "", nil, err
+	}
+	defer os.Remove(file + ".tmp")
+	err = snapshot.Write(f, manifest, state.Iterate)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if err := os.Rename(file+".tmp", file); err != nil {
+		return "", nil, err
+	}
+	return file, manifest, nil
+}
+
+// freezeState records the heights of channels and copies the db into dir
+// while channels are frozen, so they are consistent
+func (m *ChannelManager) freezeState(dir string) (*snapshot.Manifest, error) {
+	unfreeze := m.coordinator.Freeze()
+	defer unfreeze()
+
+	manifest := &snapshot.Manifest{
+		Time:     time.Now().Unix(),
+		Channels: make(map[string]*snapshot.Channel),
+		Unlocked: m.coordinator.Unlocked(),
+	}
+	for id, manager := range m.managers() {
+		height := manager.GetBlockSize()
+		if height == 0 {
+			continue
+		}
+		hash, err := manager.GetLastHash()
+		if err != nil {
+			return nil, err
+		}
+		manifest.Channels[id] = &snapshot.Channel{
+			Height: height,
+			Hash:   util.Hex(hash.Bytes()),
+		}
+	}
+	if manifest.Channels[core.GLOBALCHANNELID] == nil {
+		return nil, errors.New("There is no block to snapshot")
+	}
+	return manifest, m.db.Snapshot(dir)
+}
+
+// autoSnapshot takes a snapshot if _global grows Interval blocks since the
+// last one, and only the latest Keep snapshots are kept
+func (m *ChannelManager) autoSnapshot() {
+	height := m.GlobalChannel.GetBlockSize()
+	if height < m.nextSnapshot || !atomic.CompareAndSwapInt32(&m.snapshotting, 0, 1) {
+		return
+	}
+	m.nextSnapshot = (height/m.snapshot.Interval + 1) * m.snapshot.Interval
+	go func() {
+		defer atomic.StoreInt32(&m.snapshotting, 0)
+		file, manifest, err := m.Snapshot(m.snapshot.Path)
+		if err != nil {
+			log.Warnf("Failed to take snapshot: %v", err)
+			return
+		}
+		log.Infof("Take snapshot %s with %d records, hash: %s", file, manifest.Records, manifest.Hash)
+		files, err := snapshot.List(m.snapshot.Path)
+		if err != nil {
+			log.Warnf("Failed to list snapshots: %v", err)
+			return
+		}
+		for i := m.snapshot.Keep; i < len(files); i++ {
+			os.Remove(files[i])
+		}
+	}()
+}
+
+// loadBootstrap unlocks the blocks which were unlocked when the snapshot
+// that the peer bootstraps from was taken
+func (m *ChannelManager) loadBootstrap() error {
+	data, err := ioutil.ReadFile(filepath.Join(m.path, bootstrapFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var manifest snapshot.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return err
+	}
+	var nums = make(map[string][]uint64)
+	for id, num := range manifest.Unlocked {
+		nums[id] = []uint64{num}
+	}
+	m.coordinator.Unlocks(nums)
+	return nil
+}
+
+// fetchSnapshotExpiry is how long a signed FetchSnapshotRequest is valid
+const fetchSnapshotExpiry = time.Minute
+
+// NewFetchSnapshotRequest return the request signed by the key of peer, so
+// the peer sending the snapshot knows which channels the peer belongs to
+func NewFetchSnapshotRequest(key crypto.PrivateKey) (*pb.FetchSnapshotRequest, error) {
+	pk, err := key.PubKey().Bytes()
+	if err != nil {
+		return nil, err
+	}
+	req := &pb.FetchSnapshotRequest{
+		PK:   pk,
+		Algo: key.Algo(),
+		Time: time.Now().Unix(),
+	}
+	sig, err := key.Sign(fetchSnapshotHash(req))
+	if err != nil {
+		return nil, err
+	}
+	req.Sig, err = sig.Bytes()
+	return req, err
+}
+
+func fetchSnapshotHash(req *pb.FetchSnapshotRequest) []byte {
+	data := []byte(fmt.Sprintf("FetchSnapshot:%x:%d", req.PK, req.Time))
+	if req.Algo == crypto.KeyAlgoSecp256k1 {
+		return hash.SHA256(data)
+	}
+	return hash.SM3(data)
+}
+
+// getRequester return the peer which signs the request
+func getRequester(req *pb.FetchSnapshotRequest) (*core.Member, error) {
+	if d := time.Since(time.Unix(req.Time, 0)); d > fetchSnapshotExpiry || d < -fetchSnapshotExpiry {
+		return nil, errors.New("The request is expired")
+	}
+	pk, err := crypto.NewPublicKey(req.PK, req.Algo)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := crypto.NewSignature(req.Sig, req.Algo)
+	if err != nil {
+		return nil, err
+	}
+	if !sig.Verify(fetchSnapshotHash(req), pk) {
+		return nil, errors.New("The signature of request is invalid")
+	}
+	return core.NewMember(pk, "")
+}
+
+// FetchSnapshot is the implementation of protos, it sends the latest
+// snapshot without the private channels the requester does not belong to
+func (s *Server) FetchSnapshot(req *pb.FetchSnapshotRequest, stream pb.Peer_FetchSnapshotServer) error {
+	requester, err := getRequester(req)
+	if err != nil {
+		return err
+	}
+	if s.cfg.Snapshot.Interval == 0 {
+		return errors.New("The peer does not take snapshots")
+	}
+	files, err := snapshot.List(s.cfg.Snapshot.Path)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return errors.New("There is no snapshot yet")
+	}
+	manifest, err := snapshot.Verify(files[0])
+	if err != nil {
+		return err
+	}
+	var heights = make(map[string]uint64)
+	var excluded []string
+	for id, ch := range manifest.Channels {
+		heights[id] = ch.Height
+		switch id {
+		case core.GLOBALCHANNELID, core.CONFIGCHANNELID, core.ASSETCHANNELID:
+			continue
+		}
+		profile, err := s.cm.db.GetChannelProfile(id)
+		if err != nil {
+			return err
+		}
+		if !isMember(profile, requester) {
+			excluded = append(excluded, id)
+		}
+	}
+	filter, err := s.cm.db.ExcludeChannels(heights, excluded)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(files[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := backup.NewStreamWriter(func(data []byte) error {
+		return stream.Send(&pb.BackupChunk{Data: data})
+	})
+	if _, err := snapshot.Filter(f, w, excluded, filter); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// isMember return if the peer could run the channel, only the members listed
+// in a private channel could run it
+func isMember(profile *cc.Profile, identity *core.Member) bool {
+	if profile.Public {
+		return true
+	}
+	for _, member := range profile.Members {
+		if member.Equal(identity) {
+			return true
+		}
+	}
+	return false
+}
+
+// Bootstrap restores the state of a new peer from the snapshot file, the
+// peer syncs the blocks after the snapshot when it starts
+func Bootstrap(cfg *config.Config, file string) (*snapshot.Manifest, error) {
+	for _, dir := range []string{cfg.BlockChain.Path, cfg.DB.LevelDB.Dir} {
+		infos, err := ioutil.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if len(infos) != 0 {
+			return nil, fmt.Errorf("%s is not empty, only a new peer could bootstrap", dir)
+		}
+	}
+	manifest, err := bootstrap(cfg, file)
+	if err != nil {
+		os.RemoveAll(cfg.BlockChain.Path)
+		os.RemoveAll(cfg.DB.LevelDB.Dir)
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func bootstrap(cfg *config.Config, file string) (*snapshot.Manifest, error) {
+	identity, err := cfg.GetIdentity()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	state, err := newDB(cfg.DB.LevelDB.Dir)
+	if err != nil {
+		return nil, err
+	}
+	defer state.Close()
+
+	wb := state.NewWriteBatch()
+	var count int
+	manifest, err := snapshot.Read(f, func(key, value []byte) error {
+		wb.PutRecord(key, value)
+		if count++; count%1024 == 0 {
+			if err := wb.Sync(); err != nil {
+				return err
+			}
+			wb = state.NewWriteBatch()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := wb.Sync(); err != nil {
+		return nil, err
+	}
+	if manifest.Channels[core.GLOBALCHANNELID] == nil {
+		return nil, errors.New("There is no _global in the snapshot")
+	}
+	channels, err := joinChannels(state, identity, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	// the blocks before the snapshot are never stored
+	for id, ch := range manifest.Channels {
+		switch id {
+		case core.GLOBALCHANNELID, core.CONFIGCHANNELID, core.ASSETCHANNELID:
+		default:
+			if !util.Contain(channels, id) {
+				continue
+			}
+		}
+		cm, err := blockchain.NewManager(id, filepath.Join(cfg.BlockChain.Path, id), cfg.BlockChain.Compression)
+		if err != nil {
+			return nil, err
+		}
+		err = cm.StartAt(ch.Height, common.HexToHash(ch.Hash))
+		cm.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	return manifest, ioutil.WriteFile(filepath.Join(cfg.BlockChain.Path, bootstrapFile), data, 0644)
+}
+
+// joinChannels keeps the channels of snapshot which the peer belongs to,
+// the private channels which the snapshot does not cover are not joined and
+// their records are removed. A profile updated after creation may lose its
+// members, so only the members listed exclude the peer.
+func joinChannels(state db.DB, identity *core.Member, manifest *snapshot.Manifest) ([]string, error) {
+	var channels, excluded []string
+	for _, id := range state.GetChannels() {
+		profile, err := state.GetChannelProfile(id)
+		if err != nil {
+			return nil, err
+		}
+		if len(profile.Members) == 0 || isMember(profile, identity) {
+			channels = append(channels, id)
+		} else {
+			excluded = append(excluded, id)
+		}
+	}
+	if len(excluded) == 0 {
+		return channels, nil
+	}
+	var heights = make(map[string]uint64)
+	for id, ch := range manifest.Channels {
+		heights[id] = ch.Height
+	}
+	filter, err := state.ExcludeChannels(heights, excluded)
+	if err != nil {
+		return nil, err
+	}
+	wb := state.NewWriteBatch()
+	var count int
+	err = state.Iterate(func(key, value []byte) error {
+		kept, ok := filter(key, value)
+		switch {
+		case !ok:
+			wb.DeleteRecord(key)
+		case !bytes.Equal(kept, value):
+			wb.PutRecord(key, kept)
+		default:
+			return nil
+		}
+		if count++; count%1024 == 0 {
+			if err := wb.Sync(); err != nil {
+				return err
+			}
+			wb = state.NewWriteBatch()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return channels, wb.Sync()
+}
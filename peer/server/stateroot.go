@@ -0,0 +1,181 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"madledger/common"
+	"madledger/core"
+	"madledger/peer/channel"
+	"madledger/peer/config"
+	pb "madledger/protos"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// statePeer is a peer to compare state roots with
+type statePeer struct {
+	address string
+	conn    *grpc.ClientConn
+	client  pb.PeerClient
+}
+
+// Dial connects to the peer at address with the tls config of cfg
+func Dial(cfg *config.Config, address string) (*grpc.ClientConn, error) {
+	var opts []grpc.DialOption
+	if cfg.TLS.Enable {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			Certificates: []tls.Certificate{*(cfg.TLS.Cert)},
+			RootCAs:      cfg.TLS.Pool,
+		})))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+	return grpc.Dial(address, opts...)
+}
+
+func getStatePeers(cfg *config.Config) ([]*statePeer, error) {
+	var peers []*statePeer
+	for _, address := range cfg.StateRoot.Peers {
+		conn, err := Dial(cfg, address)
+		if err != nil {
+			return nil, err
+		}
+		peers = append(peers, &statePeer{
+			address: address,
+			conn:    conn,
+			client:  pb.NewPeerClient(conn),
+		})
+	}
+	return peers, nil
+}
+
+// compareStateRoots compares the latest state roots of channels with other
+// peers, and logs the mismatches as errors
+func (m *ChannelManager) compareStateRoots() {
+	if !atomic.CompareAndSwapInt32(&m.comparing, 0, 1) {
+		return
+	}
+	go func() {
+		defer atomic.StoreInt32(&m.comparing, 0)
+		for id, manager := range m.managers() {
+			switch id {
+			case core.GLOBALCHANNELID, core.CONFIGCHANNELID:
+				continue
+			}
+			height := manager.GetBlockSize()
+			if height == 0 {
+				continue
+			}
+			// the last block may be running
+			root, err := m.db.GetStateRoot(id, height-1)
+			if err != nil {
+				continue
+			}
+			for _, peer := range m.peers {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				remote, err := peer.client.GetStateRoot(ctx, &pb.GetStateRootRequest{
+					ChannelID: id,
+					Num:       height - 1,
+				})
+				cancel()
+				// the peer may be behind or not in the channel
+				if err != nil {
+					log.Debugf("Failed to get state root of channel %s from %s: %v", id, peer.address, err)
+					continue
+				}
+				if !bytes.Equal(root, remote.Root) {
+					log.Errorf("State root of block %d of channel %s mismatches, %x here but %x on peer %s",
+						height-1, id, root, remote.Root, peer.address)
+				}
+			}
+		}
+	}()
+}
+
+// managers return the managers of all channels
+func (m *ChannelManager) managers() map[string]*channel.Manager {
+	var managers = map[string]*channel.Manager{
+		core.GLOBALCHANNELID: m.GlobalChannel,
+		core.CONFIGCHANNELID: m.ConfigChannel,
+		core.ASSETCHANNELID:  m.AssetChannel,
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for id, manager := range m.Channels {
+		managers[id] = manager
+	}
+	return managers
+}
+
+// stateNum return the block of state queried, it is the last block if latest
+func (m *ChannelManager) stateNum(channelID string, num uint64, latest bool) (uint64, error) {
+	if !latest {
+		return num, nil
+	}
+	manager, ok := m.managers()[channelID]
+	if !ok || manager.GetBlockSize() == 0 {
+		return 0, fmt.Errorf("There is no block of channel %s", channelID)
+	}
+	return manager.GetBlockSize() - 1, nil
+}
+
+// GetStateRoot is the implementation of protos
+func (s *Server) GetStateRoot(ctx context.Context, req *pb.GetStateRootRequest) (*pb.StateRoot, error) {
+	num, err := s.cm.stateNum(req.ChannelID, req.Num, req.Latest)
+	if err != nil {
+		return nil, err
+	}
+	root, err := s.cm.db.GetStateRoot(req.ChannelID, num)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.StateRoot{
+		Num:  num,
+		Root: root,
+	}, nil
+}
+
+// GetStateProof is the implementation of protos
+func (s *Server) GetStateProof(ctx context.Context, req *pb.GetStateProofRequest) (*pb.StateProof, error) {
+	num, err := s.cm.stateNum(req.ChannelID, req.Num, req.Latest)
+	if err != nil {
+		return nil, err
+	}
+	root, err := s.cm.db.GetStateRoot(req.ChannelID, num)
+	if err != nil {
+		return nil, err
+	}
+	var slot []byte
+	if len(req.Slot) != 0 {
+		slot = req.Slot
+	}
+	key := core.StateKey(req.ChannelID, common.BytesToAddress(req.Address), slot)
+	value, proof, err := s.cm.db.GetStateProof(req.ChannelID, num, key)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.StateProof{
+		Num:       num,
+		Root:      root,
+		Key:       key,
+		Value:     value,
+		Siblings:  proof.Siblings,
+		KeyHash:   proof.KeyHash,
+		ValueHash: proof.ValueHash,
+	}, nil
+}
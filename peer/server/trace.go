@@ -0,0 +1,105 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"madledger/executor/evm"
+	pb "madledger/protos"
+)
+
+// The limits of a trace, the stack and memory are copied for each step, so
+// a trace without limits could use up the memory of peer
+const (
+	maxTraceSteps = 100000
+	maxTraceBytes = 32 << 20
+)
+
+// TraceTransaction is the implementation of protos
+func (s *Server) TraceTransaction(ctx context.Context, req *pb.TraceTransactionRequest) (*pb.TxTrace, error) {
+	manager, ok := s.cm.managers()[req.ChannelID]
+	if !ok {
+		return nil, fmt.Errorf("Channel %s is not exist", req.ChannelID)
+	}
+	config := evm.TraceConfig{
+		DisableStack:  req.DisableStack,
+		DisableMemory: req.DisableMemory,
+		MaxSteps:      maxTraceSteps,
+		MaxBytes:      maxTraceBytes,
+	}
+	if req.MaxSteps != 0 && req.MaxSteps < config.MaxSteps {
+		config.MaxSteps = req.MaxSteps
+	}
+	if req.MaxBytes != 0 && req.MaxBytes < config.MaxBytes {
+		config.MaxBytes = req.MaxBytes
+	}
+	trace, err := manager.TraceTx(req.TxID, config)
+	if err != nil {
+		return nil, err
+	}
+	result := &pb.TxTrace{
+		Status: newTxStatus(trace.Status),
+		Call:   newTraceCall(trace.Call),
+	}
+	for _, step := range trace.Steps {
+		pbStep := &pb.TraceStep{
+			Depth:   step.Depth,
+			PC:      step.PC,
+			Op:      step.Op,
+			Gas:     step.Gas,
+			GasCost: step.GasCost,
+			Memory:  step.Memory,
+			Storage: newStorageChange(step.Storage),
+		}
+		for _, word := range step.Stack {
+			pbStep.Stack = append(pbStep.Stack, word.Bytes())
+		}
+		result.Steps = append(result.Steps, pbStep)
+	}
+	for _, change := range trace.Storage {
+		result.StorageChanges = append(result.StorageChanges, newStorageChange(change))
+	}
+	return result, nil
+}
+
+func newTraceCall(call *evm.Call) *pb.TraceCall {
+	if call == nil {
+		return nil
+	}
+	result := &pb.TraceCall{
+		Op:      call.Op,
+		From:    call.From.Bytes(),
+		To:      call.To.Bytes(),
+		Input:   call.Input,
+		Output:  call.Output,
+		Value:   call.Value,
+		Gas:     call.Gas,
+		GasUsed: call.GasUsed,
+		Err:     call.Err,
+	}
+	for _, child := range call.Calls {
+		result.Calls = append(result.Calls, newTraceCall(child))
+	}
+	return result
+}
+
+func newStorageChange(change *evm.StorageChange) *pb.StorageChange {
+	if change == nil {
+		return nil
+	}
+	return &pb.StorageChange{
+		Address: change.Address.Bytes(),
+		Key:     change.Key.Bytes(),
+		Before:  change.Before.Bytes(),
+		After:   change.After.Bytes(),
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package snapshot
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+*  A snapshot is a gzip stream of
+*  1. Records: the records of db in the order of keys, each record is
+*     uvarint(len(key)) + key + uvarint(len(value)) + value
+*  2. End: uvarint(0), keys are never empty
+*  3. Manifest: the json of Manifest until EOF
+*  The hash of snapshot is the sha256 of records, so peers with the same
+*  state at the same heights have the same hash.
+ */
+
+const (
+	// Version is the version of snapshot format
+	Version = 1
+	// Ext is the extension of snapshot files
+	Ext = ".snap"
+	// MaxFieldSize is the max size of a key or value of records, the size is
+	// checked before reading the field
+	MaxFieldSize = 64 << 20
+)
+
+// Channel is the state of a channel in the snapshot
+type Channel struct {
+	// Height is the number of blocks run
+	Height uint64
+	// Hash is the hex of the hash of the last block run
+	Hash string
+}
+
+// Manifest describes the snapshot
+type Manifest struct {
+	Version  int
+	Time     int64
+	Channels map[string]*Channel
+	// Unlocked is the largest block of channels unlocked by _global
+	Unlocked map[string]uint64
+	Records  uint64
+	// Hash is the hex of the hash of records
+	Hash string
+}
+
+// Iterator calls fn with each record in the order of keys
+type Iterator func(fn func(key, value []byte) error) error
+
+// Write writes the records and the manifest into w, the Records and Hash
+// of manifest are set
+func Write(w io.Writer, manifest *Manifest, iterate Iterator) error {
+	zw := gzip.NewWriter(w)
+	bw := bufio.NewWriter(zw)
+	h := sha256.New()
+	mw := io.MultiWriter(bw, h)
+	var buf [binary.MaxVarintLen64]byte
+	manifest.Records = 0
+	err := iterate(func(key, value []byte) error {
+		if len(key) == 0 {
+			return nil
+		}
+		for _, data := range [][]byte{key, value} {
+			n := binary.PutUvarint(buf[:], uint64(len(data)))
+			if _, err := mw.Write(buf[:n]); err != nil {
+				return err
+			}
+			if _, err := mw.Write(data); err != nil {
+				return err
+			}
+		}
+		manifest.Records++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	manifest.Version = Version
+	manifest.Hash = hex.EncodeToString(h.Sum(nil))
+	if err := bw.WriteByte(0); err != nil {
+		return err
+	}
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	if _, err := bw.Write(data); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// Read calls put with each record in r and return the manifest, the
+// records are put before they are verified, so the caller should drop them
+// if it return an error. put could be nil to verify the snapshot only.
+func Read(r io.Reader, put func(key, value []byte) error) (*Manifest, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	h := sha256.New()
+	br := bufio.NewReader(zr)
+	var records uint64
+	for {
+		end, err := br.Peek(1)
+		if err != nil {
+			return nil, fmt.Errorf("The records of snapshot are damaged: %v", err)
+		}
+		if end[0] == 0 {
+			br.ReadByte()
+			break
+		}
+		key, err := readField(br, h)
+		if err != nil {
+			return nil, err
+		}
+		value, err := readField(br, h)
+		if err != nil {
+			return nil, err
+		}
+		if put != nil {
+			if err := put(key, value); err != nil {
+				return nil, err
+			}
+		}
+		records++
+	}
+	data, err := ioutil.ReadAll(br)
+	if err != nil {
+		return nil, err
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("The manifest of snapshot is damaged: %v", err)
+	}
+	if manifest.Version != Version {
+		return nil, fmt.Errorf("Unsupported snapshot version %d", manifest.Version)
+	}
+	if manifest.Records != records || manifest.Hash != hex.EncodeToString(h.Sum(nil)) {
+		return nil, errors.New("The records of snapshot do not match the hash")
+	}
+	return &manifest, nil
+}
+
+// Filter reads the snapshot in r and writes the records kept by filter into
+// w, the excluded channels are removed from the manifest. The snapshot in r
+// is verified after the records are written, so the caller should drop w if
+// it return an error.
+func Filter(r io.Reader, w io.Writer, excluded []string, filter func(key, value []byte) ([]byte, bool)) (*Manifest, error) {
+	var manifest = new(Manifest)
+	err := Write(w, manifest, func(fn func(key, value []byte) error) error {
+		read, err := Read(r, func(key, value []byte) error {
+			if value, ok := filter(key, value); ok {
+				return fn(key, value)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, id := range excluded {
+			delete(read.Channels, id)
+			delete(read.Unlocked, id)
+		}
+		manifest.Time = read.Time
+		manifest.Channels = read.Channels
+		manifest.Unlocked = read.Unlocked
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// readField reads a length prefixed field and writes it into h
+func readField(br *bufio.Reader, h hash.Hash) ([]byte, error) {
+	length, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("The records of snapshot are damaged: %v", err)
+	}
+	if length > MaxFieldSize {
+		return nil, fmt.Errorf("The records of snapshot are damaged: field of %d bytes", length)
+	}
+	var buf [binary.MaxVarintLen64]byte
+	h.Write(buf[:binary.PutUvarint(buf[:], length)])
+	data := make([]byte, length)
+	if _, err := io.ReadFull(br, data); err != nil {
+		return nil, fmt.Errorf("The records of snapshot are damaged: %v", err)
+	}
+	h.Write(data)
+	return data, nil
+}
+
+// Verify reads the snapshot file and return its manifest
+func Verify(file string) (*Manifest, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f, nil)
+}
+
+// FileName return the name of snapshot taken at the height of _global
+func FileName(height uint64) string {
+	return fmt.Sprintf("snapshot-%d%s", height, Ext)
+}
+
+// List return the snapshot files in dir, the latest is the first
+func List(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var heights []uint64
+	for _, info := range infos {
+		name := info.Name()
+		if !strings.HasPrefix(name, "snapshot-") || !strings.HasSuffix(name, Ext) {
+			continue
+		}
+		height, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(name, "snapshot-"), Ext), 10, 64)
+		if err != nil {
+			continue
+		}
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] > heights[j] })
+	var files []string
+	for _, height := range heights {
+		files = append(files, filepath.Join(dir, FileName(height)))
+	}
+	return files, nil
+}
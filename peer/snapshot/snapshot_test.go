@@ -0,0 +1,159 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package snapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testRecords = [][2]string{
+	{"account_1", "100"},
+	{"channels", "[\"test\"]"},
+	{"storage_1", ""},
+}
+
+func iterateTestRecords(fn func(key, value []byte) error) error {
+	for _, record := range testRecords {
+		if err := fn([]byte(record[0]), []byte(record[1])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeTestSnapshot(t *testing.T) ([]byte, *Manifest) {
+	manifest := &Manifest{
+		Channels: map[string]*Channel{"_global": &Channel{Height: 10, Hash: "00"}},
+		Unlocked: map[string]uint64{"test": 3},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, manifest, iterateTestRecords))
+	require.Equal(t, uint64(len(testRecords)), manifest.Records)
+	return buf.Bytes(), manifest
+}
+
+func TestReadWrite(t *testing.T) {
+	data, manifest := writeTestSnapshot(t)
+	var records [][2]string
+	read, err := Read(bytes.NewReader(data), func(key, value []byte) error {
+		records = append(records, [2]string{string(key), string(value)})
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, testRecords, records)
+	require.Equal(t, manifest, read)
+
+	// the same records have the same hash
+	data2, manifest2 := writeTestSnapshot(t)
+	require.Equal(t, manifest.Hash, manifest2.Hash)
+	_, err = Read(bytes.NewReader(data2), nil)
+	require.NoError(t, err)
+}
+
+func TestTamper(t *testing.T) {
+	data, manifest := writeTestSnapshot(t)
+	_, err := Read(bytes.NewReader(data[:len(data)/2]), nil)
+	require.Error(t, err)
+
+	// other records with the manifest of data
+	testRecords[0][1] = "1000"
+	defer func() { testRecords[0][1] = "100" }()
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, new(Manifest), iterateTestRecords))
+	zr, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	raw, err := ioutil.ReadAll(zr)
+	require.NoError(t, err)
+	raw = raw[:bytes.LastIndexByte(raw, 0)+1]
+	forged, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	buf.Reset()
+	zw := gzip.NewWriter(&buf)
+	zw.Write(append(raw, forged...))
+	require.NoError(t, zw.Close())
+	_, err = Read(&buf, nil)
+	require.Error(t, err)
+}
+
+func TestList(t *testing.T) {
+	dir := ".snapshots"
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+	files, err := List(dir)
+	require.NoError(t, err)
+	require.Empty(t, files)
+
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	for _, name := range []string{FileName(100), FileName(20), FileName(3), "snapshot-x.snap", FileName(200) + ".tmp"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), nil, 0644))
+	}
+	files, err = List(dir)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(dir, FileName(100)),
+		filepath.Join(dir, FileName(20)),
+		filepath.Join(dir, FileName(3)),
+	}, files)
+}
+
+func TestFilter(t *testing.T) {
+	data, manifest := writeTestSnapshot(t)
+	manifest.Channels["test"] = &Channel{Height: 3, Hash: "01"}
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, manifest, iterateTestRecords))
+	var out bytes.Buffer
+	filtered, err := Filter(&buf, &out, []string{"test"}, func(key, value []byte) ([]byte, bool) {
+		if string(key) == "channels" {
+			return []byte("[]"), true
+		}
+		return value, string(key) != "storage_1"
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), filtered.Records)
+	require.NotContains(t, filtered.Channels, "test")
+	require.Empty(t, filtered.Unlocked)
+	var records [][2]string
+	read, err := Read(&out, func(key, value []byte) error {
+		records = append(records, [2]string{string(key), string(value)})
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, filtered, read)
+	require.Equal(t, [][2]string{{"account_1", "100"}, {"channels", "[]"}}, records)
+
+	// the snapshot in r is verified
+	out.Reset()
+	_, err = Filter(bytes.NewReader(data[:len(data)/2]), &out, nil, func(key, value []byte) ([]byte, bool) {
+		return value, true
+	})
+	require.Error(t, err)
+}
+
+func TestFieldSize(t *testing.T) {
+	var raw bytes.Buffer
+	var buf [binary.MaxVarintLen64]byte
+	raw.Write(buf[:binary.PutUvarint(buf[:], 1<<62)])
+	var data bytes.Buffer
+	zw := gzip.NewWriter(&data)
+	zw.Write(raw.Bytes())
+	require.NoError(t, zw.Close())
+	_, err := Read(&data, nil)
+	require.Error(t, err)
+}
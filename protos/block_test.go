@@ -63,6 +63,30 @@ func TestConvertBlock(t *testing.T) {
 	}
 }
 
+func TestConvertLegacyTx(t *testing.T) {
+	rawPrivKey, _ := hex.DecodeString("289c2857d4598e37fb9647507e47a309d6133539bf21a8b9cb6df88fd5232032")
+	privKey, _ := crypto.NewPrivateKey(rawPrivKey, crypto.KeyAlgoSecp256k1)
+	tx, err := core.NewTx("test", common.ZeroAddress, []byte("Hello World"), 0, "", privKey)
+	require.NoError(t, err)
+	pbTx, err := NewTx(tx)
+	require.NoError(t, err)
+	require.Nil(t, pbTx.Data)
+	raw, err := tx.Bytes()
+	require.NoError(t, err)
+	require.Equal(t, raw, pbTx.Raw)
+
+	// the old clients set the fields instead of Raw
+	legacyTx := &Tx{
+		ID:   tx.ID,
+		Data: NewTxData(&tx.Data),
+		Time: tx.Time,
+	}
+	coreTx, err := legacyTx.ToCore()
+	require.NoError(t, err)
+	require.True(t, coreTx.Verify())
+	require.Equal(t, tx.Hash(), coreTx.Hash())
+}
+
 func convertTypesBlock(block *core.Block) (*core.Block, error) {
 	pbBlock, err := NewBlock(block)
 	if err != nil {
@@ -46,7 +46,7 @@ func (x Behavior) String() string {
 	return proto.EnumName(Behavior_name, int32(x))
 }
 func (Behavior) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_service_cabd911633115393, []int{0}
+	return fileDescriptor_service_ae0ad5e10418a490, []int{0}
 }
 
 // Identity defines the identity in the channel
@@ -73,7 +73,40 @@ func (x Identity) String() string {
 	return proto.EnumName(Identity_name, int32(x))
 }
 func (Identity) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_service_cabd911633115393, []int{1}
+	return fileDescriptor_service_ae0ad5e10418a490, []int{1}
+}
+
+// TxCode is the code of tx status
+type TxCode int32
+
+const (
+	// The status is stored before codes
+	TxCode_UNKNOWN TxCode = 0
+	TxCode_SUCCESS TxCode = 1
+	// The tx is rejected before running, nothing is charged
+	TxCode_FAILED TxCode = 2
+	// The tx fails in the evm, the gas used is charged
+	TxCode_REVERTED TxCode = 3
+)
+
+var TxCode_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "SUCCESS",
+	2: "FAILED",
+	3: "REVERTED",
+}
+var TxCode_value = map[string]int32{
+	"UNKNOWN":  0,
+	"SUCCESS":  1,
+	"FAILED":   2,
+	"REVERTED": 3,
+}
+
+func (x TxCode) String() string {
+	return proto.EnumName(TxCode_name, int32(x))
+}
+func (TxCode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{2}
 }
 
 // However, this is not contains sig now, but this is necessary
@@ -92,7 +125,7 @@ func (m *FetchBlockRequest) Reset()         { *m = FetchBlockRequest{} }
 func (m *FetchBlockRequest) String() string { return proto.CompactTextString(m) }
 func (*FetchBlockRequest) ProtoMessage()    {}
 func (*FetchBlockRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_cabd911633115393, []int{0}
+	return fileDescriptor_service_ae0ad5e10418a490, []int{0}
 }
 func (m *FetchBlockRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FetchBlockRequest.Unmarshal(m, b)
@@ -148,7 +181,7 @@ func (m *ListChannelsRequest) Reset()         { *m = ListChannelsRequest{} }
 func (m *ListChannelsRequest) String() string { return proto.CompactTextString(m) }
 func (*ListChannelsRequest) ProtoMessage()    {}
 func (*ListChannelsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_cabd911633115393, []int{1}
+	return fileDescriptor_service_ae0ad5e10418a490, []int{1}
 }
 func (m *ListChannelsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListChannelsRequest.Unmarshal(m, b)
@@ -201,7 +234,7 @@ func (m *ChannelInfos) Reset()         { *m = ChannelInfos{} }
 func (m *ChannelInfos) String() string { return proto.CompactTextString(m) }
 func (*ChannelInfos) ProtoMessage()    {}
 func (*ChannelInfos) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_cabd911633115393, []int{2}
+	return fileDescriptor_service_ae0ad5e10418a490, []int{2}
 }
 func (m *ChannelInfos) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChannelInfos.Unmarshal(m, b)
@@ -245,7 +278,7 @@ func (m *ChannelInfo) Reset()         { *m = ChannelInfo{} }
 func (m *ChannelInfo) String() string { return proto.CompactTextString(m) }
 func (*ChannelInfo) ProtoMessage()    {}
 func (*ChannelInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_cabd911633115393, []int{3}
+	return fileDescriptor_service_ae0ad5e10418a490, []int{3}
 }
 func (m *ChannelInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChannelInfo.Unmarshal(m, b)
@@ -319,7 +352,7 @@ func (m *CreateChannelRequest) Reset()         { *m = CreateChannelRequest{} }
 func (m *CreateChannelRequest) String() string { return proto.CompactTextString(m) }
 func (*CreateChannelRequest) ProtoMessage()    {}
 func (*CreateChannelRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_cabd911633115393, []int{4}
+	return fileDescriptor_service_ae0ad5e10418a490, []int{4}
 }
 func (m *CreateChannelRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateChannelRequest.Unmarshal(m, b)
@@ -358,7 +391,7 @@ func (m *CreateChannelTxPayload) Reset()         { *m = CreateChannelTxPayload{}
 func (m *CreateChannelTxPayload) String() string { return proto.CompactTextString(m) }
 func (*CreateChannelTxPayload) ProtoMessage()    {}
 func (*CreateChannelTxPayload) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_cabd911633115393, []int{5}
+	return fileDescriptor_service_ae0ad5e10418a490, []int{5}
 }
 func (m *CreateChannelTxPayload) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_CreateChannelTxPayload.Unmarshal(m, b)
@@ -396,7 +429,7 @@ func (m *AddTxRequest) Reset()         { *m = AddTxRequest{} }
 func (m *AddTxRequest) String() string { return proto.CompactTextString(m) }
 func (*AddTxRequest) ProtoMessage()    {}
 func (*AddTxRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_cabd911633115393, []int{6}
+	return fileDescriptor_service_ae0ad5e10418a490, []int{6}
 }
 func (m *AddTxRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_AddTxRequest.Unmarshal(m, b)
@@ -423,13 +456,20 @@ func (m *AddTxRequest) GetTx() *Tx {
 	return nil
 }
 
-// TxStatus include nothing now.
+// TxStatus is the receipt of tx
 type TxStatus struct {
-	Err                  string   `protobuf:"bytes,1,opt,name=Err,proto3" json:"Err,omitempty"`
-	BlockNumber          uint64   `protobuf:"varint,2,opt,name=BlockNumber,proto3" json:"BlockNumber,omitempty"`
-	BlockIndex           int32    `protobuf:"varint,3,opt,name=BlockIndex,proto3" json:"BlockIndex,omitempty"`
-	Output               []byte   `protobuf:"bytes,4,opt,name=Output,proto3" json:"Output,omitempty"`
-	ContractAddress      string   `protobuf:"bytes,5,opt,name=ContractAddress,proto3" json:"ContractAddress,omitempty"`
+	Err             string `protobuf:"bytes,1,opt,name=Err,proto3" json:"Err,omitempty"`
+	BlockNumber     uint64 `protobuf:"varint,2,opt,name=BlockNumber,proto3" json:"BlockNumber,omitempty"`
+	BlockIndex      int32  `protobuf:"varint,3,opt,name=BlockIndex,proto3" json:"BlockIndex,omitempty"`
+	Output          []byte `protobuf:"bytes,4,opt,name=Output,proto3" json:"Output,omitempty"`
+	ContractAddress string `protobuf:"bytes,5,opt,name=ContractAddress,proto3" json:"ContractAddress,omitempty"`
+	Code            TxCode `protobuf:"varint,6,opt,name=Code,proto3,enum=protos.TxCode" json:"Code,omitempty"`
+	Sender          string `protobuf:"bytes,7,opt,name=Sender,proto3" json:"Sender,omitempty"`
+	GasLimit        uint64 `protobuf:"varint,8,opt,name=GasLimit,proto3" json:"GasLimit,omitempty"`
+	GasUsed         uint64 `protobuf:"varint,9,opt,name=GasUsed,proto3" json:"GasUsed,omitempty"`
+	// Tokens charged for the gas used, or burned before redeeming
+	Tokens               uint64   `protobuf:"varint,10,opt,name=Tokens,proto3" json:"Tokens,omitempty"`
+	Logs                 []*TxLog `protobuf:"bytes,11,rep,name=Logs,proto3" json:"Logs,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -439,7 +479,7 @@ func (m *TxStatus) Reset()         { *m = TxStatus{} }
 func (m *TxStatus) String() string { return proto.CompactTextString(m) }
 func (*TxStatus) ProtoMessage()    {}
 func (*TxStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_cabd911633115393, []int{7}
+	return fileDescriptor_service_ae0ad5e10418a490, []int{7}
 }
 func (m *TxStatus) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxStatus.Unmarshal(m, b)
@@ -494,326 +534,2302 @@ func (m *TxStatus) GetContractAddress() string {
 	return ""
 }
 
-type GetTxStatusRequest struct {
-	ChannelID            string   `protobuf:"bytes,1,opt,name=ChannelID,proto3" json:"ChannelID,omitempty"`
-	TxID                 string   `protobuf:"bytes,2,opt,name=TxID,proto3" json:"TxID,omitempty"`
-	Behavior             Behavior `protobuf:"varint,3,opt,name=Behavior,proto3,enum=protos.Behavior" json:"Behavior,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *TxStatus) GetCode() TxCode {
+	if m != nil {
+		return m.Code
+	}
+	return TxCode_UNKNOWN
 }
 
-func (m *GetTxStatusRequest) Reset()         { *m = GetTxStatusRequest{} }
-func (m *GetTxStatusRequest) String() string { return proto.CompactTextString(m) }
-func (*GetTxStatusRequest) ProtoMessage()    {}
-func (*GetTxStatusRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_cabd911633115393, []int{8}
-}
-func (m *GetTxStatusRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetTxStatusRequest.Unmarshal(m, b)
-}
-func (m *GetTxStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetTxStatusRequest.Marshal(b, m, deterministic)
-}
-func (dst *GetTxStatusRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetTxStatusRequest.Merge(dst, src)
-}
-func (m *GetTxStatusRequest) XXX_Size() int {
-	return xxx_messageInfo_GetTxStatusRequest.Size(m)
-}
-func (m *GetTxStatusRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetTxStatusRequest.DiscardUnknown(m)
+func (m *TxStatus) GetSender() string {
+	if m != nil {
+		return m.Sender
+	}
+	return ""
 }
 
-var xxx_messageInfo_GetTxStatusRequest proto.InternalMessageInfo
+func (m *TxStatus) GetGasLimit() uint64 {
+	if m != nil {
+		return m.GasLimit
+	}
+	return 0
+}
 
-func (m *GetTxStatusRequest) GetChannelID() string {
+func (m *TxStatus) GetGasUsed() uint64 {
 	if m != nil {
-		return m.ChannelID
+		return m.GasUsed
 	}
-	return ""
+	return 0
 }
 
-func (m *GetTxStatusRequest) GetTxID() string {
+func (m *TxStatus) GetTokens() uint64 {
 	if m != nil {
-		return m.TxID
+		return m.Tokens
 	}
-	return ""
+	return 0
 }
 
-func (m *GetTxStatusRequest) GetBehavior() Behavior {
+func (m *TxStatus) GetLogs() []*TxLog {
 	if m != nil {
-		return m.Behavior
+		return m.Logs
 	}
-	return Behavior_FAIL_IF_NOT_READY
+	return nil
 }
 
-type ListTxHistoryRequest struct {
+type TxLog struct {
 	Address              []byte   `protobuf:"bytes,1,opt,name=Address,proto3" json:"Address,omitempty"`
+	Topics               [][]byte `protobuf:"bytes,2,rep,name=Topics,proto3" json:"Topics,omitempty"`
+	Data                 []byte   `protobuf:"bytes,3,opt,name=Data,proto3" json:"Data,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *ListTxHistoryRequest) Reset()         { *m = ListTxHistoryRequest{} }
-func (m *ListTxHistoryRequest) String() string { return proto.CompactTextString(m) }
-func (*ListTxHistoryRequest) ProtoMessage()    {}
-func (*ListTxHistoryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_cabd911633115393, []int{9}
+func (m *TxLog) Reset()         { *m = TxLog{} }
+func (m *TxLog) String() string { return proto.CompactTextString(m) }
+func (*TxLog) ProtoMessage()    {}
+func (*TxLog) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{8}
 }
-func (m *ListTxHistoryRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_ListTxHistoryRequest.Unmarshal(m, b)
+func (m *TxLog) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxLog.Unmarshal(m, b)
 }
-func (m *ListTxHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_ListTxHistoryRequest.Marshal(b, m, deterministic)
+func (m *TxLog) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxLog.Marshal(b, m, deterministic)
 }
-func (dst *ListTxHistoryRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_ListTxHistoryRequest.Merge(dst, src)
+func (dst *TxLog) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxLog.Merge(dst, src)
 }
-func (m *ListTxHistoryRequest) XXX_Size() int {
-	return xxx_messageInfo_ListTxHistoryRequest.Size(m)
+func (m *TxLog) XXX_Size() int {
+	return xxx_messageInfo_TxLog.Size(m)
 }
-func (m *ListTxHistoryRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_ListTxHistoryRequest.DiscardUnknown(m)
+func (m *TxLog) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxLog.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_ListTxHistoryRequest proto.InternalMessageInfo
+var xxx_messageInfo_TxLog proto.InternalMessageInfo
 
-func (m *ListTxHistoryRequest) GetAddress() []byte {
+func (m *TxLog) GetAddress() []byte {
 	if m != nil {
 		return m.Address
 	}
 	return nil
 }
 
-// TxHistory includes all txs
-type TxHistory struct {
-	// repeated string Txs = 1;
-	Txs                  map[string]*StringList `protobuf:"bytes,1,rep,name=Txs,proto3" json:"Txs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
-	XXX_unrecognized     []byte                 `json:"-"`
-	XXX_sizecache        int32                  `json:"-"`
-}
-
-func (m *TxHistory) Reset()         { *m = TxHistory{} }
-func (m *TxHistory) String() string { return proto.CompactTextString(m) }
-func (*TxHistory) ProtoMessage()    {}
-func (*TxHistory) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_cabd911633115393, []int{10}
-}
-func (m *TxHistory) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_TxHistory.Unmarshal(m, b)
-}
-func (m *TxHistory) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_TxHistory.Marshal(b, m, deterministic)
-}
-func (dst *TxHistory) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TxHistory.Merge(dst, src)
-}
-func (m *TxHistory) XXX_Size() int {
-	return xxx_messageInfo_TxHistory.Size(m)
-}
-func (m *TxHistory) XXX_DiscardUnknown() {
-	xxx_messageInfo_TxHistory.DiscardUnknown(m)
+func (m *TxLog) GetTopics() [][]byte {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
 }
 
-var xxx_messageInfo_TxHistory proto.InternalMessageInfo
-
-func (m *TxHistory) GetTxs() map[string]*StringList {
+func (m *TxLog) GetData() []byte {
 	if m != nil {
-		return m.Txs
+		return m.Data
 	}
 	return nil
 }
 
-type GetAccountInfoRequest struct {
-	Address              []byte   `protobuf:"bytes,1,opt,name=Address,proto3" json:"Address,omitempty"`
+type GetStateRootRequest struct {
+	ChannelID string `protobuf:"bytes,1,opt,name=ChannelID,proto3" json:"ChannelID,omitempty"`
+	// Num is ignored if Latest is true
+	Num                  uint64   `protobuf:"varint,2,opt,name=Num,proto3" json:"Num,omitempty"`
+	Latest               bool     `protobuf:"varint,3,opt,name=Latest,proto3" json:"Latest,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GetAccountInfoRequest) Reset()         { *m = GetAccountInfoRequest{} }
-func (m *GetAccountInfoRequest) String() string { return proto.CompactTextString(m) }
-func (*GetAccountInfoRequest) ProtoMessage()    {}
-func (*GetAccountInfoRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_cabd911633115393, []int{11}
+func (m *GetStateRootRequest) Reset()         { *m = GetStateRootRequest{} }
+func (m *GetStateRootRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStateRootRequest) ProtoMessage()    {}
+func (*GetStateRootRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{9}
 }
-func (m *GetAccountInfoRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetAccountInfoRequest.Unmarshal(m, b)
+func (m *GetStateRootRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStateRootRequest.Unmarshal(m, b)
 }
-func (m *GetAccountInfoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetAccountInfoRequest.Marshal(b, m, deterministic)
+func (m *GetStateRootRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStateRootRequest.Marshal(b, m, deterministic)
 }
-func (dst *GetAccountInfoRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetAccountInfoRequest.Merge(dst, src)
+func (dst *GetStateRootRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStateRootRequest.Merge(dst, src)
 }
-func (m *GetAccountInfoRequest) XXX_Size() int {
-	return xxx_messageInfo_GetAccountInfoRequest.Size(m)
+func (m *GetStateRootRequest) XXX_Size() int {
+	return xxx_messageInfo_GetStateRootRequest.Size(m)
 }
-func (m *GetAccountInfoRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetAccountInfoRequest.DiscardUnknown(m)
+func (m *GetStateRootRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStateRootRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetAccountInfoRequest proto.InternalMessageInfo
+var xxx_messageInfo_GetStateRootRequest proto.InternalMessageInfo
 
-func (m *GetAccountInfoRequest) GetAddress() []byte {
+func (m *GetStateRootRequest) GetChannelID() string {
 	if m != nil {
-		return m.Address
+		return m.ChannelID
 	}
-	return nil
+	return ""
 }
 
-type AccountInfo struct {
-	Balance              uint64   `protobuf:"varint,1,opt,name=Balance,proto3" json:"Balance,omitempty"`
+func (m *GetStateRootRequest) GetNum() uint64 {
+	if m != nil {
+		return m.Num
+	}
+	return 0
+}
+
+func (m *GetStateRootRequest) GetLatest() bool {
+	if m != nil {
+		return m.Latest
+	}
+	return false
+}
+
+// StateRoot is the root of state tree of a channel after block Num
+type StateRoot struct {
+	Num                  uint64   `protobuf:"varint,1,opt,name=Num,proto3" json:"Num,omitempty"`
+	Root                 []byte   `protobuf:"bytes,2,opt,name=Root,proto3" json:"Root,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *AccountInfo) Reset()         { *m = AccountInfo{} }
-func (m *AccountInfo) String() string { return proto.CompactTextString(m) }
-func (*AccountInfo) ProtoMessage()    {}
-func (*AccountInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_cabd911633115393, []int{12}
+func (m *StateRoot) Reset()         { *m = StateRoot{} }
+func (m *StateRoot) String() string { return proto.CompactTextString(m) }
+func (*StateRoot) ProtoMessage()    {}
+func (*StateRoot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{10}
 }
-func (m *AccountInfo) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_AccountInfo.Unmarshal(m, b)
+func (m *StateRoot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateRoot.Unmarshal(m, b)
 }
-func (m *AccountInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_AccountInfo.Marshal(b, m, deterministic)
+func (m *StateRoot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateRoot.Marshal(b, m, deterministic)
 }
-func (dst *AccountInfo) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_AccountInfo.Merge(dst, src)
+func (dst *StateRoot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateRoot.Merge(dst, src)
 }
-func (m *AccountInfo) XXX_Size() int {
-	return xxx_messageInfo_AccountInfo.Size(m)
+func (m *StateRoot) XXX_Size() int {
+	return xxx_messageInfo_StateRoot.Size(m)
 }
-func (m *AccountInfo) XXX_DiscardUnknown() {
-	xxx_messageInfo_AccountInfo.DiscardUnknown(m)
+func (m *StateRoot) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateRoot.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_AccountInfo proto.InternalMessageInfo
+var xxx_messageInfo_StateRoot proto.InternalMessageInfo
 
-func (m *AccountInfo) GetBalance() uint64 {
+func (m *StateRoot) GetNum() uint64 {
 	if m != nil {
-		return m.Balance
+		return m.Num
 	}
 	return 0
 }
 
-type GetTokenInfoRequest struct {
-	Address              []byte   `protobuf:"bytes,1,opt,name=Address,proto3" json:"Address,omitempty"`
-	ChannelID            []byte   `protobuf:"bytes,2,opt,name=ChannelID,proto3" json:"ChannelID,omitempty"`
+func (m *StateRoot) GetRoot() []byte {
+	if m != nil {
+		return m.Root
+	}
+	return nil
+}
+
+type GetStateProofRequest struct {
+	ChannelID string `protobuf:"bytes,1,opt,name=ChannelID,proto3" json:"ChannelID,omitempty"`
+	// Num is ignored if Latest is true
+	Num     uint64 `protobuf:"varint,2,opt,name=Num,proto3" json:"Num,omitempty"`
+	Latest  bool   `protobuf:"varint,3,opt,name=Latest,proto3" json:"Latest,omitempty"`
+	Address []byte `protobuf:"bytes,4,opt,name=Address,proto3" json:"Address,omitempty"`
+	// Slot is empty to prove the account
+	Slot                 []byte   `protobuf:"bytes,5,opt,name=Slot,proto3" json:"Slot,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *GetTokenInfoRequest) Reset()         { *m = GetTokenInfoRequest{} }
-func (m *GetTokenInfoRequest) String() string { return proto.CompactTextString(m) }
-func (*GetTokenInfoRequest) ProtoMessage()    {}
-func (*GetTokenInfoRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_cabd911633115393, []int{13}
+func (m *GetStateProofRequest) Reset()         { *m = GetStateProofRequest{} }
+func (m *GetStateProofRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStateProofRequest) ProtoMessage()    {}
+func (*GetStateProofRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{11}
 }
-func (m *GetTokenInfoRequest) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_GetTokenInfoRequest.Unmarshal(m, b)
+func (m *GetStateProofRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStateProofRequest.Unmarshal(m, b)
 }
-func (m *GetTokenInfoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_GetTokenInfoRequest.Marshal(b, m, deterministic)
+func (m *GetStateProofRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStateProofRequest.Marshal(b, m, deterministic)
 }
-func (dst *GetTokenInfoRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_GetTokenInfoRequest.Merge(dst, src)
+func (dst *GetStateProofRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStateProofRequest.Merge(dst, src)
 }
-func (m *GetTokenInfoRequest) XXX_Size() int {
-	return xxx_messageInfo_GetTokenInfoRequest.Size(m)
+func (m *GetStateProofRequest) XXX_Size() int {
+	return xxx_messageInfo_GetStateProofRequest.Size(m)
 }
-func (m *GetTokenInfoRequest) XXX_DiscardUnknown() {
-	xxx_messageInfo_GetTokenInfoRequest.DiscardUnknown(m)
+func (m *GetStateProofRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStateProofRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_GetTokenInfoRequest proto.InternalMessageInfo
+var xxx_messageInfo_GetStateProofRequest proto.InternalMessageInfo
 
-func (m *GetTokenInfoRequest) GetAddress() []byte {
+func (m *GetStateProofRequest) GetChannelID() string {
+	if m != nil {
+		return m.ChannelID
+	}
+	return ""
+}
+
+func (m *GetStateProofRequest) GetNum() uint64 {
+	if m != nil {
+		return m.Num
+	}
+	return 0
+}
+
+func (m *GetStateProofRequest) GetLatest() bool {
+	if m != nil {
+		return m.Latest
+	}
+	return false
+}
+
+func (m *GetStateProofRequest) GetAddress() []byte {
 	if m != nil {
 		return m.Address
 	}
 	return nil
 }
 
-func (m *GetTokenInfoRequest) GetChannelID() []byte {
+func (m *GetStateProofRequest) GetSlot() []byte {
 	if m != nil {
-		return m.ChannelID
+		return m.Slot
 	}
 	return nil
 }
 
-type TokenInfo struct {
-	Balance              uint64   `protobuf:"varint,1,opt,name=Balance,proto3" json:"Balance,omitempty"`
+// StateProof proves the value of Key in the state tree with Root
+type StateProof struct {
+	Num  uint64 `protobuf:"varint,1,opt,name=Num,proto3" json:"Num,omitempty"`
+	Root []byte `protobuf:"bytes,2,opt,name=Root,proto3" json:"Root,omitempty"`
+	Key  []byte `protobuf:"bytes,3,opt,name=Key,proto3" json:"Key,omitempty"`
+	// Value is empty if the key does not exist
+	Value    []byte   `protobuf:"bytes,4,opt,name=Value,proto3" json:"Value,omitempty"`
+	Siblings [][]byte `protobuf:"bytes,5,rep,name=Siblings,proto3" json:"Siblings,omitempty"`
+	// KeyHash and ValueHash are the leaf at the end of path
+	KeyHash              []byte   `protobuf:"bytes,6,opt,name=KeyHash,proto3" json:"KeyHash,omitempty"`
+	ValueHash            []byte   `protobuf:"bytes,7,opt,name=ValueHash,proto3" json:"ValueHash,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *TokenInfo) Reset()         { *m = TokenInfo{} }
-func (m *TokenInfo) String() string { return proto.CompactTextString(m) }
-func (*TokenInfo) ProtoMessage()    {}
-func (*TokenInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_service_cabd911633115393, []int{14}
+func (m *StateProof) Reset()         { *m = StateProof{} }
+func (m *StateProof) String() string { return proto.CompactTextString(m) }
+func (*StateProof) ProtoMessage()    {}
+func (*StateProof) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{12}
 }
-func (m *TokenInfo) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_TokenInfo.Unmarshal(m, b)
+func (m *StateProof) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateProof.Unmarshal(m, b)
 }
-func (m *TokenInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_TokenInfo.Marshal(b, m, deterministic)
+func (m *StateProof) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateProof.Marshal(b, m, deterministic)
 }
-func (dst *TokenInfo) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_TokenInfo.Merge(dst, src)
+func (dst *StateProof) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateProof.Merge(dst, src)
 }
-func (m *TokenInfo) XXX_Size() int {
-	return xxx_messageInfo_TokenInfo.Size(m)
+func (m *StateProof) XXX_Size() int {
+	return xxx_messageInfo_StateProof.Size(m)
 }
-func (m *TokenInfo) XXX_DiscardUnknown() {
-	xxx_messageInfo_TokenInfo.DiscardUnknown(m)
+func (m *StateProof) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateProof.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_TokenInfo proto.InternalMessageInfo
+var xxx_messageInfo_StateProof proto.InternalMessageInfo
 
-func (m *TokenInfo) GetBalance() uint64 {
+func (m *StateProof) GetNum() uint64 {
 	if m != nil {
-		return m.Balance
+		return m.Num
 	}
 	return 0
 }
 
-func init() {
-	proto.RegisterType((*FetchBlockRequest)(nil), "protos.FetchBlockRequest")
-	proto.RegisterType((*ListChannelsRequest)(nil), "protos.ListChannelsRequest")
-	proto.RegisterType((*ChannelInfos)(nil), "protos.ChannelInfos")
-	proto.RegisterType((*ChannelInfo)(nil), "protos.ChannelInfo")
-	proto.RegisterType((*CreateChannelRequest)(nil), "protos.CreateChannelRequest")
-	proto.RegisterType((*CreateChannelTxPayload)(nil), "protos.CreateChannelTxPayload")
-	proto.RegisterType((*AddTxRequest)(nil), "protos.AddTxRequest")
-	proto.RegisterType((*TxStatus)(nil), "protos.TxStatus")
-	proto.RegisterType((*GetTxStatusRequest)(nil), "protos.GetTxStatusRequest")
-	proto.RegisterType((*ListTxHistoryRequest)(nil), "protos.ListTxHistoryRequest")
-	proto.RegisterType((*TxHistory)(nil), "protos.TxHistory")
-	proto.RegisterMapType((map[string]*StringList)(nil), "protos.TxHistory.TxsEntry")
-	proto.RegisterType((*GetAccountInfoRequest)(nil), "protos.GetAccountInfoRequest")
-	proto.RegisterType((*AccountInfo)(nil), "protos.AccountInfo")
-	proto.RegisterType((*GetTokenInfoRequest)(nil), "protos.GetTokenInfoRequest")
-	proto.RegisterType((*TokenInfo)(nil), "protos.TokenInfo")
-	proto.RegisterEnum("protos.Behavior", Behavior_name, Behavior_value)
-	proto.RegisterEnum("protos.Identity", Identity_name, Identity_value)
+func (m *StateProof) GetRoot() []byte {
+	if m != nil {
+		return m.Root
+	}
+	return nil
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *StateProof) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *StateProof) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *StateProof) GetSiblings() [][]byte {
+	if m != nil {
+		return m.Siblings
+	}
+	return nil
+}
+
+func (m *StateProof) GetKeyHash() []byte {
+	if m != nil {
+		return m.KeyHash
+	}
+	return nil
+}
+
+func (m *StateProof) GetValueHash() []byte {
+	if m != nil {
+		return m.ValueHash
+	}
+	return nil
+}
+
+type GetAccountAtRequest struct {
+	ChannelID            string   `protobuf:"bytes,1,opt,name=ChannelID,proto3" json:"ChannelID,omitempty"`
+	Num                  uint64   `protobuf:"varint,2,opt,name=Num,proto3" json:"Num,omitempty"`
+	Address              []byte   `protobuf:"bytes,3,opt,name=Address,proto3" json:"Address,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetAccountAtRequest) Reset()         { *m = GetAccountAtRequest{} }
+func (m *GetAccountAtRequest) String() string { return proto.CompactTextString(m) }
+func (*GetAccountAtRequest) ProtoMessage()    {}
+func (*GetAccountAtRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{13}
+}
+func (m *GetAccountAtRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetAccountAtRequest.Unmarshal(m, b)
+}
+func (m *GetAccountAtRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetAccountAtRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetAccountAtRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetAccountAtRequest.Merge(dst, src)
+}
+func (m *GetAccountAtRequest) XXX_Size() int {
+	return xxx_messageInfo_GetAccountAtRequest.Size(m)
+}
+func (m *GetAccountAtRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetAccountAtRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetAccountAtRequest proto.InternalMessageInfo
+
+func (m *GetAccountAtRequest) GetChannelID() string {
+	if m != nil {
+		return m.ChannelID
+	}
+	return ""
+}
+
+func (m *GetAccountAtRequest) GetNum() uint64 {
+	if m != nil {
+		return m.Num
+	}
+	return 0
+}
+
+func (m *GetAccountAtRequest) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+// StateAccount is an account in the state of a channel after block Num
+type StateAccount struct {
+	Num                  uint64   `protobuf:"varint,1,opt,name=Num,proto3" json:"Num,omitempty"`
+	Exist                bool     `protobuf:"varint,2,opt,name=Exist,proto3" json:"Exist,omitempty"`
+	Balance              uint64   `protobuf:"varint,3,opt,name=Balance,proto3" json:"Balance,omitempty"`
+	Code                 []byte   `protobuf:"bytes,4,opt,name=Code,proto3" json:"Code,omitempty"`
+	Nonce                uint64   `protobuf:"varint,5,opt,name=Nonce,proto3" json:"Nonce,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StateAccount) Reset()         { *m = StateAccount{} }
+func (m *StateAccount) String() string { return proto.CompactTextString(m) }
+func (*StateAccount) ProtoMessage()    {}
+func (*StateAccount) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{14}
+}
+func (m *StateAccount) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateAccount.Unmarshal(m, b)
+}
+func (m *StateAccount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateAccount.Marshal(b, m, deterministic)
+}
+func (dst *StateAccount) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateAccount.Merge(dst, src)
+}
+func (m *StateAccount) XXX_Size() int {
+	return xxx_messageInfo_StateAccount.Size(m)
+}
+func (m *StateAccount) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateAccount.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateAccount proto.InternalMessageInfo
+
+func (m *StateAccount) GetNum() uint64 {
+	if m != nil {
+		return m.Num
+	}
+	return 0
+}
+
+func (m *StateAccount) GetExist() bool {
+	if m != nil {
+		return m.Exist
+	}
+	return false
+}
+
+func (m *StateAccount) GetBalance() uint64 {
+	if m != nil {
+		return m.Balance
+	}
+	return 0
+}
+
+func (m *StateAccount) GetCode() []byte {
+	if m != nil {
+		return m.Code
+	}
+	return nil
+}
+
+func (m *StateAccount) GetNonce() uint64 {
+	if m != nil {
+		return m.Nonce
+	}
+	return 0
+}
+
+type GetStorageAtRequest struct {
+	ChannelID            string   `protobuf:"bytes,1,opt,name=ChannelID,proto3" json:"ChannelID,omitempty"`
+	Num                  uint64   `protobuf:"varint,2,opt,name=Num,proto3" json:"Num,omitempty"`
+	Address              []byte   `protobuf:"bytes,3,opt,name=Address,proto3" json:"Address,omitempty"`
+	Key                  []byte   `protobuf:"bytes,4,opt,name=Key,proto3" json:"Key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStorageAtRequest) Reset()         { *m = GetStorageAtRequest{} }
+func (m *GetStorageAtRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStorageAtRequest) ProtoMessage()    {}
+func (*GetStorageAtRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{15}
+}
+func (m *GetStorageAtRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStorageAtRequest.Unmarshal(m, b)
+}
+func (m *GetStorageAtRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStorageAtRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetStorageAtRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStorageAtRequest.Merge(dst, src)
+}
+func (m *GetStorageAtRequest) XXX_Size() int {
+	return xxx_messageInfo_GetStorageAtRequest.Size(m)
+}
+func (m *GetStorageAtRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStorageAtRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStorageAtRequest proto.InternalMessageInfo
+
+func (m *GetStorageAtRequest) GetChannelID() string {
+	if m != nil {
+		return m.ChannelID
+	}
+	return ""
+}
+
+func (m *GetStorageAtRequest) GetNum() uint64 {
+	if m != nil {
+		return m.Num
+	}
+	return 0
+}
+
+func (m *GetStorageAtRequest) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *GetStorageAtRequest) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+// StateStorage is a storage slot in the state of a channel after block Num
+type StateStorage struct {
+	Num                  uint64   `protobuf:"varint,1,opt,name=Num,proto3" json:"Num,omitempty"`
+	Value                []byte   `protobuf:"bytes,2,opt,name=Value,proto3" json:"Value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StateStorage) Reset()         { *m = StateStorage{} }
+func (m *StateStorage) String() string { return proto.CompactTextString(m) }
+func (*StateStorage) ProtoMessage()    {}
+func (*StateStorage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{16}
+}
+func (m *StateStorage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StateStorage.Unmarshal(m, b)
+}
+func (m *StateStorage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StateStorage.Marshal(b, m, deterministic)
+}
+func (dst *StateStorage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StateStorage.Merge(dst, src)
+}
+func (m *StateStorage) XXX_Size() int {
+	return xxx_messageInfo_StateStorage.Size(m)
+}
+func (m *StateStorage) XXX_DiscardUnknown() {
+	xxx_messageInfo_StateStorage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StateStorage proto.InternalMessageInfo
+
+func (m *StateStorage) GetNum() uint64 {
+	if m != nil {
+		return m.Num
+	}
+	return 0
+}
+
+func (m *StateStorage) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+// CallAtRequest calls a contract on the state of a channel after block Num,
+// the changes are dropped
+type CallAtRequest struct {
+	ChannelID string `protobuf:"bytes,1,opt,name=ChannelID,proto3" json:"ChannelID,omitempty"`
+	Num       uint64 `protobuf:"varint,2,opt,name=Num,proto3" json:"Num,omitempty"`
+	Caller    []byte `protobuf:"bytes,3,opt,name=Caller,proto3" json:"Caller,omitempty"`
+	Contract  []byte `protobuf:"bytes,4,opt,name=Contract,proto3" json:"Contract,omitempty"`
+	Payload   []byte `protobuf:"bytes,5,opt,name=Payload,proto3" json:"Payload,omitempty"`
+	// Gas is the max gas of channel if it is zero
+	Gas                  uint64   `protobuf:"varint,6,opt,name=Gas,proto3" json:"Gas,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CallAtRequest) Reset()         { *m = CallAtRequest{} }
+func (m *CallAtRequest) String() string { return proto.CompactTextString(m) }
+func (*CallAtRequest) ProtoMessage()    {}
+func (*CallAtRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{17}
+}
+func (m *CallAtRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CallAtRequest.Unmarshal(m, b)
+}
+func (m *CallAtRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CallAtRequest.Marshal(b, m, deterministic)
+}
+func (dst *CallAtRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CallAtRequest.Merge(dst, src)
+}
+func (m *CallAtRequest) XXX_Size() int {
+	return xxx_messageInfo_CallAtRequest.Size(m)
+}
+func (m *CallAtRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CallAtRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CallAtRequest proto.InternalMessageInfo
+
+func (m *CallAtRequest) GetChannelID() string {
+	if m != nil {
+		return m.ChannelID
+	}
+	return ""
+}
+
+func (m *CallAtRequest) GetNum() uint64 {
+	if m != nil {
+		return m.Num
+	}
+	return 0
+}
+
+func (m *CallAtRequest) GetCaller() []byte {
+	if m != nil {
+		return m.Caller
+	}
+	return nil
+}
+
+func (m *CallAtRequest) GetContract() []byte {
+	if m != nil {
+		return m.Contract
+	}
+	return nil
+}
+
+func (m *CallAtRequest) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *CallAtRequest) GetGas() uint64 {
+	if m != nil {
+		return m.Gas
+	}
+	return 0
+}
+
+type CallResult struct {
+	Num                  uint64   `protobuf:"varint,1,opt,name=Num,proto3" json:"Num,omitempty"`
+	Output               []byte   `protobuf:"bytes,2,opt,name=Output,proto3" json:"Output,omitempty"`
+	GasUsed              uint64   `protobuf:"varint,3,opt,name=GasUsed,proto3" json:"GasUsed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CallResult) Reset()         { *m = CallResult{} }
+func (m *CallResult) String() string { return proto.CompactTextString(m) }
+func (*CallResult) ProtoMessage()    {}
+func (*CallResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{18}
+}
+func (m *CallResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CallResult.Unmarshal(m, b)
+}
+func (m *CallResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CallResult.Marshal(b, m, deterministic)
+}
+func (dst *CallResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CallResult.Merge(dst, src)
+}
+func (m *CallResult) XXX_Size() int {
+	return xxx_messageInfo_CallResult.Size(m)
+}
+func (m *CallResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_CallResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CallResult proto.InternalMessageInfo
+
+func (m *CallResult) GetNum() uint64 {
+	if m != nil {
+		return m.Num
+	}
+	return 0
+}
+
+func (m *CallResult) GetOutput() []byte {
+	if m != nil {
+		return m.Output
+	}
+	return nil
+}
+
+func (m *CallResult) GetGasUsed() uint64 {
+	if m != nil {
+		return m.GasUsed
+	}
+	return 0
+}
+
+// EstimateGasRequest dry-runs a tx on the state of a channel after the
+// latest block
+type EstimateGasRequest struct {
+	ChannelID string `protobuf:"bytes,1,opt,name=ChannelID,proto3" json:"ChannelID,omitempty"`
+	Caller    []byte `protobuf:"bytes,2,opt,name=Caller,proto3" json:"Caller,omitempty"`
+	// Receiver is empty if the tx creates a contract
+	Receiver             []byte   `protobuf:"bytes,3,opt,name=Receiver,proto3" json:"Receiver,omitempty"`
+	Payload              []byte   `protobuf:"bytes,4,opt,name=Payload,proto3" json:"Payload,omitempty"`
+	Value                uint64   `protobuf:"varint,5,opt,name=Value,proto3" json:"Value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EstimateGasRequest) Reset()         { *m = EstimateGasRequest{} }
+func (m *EstimateGasRequest) String() string { return proto.CompactTextString(m) }
+func (*EstimateGasRequest) ProtoMessage()    {}
+func (*EstimateGasRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{19}
+}
+func (m *EstimateGasRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EstimateGasRequest.Unmarshal(m, b)
+}
+func (m *EstimateGasRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EstimateGasRequest.Marshal(b, m, deterministic)
+}
+func (dst *EstimateGasRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EstimateGasRequest.Merge(dst, src)
+}
+func (m *EstimateGasRequest) XXX_Size() int {
+	return xxx_messageInfo_EstimateGasRequest.Size(m)
+}
+func (m *EstimateGasRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EstimateGasRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EstimateGasRequest proto.InternalMessageInfo
+
+func (m *EstimateGasRequest) GetChannelID() string {
+	if m != nil {
+		return m.ChannelID
+	}
+	return ""
+}
+
+func (m *EstimateGasRequest) GetCaller() []byte {
+	if m != nil {
+		return m.Caller
+	}
+	return nil
+}
+
+func (m *EstimateGasRequest) GetReceiver() []byte {
+	if m != nil {
+		return m.Receiver
+	}
+	return nil
+}
+
+func (m *EstimateGasRequest) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *EstimateGasRequest) GetValue() uint64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+// GasEstimate is the least gas limit the tx succeeds with
+type GasEstimate struct {
+	Num     uint64 `protobuf:"varint,1,opt,name=Num,proto3" json:"Num,omitempty"`
+	Gas     uint64 `protobuf:"varint,2,opt,name=Gas,proto3" json:"Gas,omitempty"`
+	GasUsed uint64 `protobuf:"varint,3,opt,name=GasUsed,proto3" json:"GasUsed,omitempty"`
+	// Tokens is the tokens charged for GasUsed under the gas price
+	Tokens               uint64   `protobuf:"varint,4,opt,name=Tokens,proto3" json:"Tokens,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GasEstimate) Reset()         { *m = GasEstimate{} }
+func (m *GasEstimate) String() string { return proto.CompactTextString(m) }
+func (*GasEstimate) ProtoMessage()    {}
+func (*GasEstimate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{20}
+}
+func (m *GasEstimate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GasEstimate.Unmarshal(m, b)
+}
+func (m *GasEstimate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GasEstimate.Marshal(b, m, deterministic)
+}
+func (dst *GasEstimate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GasEstimate.Merge(dst, src)
+}
+func (m *GasEstimate) XXX_Size() int {
+	return xxx_messageInfo_GasEstimate.Size(m)
+}
+func (m *GasEstimate) XXX_DiscardUnknown() {
+	xxx_messageInfo_GasEstimate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GasEstimate proto.InternalMessageInfo
+
+func (m *GasEstimate) GetNum() uint64 {
+	if m != nil {
+		return m.Num
+	}
+	return 0
+}
+
+func (m *GasEstimate) GetGas() uint64 {
+	if m != nil {
+		return m.Gas
+	}
+	return 0
+}
+
+func (m *GasEstimate) GetGasUsed() uint64 {
+	if m != nil {
+		return m.GasUsed
+	}
+	return 0
+}
+
+func (m *GasEstimate) GetTokens() uint64 {
+	if m != nil {
+		return m.Tokens
+	}
+	return 0
+}
+
+// TraceTransactionRequest asks peers to trace a tx, the limits are capped by
+// the limits of peer and zero means the limit of peer. The trace fails if it
+// exceeds the limits.
+type TraceTransactionRequest struct {
+	ChannelID     string `protobuf:"bytes,1,opt,name=ChannelID,proto3" json:"ChannelID,omitempty"`
+	TxID          string `protobuf:"bytes,2,opt,name=TxID,proto3" json:"TxID,omitempty"`
+	DisableStack  bool   `protobuf:"varint,3,opt,name=DisableStack,proto3" json:"DisableStack,omitempty"`
+	DisableMemory bool   `protobuf:"varint,4,opt,name=DisableMemory,proto3" json:"DisableMemory,omitempty"`
+	// MaxSteps is the max number of steps
+	MaxSteps uint64 `protobuf:"varint,5,opt,name=MaxSteps,proto3" json:"MaxSteps,omitempty"`
+	// MaxBytes is the max size of stack and memory of all steps
+	MaxBytes             uint64   `protobuf:"varint,6,opt,name=MaxBytes,proto3" json:"MaxBytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TraceTransactionRequest) Reset()         { *m = TraceTransactionRequest{} }
+func (m *TraceTransactionRequest) String() string { return proto.CompactTextString(m) }
+func (*TraceTransactionRequest) ProtoMessage()    {}
+func (*TraceTransactionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{21}
+}
+func (m *TraceTransactionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TraceTransactionRequest.Unmarshal(m, b)
+}
+func (m *TraceTransactionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TraceTransactionRequest.Marshal(b, m, deterministic)
+}
+func (dst *TraceTransactionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceTransactionRequest.Merge(dst, src)
+}
+func (m *TraceTransactionRequest) XXX_Size() int {
+	return xxx_messageInfo_TraceTransactionRequest.Size(m)
+}
+func (m *TraceTransactionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceTransactionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TraceTransactionRequest proto.InternalMessageInfo
+
+func (m *TraceTransactionRequest) GetChannelID() string {
+	if m != nil {
+		return m.ChannelID
+	}
+	return ""
+}
+
+func (m *TraceTransactionRequest) GetTxID() string {
+	if m != nil {
+		return m.TxID
+	}
+	return ""
+}
+
+func (m *TraceTransactionRequest) GetDisableStack() bool {
+	if m != nil {
+		return m.DisableStack
+	}
+	return false
+}
+
+func (m *TraceTransactionRequest) GetDisableMemory() bool {
+	if m != nil {
+		return m.DisableMemory
+	}
+	return false
+}
+
+func (m *TraceTransactionRequest) GetMaxSteps() uint64 {
+	if m != nil {
+		return m.MaxSteps
+	}
+	return 0
+}
+
+func (m *TraceTransactionRequest) GetMaxBytes() uint64 {
+	if m != nil {
+		return m.MaxBytes
+	}
+	return 0
+}
+
+// TxTrace is the trace of a tx which runs again in the context of its block
+type TxTrace struct {
+	// Status is the status of the tx when it runs again
+	Status *TxStatus    `protobuf:"bytes,1,opt,name=Status,proto3" json:"Status,omitempty"`
+	Steps  []*TraceStep `protobuf:"bytes,2,rep,name=Steps,proto3" json:"Steps,omitempty"`
+	// Call is the call or create of tx, the calls it makes are its children
+	Call                 *TraceCall       `protobuf:"bytes,3,opt,name=Call,proto3" json:"Call,omitempty"`
+	StorageChanges       []*StorageChange `protobuf:"bytes,4,rep,name=StorageChanges,proto3" json:"StorageChanges,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *TxTrace) Reset()         { *m = TxTrace{} }
+func (m *TxTrace) String() string { return proto.CompactTextString(m) }
+func (*TxTrace) ProtoMessage()    {}
+func (*TxTrace) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{22}
+}
+func (m *TxTrace) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxTrace.Unmarshal(m, b)
+}
+func (m *TxTrace) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxTrace.Marshal(b, m, deterministic)
+}
+func (dst *TxTrace) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxTrace.Merge(dst, src)
+}
+func (m *TxTrace) XXX_Size() int {
+	return xxx_messageInfo_TxTrace.Size(m)
+}
+func (m *TxTrace) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxTrace.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxTrace proto.InternalMessageInfo
+
+func (m *TxTrace) GetStatus() *TxStatus {
+	if m != nil {
+		return m.Status
+	}
+	return nil
+}
+
+func (m *TxTrace) GetSteps() []*TraceStep {
+	if m != nil {
+		return m.Steps
+	}
+	return nil
+}
+
+func (m *TxTrace) GetCall() *TraceCall {
+	if m != nil {
+		return m.Call
+	}
+	return nil
+}
+
+func (m *TxTrace) GetStorageChanges() []*StorageChange {
+	if m != nil {
+		return m.StorageChanges
+	}
+	return nil
+}
+
+// TraceStep is an op run by evm
+type TraceStep struct {
+	// Depth is 1 for the code of tx
+	Depth uint64 `protobuf:"varint,1,opt,name=Depth,proto3" json:"Depth,omitempty"`
+	PC    uint64 `protobuf:"varint,2,opt,name=PC,proto3" json:"PC,omitempty"`
+	Op    string `protobuf:"bytes,3,opt,name=Op,proto3" json:"Op,omitempty"`
+	// Gas is the gas left before the op runs
+	Gas     uint64 `protobuf:"varint,4,opt,name=Gas,proto3" json:"Gas,omitempty"`
+	GasCost uint64 `protobuf:"varint,5,opt,name=GasCost,proto3" json:"GasCost,omitempty"`
+	// Stack is the stack before the op runs, the top is the last one
+	Stack  [][]byte `protobuf:"bytes,6,rep,name=Stack,proto3" json:"Stack,omitempty"`
+	Memory []byte   `protobuf:"bytes,7,opt,name=Memory,proto3" json:"Memory,omitempty"`
+	// Storage is the storage set if the op is SSTORE
+	Storage              *StorageChange `protobuf:"bytes,8,opt,name=Storage,proto3" json:"Storage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *TraceStep) Reset()         { *m = TraceStep{} }
+func (m *TraceStep) String() string { return proto.CompactTextString(m) }
+func (*TraceStep) ProtoMessage()    {}
+func (*TraceStep) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{23}
+}
+func (m *TraceStep) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TraceStep.Unmarshal(m, b)
+}
+func (m *TraceStep) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TraceStep.Marshal(b, m, deterministic)
+}
+func (dst *TraceStep) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceStep.Merge(dst, src)
+}
+func (m *TraceStep) XXX_Size() int {
+	return xxx_messageInfo_TraceStep.Size(m)
+}
+func (m *TraceStep) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceStep.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TraceStep proto.InternalMessageInfo
+
+func (m *TraceStep) GetDepth() uint64 {
+	if m != nil {
+		return m.Depth
+	}
+	return 0
+}
+
+func (m *TraceStep) GetPC() uint64 {
+	if m != nil {
+		return m.PC
+	}
+	return 0
+}
+
+func (m *TraceStep) GetOp() string {
+	if m != nil {
+		return m.Op
+	}
+	return ""
+}
+
+func (m *TraceStep) GetGas() uint64 {
+	if m != nil {
+		return m.Gas
+	}
+	return 0
+}
+
+func (m *TraceStep) GetGasCost() uint64 {
+	if m != nil {
+		return m.GasCost
+	}
+	return 0
+}
+
+func (m *TraceStep) GetStack() [][]byte {
+	if m != nil {
+		return m.Stack
+	}
+	return nil
+}
+
+func (m *TraceStep) GetMemory() []byte {
+	if m != nil {
+		return m.Memory
+	}
+	return nil
+}
+
+func (m *TraceStep) GetStorage() *StorageChange {
+	if m != nil {
+		return m.Storage
+	}
+	return nil
+}
+
+// TraceCall is a call or create in evm
+type TraceCall struct {
+	Op                   string       `protobuf:"bytes,1,opt,name=Op,proto3" json:"Op,omitempty"`
+	From                 []byte       `protobuf:"bytes,2,opt,name=From,proto3" json:"From,omitempty"`
+	To                   []byte       `protobuf:"bytes,3,opt,name=To,proto3" json:"To,omitempty"`
+	Input                []byte       `protobuf:"bytes,4,opt,name=Input,proto3" json:"Input,omitempty"`
+	Output               []byte       `protobuf:"bytes,5,opt,name=Output,proto3" json:"Output,omitempty"`
+	Value                uint64       `protobuf:"varint,6,opt,name=Value,proto3" json:"Value,omitempty"`
+	Gas                  uint64       `protobuf:"varint,7,opt,name=Gas,proto3" json:"Gas,omitempty"`
+	GasUsed              uint64       `protobuf:"varint,8,opt,name=GasUsed,proto3" json:"GasUsed,omitempty"`
+	Err                  string       `protobuf:"bytes,9,opt,name=Err,proto3" json:"Err,omitempty"`
+	Calls                []*TraceCall `protobuf:"bytes,10,rep,name=Calls,proto3" json:"Calls,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *TraceCall) Reset()         { *m = TraceCall{} }
+func (m *TraceCall) String() string { return proto.CompactTextString(m) }
+func (*TraceCall) ProtoMessage()    {}
+func (*TraceCall) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{24}
+}
+func (m *TraceCall) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TraceCall.Unmarshal(m, b)
+}
+func (m *TraceCall) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TraceCall.Marshal(b, m, deterministic)
+}
+func (dst *TraceCall) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TraceCall.Merge(dst, src)
+}
+func (m *TraceCall) XXX_Size() int {
+	return xxx_messageInfo_TraceCall.Size(m)
+}
+func (m *TraceCall) XXX_DiscardUnknown() {
+	xxx_messageInfo_TraceCall.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TraceCall proto.InternalMessageInfo
+
+func (m *TraceCall) GetOp() string {
+	if m != nil {
+		return m.Op
+	}
+	return ""
+}
+
+func (m *TraceCall) GetFrom() []byte {
+	if m != nil {
+		return m.From
+	}
+	return nil
+}
+
+func (m *TraceCall) GetTo() []byte {
+	if m != nil {
+		return m.To
+	}
+	return nil
+}
+
+func (m *TraceCall) GetInput() []byte {
+	if m != nil {
+		return m.Input
+	}
+	return nil
+}
+
+func (m *TraceCall) GetOutput() []byte {
+	if m != nil {
+		return m.Output
+	}
+	return nil
+}
+
+func (m *TraceCall) GetValue() uint64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *TraceCall) GetGas() uint64 {
+	if m != nil {
+		return m.Gas
+	}
+	return 0
+}
+
+func (m *TraceCall) GetGasUsed() uint64 {
+	if m != nil {
+		return m.GasUsed
+	}
+	return 0
+}
+
+func (m *TraceCall) GetErr() string {
+	if m != nil {
+		return m.Err
+	}
+	return ""
+}
+
+func (m *TraceCall) GetCalls() []*TraceCall {
+	if m != nil {
+		return m.Calls
+	}
+	return nil
+}
+
+// StorageChange is a change of the storage of a contract
+type StorageChange struct {
+	Address              []byte   `protobuf:"bytes,1,opt,name=Address,proto3" json:"Address,omitempty"`
+	Key                  []byte   `protobuf:"bytes,2,opt,name=Key,proto3" json:"Key,omitempty"`
+	Before               []byte   `protobuf:"bytes,3,opt,name=Before,proto3" json:"Before,omitempty"`
+	After                []byte   `protobuf:"bytes,4,opt,name=After,proto3" json:"After,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StorageChange) Reset()         { *m = StorageChange{} }
+func (m *StorageChange) String() string { return proto.CompactTextString(m) }
+func (*StorageChange) ProtoMessage()    {}
+func (*StorageChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{25}
+}
+func (m *StorageChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StorageChange.Unmarshal(m, b)
+}
+func (m *StorageChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StorageChange.Marshal(b, m, deterministic)
+}
+func (dst *StorageChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StorageChange.Merge(dst, src)
+}
+func (m *StorageChange) XXX_Size() int {
+	return xxx_messageInfo_StorageChange.Size(m)
+}
+func (m *StorageChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_StorageChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StorageChange proto.InternalMessageInfo
+
+func (m *StorageChange) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *StorageChange) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *StorageChange) GetBefore() []byte {
+	if m != nil {
+		return m.Before
+	}
+	return nil
+}
+
+func (m *StorageChange) GetAfter() []byte {
+	if m != nil {
+		return m.After
+	}
+	return nil
+}
+
+type GetTxStatusRequest struct {
+	ChannelID            string   `protobuf:"bytes,1,opt,name=ChannelID,proto3" json:"ChannelID,omitempty"`
+	TxID                 string   `protobuf:"bytes,2,opt,name=TxID,proto3" json:"TxID,omitempty"`
+	Behavior             Behavior `protobuf:"varint,3,opt,name=Behavior,proto3,enum=protos.Behavior" json:"Behavior,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetTxStatusRequest) Reset()         { *m = GetTxStatusRequest{} }
+func (m *GetTxStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetTxStatusRequest) ProtoMessage()    {}
+func (*GetTxStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{26}
+}
+func (m *GetTxStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTxStatusRequest.Unmarshal(m, b)
+}
+func (m *GetTxStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTxStatusRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetTxStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTxStatusRequest.Merge(dst, src)
+}
+func (m *GetTxStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_GetTxStatusRequest.Size(m)
+}
+func (m *GetTxStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTxStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTxStatusRequest proto.InternalMessageInfo
+
+func (m *GetTxStatusRequest) GetChannelID() string {
+	if m != nil {
+		return m.ChannelID
+	}
+	return ""
+}
+
+func (m *GetTxStatusRequest) GetTxID() string {
+	if m != nil {
+		return m.TxID
+	}
+	return ""
+}
+
+func (m *GetTxStatusRequest) GetBehavior() Behavior {
+	if m != nil {
+		return m.Behavior
+	}
+	return Behavior_FAIL_IF_NOT_READY
+}
+
+type ListTxHistoryRequest struct {
+	Address              []byte   `protobuf:"bytes,1,opt,name=Address,proto3" json:"Address,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListTxHistoryRequest) Reset()         { *m = ListTxHistoryRequest{} }
+func (m *ListTxHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*ListTxHistoryRequest) ProtoMessage()    {}
+func (*ListTxHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{27}
+}
+func (m *ListTxHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListTxHistoryRequest.Unmarshal(m, b)
+}
+func (m *ListTxHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListTxHistoryRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListTxHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListTxHistoryRequest.Merge(dst, src)
+}
+func (m *ListTxHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_ListTxHistoryRequest.Size(m)
+}
+func (m *ListTxHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListTxHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListTxHistoryRequest proto.InternalMessageInfo
+
+func (m *ListTxHistoryRequest) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+// TxHistory includes all txs
+type TxHistory struct {
+	// repeated string Txs = 1;
+	Txs                  map[string]*StringList `protobuf:"bytes,1,rep,name=Txs,proto3" json:"Txs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *TxHistory) Reset()         { *m = TxHistory{} }
+func (m *TxHistory) String() string { return proto.CompactTextString(m) }
+func (*TxHistory) ProtoMessage()    {}
+func (*TxHistory) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{28}
+}
+func (m *TxHistory) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TxHistory.Unmarshal(m, b)
+}
+func (m *TxHistory) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TxHistory.Marshal(b, m, deterministic)
+}
+func (dst *TxHistory) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TxHistory.Merge(dst, src)
+}
+func (m *TxHistory) XXX_Size() int {
+	return xxx_messageInfo_TxHistory.Size(m)
+}
+func (m *TxHistory) XXX_DiscardUnknown() {
+	xxx_messageInfo_TxHistory.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TxHistory proto.InternalMessageInfo
+
+func (m *TxHistory) GetTxs() map[string]*StringList {
+	if m != nil {
+		return m.Txs
+	}
+	return nil
+}
+
+type GetAccountInfoRequest struct {
+	Address []byte `protobuf:"bytes,1,opt,name=Address,proto3" json:"Address,omitempty"`
+	// AssetID is empty if query the native asset
+	AssetID              string   `protobuf:"bytes,2,opt,name=AssetID,proto3" json:"AssetID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetAccountInfoRequest) Reset()         { *m = GetAccountInfoRequest{} }
+func (m *GetAccountInfoRequest) String() string { return proto.CompactTextString(m) }
+func (*GetAccountInfoRequest) ProtoMessage()    {}
+func (*GetAccountInfoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{29}
+}
+func (m *GetAccountInfoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetAccountInfoRequest.Unmarshal(m, b)
+}
+func (m *GetAccountInfoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetAccountInfoRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetAccountInfoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetAccountInfoRequest.Merge(dst, src)
+}
+func (m *GetAccountInfoRequest) XXX_Size() int {
+	return xxx_messageInfo_GetAccountInfoRequest.Size(m)
+}
+func (m *GetAccountInfoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetAccountInfoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetAccountInfoRequest proto.InternalMessageInfo
+
+func (m *GetAccountInfoRequest) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *GetAccountInfoRequest) GetAssetID() string {
+	if m != nil {
+		return m.AssetID
+	}
+	return ""
+}
+
+type AccountInfo struct {
+	Balance uint64 `protobuf:"varint,1,opt,name=Balance,proto3" json:"Balance,omitempty"`
+	AssetID string `protobuf:"bytes,2,opt,name=AssetID,proto3" json:"AssetID,omitempty"`
+	// Decimals, Cap and Supply are infos of named asset
+	Decimals uint32 `protobuf:"varint,3,opt,name=Decimals,proto3" json:"Decimals,omitempty"`
+	Cap      uint64 `protobuf:"varint,4,opt,name=Cap,proto3" json:"Cap,omitempty"`
+	Supply   uint64 `protobuf:"varint,5,opt,name=Supply,proto3" json:"Supply,omitempty"`
+	// Frozen account can not transfer asset out
+	Frozen               bool     `protobuf:"varint,6,opt,name=Frozen,proto3" json:"Frozen,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AccountInfo) Reset()         { *m = AccountInfo{} }
+func (m *AccountInfo) String() string { return proto.CompactTextString(m) }
+func (*AccountInfo) ProtoMessage()    {}
+func (*AccountInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{30}
+}
+func (m *AccountInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AccountInfo.Unmarshal(m, b)
+}
+func (m *AccountInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AccountInfo.Marshal(b, m, deterministic)
+}
+func (dst *AccountInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountInfo.Merge(dst, src)
+}
+func (m *AccountInfo) XXX_Size() int {
+	return xxx_messageInfo_AccountInfo.Size(m)
+}
+func (m *AccountInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_AccountInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AccountInfo proto.InternalMessageInfo
+
+func (m *AccountInfo) GetBalance() uint64 {
+	if m != nil {
+		return m.Balance
+	}
+	return 0
+}
+
+func (m *AccountInfo) GetAssetID() string {
+	if m != nil {
+		return m.AssetID
+	}
+	return ""
+}
+
+func (m *AccountInfo) GetDecimals() uint32 {
+	if m != nil {
+		return m.Decimals
+	}
+	return 0
+}
+
+func (m *AccountInfo) GetCap() uint64 {
+	if m != nil {
+		return m.Cap
+	}
+	return 0
+}
+
+func (m *AccountInfo) GetSupply() uint64 {
+	if m != nil {
+		return m.Supply
+	}
+	return 0
+}
+
+func (m *AccountInfo) GetFrozen() bool {
+	if m != nil {
+		return m.Frozen
+	}
+	return false
+}
+
+type GetComplianceHistoryRequest struct {
+	Address              []byte   `protobuf:"bytes,1,opt,name=Address,proto3" json:"Address,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetComplianceHistoryRequest) Reset()         { *m = GetComplianceHistoryRequest{} }
+func (m *GetComplianceHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*GetComplianceHistoryRequest) ProtoMessage()    {}
+func (*GetComplianceHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{31}
+}
+func (m *GetComplianceHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetComplianceHistoryRequest.Unmarshal(m, b)
+}
+func (m *GetComplianceHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetComplianceHistoryRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetComplianceHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetComplianceHistoryRequest.Merge(dst, src)
+}
+func (m *GetComplianceHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_GetComplianceHistoryRequest.Size(m)
+}
+func (m *GetComplianceHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetComplianceHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetComplianceHistoryRequest proto.InternalMessageInfo
+
+func (m *GetComplianceHistoryRequest) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+type ComplianceRecord struct {
+	TxID string `protobuf:"bytes,1,opt,name=TxID,proto3" json:"TxID,omitempty"`
+	// Action is freeze, unfreeze or burn
+	Action               string   `protobuf:"bytes,2,opt,name=Action,proto3" json:"Action,omitempty"`
+	AssetID              string   `protobuf:"bytes,3,opt,name=AssetID,proto3" json:"AssetID,omitempty"`
+	Value                uint64   `protobuf:"varint,4,opt,name=Value,proto3" json:"Value,omitempty"`
+	Reason               string   `protobuf:"bytes,5,opt,name=Reason,proto3" json:"Reason,omitempty"`
+	BlockNumber          uint64   `protobuf:"varint,6,opt,name=BlockNumber,proto3" json:"BlockNumber,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ComplianceRecord) Reset()         { *m = ComplianceRecord{} }
+func (m *ComplianceRecord) String() string { return proto.CompactTextString(m) }
+func (*ComplianceRecord) ProtoMessage()    {}
+func (*ComplianceRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{32}
+}
+func (m *ComplianceRecord) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ComplianceRecord.Unmarshal(m, b)
+}
+func (m *ComplianceRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ComplianceRecord.Marshal(b, m, deterministic)
+}
+func (dst *ComplianceRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ComplianceRecord.Merge(dst, src)
+}
+func (m *ComplianceRecord) XXX_Size() int {
+	return xxx_messageInfo_ComplianceRecord.Size(m)
+}
+func (m *ComplianceRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_ComplianceRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ComplianceRecord proto.InternalMessageInfo
+
+func (m *ComplianceRecord) GetTxID() string {
+	if m != nil {
+		return m.TxID
+	}
+	return ""
+}
+
+func (m *ComplianceRecord) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *ComplianceRecord) GetAssetID() string {
+	if m != nil {
+		return m.AssetID
+	}
+	return ""
+}
+
+func (m *ComplianceRecord) GetValue() uint64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *ComplianceRecord) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *ComplianceRecord) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+// ComplianceHistory includes all actions the asset admin takes on an account
+type ComplianceHistory struct {
+	Records              []*ComplianceRecord `protobuf:"bytes,1,rep,name=Records,proto3" json:"Records,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *ComplianceHistory) Reset()         { *m = ComplianceHistory{} }
+func (m *ComplianceHistory) String() string { return proto.CompactTextString(m) }
+func (*ComplianceHistory) ProtoMessage()    {}
+func (*ComplianceHistory) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{33}
+}
+func (m *ComplianceHistory) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ComplianceHistory.Unmarshal(m, b)
+}
+func (m *ComplianceHistory) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ComplianceHistory.Marshal(b, m, deterministic)
+}
+func (dst *ComplianceHistory) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ComplianceHistory.Merge(dst, src)
+}
+func (m *ComplianceHistory) XXX_Size() int {
+	return xxx_messageInfo_ComplianceHistory.Size(m)
+}
+func (m *ComplianceHistory) XXX_DiscardUnknown() {
+	xxx_messageInfo_ComplianceHistory.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ComplianceHistory proto.InternalMessageInfo
+
+func (m *ComplianceHistory) GetRecords() []*ComplianceRecord {
+	if m != nil {
+		return m.Records
+	}
+	return nil
+}
+
+type GetChannelBillingRequest struct {
+	ChannelID string `protobuf:"bytes,1,opt,name=ChannelID,proto3" json:"ChannelID,omitempty"`
+	// Count is the number of latest charges, zero means all
+	Count                uint32   `protobuf:"varint,2,opt,name=Count,proto3" json:"Count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetChannelBillingRequest) Reset()         { *m = GetChannelBillingRequest{} }
+func (m *GetChannelBillingRequest) String() string { return proto.CompactTextString(m) }
+func (*GetChannelBillingRequest) ProtoMessage()    {}
+func (*GetChannelBillingRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{34}
+}
+func (m *GetChannelBillingRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetChannelBillingRequest.Unmarshal(m, b)
+}
+func (m *GetChannelBillingRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetChannelBillingRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetChannelBillingRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetChannelBillingRequest.Merge(dst, src)
+}
+func (m *GetChannelBillingRequest) XXX_Size() int {
+	return xxx_messageInfo_GetChannelBillingRequest.Size(m)
+}
+func (m *GetChannelBillingRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetChannelBillingRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetChannelBillingRequest proto.InternalMessageInfo
+
+func (m *GetChannelBillingRequest) GetChannelID() string {
+	if m != nil {
+		return m.ChannelID
+	}
+	return ""
+}
+
+func (m *GetChannelBillingRequest) GetCount() uint32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+// BillingRecord is the storage charge of a block
+type BillingRecord struct {
+	BlockNumber uint64 `protobuf:"varint,1,opt,name=BlockNumber,proto3" json:"BlockNumber,omitempty"`
+	Size        uint64 `protobuf:"varint,2,opt,name=Size,proto3" json:"Size,omitempty"`
+	// Fee is Size * BlockPrice, which is the sum of Paid and Due
+	Fee                  uint64   `protobuf:"varint,3,opt,name=Fee,proto3" json:"Fee,omitempty"`
+	Paid                 uint64   `protobuf:"varint,4,opt,name=Paid,proto3" json:"Paid,omitempty"`
+	Due                  uint64   `protobuf:"varint,5,opt,name=Due,proto3" json:"Due,omitempty"`
+	Time                 int64    `protobuf:"varint,6,opt,name=Time,proto3" json:"Time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BillingRecord) Reset()         { *m = BillingRecord{} }
+func (m *BillingRecord) String() string { return proto.CompactTextString(m) }
+func (*BillingRecord) ProtoMessage()    {}
+func (*BillingRecord) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{35}
+}
+func (m *BillingRecord) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BillingRecord.Unmarshal(m, b)
+}
+func (m *BillingRecord) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BillingRecord.Marshal(b, m, deterministic)
+}
+func (dst *BillingRecord) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BillingRecord.Merge(dst, src)
+}
+func (m *BillingRecord) XXX_Size() int {
+	return xxx_messageInfo_BillingRecord.Size(m)
+}
+func (m *BillingRecord) XXX_DiscardUnknown() {
+	xxx_messageInfo_BillingRecord.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BillingRecord proto.InternalMessageInfo
+
+func (m *BillingRecord) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *BillingRecord) GetSize() uint64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *BillingRecord) GetFee() uint64 {
+	if m != nil {
+		return m.Fee
+	}
+	return 0
+}
+
+func (m *BillingRecord) GetPaid() uint64 {
+	if m != nil {
+		return m.Paid
+	}
+	return 0
+}
+
+func (m *BillingRecord) GetDue() uint64 {
+	if m != nil {
+		return m.Due
+	}
+	return 0
+}
+
+func (m *BillingRecord) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+// ChannelBilling is the billing statement of a user channel
+type ChannelBilling struct {
+	ChannelID string `protobuf:"bytes,1,opt,name=ChannelID,proto3" json:"ChannelID,omitempty"`
+	Balance   uint64 `protobuf:"varint,2,opt,name=Balance,proto3" json:"Balance,omitempty"`
+	Due       uint64 `protobuf:"varint,3,opt,name=Due,proto3" json:"Due,omitempty"`
+	// Halted channel refuses txs until the due is paid off
+	Halted               bool             `protobuf:"varint,4,opt,name=Halted,proto3" json:"Halted,omitempty"`
+	BlockPrice           uint64           `protobuf:"varint,5,opt,name=BlockPrice,proto3" json:"BlockPrice,omitempty"`
+	Records              []*BillingRecord `protobuf:"bytes,6,rep,name=Records,proto3" json:"Records,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ChannelBilling) Reset()         { *m = ChannelBilling{} }
+func (m *ChannelBilling) String() string { return proto.CompactTextString(m) }
+func (*ChannelBilling) ProtoMessage()    {}
+func (*ChannelBilling) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{36}
+}
+func (m *ChannelBilling) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChannelBilling.Unmarshal(m, b)
+}
+func (m *ChannelBilling) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChannelBilling.Marshal(b, m, deterministic)
+}
+func (dst *ChannelBilling) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChannelBilling.Merge(dst, src)
+}
+func (m *ChannelBilling) XXX_Size() int {
+	return xxx_messageInfo_ChannelBilling.Size(m)
+}
+func (m *ChannelBilling) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChannelBilling.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChannelBilling proto.InternalMessageInfo
+
+func (m *ChannelBilling) GetChannelID() string {
+	if m != nil {
+		return m.ChannelID
+	}
+	return ""
+}
+
+func (m *ChannelBilling) GetBalance() uint64 {
+	if m != nil {
+		return m.Balance
+	}
+	return 0
+}
+
+func (m *ChannelBilling) GetDue() uint64 {
+	if m != nil {
+		return m.Due
+	}
+	return 0
+}
+
+func (m *ChannelBilling) GetHalted() bool {
+	if m != nil {
+		return m.Halted
+	}
+	return false
+}
+
+func (m *ChannelBilling) GetBlockPrice() uint64 {
+	if m != nil {
+		return m.BlockPrice
+	}
+	return 0
+}
+
+func (m *ChannelBilling) GetRecords() []*BillingRecord {
+	if m != nil {
+		return m.Records
+	}
+	return nil
+}
+
+type WatchBillingRequest struct {
+	// ChannelID is the channel watched, empty means all user channels
+	ChannelID            string   `protobuf:"bytes,1,opt,name=ChannelID,proto3" json:"ChannelID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchBillingRequest) Reset()         { *m = WatchBillingRequest{} }
+func (m *WatchBillingRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchBillingRequest) ProtoMessage()    {}
+func (*WatchBillingRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{37}
+}
+func (m *WatchBillingRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchBillingRequest.Unmarshal(m, b)
+}
+func (m *WatchBillingRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchBillingRequest.Marshal(b, m, deterministic)
+}
+func (dst *WatchBillingRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchBillingRequest.Merge(dst, src)
+}
+func (m *WatchBillingRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchBillingRequest.Size(m)
+}
+func (m *WatchBillingRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchBillingRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchBillingRequest proto.InternalMessageInfo
+
+func (m *WatchBillingRequest) GetChannelID() string {
+	if m != nil {
+		return m.ChannelID
+	}
+	return ""
+}
+
+// BillingEvent is sent when a user channel is halted or woken
+type BillingEvent struct {
+	ChannelID string `protobuf:"bytes,1,opt,name=ChannelID,proto3" json:"ChannelID,omitempty"`
+	Halted    bool   `protobuf:"varint,2,opt,name=Halted,proto3" json:"Halted,omitempty"`
+	// Due is the due of channel when it is halted
+	Due                  uint64   `protobuf:"varint,3,opt,name=Due,proto3" json:"Due,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BillingEvent) Reset()         { *m = BillingEvent{} }
+func (m *BillingEvent) String() string { return proto.CompactTextString(m) }
+func (*BillingEvent) ProtoMessage()    {}
+func (*BillingEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{38}
+}
+func (m *BillingEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BillingEvent.Unmarshal(m, b)
+}
+func (m *BillingEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BillingEvent.Marshal(b, m, deterministic)
+}
+func (dst *BillingEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BillingEvent.Merge(dst, src)
+}
+func (m *BillingEvent) XXX_Size() int {
+	return xxx_messageInfo_BillingEvent.Size(m)
+}
+func (m *BillingEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_BillingEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BillingEvent proto.InternalMessageInfo
+
+func (m *BillingEvent) GetChannelID() string {
+	if m != nil {
+		return m.ChannelID
+	}
+	return ""
+}
+
+func (m *BillingEvent) GetHalted() bool {
+	if m != nil {
+		return m.Halted
+	}
+	return false
+}
+
+func (m *BillingEvent) GetDue() uint64 {
+	if m != nil {
+		return m.Due
+	}
+	return 0
+}
+
+type GetTokenInfoRequest struct {
+	Address              []byte   `protobuf:"bytes,1,opt,name=Address,proto3" json:"Address,omitempty"`
+	ChannelID            []byte   `protobuf:"bytes,2,opt,name=ChannelID,proto3" json:"ChannelID,omitempty"`
+	AssetID              string   `protobuf:"bytes,3,opt,name=AssetID,proto3" json:"AssetID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetTokenInfoRequest) Reset()         { *m = GetTokenInfoRequest{} }
+func (m *GetTokenInfoRequest) String() string { return proto.CompactTextString(m) }
+func (*GetTokenInfoRequest) ProtoMessage()    {}
+func (*GetTokenInfoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{39}
+}
+func (m *GetTokenInfoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTokenInfoRequest.Unmarshal(m, b)
+}
+func (m *GetTokenInfoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTokenInfoRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetTokenInfoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTokenInfoRequest.Merge(dst, src)
+}
+func (m *GetTokenInfoRequest) XXX_Size() int {
+	return xxx_messageInfo_GetTokenInfoRequest.Size(m)
+}
+func (m *GetTokenInfoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTokenInfoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTokenInfoRequest proto.InternalMessageInfo
+
+func (m *GetTokenInfoRequest) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *GetTokenInfoRequest) GetChannelID() []byte {
+	if m != nil {
+		return m.ChannelID
+	}
+	return nil
+}
+
+func (m *GetTokenInfoRequest) GetAssetID() string {
+	if m != nil {
+		return m.AssetID
+	}
+	return ""
+}
+
+type TokenInfo struct {
+	Balance uint64 `protobuf:"varint,1,opt,name=Balance,proto3" json:"Balance,omitempty"`
+	// AssetBalance is the balance of asset in _asset channel
+	AssetBalance         uint64   `protobuf:"varint,2,opt,name=AssetBalance,proto3" json:"AssetBalance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TokenInfo) Reset()         { *m = TokenInfo{} }
+func (m *TokenInfo) String() string { return proto.CompactTextString(m) }
+func (*TokenInfo) ProtoMessage()    {}
+func (*TokenInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{40}
+}
+func (m *TokenInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TokenInfo.Unmarshal(m, b)
+}
+func (m *TokenInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TokenInfo.Marshal(b, m, deterministic)
+}
+func (dst *TokenInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TokenInfo.Merge(dst, src)
+}
+func (m *TokenInfo) XXX_Size() int {
+	return xxx_messageInfo_TokenInfo.Size(m)
+}
+func (m *TokenInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_TokenInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TokenInfo proto.InternalMessageInfo
+
+func (m *TokenInfo) GetBalance() uint64 {
+	if m != nil {
+		return m.Balance
+	}
+	return 0
+}
+
+func (m *TokenInfo) GetAssetBalance() uint64 {
+	if m != nil {
+		return m.AssetBalance
+	}
+	return 0
+}
+
+type BackupRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BackupRequest) Reset()         { *m = BackupRequest{} }
+func (m *BackupRequest) String() string { return proto.CompactTextString(m) }
+func (*BackupRequest) ProtoMessage()    {}
+func (*BackupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{41}
+}
+func (m *BackupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BackupRequest.Unmarshal(m, b)
+}
+func (m *BackupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BackupRequest.Marshal(b, m, deterministic)
+}
+func (dst *BackupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BackupRequest.Merge(dst, src)
+}
+func (m *BackupRequest) XXX_Size() int {
+	return xxx_messageInfo_BackupRequest.Size(m)
+}
+func (m *BackupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BackupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BackupRequest proto.InternalMessageInfo
+
+// BackupChunk is a piece of the backup archive
+type BackupChunk struct {
+	Data                 []byte   `protobuf:"bytes,1,opt,name=Data,proto3" json:"Data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BackupChunk) Reset()         { *m = BackupChunk{} }
+func (m *BackupChunk) String() string { return proto.CompactTextString(m) }
+func (*BackupChunk) ProtoMessage()    {}
+func (*BackupChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{42}
+}
+func (m *BackupChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BackupChunk.Unmarshal(m, b)
+}
+func (m *BackupChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BackupChunk.Marshal(b, m, deterministic)
+}
+func (dst *BackupChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BackupChunk.Merge(dst, src)
+}
+func (m *BackupChunk) XXX_Size() int {
+	return xxx_messageInfo_BackupChunk.Size(m)
+}
+func (m *BackupChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_BackupChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BackupChunk proto.InternalMessageInfo
+
+func (m *BackupChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+// FetchSnapshotRequest asks for the latest state snapshot of peer, which
+// is sent in BackupChunk. It is signed by the key of the requesting peer at
+// Time, and only the channels it belongs to are sent.
+type FetchSnapshotRequest struct {
+	PK                   []byte   `protobuf:"bytes,1,opt,name=PK,proto3" json:"PK,omitempty"`
+	Algo                 int32    `protobuf:"varint,2,opt,name=Algo,proto3" json:"Algo,omitempty"`
+	Time                 int64    `protobuf:"varint,3,opt,name=Time,proto3" json:"Time,omitempty"`
+	Sig                  []byte   `protobuf:"bytes,4,opt,name=Sig,proto3" json:"Sig,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FetchSnapshotRequest) Reset()         { *m = FetchSnapshotRequest{} }
+func (m *FetchSnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*FetchSnapshotRequest) ProtoMessage()    {}
+func (*FetchSnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_service_ae0ad5e10418a490, []int{43}
+}
+func (m *FetchSnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FetchSnapshotRequest.Unmarshal(m, b)
+}
+func (m *FetchSnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FetchSnapshotRequest.Marshal(b, m, deterministic)
+}
+func (dst *FetchSnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FetchSnapshotRequest.Merge(dst, src)
+}
+func (m *FetchSnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_FetchSnapshotRequest.Size(m)
+}
+func (m *FetchSnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FetchSnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FetchSnapshotRequest proto.InternalMessageInfo
+
+func (m *FetchSnapshotRequest) GetPK() []byte {
+	if m != nil {
+		return m.PK
+	}
+	return nil
+}
+
+func (m *FetchSnapshotRequest) GetAlgo() int32 {
+	if m != nil {
+		return m.Algo
+	}
+	return 0
+}
+
+func (m *FetchSnapshotRequest) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *FetchSnapshotRequest) GetSig() []byte {
+	if m != nil {
+		return m.Sig
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*FetchBlockRequest)(nil), "protos.FetchBlockRequest")
+	proto.RegisterType((*ListChannelsRequest)(nil), "protos.ListChannelsRequest")
+	proto.RegisterType((*ChannelInfos)(nil), "protos.ChannelInfos")
+	proto.RegisterType((*ChannelInfo)(nil), "protos.ChannelInfo")
+	proto.RegisterType((*CreateChannelRequest)(nil), "protos.CreateChannelRequest")
+	proto.RegisterType((*CreateChannelTxPayload)(nil), "protos.CreateChannelTxPayload")
+	proto.RegisterType((*AddTxRequest)(nil), "protos.AddTxRequest")
+	proto.RegisterType((*TxStatus)(nil), "protos.TxStatus")
+	proto.RegisterType((*TxLog)(nil), "protos.TxLog")
+	proto.RegisterType((*GetStateRootRequest)(nil), "protos.GetStateRootRequest")
+	proto.RegisterType((*StateRoot)(nil), "protos.StateRoot")
+	proto.RegisterType((*GetStateProofRequest)(nil), "protos.GetStateProofRequest")
+	proto.RegisterType((*StateProof)(nil), "protos.StateProof")
+	proto.RegisterType((*GetAccountAtRequest)(nil), "protos.GetAccountAtRequest")
+	proto.RegisterType((*StateAccount)(nil), "protos.StateAccount")
+	proto.RegisterType((*GetStorageAtRequest)(nil), "protos.GetStorageAtRequest")
+	proto.RegisterType((*StateStorage)(nil), "protos.StateStorage")
+	proto.RegisterType((*CallAtRequest)(nil), "protos.CallAtRequest")
+	proto.RegisterType((*CallResult)(nil), "protos.CallResult")
+	proto.RegisterType((*EstimateGasRequest)(nil), "protos.EstimateGasRequest")
+	proto.RegisterType((*GasEstimate)(nil), "protos.GasEstimate")
+	proto.RegisterType((*TraceTransactionRequest)(nil), "protos.TraceTransactionRequest")
+	proto.RegisterType((*TxTrace)(nil), "protos.TxTrace")
+	proto.RegisterType((*TraceStep)(nil), "protos.TraceStep")
+	proto.RegisterType((*TraceCall)(nil), "protos.TraceCall")
+	proto.RegisterType((*StorageChange)(nil), "protos.StorageChange")
+	proto.RegisterType((*GetTxStatusRequest)(nil), "protos.GetTxStatusRequest")
+	proto.RegisterType((*ListTxHistoryRequest)(nil), "protos.ListTxHistoryRequest")
+	proto.RegisterType((*TxHistory)(nil), "protos.TxHistory")
+	proto.RegisterMapType((map[string]*StringList)(nil), "protos.TxHistory.TxsEntry")
+	proto.RegisterType((*GetAccountInfoRequest)(nil), "protos.GetAccountInfoRequest")
+	proto.RegisterType((*AccountInfo)(nil), "protos.AccountInfo")
+	proto.RegisterType((*GetComplianceHistoryRequest)(nil), "protos.GetComplianceHistoryRequest")
+	proto.RegisterType((*ComplianceRecord)(nil), "protos.ComplianceRecord")
+	proto.RegisterType((*ComplianceHistory)(nil), "protos.ComplianceHistory")
+	proto.RegisterType((*GetChannelBillingRequest)(nil), "protos.GetChannelBillingRequest")
+	proto.RegisterType((*BillingRecord)(nil), "protos.BillingRecord")
+	proto.RegisterType((*ChannelBilling)(nil), "protos.ChannelBilling")
+	proto.RegisterType((*WatchBillingRequest)(nil), "protos.WatchBillingRequest")
+	proto.RegisterType((*BillingEvent)(nil), "protos.BillingEvent")
+	proto.RegisterType((*GetTokenInfoRequest)(nil), "protos.GetTokenInfoRequest")
+	proto.RegisterType((*TokenInfo)(nil), "protos.TokenInfo")
+	proto.RegisterType((*BackupRequest)(nil), "protos.BackupRequest")
+	proto.RegisterType((*BackupChunk)(nil), "protos.BackupChunk")
+	proto.RegisterType((*FetchSnapshotRequest)(nil), "protos.FetchSnapshotRequest")
+	proto.RegisterEnum("protos.Behavior", Behavior_name, Behavior_value)
+	proto.RegisterEnum("protos.Identity", Identity_name, Identity_value)
+	proto.RegisterEnum("protos.TxCode", TxCode_name, TxCode_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
 
 // OrdererClient is the client API for Orderer service.
 //
@@ -824,6 +2840,13 @@ type OrdererClient interface {
 	CreateChannel(ctx context.Context, in *CreateChannelRequest, opts ...grpc.CallOption) (*ChannelInfo, error)
 	AddTx(ctx context.Context, in *AddTxRequest, opts ...grpc.CallOption) (*TxStatus, error)
 	GetAccountInfo(ctx context.Context, in *GetAccountInfoRequest, opts ...grpc.CallOption) (*AccountInfo, error)
+	GetComplianceHistory(ctx context.Context, in *GetComplianceHistoryRequest, opts ...grpc.CallOption) (*ComplianceHistory, error)
+	GetChannelBilling(ctx context.Context, in *GetChannelBillingRequest, opts ...grpc.CallOption) (*ChannelBilling, error)
+	// WatchBilling streams the billing events of user channels, the stream
+	// ends with an error if the watcher falls behind
+	WatchBilling(ctx context.Context, in *WatchBillingRequest, opts ...grpc.CallOption) (Orderer_WatchBillingClient, error)
+	// Backup streams a backup archive, only local callers are allowed
+	Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (Orderer_BackupClient, error)
 }
 
 type ordererClient struct {
@@ -879,6 +2902,88 @@ func (c *ordererClient) GetAccountInfo(ctx context.Context, in *GetAccountInfoRe
 	return out, nil
 }
 
+func (c *ordererClient) GetComplianceHistory(ctx context.Context, in *GetComplianceHistoryRequest, opts ...grpc.CallOption) (*ComplianceHistory, error) {
+	out := new(ComplianceHistory)
+	err := c.cc.Invoke(ctx, "/protos.Orderer/GetComplianceHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordererClient) GetChannelBilling(ctx context.Context, in *GetChannelBillingRequest, opts ...grpc.CallOption) (*ChannelBilling, error) {
+	out := new(ChannelBilling)
+	err := c.cc.Invoke(ctx, "/protos.Orderer/GetChannelBilling", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordererClient) WatchBilling(ctx context.Context, in *WatchBillingRequest, opts ...grpc.CallOption) (Orderer_WatchBillingClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Orderer_serviceDesc.Streams[0], "/protos.Orderer/WatchBilling", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ordererWatchBillingClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Orderer_WatchBillingClient interface {
+	Recv() (*BillingEvent, error)
+	grpc.ClientStream
+}
+
+type ordererWatchBillingClient struct {
+	grpc.ClientStream
+}
+
+func (x *ordererWatchBillingClient) Recv() (*BillingEvent, error) {
+	m := new(BillingEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *ordererClient) Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (Orderer_BackupClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Orderer_serviceDesc.Streams[1], "/protos.Orderer/Backup", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ordererBackupClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Orderer_BackupClient interface {
+	Recv() (*BackupChunk, error)
+	grpc.ClientStream
+}
+
+type ordererBackupClient struct {
+	grpc.ClientStream
+}
+
+func (x *ordererBackupClient) Recv() (*BackupChunk, error) {
+	m := new(BackupChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // OrdererServer is the server API for Orderer service.
 type OrdererServer interface {
 	FetchBlock(context.Context, *FetchBlockRequest) (*Block, error)
@@ -886,6 +2991,13 @@ type OrdererServer interface {
 	CreateChannel(context.Context, *CreateChannelRequest) (*ChannelInfo, error)
 	AddTx(context.Context, *AddTxRequest) (*TxStatus, error)
 	GetAccountInfo(context.Context, *GetAccountInfoRequest) (*AccountInfo, error)
+	GetComplianceHistory(context.Context, *GetComplianceHistoryRequest) (*ComplianceHistory, error)
+	GetChannelBilling(context.Context, *GetChannelBillingRequest) (*ChannelBilling, error)
+	// WatchBilling streams the billing events of user channels, the stream
+	// ends with an error if the watcher falls behind
+	WatchBilling(*WatchBillingRequest, Orderer_WatchBillingServer) error
+	// Backup streams a backup archive, only local callers are allowed
+	Backup(*BackupRequest, Orderer_BackupServer) error
 }
 
 func RegisterOrdererServer(s *grpc.Server, srv OrdererServer) {
@@ -920,68 +3032,146 @@ func _Orderer_ListChannels_Handler(srv interface{}, ctx context.Context, dec fun
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/protos.Orderer/ListChannels",
+		FullMethod: "/protos.Orderer/ListChannels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdererServer).ListChannels(ctx, req.(*ListChannelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Orderer_CreateChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdererServer).CreateChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Orderer/CreateChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdererServer).CreateChannel(ctx, req.(*CreateChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Orderer_AddTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdererServer).AddTx(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Orderer/AddTx",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(OrdererServer).ListChannels(ctx, req.(*ListChannelsRequest))
+		return srv.(OrdererServer).AddTx(ctx, req.(*AddTxRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Orderer_CreateChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateChannelRequest)
+func _Orderer_GetAccountInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountInfoRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(OrdererServer).CreateChannel(ctx, in)
+		return srv.(OrdererServer).GetAccountInfo(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/protos.Orderer/CreateChannel",
+		FullMethod: "/protos.Orderer/GetAccountInfo",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(OrdererServer).CreateChannel(ctx, req.(*CreateChannelRequest))
+		return srv.(OrdererServer).GetAccountInfo(ctx, req.(*GetAccountInfoRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Orderer_AddTx_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AddTxRequest)
+func _Orderer_GetComplianceHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetComplianceHistoryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(OrdererServer).AddTx(ctx, in)
+		return srv.(OrdererServer).GetComplianceHistory(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/protos.Orderer/AddTx",
+		FullMethod: "/protos.Orderer/GetComplianceHistory",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(OrdererServer).AddTx(ctx, req.(*AddTxRequest))
+		return srv.(OrdererServer).GetComplianceHistory(ctx, req.(*GetComplianceHistoryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Orderer_GetAccountInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetAccountInfoRequest)
+func _Orderer_GetChannelBilling_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChannelBillingRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(OrdererServer).GetAccountInfo(ctx, in)
+		return srv.(OrdererServer).GetChannelBilling(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/protos.Orderer/GetAccountInfo",
+		FullMethod: "/protos.Orderer/GetChannelBilling",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(OrdererServer).GetAccountInfo(ctx, req.(*GetAccountInfoRequest))
+		return srv.(OrdererServer).GetChannelBilling(ctx, req.(*GetChannelBillingRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Orderer_WatchBilling_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchBillingRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrdererServer).WatchBilling(m, &ordererWatchBillingServer{stream})
+}
+
+type Orderer_WatchBillingServer interface {
+	Send(*BillingEvent) error
+	grpc.ServerStream
+}
+
+type ordererWatchBillingServer struct {
+	grpc.ServerStream
+}
+
+func (x *ordererWatchBillingServer) Send(m *BillingEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Orderer_Backup_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BackupRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrdererServer).Backup(m, &ordererBackupServer{stream})
+}
+
+type Orderer_BackupServer interface {
+	Send(*BackupChunk) error
+	grpc.ServerStream
+}
+
+type ordererBackupServer struct {
+	grpc.ServerStream
+}
+
+func (x *ordererBackupServer) Send(m *BackupChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Orderer_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "protos.Orderer",
 	HandlerType: (*OrdererServer)(nil),
@@ -1006,8 +3196,27 @@ var _Orderer_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetAccountInfo",
 			Handler:    _Orderer_GetAccountInfo_Handler,
 		},
+		{
+			MethodName: "GetComplianceHistory",
+			Handler:    _Orderer_GetComplianceHistory_Handler,
+		},
+		{
+			MethodName: "GetChannelBilling",
+			Handler:    _Orderer_GetChannelBilling_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchBilling",
+			Handler:       _Orderer_WatchBilling_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Backup",
+			Handler:       _Orderer_Backup_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "service.proto",
 }
 
@@ -1018,6 +3227,15 @@ type PeerClient interface {
 	GetTxStatus(ctx context.Context, in *GetTxStatusRequest, opts ...grpc.CallOption) (*TxStatus, error)
 	ListTxHistory(ctx context.Context, in *ListTxHistoryRequest, opts ...grpc.CallOption) (*TxHistory, error)
 	GetTokenInfo(ctx context.Context, in *GetTokenInfoRequest, opts ...grpc.CallOption) (*TokenInfo, error)
+	Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (Peer_BackupClient, error)
+	FetchSnapshot(ctx context.Context, in *FetchSnapshotRequest, opts ...grpc.CallOption) (Peer_FetchSnapshotClient, error)
+	GetStateRoot(ctx context.Context, in *GetStateRootRequest, opts ...grpc.CallOption) (*StateRoot, error)
+	GetStateProof(ctx context.Context, in *GetStateProofRequest, opts ...grpc.CallOption) (*StateProof, error)
+	GetAccountAt(ctx context.Context, in *GetAccountAtRequest, opts ...grpc.CallOption) (*StateAccount, error)
+	GetStorageAt(ctx context.Context, in *GetStorageAtRequest, opts ...grpc.CallOption) (*StateStorage, error)
+	CallAt(ctx context.Context, in *CallAtRequest, opts ...grpc.CallOption) (*CallResult, error)
+	EstimateGas(ctx context.Context, in *EstimateGasRequest, opts ...grpc.CallOption) (*GasEstimate, error)
+	TraceTransaction(ctx context.Context, in *TraceTransactionRequest, opts ...grpc.CallOption) (*TxTrace, error)
 }
 
 type peerClient struct {
@@ -1055,11 +3273,147 @@ func (c *peerClient) GetTokenInfo(ctx context.Context, in *GetTokenInfoRequest,
 	return out, nil
 }
 
+func (c *peerClient) Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (Peer_BackupClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Peer_serviceDesc.Streams[0], "/protos.Peer/Backup", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &peerBackupClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Peer_BackupClient interface {
+	Recv() (*BackupChunk, error)
+	grpc.ClientStream
+}
+
+type peerBackupClient struct {
+	grpc.ClientStream
+}
+
+func (x *peerBackupClient) Recv() (*BackupChunk, error) {
+	m := new(BackupChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *peerClient) FetchSnapshot(ctx context.Context, in *FetchSnapshotRequest, opts ...grpc.CallOption) (Peer_FetchSnapshotClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Peer_serviceDesc.Streams[1], "/protos.Peer/FetchSnapshot", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &peerFetchSnapshotClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Peer_FetchSnapshotClient interface {
+	Recv() (*BackupChunk, error)
+	grpc.ClientStream
+}
+
+type peerFetchSnapshotClient struct {
+	grpc.ClientStream
+}
+
+func (x *peerFetchSnapshotClient) Recv() (*BackupChunk, error) {
+	m := new(BackupChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *peerClient) GetStateRoot(ctx context.Context, in *GetStateRootRequest, opts ...grpc.CallOption) (*StateRoot, error) {
+	out := new(StateRoot)
+	err := c.cc.Invoke(ctx, "/protos.Peer/GetStateRoot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerClient) GetStateProof(ctx context.Context, in *GetStateProofRequest, opts ...grpc.CallOption) (*StateProof, error) {
+	out := new(StateProof)
+	err := c.cc.Invoke(ctx, "/protos.Peer/GetStateProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerClient) GetAccountAt(ctx context.Context, in *GetAccountAtRequest, opts ...grpc.CallOption) (*StateAccount, error) {
+	out := new(StateAccount)
+	err := c.cc.Invoke(ctx, "/protos.Peer/GetAccountAt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerClient) GetStorageAt(ctx context.Context, in *GetStorageAtRequest, opts ...grpc.CallOption) (*StateStorage, error) {
+	out := new(StateStorage)
+	err := c.cc.Invoke(ctx, "/protos.Peer/GetStorageAt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerClient) CallAt(ctx context.Context, in *CallAtRequest, opts ...grpc.CallOption) (*CallResult, error) {
+	out := new(CallResult)
+	err := c.cc.Invoke(ctx, "/protos.Peer/CallAt", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerClient) EstimateGas(ctx context.Context, in *EstimateGasRequest, opts ...grpc.CallOption) (*GasEstimate, error) {
+	out := new(GasEstimate)
+	err := c.cc.Invoke(ctx, "/protos.Peer/EstimateGas", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peerClient) TraceTransaction(ctx context.Context, in *TraceTransactionRequest, opts ...grpc.CallOption) (*TxTrace, error) {
+	out := new(TxTrace)
+	err := c.cc.Invoke(ctx, "/protos.Peer/TraceTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PeerServer is the server API for Peer service.
 type PeerServer interface {
 	GetTxStatus(context.Context, *GetTxStatusRequest) (*TxStatus, error)
 	ListTxHistory(context.Context, *ListTxHistoryRequest) (*TxHistory, error)
 	GetTokenInfo(context.Context, *GetTokenInfoRequest) (*TokenInfo, error)
+	Backup(*BackupRequest, Peer_BackupServer) error
+	FetchSnapshot(*FetchSnapshotRequest, Peer_FetchSnapshotServer) error
+	GetStateRoot(context.Context, *GetStateRootRequest) (*StateRoot, error)
+	GetStateProof(context.Context, *GetStateProofRequest) (*StateProof, error)
+	GetAccountAt(context.Context, *GetAccountAtRequest) (*StateAccount, error)
+	GetStorageAt(context.Context, *GetStorageAtRequest) (*StateStorage, error)
+	CallAt(context.Context, *CallAtRequest) (*CallResult, error)
+	EstimateGas(context.Context, *EstimateGasRequest) (*GasEstimate, error)
+	TraceTransaction(context.Context, *TraceTransactionRequest) (*TxTrace, error)
 }
 
 func RegisterPeerServer(s *grpc.Server, srv PeerServer) {
@@ -1120,6 +3474,174 @@ func _Peer_GetTokenInfo_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Peer_Backup_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BackupRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PeerServer).Backup(m, &peerBackupServer{stream})
+}
+
+type Peer_BackupServer interface {
+	Send(*BackupChunk) error
+	grpc.ServerStream
+}
+
+type peerBackupServer struct {
+	grpc.ServerStream
+}
+
+func (x *peerBackupServer) Send(m *BackupChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Peer_FetchSnapshot_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FetchSnapshotRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PeerServer).FetchSnapshot(m, &peerFetchSnapshotServer{stream})
+}
+
+type Peer_FetchSnapshotServer interface {
+	Send(*BackupChunk) error
+	grpc.ServerStream
+}
+
+type peerFetchSnapshotServer struct {
+	grpc.ServerStream
+}
+
+func (x *peerFetchSnapshotServer) Send(m *BackupChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Peer_GetStateRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStateRootRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServer).GetStateRoot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Peer/GetStateRoot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServer).GetStateRoot(ctx, req.(*GetStateRootRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peer_GetStateProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStateProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServer).GetStateProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Peer/GetStateProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServer).GetStateProof(ctx, req.(*GetStateProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peer_GetAccountAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountAtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServer).GetAccountAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Peer/GetAccountAt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServer).GetAccountAt(ctx, req.(*GetAccountAtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peer_GetStorageAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStorageAtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServer).GetStorageAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Peer/GetStorageAt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServer).GetStorageAt(ctx, req.(*GetStorageAtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peer_CallAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CallAtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServer).CallAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Peer/CallAt",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServer).CallAt(ctx, req.(*CallAtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peer_EstimateGas_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EstimateGasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServer).EstimateGas(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Peer/EstimateGas",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServer).EstimateGas(ctx, req.(*EstimateGasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peer_TraceTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TraceTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeerServer).TraceTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.Peer/TraceTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeerServer).TraceTransaction(ctx, req.(*TraceTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Peer_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "protos.Peer",
 	HandlerType: (*PeerServer)(nil),
@@ -1136,68 +3658,196 @@ var _Peer_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetTokenInfo",
 			Handler:    _Peer_GetTokenInfo_Handler,
 		},
+		{
+			MethodName: "GetStateRoot",
+			Handler:    _Peer_GetStateRoot_Handler,
+		},
+		{
+			MethodName: "GetStateProof",
+			Handler:    _Peer_GetStateProof_Handler,
+		},
+		{
+			MethodName: "GetAccountAt",
+			Handler:    _Peer_GetAccountAt_Handler,
+		},
+		{
+			MethodName: "GetStorageAt",
+			Handler:    _Peer_GetStorageAt_Handler,
+		},
+		{
+			MethodName: "CallAt",
+			Handler:    _Peer_CallAt_Handler,
+		},
+		{
+			MethodName: "EstimateGas",
+			Handler:    _Peer_EstimateGas_Handler,
+		},
+		{
+			MethodName: "TraceTransaction",
+			Handler:    _Peer_TraceTransaction_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Backup",
+			Handler:       _Peer_Backup_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "FetchSnapshot",
+			Handler:       _Peer_FetchSnapshot_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "service.proto",
 }
 
-func init() { proto.RegisterFile("service.proto", fileDescriptor_service_cabd911633115393) }
-
-var fileDescriptor_service_cabd911633115393 = []byte{
-	// 865 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0x51, 0x6f, 0xe3, 0x44,
-	0x10, 0xb6, 0xd3, 0x24, 0x97, 0x4c, 0xd2, 0x23, 0x9d, 0xf6, 0xaa, 0x60, 0x0a, 0x8a, 0x56, 0x42,
-	0x44, 0xd5, 0xa9, 0xc7, 0x05, 0x09, 0x1d, 0x48, 0x08, 0x92, 0x26, 0x0d, 0xe6, 0x9a, 0x34, 0x6c,
-	0xdc, 0x07, 0x9e, 0x2a, 0xd7, 0x59, 0xae, 0x56, 0x53, 0xfb, 0xd8, 0xdd, 0x14, 0x87, 0x77, 0xfe,
-	0x07, 0x7f, 0x07, 0xf1, 0xcc, 0xff, 0x41, 0x5e, 0x7b, 0x6d, 0xa7, 0x17, 0xb8, 0xf2, 0xd4, 0x9d,
-	0x6f, 0xbf, 0xce, 0x8c, 0xbf, 0xfd, 0x66, 0x02, 0xbb, 0x82, 0xf1, 0x7b, 0xdf, 0x63, 0x27, 0x6f,
-	0x79, 0x28, 0x43, 0xac, 0xaa, 0x3f, 0xc2, 0x6a, 0x7a, 0xe1, 0xdd, 0x5d, 0x18, 0x24, 0xa8, 0x55,
-	0x93, 0x51, 0x7a, 0x6a, 0x5c, 0x2f, 0x43, 0xef, 0x36, 0x09, 0xc8, 0xaf, 0xb0, 0x77, 0xc6, 0xa4,
-	0x77, 0x33, 0x88, 0x31, 0xca, 0x7e, 0x59, 0x31, 0x21, 0xf1, 0x08, 0xea, 0xa7, 0x37, 0x6e, 0x10,
-	0xb0, 0xa5, 0x3d, 0x6c, 0x9b, 0x1d, 0xb3, 0x5b, 0xa7, 0x39, 0x80, 0x87, 0x50, 0x9d, 0xae, 0xee,
-	0xae, 0x19, 0x6f, 0x97, 0x3a, 0x66, 0xb7, 0x4c, 0xd3, 0x08, 0x9f, 0x43, 0x6d, 0xc0, 0x6e, 0xdc,
-	0x7b, 0x3f, 0xe4, 0xed, 0x9d, 0x8e, 0xd9, 0x7d, 0xda, 0x6b, 0x25, 0x45, 0xc4, 0x89, 0xc6, 0x69,
-	0xc6, 0x20, 0x3f, 0xc2, 0xfe, 0xb9, 0x2f, 0x64, 0x9a, 0x56, 0xe8, 0xd2, 0x87, 0x50, 0x9d, 0xaf,
-	0x85, 0x64, 0x77, 0xaa, 0x6e, 0x8d, 0xa6, 0x11, 0x3e, 0x85, 0xd2, 0xec, 0xb5, 0x2a, 0xd8, 0xa4,
-	0xa5, 0xd9, 0x6b, 0x44, 0x28, 0xf7, 0x97, 0x6f, 0x42, 0x55, 0xa8, 0x42, 0xd5, 0x99, 0x7c, 0x0b,
-	0x4d, 0xdd, 0x65, 0xf0, 0x73, 0x28, 0xf0, 0x05, 0xd4, 0x74, 0xfa, 0xb6, 0xd9, 0xd9, 0xe9, 0x36,
-	0x7a, 0xfb, 0xba, 0xa1, 0x02, 0x8f, 0x66, 0x24, 0xf2, 0xb7, 0x09, 0x8d, 0xc2, 0xcd, 0x7b, 0x74,
-	0x38, 0x82, 0xba, 0x52, 0x6d, 0xee, 0xff, 0xc6, 0x52, 0x29, 0x72, 0x20, 0x56, 0xc3, 0x5e, 0xb0,
-	0x40, 0xfa, 0x72, 0xfd, 0x50, 0x0d, 0x8d, 0xd3, 0x8c, 0x11, 0x7f, 0xf6, 0xc4, 0x8d, 0xc6, 0xae,
-	0x68, 0x97, 0x13, 0x4d, 0x93, 0x08, 0x2d, 0xa8, 0x8d, 0x5d, 0x31, 0xe3, 0xbe, 0xc7, 0xda, 0x15,
-	0x75, 0x93, 0xc5, 0xd8, 0x85, 0x0f, 0xfa, 0x42, 0x30, 0xe9, 0x84, 0xb7, 0x2c, 0xa0, 0xae, 0xf4,
-	0xc3, 0x76, 0x55, 0x51, 0x1e, 0xc2, 0xa4, 0x07, 0x07, 0xa7, 0x9c, 0xb9, 0x92, 0xa5, 0xcd, 0x6b,
-	0xb1, 0x2d, 0x28, 0x39, 0x91, 0xfa, 0xb0, 0x46, 0x0f, 0x74, 0x77, 0x4e, 0x44, 0x4b, 0x4e, 0x44,
-	0xbe, 0x84, 0xc3, 0x8d, 0xff, 0x71, 0xa2, 0x99, 0xbb, 0x5e, 0x86, 0xee, 0xe2, 0xbf, 0x55, 0x21,
-	0xc7, 0xd0, 0xec, 0x2f, 0x16, 0x4e, 0xf4, 0x98, 0x1a, 0x7f, 0x98, 0x50, 0x73, 0xa2, 0xb9, 0x74,
-	0xe5, 0x4a, 0x60, 0x0b, 0x76, 0x46, 0x9c, 0xa7, 0x09, 0xe3, 0x23, 0x76, 0xa0, 0xa1, 0xf4, 0xdc,
-	0x70, 0x5b, 0x11, 0xc2, 0x4f, 0x00, 0x54, 0x68, 0x07, 0x0b, 0x16, 0xa5, 0x5e, 0x28, 0x20, 0xb1,
-	0xac, 0x17, 0x2b, 0xf9, 0x76, 0x25, 0x95, 0xac, 0x4d, 0x9a, 0x46, 0xb1, 0x74, 0xa7, 0x61, 0x20,
-	0xb9, 0xeb, 0xc9, 0xfe, 0x62, 0xc1, 0x99, 0x10, 0x4a, 0xdd, 0x3a, 0x7d, 0x08, 0x13, 0x09, 0x38,
-	0x66, 0x52, 0x37, 0xf9, 0xb8, 0x01, 0x41, 0x28, 0x3b, 0x91, 0x3d, 0x54, 0x0d, 0xd7, 0xa9, 0x3a,
-	0xff, 0xcf, 0xe1, 0xf8, 0x1c, 0x0e, 0xe2, 0xe1, 0x70, 0xa2, 0xef, 0x7d, 0x21, 0x43, 0xbe, 0xd6,
-	0x75, 0xdb, 0xf0, 0x44, 0xf7, 0x6b, 0xaa, 0x0f, 0xd2, 0x21, 0xf9, 0xdd, 0x84, 0x7a, 0x46, 0xc7,
-	0xe7, 0xb0, 0xe3, 0x44, 0xda, 0xf4, 0x56, 0xae, 0x7a, 0x7a, 0x7f, 0xe2, 0x44, 0x62, 0x14, 0x48,
-	0xbe, 0xa6, 0x31, 0xcd, 0xfa, 0x21, 0x7e, 0x85, 0x04, 0x88, 0x5f, 0xe1, 0x96, 0xad, 0xf5, 0x2b,
-	0xdc, 0xb2, 0x35, 0x76, 0xa1, 0x72, 0xef, 0x2e, 0x57, 0x89, 0xc5, 0x1b, 0x3d, 0xd4, 0xd9, 0xe6,
-	0x92, 0xfb, 0xc1, 0x9b, 0xb8, 0x4d, 0x9a, 0x10, 0xbe, 0x2e, 0xbd, 0x32, 0xc9, 0x4b, 0x78, 0x36,
-	0x66, 0xb2, 0xef, 0x79, 0xe1, 0x2a, 0x90, 0x6a, 0xbc, 0xde, 0xdb, 0xfa, 0x67, 0xd0, 0x28, 0xf0,
-	0x63, 0xe2, 0xc0, 0x5d, 0xba, 0x81, 0xc7, 0x14, 0xb1, 0x4c, 0x75, 0x48, 0x26, 0xb0, 0x3f, 0x4e,
-	0x7d, 0xfd, 0xa8, 0xcc, 0x9b, 0xcf, 0x94, 0xec, 0x8e, 0x82, 0x53, 0x3f, 0x85, 0x7a, 0x96, 0xeb,
-	0xdf, 0xab, 0x1e, 0x7f, 0x95, 0xbf, 0x1c, 0x3e, 0x83, 0xbd, 0xb3, 0xbe, 0x7d, 0x7e, 0x65, 0x9f,
-	0x5d, 0x4d, 0x2f, 0x9c, 0x2b, 0x3a, 0xea, 0x0f, 0x7f, 0x6a, 0x19, 0x78, 0x08, 0x48, 0x47, 0xce,
-	0x25, 0x9d, 0x5e, 0x5d, 0x4e, 0x1d, 0xfb, 0x3c, 0xc5, 0xcd, 0xe3, 0x17, 0xf9, 0x0e, 0x40, 0x80,
-	0xea, 0x64, 0x34, 0x19, 0x8c, 0x68, 0xcb, 0xc0, 0x3a, 0x54, 0xfa, 0xc3, 0x89, 0x3d, 0x6d, 0x99,
-	0xd8, 0x84, 0xda, 0xc5, 0xa5, 0x33, 0xb7, 0x87, 0x23, 0xda, 0x2a, 0xf5, 0xfe, 0x2c, 0xc1, 0x93,
-	0x0b, 0xbe, 0x60, 0x9c, 0x71, 0x7c, 0x05, 0x90, 0x6f, 0x66, 0xfc, 0x50, 0xcb, 0xfe, 0xce, 0xb6,
-	0xb6, 0x76, 0x33, 0x23, 0xc5, 0x28, 0x31, 0xf0, 0x14, 0x9a, 0xc5, 0xd5, 0x8a, 0x1f, 0x69, 0xc2,
-	0x96, 0x85, 0x6b, 0x1d, 0x6c, 0x59, 0x89, 0x82, 0x18, 0x38, 0x84, 0xdd, 0x8d, 0xf9, 0xc7, 0xa3,
-	0x8c, 0xb8, 0x65, 0x95, 0x58, 0xdb, 0x36, 0x2b, 0x31, 0xf0, 0x25, 0x54, 0xd4, 0x36, 0xc0, 0xac,
-	0x4c, 0x71, 0x39, 0x58, 0xad, 0xdc, 0x9a, 0xc9, 0x80, 0x11, 0x03, 0xcf, 0xe0, 0xe9, 0xa6, 0x83,
-	0xf0, 0x63, 0xcd, 0xda, 0xea, 0xac, 0xbc, 0x74, 0xe1, 0x8e, 0x18, 0xbd, 0xbf, 0x4c, 0x28, 0xcf,
-	0x18, 0xe3, 0xf8, 0x0d, 0x34, 0x0a, 0x23, 0x8c, 0x56, 0x21, 0xdb, 0x83, 0xb9, 0xde, 0xda, 0xcf,
-	0x00, 0x76, 0x37, 0x66, 0x31, 0x17, 0x62, 0xdb, 0x88, 0x5a, 0x7b, 0xef, 0x4c, 0x1b, 0x31, 0xf0,
-	0x3b, 0x68, 0x16, 0x9d, 0x9b, 0xbf, 0xc8, 0x16, 0x3f, 0x17, 0x32, 0xe8, 0x1b, 0x62, 0x5c, 0x27,
-	0x3f, 0xea, 0x5f, 0xfc, 0x13, 0x00, 0x00, 0xff, 0xff, 0x6e, 0xcc, 0x25, 0x56, 0xec, 0x07, 0x00,
-	0x00,
+func init() { proto.RegisterFile("service.proto", fileDescriptor_service_ae0ad5e10418a490) }
+
+var fileDescriptor_service_ae0ad5e10418a490 = []byte{
+	// 2296 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x59, 0x4f, 0x77, 0x23, 0x47,
+	0x11, 0xd7, 0x8c, 0xfe, 0x58, 0x2a, 0x49, 0x5e, 0xb9, 0xd7, 0x6b, 0x14, 0x65, 0x01, 0xa7, 0x81,
+	0x17, 0xbf, 0x7d, 0x79, 0xbb, 0x89, 0xf3, 0x08, 0x0b, 0x8f, 0x3c, 0x90, 0x25, 0xd9, 0x2b, 0x6c,
+	0xcb, 0xa6, 0x35, 0xde, 0xc0, 0x81, 0x67, 0xc6, 0x52, 0xaf, 0x3c, 0xcf, 0xd2, 0x8c, 0x32, 0xd3,
+	0x32, 0xd2, 0xde, 0xb9, 0x71, 0xe1, 0xc2, 0x35, 0x57, 0x4e, 0x7c, 0x08, 0xb8, 0x92, 0x23, 0x5f,
+	0x82, 0x3b, 0x77, 0x5e, 0xff, 0x9b, 0x9e, 0x91, 0xe4, 0x5d, 0x67, 0x21, 0x27, 0x75, 0x75, 0xf5,
+	0x54, 0x57, 0xfd, 0xaa, 0xba, 0xba, 0xba, 0x04, 0xd5, 0x88, 0x86, 0xb7, 0xde, 0x80, 0x3e, 0x9d,
+	0x86, 0x01, 0x0b, 0x50, 0x41, 0xfc, 0x44, 0x8d, 0xca, 0x20, 0x98, 0x4c, 0x02, 0x5f, 0xce, 0x36,
+	0x8a, 0x6c, 0xae, 0x46, 0xe5, 0xab, 0x71, 0x30, 0xb8, 0x91, 0x04, 0xfe, 0x03, 0x6c, 0x1d, 0x52,
+	0x36, 0xb8, 0x3e, 0xe0, 0x73, 0x84, 0x7e, 0x39, 0xa3, 0x11, 0x43, 0x8f, 0xa1, 0xd4, 0xba, 0x76,
+	0x7d, 0x9f, 0x8e, 0xbb, 0xed, 0xba, 0xb5, 0x6b, 0xed, 0x95, 0x88, 0x99, 0x40, 0x3b, 0x50, 0xe8,
+	0xcd, 0x26, 0x57, 0x34, 0xac, 0xdb, 0xbb, 0xd6, 0x5e, 0x8e, 0x28, 0x0a, 0x7d, 0x04, 0xc5, 0x03,
+	0x7a, 0xed, 0xde, 0x7a, 0x41, 0x58, 0xcf, 0xee, 0x5a, 0x7b, 0x9b, 0xfb, 0x35, 0xb9, 0x49, 0xf4,
+	0x54, 0xcf, 0x93, 0x78, 0x05, 0xfe, 0x35, 0x3c, 0x3c, 0xf1, 0x22, 0xa6, 0xc4, 0x46, 0x7a, 0xeb,
+	0x1d, 0x28, 0xf4, 0x17, 0x11, 0xa3, 0x13, 0xb1, 0x6f, 0x91, 0x28, 0x0a, 0x6d, 0x82, 0x7d, 0x7e,
+	0x2c, 0x36, 0xac, 0x10, 0xfb, 0xfc, 0x18, 0x21, 0xc8, 0x35, 0xc7, 0xa3, 0x40, 0x6c, 0x94, 0x27,
+	0x62, 0x8c, 0x7f, 0x01, 0x15, 0xad, 0xa5, 0xff, 0x2a, 0x88, 0xd0, 0x33, 0x28, 0x6a, 0xf1, 0x75,
+	0x6b, 0x37, 0xbb, 0x57, 0xde, 0x7f, 0xa8, 0x15, 0x4a, 0xac, 0x23, 0xf1, 0x22, 0xfc, 0x2f, 0x0b,
+	0xca, 0x09, 0xce, 0x5b, 0x70, 0x78, 0x0c, 0x25, 0x81, 0x5a, 0xdf, 0x7b, 0x4d, 0x15, 0x14, 0x66,
+	0x82, 0xa3, 0xd1, 0x1d, 0x52, 0x9f, 0x79, 0x6c, 0xb1, 0x8c, 0x86, 0x9e, 0x27, 0xf1, 0x0a, 0x6e,
+	0xf6, 0xa9, 0x3b, 0x3f, 0x72, 0xa3, 0x7a, 0x4e, 0x62, 0x2a, 0x29, 0xd4, 0x80, 0xe2, 0x91, 0x1b,
+	0x9d, 0x87, 0xde, 0x80, 0xd6, 0xf3, 0x82, 0x13, 0xd3, 0x68, 0x0f, 0x1e, 0x34, 0xa3, 0x88, 0x32,
+	0x27, 0xb8, 0xa1, 0x3e, 0x71, 0x99, 0x17, 0xd4, 0x0b, 0x62, 0xc9, 0xf2, 0x34, 0xde, 0x87, 0xed,
+	0x56, 0x48, 0x5d, 0x46, 0x95, 0xf2, 0x1a, 0xec, 0x06, 0xd8, 0xce, 0x5c, 0x18, 0x56, 0xde, 0x07,
+	0xad, 0x9d, 0x33, 0x27, 0xb6, 0x33, 0xc7, 0x9f, 0xc1, 0x4e, 0xea, 0x1b, 0x67, 0x7e, 0xee, 0x2e,
+	0xc6, 0x81, 0x3b, 0x7c, 0x33, 0x2a, 0xf8, 0x09, 0x54, 0x9a, 0xc3, 0xa1, 0x33, 0xbf, 0xcf, 0x1e,
+	0x5f, 0xdb, 0x50, 0x74, 0xe6, 0x7d, 0xe6, 0xb2, 0x59, 0x84, 0x6a, 0x90, 0xed, 0x84, 0xa1, 0x12,
+	0xc8, 0x87, 0x68, 0x17, 0xca, 0x02, 0xcf, 0x54, 0xb4, 0x25, 0xa7, 0xd0, 0xf7, 0x00, 0x04, 0xd9,
+	0xf5, 0x87, 0x74, 0xae, 0x62, 0x21, 0x31, 0xc3, 0x61, 0x3d, 0x9b, 0xb1, 0xe9, 0x8c, 0x09, 0x58,
+	0x2b, 0x44, 0x51, 0x1c, 0xba, 0x56, 0xe0, 0xb3, 0xd0, 0x1d, 0xb0, 0xe6, 0x70, 0x18, 0xd2, 0x28,
+	0x12, 0xe8, 0x96, 0xc8, 0xf2, 0x34, 0xc2, 0x90, 0x6b, 0x05, 0x43, 0x2a, 0x90, 0xdd, 0xdc, 0xdf,
+	0x34, 0x06, 0xf0, 0x59, 0x22, 0x78, 0x22, 0x66, 0xa9, 0x3f, 0xa4, 0x61, 0x7d, 0x43, 0x08, 0x51,
+	0x94, 0x72, 0xde, 0x89, 0x37, 0xf1, 0x58, 0xbd, 0x18, 0x3b, 0x4f, 0xd0, 0xa8, 0x0e, 0x1b, 0x47,
+	0x6e, 0x74, 0x11, 0xd1, 0x61, 0xbd, 0x24, 0x58, 0x9a, 0xe4, 0xd2, 0x84, 0xeb, 0xa2, 0x3a, 0xc8,
+	0x50, 0x90, 0x14, 0xfa, 0x00, 0x72, 0x27, 0xc1, 0x28, 0xaa, 0x97, 0x45, 0x24, 0x57, 0x8d, 0x26,
+	0x27, 0xc1, 0x88, 0x08, 0x16, 0x3e, 0x85, 0xbc, 0x20, 0xb9, 0x74, 0x6d, 0x97, 0x25, 0x0c, 0xd7,
+	0xa4, 0x94, 0x3e, 0xf5, 0x06, 0x51, 0xdd, 0xde, 0xcd, 0x72, 0x44, 0x24, 0xc5, 0xcf, 0x53, 0xdb,
+	0x65, 0xae, 0xc0, 0xb0, 0x42, 0xc4, 0x18, 0xff, 0x0e, 0x1e, 0x1e, 0x51, 0xc6, 0xdd, 0x43, 0x49,
+	0x10, 0xb0, 0xfb, 0x65, 0x87, 0x1a, 0x64, 0x7b, 0xb3, 0x89, 0x72, 0x16, 0x1f, 0xf2, 0x2d, 0x4f,
+	0x5c, 0x46, 0x23, 0x26, 0x84, 0x17, 0x89, 0xa2, 0xf0, 0x27, 0x50, 0x8a, 0x65, 0xeb, 0xcf, 0x2c,
+	0xf3, 0x19, 0x82, 0x1c, 0xe7, 0xa8, 0x33, 0x2f, 0xc6, 0xf8, 0x4f, 0x16, 0x6c, 0x6b, 0x95, 0xce,
+	0xc3, 0x20, 0x78, 0xf5, 0x7f, 0xd6, 0x29, 0x09, 0x5c, 0x2e, 0x0d, 0x1c, 0x82, 0x5c, 0x7f, 0x1c,
+	0x30, 0x11, 0x27, 0x15, 0x22, 0xc6, 0xf8, 0x6f, 0x16, 0x80, 0xd1, 0xe5, 0x7e, 0x36, 0xf0, 0x55,
+	0xc7, 0x74, 0xa1, 0x80, 0xe6, 0x43, 0xb4, 0x0d, 0xf9, 0x97, 0xee, 0x78, 0x46, 0xd5, 0x96, 0x92,
+	0xe0, 0xd1, 0xd3, 0xf7, 0xae, 0xc6, 0x9e, 0x3f, 0xe2, 0xc1, 0xc9, 0x7d, 0x15, 0xd3, 0x5c, 0xcd,
+	0x63, 0xba, 0x78, 0xe1, 0x46, 0xd7, 0x22, 0x30, 0x2b, 0x44, 0x93, 0x1c, 0x08, 0xf1, 0xb9, 0xe0,
+	0x6d, 0x08, 0x9e, 0x99, 0xc0, 0x97, 0xc2, 0xa3, 0xcd, 0xc1, 0x20, 0x98, 0xf9, 0xac, 0xf9, 0xce,
+	0x1e, 0x4d, 0xa0, 0x94, 0x4d, 0xa1, 0x84, 0x5f, 0x43, 0x45, 0x00, 0xa2, 0xb6, 0x58, 0x03, 0xc9,
+	0x36, 0xe4, 0x3b, 0x73, 0x2f, 0x92, 0x98, 0x14, 0x89, 0x24, 0xb8, 0xc4, 0x03, 0x77, 0xec, 0xfa,
+	0x03, 0x2a, 0x24, 0xe6, 0x88, 0x26, 0x39, 0x84, 0xe2, 0x00, 0x4a, 0x6c, 0xc4, 0x98, 0xcb, 0xe8,
+	0x05, 0x7e, 0x9c, 0x12, 0x25, 0x81, 0xbf, 0x54, 0xe1, 0x1a, 0x84, 0xee, 0x88, 0x7e, 0x0b, 0xc6,
+	0x69, 0xcf, 0xe5, 0x62, 0xcf, 0xe1, 0xcf, 0x94, 0xb9, 0x6a, 0xd3, 0xf5, 0xe6, 0x4a, 0xdf, 0xda,
+	0x09, 0xdf, 0xe2, 0xaf, 0x2c, 0xa8, 0xb6, 0xdc, 0xf1, 0xb8, 0xf9, 0xbf, 0x1c, 0x2a, 0x2e, 0x80,
+	0x86, 0x4a, 0x49, 0x45, 0xf1, 0xa8, 0xd1, 0x29, 0x4c, 0x29, 0x1a, 0xd3, 0xdc, 0x32, 0x95, 0xc3,
+	0x55, 0x14, 0x6b, 0x92, 0xcb, 0xe7, 0x77, 0x8f, 0xbc, 0x3e, 0xf8, 0x10, 0x9f, 0x03, 0x70, 0x89,
+	0x84, 0x46, 0xb3, 0xf1, 0x3a, 0x37, 0x9a, 0xcc, 0x6a, 0xa7, 0x32, 0x6b, 0x22, 0xaf, 0x65, 0x53,
+	0x79, 0x0d, 0xff, 0xc5, 0x02, 0xd4, 0x89, 0x98, 0x37, 0x71, 0x19, 0x3d, 0x72, 0xa3, 0x7b, 0xd7,
+	0x1a, 0xca, 0x4c, 0x7b, 0xd9, 0x4c, 0x42, 0x07, 0xd4, 0xbb, 0x8d, 0x01, 0x88, 0xe9, 0xa4, 0x99,
+	0xb9, 0xb4, 0x99, 0xb1, 0x33, 0x54, 0xdc, 0x48, 0x67, 0x0c, 0xa0, 0x7c, 0xe4, 0x46, 0x5a, 0xb5,
+	0x35, 0xb6, 0x2a, 0x74, 0xec, 0x18, 0x9d, 0xbb, 0xad, 0x4c, 0x64, 0xef, 0x5c, 0x32, 0x7b, 0xe3,
+	0xaf, 0x2d, 0xf8, 0x8e, 0x13, 0xba, 0x03, 0xea, 0x84, 0xae, 0x1f, 0xb9, 0x03, 0xe6, 0x05, 0xfe,
+	0xfd, 0x20, 0x40, 0x90, 0x73, 0xe6, 0xdd, 0xb6, 0xd8, 0xbe, 0x44, 0xc4, 0x18, 0x61, 0xa8, 0xb4,
+	0xbd, 0xc8, 0xbd, 0x1a, 0xd3, 0x3e, 0x73, 0x07, 0x37, 0x2a, 0x89, 0xa5, 0xe6, 0xd0, 0x0f, 0xa1,
+	0xaa, 0xe8, 0x53, 0x3a, 0x09, 0x42, 0x19, 0xb7, 0x45, 0x92, 0x9e, 0xe4, 0x40, 0x9e, 0xba, 0xf3,
+	0x3e, 0xa3, 0xd3, 0x48, 0x17, 0x18, 0x9a, 0x56, 0xbc, 0x83, 0x05, 0xa3, 0x3a, 0x34, 0x62, 0x1a,
+	0xff, 0xdd, 0x82, 0x0d, 0x67, 0x2e, 0x2c, 0x42, 0x7b, 0x50, 0x90, 0x77, 0xb8, 0xba, 0xe6, 0x6b,
+	0xe6, 0x6e, 0x92, 0xf3, 0x44, 0xf1, 0xd1, 0x87, 0x90, 0x97, 0x5b, 0xd9, 0xe2, 0x12, 0xdb, 0x8a,
+	0x17, 0x72, 0x39, 0x9c, 0x43, 0x24, 0x1f, 0xfd, 0x08, 0x72, 0xdc, 0xd3, 0xc2, 0xb0, 0xe5, 0x75,
+	0x9c, 0x41, 0x04, 0x1b, 0x7d, 0x0e, 0x9b, 0xea, 0xe8, 0x71, 0xbc, 0x46, 0x94, 0xa3, 0xce, 0x05,
+	0x3f, 0xd2, 0x1f, 0xa4, 0xb8, 0x64, 0x69, 0x31, 0xfe, 0xa7, 0x05, 0xa5, 0x78, 0x6b, 0x1e, 0x1d,
+	0x6d, 0x3a, 0x65, 0xd7, 0xca, 0xf5, 0x92, 0x10, 0x85, 0x67, 0x4b, 0xf9, 0xde, 0x3e, 0x6f, 0x71,
+	0xfa, 0x6c, 0x2a, 0xf4, 0x2a, 0x11, 0xfb, 0x6c, 0xaa, 0x83, 0x23, 0xb7, 0x1c, 0x1c, 0xad, 0x20,
+	0x62, 0x0a, 0x51, 0x4d, 0xf2, 0x1d, 0xa4, 0xbf, 0x0a, 0x22, 0x9f, 0x4b, 0x42, 0xd4, 0x7e, 0xd2,
+	0x43, 0x32, 0x5f, 0x2b, 0x0a, 0x3d, 0x83, 0x0d, 0xa5, 0xaf, 0xa8, 0x1e, 0xee, 0xb4, 0x4a, 0xaf,
+	0xc2, 0xff, 0xd6, 0xe6, 0x08, 0x6c, 0xa4, 0xa2, 0x56, 0xac, 0x28, 0x82, 0xdc, 0x61, 0x18, 0x4c,
+	0xf4, 0x5d, 0xc4, 0xc7, 0x7c, 0x8d, 0x13, 0xa8, 0x03, 0x64, 0x3b, 0x01, 0x57, 0xb0, 0xeb, 0x9b,
+	0x72, 0x49, 0x12, 0x89, 0xb3, 0x9e, 0x4f, 0x9d, 0xf5, 0xf8, 0x38, 0x15, 0x12, 0xc7, 0x49, 0x03,
+	0xb2, 0xb1, 0xf6, 0xb4, 0x14, 0xd3, 0xa7, 0x45, 0xd5, 0x7c, 0x25, 0x53, 0xf3, 0x7d, 0x08, 0x79,
+	0xae, 0x3d, 0x2f, 0x7e, 0xb2, 0xeb, 0x3d, 0x2f, 0xf9, 0xd8, 0x83, 0x6a, 0x0a, 0x86, 0x37, 0xd4,
+	0x3c, 0x2a, 0x6f, 0xdb, 0xe6, 0xc6, 0xdd, 0x81, 0xc2, 0x01, 0x7d, 0x15, 0x84, 0x54, 0x67, 0x4f,
+	0x49, 0x71, 0x8b, 0x9a, 0xaf, 0x18, 0x0d, 0xb5, 0xfd, 0x82, 0xc0, 0x0c, 0xd0, 0x11, 0x65, 0x71,
+	0x30, 0xbf, 0xf3, 0xa9, 0xfd, 0x66, 0x0f, 0xa4, 0x8f, 0x61, 0x9b, 0x3f, 0x90, 0x9c, 0xf9, 0x0b,
+	0x2f, 0x62, 0x41, 0xb8, 0xd0, 0xfb, 0xde, 0x69, 0x27, 0xfe, 0x23, 0xf7, 0xbf, 0x5e, 0x8e, 0x3e,
+	0x82, 0xac, 0x33, 0xd7, 0x0f, 0x9f, 0x86, 0x39, 0x92, 0x8a, 0xff, 0xd4, 0x99, 0x47, 0x1d, 0x9f,
+	0x85, 0x0b, 0xc2, 0x97, 0x35, 0x7e, 0x05, 0x45, 0x3d, 0xc1, 0xf1, 0xba, 0xa1, 0x0b, 0x5d, 0x89,
+	0xdf, 0xd0, 0x05, 0xda, 0x83, 0xfc, 0x6d, 0x7c, 0x8b, 0x95, 0xf7, 0x91, 0x09, 0xc4, 0xd0, 0xf3,
+	0x47, 0x5c, 0x4d, 0x22, 0x17, 0xfc, 0xcc, 0x7e, 0x6e, 0xe1, 0x63, 0x78, 0x64, 0xaa, 0x0c, 0xf1,
+	0xc4, 0x7a, 0x9b, 0xea, 0x82, 0xc3, 0x1f, 0x2d, 0x31, 0x62, 0x9a, 0xe4, 0x57, 0x65, 0x39, 0x21,
+	0x2a, 0x59, 0x29, 0x58, 0xe9, 0x4a, 0xe1, 0x4e, 0x19, 0x3c, 0x91, 0xb5, 0xe9, 0xc0, 0x9b, 0xb8,
+	0x63, 0x79, 0xa7, 0x57, 0x49, 0x4c, 0x73, 0x63, 0x5b, 0xee, 0x54, 0x9f, 0xdf, 0x96, 0x3b, 0x15,
+	0xe5, 0xfc, 0x6c, 0x3a, 0x1d, 0x2f, 0xd4, 0xf1, 0x55, 0x14, 0x9f, 0x3f, 0x0c, 0x83, 0xd7, 0xd4,
+	0x17, 0xf1, 0x5e, 0x24, 0x8a, 0xc2, 0x3f, 0x81, 0xf7, 0x8f, 0x28, 0x6b, 0x05, 0x93, 0xe9, 0xd8,
+	0xe3, 0x8a, 0xdc, 0xdb, 0x5f, 0x7f, 0xb5, 0xa0, 0x66, 0x3e, 0x23, 0x74, 0x10, 0x84, 0xc3, 0x38,
+	0x70, 0xac, 0x44, 0xe0, 0xec, 0x40, 0xa1, 0x29, 0x6e, 0x0c, 0x65, 0x98, 0xa2, 0x92, 0x16, 0x67,
+	0xd3, 0x16, 0xa7, 0x4a, 0xca, 0xf8, 0x68, 0xee, 0x40, 0x81, 0x50, 0x37, 0x0a, 0x7c, 0xf5, 0xda,
+	0x51, 0xd4, 0xf2, 0x43, 0xab, 0xb0, 0xf2, 0xd0, 0xc2, 0x47, 0xb0, 0xb5, 0x62, 0x20, 0xda, 0x87,
+	0x0d, 0xa9, 0xb4, 0x8e, 0xb2, 0x7a, 0xfc, 0xbc, 0x5e, 0xb2, 0x8a, 0xe8, 0x85, 0xb8, 0x07, 0x75,
+	0x0e, 0x96, 0x3c, 0x27, 0x07, 0xde, 0x98, 0xd7, 0xb3, 0xf7, 0x3b, 0x51, 0xdb, 0x90, 0x6f, 0xf1,
+	0x28, 0x10, 0x18, 0x54, 0x89, 0x24, 0xf0, 0x9f, 0x2d, 0xa8, 0xc6, 0x62, 0x04, 0x80, 0x4b, 0xc6,
+	0x58, 0xab, 0xaf, 0x46, 0x5e, 0xca, 0x9b, 0x37, 0xbb, 0x18, 0xf3, 0x30, 0x38, 0xa4, 0xba, 0xf8,
+	0xe4, 0x43, 0xbe, 0xea, 0xdc, 0xf5, 0x86, 0x0a, 0x41, 0x31, 0xe6, 0xab, 0xda, 0x71, 0xf9, 0xc0,
+	0x87, 0xc2, 0x5d, 0xde, 0x44, 0xa6, 0xc0, 0x2c, 0x11, 0x63, 0xfc, 0x0f, 0x0b, 0x36, 0xd3, 0x16,
+	0xbe, 0xc5, 0xb4, 0x44, 0x4c, 0xdb, 0xe9, 0x98, 0x56, 0x1b, 0x66, 0xcd, 0x86, 0x3b, 0x50, 0x78,
+	0xe1, 0x8e, 0x19, 0x1d, 0xaa, 0xfb, 0x5c, 0x51, 0xf1, 0x53, 0x38, 0xd9, 0x2b, 0x48, 0xcc, 0xf0,
+	0xdb, 0x44, 0x3b, 0xab, 0x90, 0xbe, 0x23, 0x53, 0xf0, 0x19, 0x4f, 0x7d, 0x0a, 0x0f, 0xbf, 0x70,
+	0x79, 0x67, 0xe8, 0x1b, 0x38, 0x09, 0xbf, 0x84, 0x8a, 0x5a, 0xdf, 0xb9, 0xa5, 0xfe, 0x3d, 0xaa,
+	0x3b, 0x65, 0x8b, 0x9d, 0xb2, 0x65, 0xc5, 0x6a, 0x3c, 0x12, 0xb5, 0xbd, 0xa8, 0xa5, 0xee, 0x97,
+	0x50, 0x52, 0x1b, 0xcb, 0xcc, 0x9f, 0x06, 0x7c, 0xfd, 0xc1, 0xc1, 0x5d, 0x28, 0xc5, 0xbb, 0xbc,
+	0x21, 0xd7, 0x60, 0xa8, 0x88, 0x2f, 0xd2, 0x6e, 0x4b, 0xcd, 0xe1, 0x07, 0x50, 0x3d, 0x70, 0x07,
+	0x37, 0xb3, 0xa9, 0xd2, 0x16, 0x7f, 0x00, 0x65, 0x39, 0xd1, 0xba, 0x9e, 0xf9, 0x37, 0xf1, 0x93,
+	0xdb, 0x4a, 0x3c, 0xb9, 0x7f, 0x0f, 0xdb, 0xa2, 0x1d, 0xd7, 0xf7, 0xdd, 0x69, 0x74, 0x6d, 0xde,
+	0xdc, 0xb2, 0xfd, 0x65, 0xad, 0xb4, 0xbf, 0x6c, 0xd3, 0xfe, 0x8a, 0x43, 0x31, 0x6b, 0x42, 0x91,
+	0x23, 0xd9, 0xf7, 0x46, 0xfa, 0xc9, 0xd2, 0xf7, 0x46, 0x4f, 0x7e, 0x6a, 0x2e, 0x21, 0xf4, 0x08,
+	0xb6, 0x0e, 0x9b, 0xdd, 0x93, 0xcb, 0xee, 0xe1, 0x65, 0xef, 0xcc, 0xb9, 0x24, 0x9d, 0x66, 0xfb,
+	0xb7, 0xb5, 0x0c, 0xda, 0x01, 0x44, 0x3a, 0xce, 0x05, 0xe9, 0x5d, 0x5e, 0xf4, 0x9c, 0xee, 0x89,
+	0x9a, 0xb7, 0x9e, 0x3c, 0x33, 0x2d, 0x2d, 0x04, 0x50, 0x38, 0xed, 0x9c, 0x1e, 0x74, 0x48, 0x2d,
+	0x83, 0x4a, 0x90, 0x6f, 0xb6, 0x4f, 0xbb, 0xbd, 0x9a, 0x85, 0x2a, 0x50, 0x3c, 0xbb, 0x70, 0xfa,
+	0xdd, 0x76, 0x87, 0xd4, 0xec, 0x27, 0x3f, 0x87, 0x82, 0x6c, 0x94, 0xa0, 0x32, 0x6c, 0x5c, 0xf4,
+	0x8e, 0x7b, 0x67, 0x5f, 0xf4, 0x6a, 0x19, 0x4e, 0xf4, 0x2f, 0x5a, 0xad, 0x4e, 0xbf, 0x5f, 0xb3,
+	0xb8, 0x20, 0xae, 0x43, 0xa7, 0x5d, 0xb3, 0xf9, 0xd7, 0xa4, 0xf3, 0xb2, 0x43, 0x9c, 0x4e, 0xbb,
+	0x96, 0xdd, 0xff, 0x4f, 0x0e, 0x36, 0xce, 0xc2, 0x21, 0x0d, 0x69, 0x88, 0x9e, 0x03, 0x98, 0x36,
+	0x25, 0x7a, 0x4f, 0x87, 0xee, 0x4a, 0xeb, 0xb2, 0x11, 0xf7, 0x45, 0xc4, 0x2c, 0xce, 0xa0, 0x16,
+	0x54, 0x92, 0x7d, 0x46, 0xf4, 0xbe, 0x5e, 0xb0, 0xa6, 0xfb, 0xd8, 0xd8, 0x5e, 0xd3, 0x1f, 0x8c,
+	0x70, 0x06, 0xb5, 0xa1, 0x9a, 0x6a, 0x86, 0xa1, 0xc7, 0xf1, 0xc2, 0x35, 0x7d, 0xb5, 0xc6, 0xba,
+	0x36, 0x23, 0xce, 0xa0, 0x4f, 0x20, 0x2f, 0x5a, 0x63, 0x28, 0xde, 0x26, 0xd9, 0x29, 0x6b, 0xac,
+	0x94, 0xcd, 0x38, 0x83, 0x0e, 0x61, 0x33, 0x7d, 0x95, 0xa2, 0xef, 0xea, 0x55, 0x6b, 0xaf, 0x58,
+	0xb3, 0x75, 0x82, 0x87, 0x33, 0xe8, 0x37, 0xa2, 0x6f, 0xb2, 0x9a, 0xc2, 0x7f, 0x90, 0x90, 0x76,
+	0xd7, 0x0d, 0xd6, 0x78, 0x6f, 0x35, 0xad, 0xab, 0x15, 0x38, 0x83, 0xce, 0x60, 0x6b, 0x25, 0xa1,
+	0xa3, 0xdd, 0xa4, 0xd8, 0x75, 0xb9, 0xbe, 0xb1, 0xb3, 0x04, 0x91, 0x62, 0xe3, 0x0c, 0xea, 0x40,
+	0x25, 0x99, 0x77, 0x8c, 0xc3, 0xd6, 0x64, 0x23, 0xe3, 0xb0, 0x64, 0xd6, 0xc1, 0x99, 0x8f, 0x2d,
+	0xf4, 0x1c, 0x0a, 0xf2, 0xb0, 0x21, 0x93, 0xe8, 0x92, 0xa7, 0xb1, 0xf1, 0x30, 0x3d, 0x2d, 0xce,
+	0x24, 0xff, 0x72, 0xff, 0xab, 0x02, 0xe4, 0xce, 0x29, 0x0d, 0xd1, 0xe7, 0x50, 0x4e, 0xd4, 0x7d,
+	0xa8, 0x91, 0x30, 0x6a, 0xa9, 0x18, 0x5c, 0xeb, 0xbb, 0x03, 0xa8, 0xa6, 0x0a, 0x38, 0x13, 0x34,
+	0xeb, 0xea, 0xba, 0xc6, 0xd6, 0x4a, 0x89, 0x86, 0x33, 0xe8, 0x97, 0x50, 0x49, 0xe6, 0x3d, 0x03,
+	0xc6, 0x9a, 0x6c, 0x98, 0x90, 0xa0, 0x39, 0x38, 0xf3, 0xee, 0x38, 0xa0, 0x43, 0xa8, 0xa6, 0x72,
+	0x91, 0xd1, 0x7f, 0x5d, 0x8a, 0xba, 0x5b, 0x8e, 0xb4, 0xc1, 0xb4, 0xfa, 0x92, 0x36, 0x2c, 0x37,
+	0x17, 0x8d, 0x0d, 0x31, 0x47, 0x9c, 0xe1, 0x6a, 0xaa, 0xeb, 0x67, 0x34, 0x59, 0xd7, 0x0c, 0x6c,
+	0xa0, 0x94, 0x0c, 0xc1, 0x92, 0x89, 0x20, 0xd9, 0xfb, 0x4a, 0xa9, 0xb1, 0xdc, 0x11, 0x6b, 0x6c,
+	0xa7, 0x44, 0x28, 0x76, 0x2c, 0x24, 0xee, 0x31, 0x2d, 0xd9, 0x92, 0xee, 0x3c, 0x2d, 0x09, 0x51,
+	0x6c, 0x9c, 0x41, 0x3f, 0x96, 0x4d, 0x8d, 0x26, 0x33, 0x2e, 0x49, 0x35, 0x83, 0x1a, 0x28, 0x39,
+	0x2d, 0x5b, 0x30, 0x22, 0x16, 0xca, 0x89, 0xfe, 0x89, 0x09, 0xc7, 0xd5, 0xa6, 0x8a, 0xf1, 0x45,
+	0xa2, 0xb1, 0x21, 0xb2, 0x49, 0x6d, 0xb9, 0x07, 0x81, 0xbe, 0x9f, 0x7a, 0x61, 0xad, 0x76, 0x27,
+	0x1a, 0x0f, 0x4c, 0x5c, 0x8a, 0x25, 0x38, 0x73, 0x25, 0xff, 0x61, 0xfa, 0xf4, 0xbf, 0x03, 0x00,
+	0xb5, 0xd3, 0xea, 0xb1, 0x79, 0x1a, 0x00, 0x00,
 }
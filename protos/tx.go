@@ -17,6 +17,9 @@ import (
 
 // ToCore convert pb.Tx to core.Tx
 func (tx *Tx) ToCore() (*core.Tx, error) {
+	if len(tx.Raw) != 0 {
+		return core.BytesToTx(tx.Raw)
+	}
 	t := &core.Tx{
 		ID:   tx.ID,
 		Time: tx.Time,
@@ -32,10 +35,12 @@ func NewTx(tx *core.Tx) (*Tx, error) {
 	if tx == nil {
 		return nil, nil
 	}
+	raw, err := tx.Bytes()
+	if err != nil {
+		return nil, err
+	}
 	return &Tx{
-		ID:   tx.ID,
-		Data: NewTxData(&(tx.Data)),
-		Time: tx.Time,
+		Raw: raw,
 	}, nil
 }
 
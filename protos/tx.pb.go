@@ -20,9 +20,12 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 // Tx is the transaction, which structure is not decided yet
 type Tx struct {
-	ID                   string   `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	Data                 *TxData  `protobuf:"bytes,2,opt,name=Data,proto3" json:"Data,omitempty"`
-	Time                 int64    `protobuf:"varint,3,opt,name=Time,proto3" json:"Time,omitempty"`
+	ID   string  `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Data *TxData `protobuf:"bytes,2,opt,name=Data,proto3" json:"Data,omitempty"`
+	Time int64   `protobuf:"varint,3,opt,name=Time,proto3" json:"Time,omitempty"`
+	// Raw is the binary encoding of core.Tx, the fields above are only set
+	// by the clients which do not support it
+	Raw                  []byte   `protobuf:"bytes,4,opt,name=Raw,proto3" json:"Raw,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -32,7 +35,7 @@ func (m *Tx) Reset()         { *m = Tx{} }
 func (m *Tx) String() string { return proto.CompactTextString(m) }
 func (*Tx) ProtoMessage()    {}
 func (*Tx) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tx_5927ea3b06b4a99d, []int{0}
+	return fileDescriptor_tx_7328398de80d22b0, []int{0}
 }
 func (m *Tx) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Tx.Unmarshal(m, b)
@@ -73,6 +76,13 @@ func (m *Tx) GetTime() int64 {
 	return 0
 }
 
+func (m *Tx) GetRaw() []byte {
+	if m != nil {
+		return m.Raw
+	}
+	return nil
+}
+
 // txData is the data of Tx
 type TxData struct {
 	ChannelID            string   `protobuf:"bytes,1,opt,name=ChannelID,proto3" json:"ChannelID,omitempty"`
@@ -93,7 +103,7 @@ func (m *TxData) Reset()         { *m = TxData{} }
 func (m *TxData) String() string { return proto.CompactTextString(m) }
 func (*TxData) ProtoMessage()    {}
 func (*TxData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tx_5927ea3b06b4a99d, []int{1}
+	return fileDescriptor_tx_7328398de80d22b0, []int{1}
 }
 func (m *TxData) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxData.Unmarshal(m, b)
@@ -192,7 +202,7 @@ func (m *TxSig) Reset()         { *m = TxSig{} }
 func (m *TxSig) String() string { return proto.CompactTextString(m) }
 func (*TxSig) ProtoMessage()    {}
 func (*TxSig) Descriptor() ([]byte, []int) {
-	return fileDescriptor_tx_5927ea3b06b4a99d, []int{2}
+	return fileDescriptor_tx_7328398de80d22b0, []int{2}
 }
 func (m *TxSig) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_TxSig.Unmarshal(m, b)
@@ -239,26 +249,26 @@ func init() {
 	proto.RegisterType((*TxSig)(nil), "protos.txSig")
 }
 
-func init() { proto.RegisterFile("tx.proto", fileDescriptor_tx_5927ea3b06b4a99d) }
-
-var fileDescriptor_tx_5927ea3b06b4a99d = []byte{
-	// 275 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x44, 0x51, 0x4d, 0x4b, 0xc3, 0x40,
-	0x10, 0x65, 0x37, 0x1f, 0x6d, 0xc6, 0x5a, 0x64, 0xf0, 0xb0, 0x07, 0xc1, 0x90, 0x53, 0x4e, 0x3d,
-	0xe8, 0xd9, 0x83, 0x18, 0x90, 0x52, 0x95, 0xb0, 0x2d, 0xbd, 0xaf, 0x75, 0x89, 0x0b, 0x31, 0x5b,
-	0x9a, 0x08, 0xf1, 0x27, 0xfb, 0x2f, 0x64, 0x66, 0xa9, 0x39, 0xe5, 0x7d, 0xcc, 0xbc, 0xe1, 0x65,
-	0x61, 0x3e, 0x8c, 0xab, 0xe3, 0xc9, 0x0f, 0x1e, 0x53, 0xfe, 0xf4, 0xc5, 0x0b, 0xc8, 0xdd, 0x88,
-	0x4b, 0x90, 0xeb, 0x4a, 0x89, 0x5c, 0x94, 0x99, 0x96, 0xeb, 0x0a, 0x0b, 0x88, 0x2b, 0x33, 0x18,
-	0x25, 0x73, 0x51, 0x5e, 0xdc, 0x2d, 0xc3, 0x4e, 0xbf, 0x1a, 0x46, 0x52, 0x35, 0x7b, 0x88, 0x10,
-	0xef, 0xdc, 0x97, 0x55, 0x51, 0x2e, 0xca, 0x48, 0x33, 0x2e, 0x7e, 0x05, 0xa4, 0x61, 0x08, 0x6f,
-	0x20, 0x7b, 0xfa, 0x34, 0x5d, 0x67, 0xdb, 0xff, 0xe4, 0x49, 0xc0, 0x6b, 0x48, 0xde, 0x7c, 0x77,
-	0xb0, 0x7c, 0x21, 0xd6, 0x81, 0xd0, 0x8e, 0xb6, 0x07, 0x77, 0x74, 0xb6, 0x1b, 0x38, 0x77, 0xa1,
-	0x27, 0x01, 0x15, 0xcc, 0x6a, 0xf3, 0xd3, 0x7a, 0xf3, 0xa1, 0x62, 0xf6, 0xce, 0x94, 0xd2, 0xf6,
-	0xa6, 0xfd, 0xb6, 0x2a, 0x09, 0x69, 0x4c, 0xf0, 0x0a, 0xa2, 0xd7, 0xbe, 0x51, 0x29, 0xdf, 0x26,
-	0x48, 0x09, 0x7b, 0x7b, 0xea, 0x9d, 0xef, 0xd4, 0x2c, 0x17, 0x65, 0xa2, 0xcf, 0x14, 0x6f, 0x21,
-	0xda, 0xba, 0x46, 0xcd, 0xb9, 0xef, 0xe5, 0xd4, 0x77, 0xeb, 0x1a, 0x4d, 0x0e, 0x85, 0x3d, 0x9b,
-	0x5e, 0x65, 0x7c, 0x80, 0x60, 0xf1, 0x00, 0x09, 0xfb, 0xf4, 0xf3, 0xea, 0x0d, 0x57, 0x5c, 0x68,
-	0x59, 0x6f, 0x68, 0x94, 0xb2, 0x24, 0x0b, 0xbc, 0x8c, 0x10, 0x3f, 0xb6, 0x8d, 0xe7, 0x4a, 0x89,
-	0x66, 0xfc, 0x1e, 0x1e, 0xe0, 0xfe, 0x2f, 0x00, 0x00, 0xff, 0xff, 0x86, 0x81, 0x31, 0xe9, 0x93,
-	0x01, 0x00, 0x00,
+func init() { proto.RegisterFile("tx.proto", fileDescriptor_tx_7328398de80d22b0) }
+
+var fileDescriptor_tx_7328398de80d22b0 = []byte{
+	// 287 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x44, 0x51, 0x4f, 0x4b, 0xfb, 0x40,
+	0x10, 0x65, 0x37, 0x49, 0xff, 0xcc, 0xaf, 0xbf, 0x52, 0x06, 0x0f, 0x7b, 0x10, 0x0c, 0x39, 0xe5,
+	0xd4, 0x83, 0x9e, 0x3d, 0x88, 0x05, 0x29, 0x45, 0x29, 0xd3, 0xd2, 0xa3, 0xb0, 0xd6, 0x25, 0x2e,
+	0xc4, 0x6c, 0x69, 0x22, 0xd6, 0x8f, 0xec, 0xb7, 0x90, 0x99, 0xb5, 0xe6, 0xb4, 0xef, 0xcd, 0xec,
+	0xbc, 0xb7, 0xf3, 0x16, 0x46, 0xdd, 0x69, 0x7e, 0x38, 0x86, 0x2e, 0xe0, 0x40, 0x8e, 0xb6, 0x78,
+	0x06, 0xbd, 0x3d, 0xe1, 0x14, 0xf4, 0x72, 0x61, 0x54, 0xae, 0xca, 0x31, 0xe9, 0xe5, 0x02, 0x0b,
+	0x48, 0x17, 0xb6, 0xb3, 0x46, 0xe7, 0xaa, 0xfc, 0x77, 0x3d, 0x8d, 0x33, 0xed, 0xbc, 0x3b, 0x71,
+	0x95, 0xa4, 0x87, 0x08, 0xe9, 0xd6, 0xbf, 0x3b, 0x93, 0xe4, 0xaa, 0x4c, 0x48, 0x30, 0xce, 0x20,
+	0x21, 0xfb, 0x69, 0xd2, 0x5c, 0x95, 0x13, 0x62, 0x58, 0x7c, 0x2b, 0x18, 0xc4, 0x31, 0xbc, 0x84,
+	0xf1, 0xfd, 0x9b, 0x6d, 0x1a, 0x57, 0xff, 0x79, 0xf5, 0x05, 0xbc, 0x80, 0xec, 0x29, 0x34, 0x7b,
+	0x27, 0x9e, 0x29, 0x45, 0xc2, 0x33, 0xe4, 0xf6, 0xfe, 0xe0, 0x5d, 0xd3, 0x89, 0xd3, 0x84, 0xfa,
+	0x02, 0x1a, 0x18, 0xae, 0xed, 0x57, 0x1d, 0xec, 0xeb, 0xaf, 0xe5, 0x99, 0xb2, 0xda, 0xce, 0xd6,
+	0x1f, 0xce, 0x64, 0x51, 0x4d, 0x08, 0x3f, 0xef, 0xb1, 0xad, 0xcc, 0x40, 0xbc, 0x19, 0xb2, 0xc2,
+	0xce, 0x1d, 0x5b, 0x1f, 0x1a, 0x33, 0xcc, 0x55, 0x99, 0xd1, 0x99, 0xe2, 0x15, 0x24, 0x1b, 0x5f,
+	0x99, 0x91, 0x24, 0xf0, 0xbf, 0x4f, 0x60, 0xe3, 0x2b, 0xe2, 0x0e, 0x8b, 0x3d, 0xd8, 0xd6, 0x8c,
+	0xc5, 0x80, 0x61, 0x71, 0x0b, 0x99, 0xf4, 0x39, 0xce, 0xf5, 0x4a, 0x56, 0x9c, 0x90, 0x5e, 0xaf,
+	0x70, 0x16, 0xb5, 0x74, 0x8c, 0x85, 0x6f, 0x20, 0xa4, 0x77, 0x75, 0x15, 0x64, 0xa5, 0x8c, 0x04,
+	0xbf, 0xc4, 0x2f, 0xb9, 0xf9, 0x19, 0x00, 0x90, 0x1a, 0x83, 0x7e, 0xa5, 0x01, 0x00, 0x00,
 }
@@ -11,14 +11,29 @@
 package tests
 
 import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"madledger/blockchain"
+	"madledger/client/lib"
 	"madledger/common"
+	"madledger/common/abi"
+	"madledger/common/backup"
 	"madledger/common/util"
 	"madledger/core"
+	"madledger/executor/evm"
+	orderer "madledger/orderer/server"
 	pc "madledger/peer/config"
+	peer "madledger/peer/server"
+	pb "madledger/protos"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	eabi "github.com/thu-arxan/evm/abi"
 )
 
 /*
@@ -83,10 +98,371 @@ func TestAllSoloAsset(t *testing.T) {
 	testAsset(t, client)
 }
 
+func TestAllSoloStateRoot(t *testing.T) {
+	client, err := getSoloClient()
+	require.NoError(t, err)
+	for _, channelID := range []string{"public", "private"} {
+		root, err := client.GetStateRoot(channelID)
+		require.NoError(t, err)
+		proof, err := client.GetStateProof(channelID, contractAddress[channelID], nil)
+		require.NoError(t, err)
+		require.Equal(t, root.Root, proof.Root)
+		require.NotEmpty(t, proof.Value)
+		// an address which is never used
+		proof, err = client.GetStateProof(channelID, common.HexToAddress("0x1234"), nil)
+		require.NoError(t, err)
+		require.Empty(t, proof.Value)
+	}
+	// the channels have different states
+	public, err := client.GetStateRoot("public")
+	require.NoError(t, err)
+	private, err := client.GetStateRoot("private")
+	require.NoError(t, err)
+	require.NotEqual(t, public.Root, private.Root)
+}
+
+func TestAllSoloHistory(t *testing.T) {
+	client, err := getSoloClient()
+	require.NoError(t, err)
+	payload, err := abi.Pack(BalanceAbi, "get")
+	require.NoError(t, err)
+	for _, channelID := range []string{"public", "private"} {
+		root, err := client.GetStateRoot(channelID)
+		require.NoError(t, err)
+		// the values of contract at each height, the repeated ones are skipped
+		var values []string
+		for num := uint64(0); num <= root.Num; num++ {
+			result, err := client.CallAt(channelID, num, contractAddress[channelID], payload)
+			if err != nil {
+				// the contract is not created yet
+				require.Empty(t, values)
+				continue
+			}
+			outputs, err := abi.Unpack(BalanceAbi, "get", result.Output)
+			require.NoError(t, err)
+			require.Len(t, outputs, 1)
+			if len(values) == 0 || values[len(values)-1] != outputs[0] {
+				values = append(values, outputs[0])
+			}
+		}
+		require.Equal(t, []string{"10", "1314", "520", "1314"}, values)
+
+		account, err := client.GetAccountAt(channelID, contractAddress[channelID], 0)
+		require.NoError(t, err)
+		require.False(t, account.Exist)
+		account, err = client.GetAccountAt(channelID, contractAddress[channelID], root.Num)
+		require.NoError(t, err)
+		require.True(t, account.Exist)
+		require.NotEmpty(t, account.Code)
+	}
+}
+
+func TestAllSoloReindex(t *testing.T) {
+	// the nodes are idle now, so the copies of their data are consistent
+	ordererCfg, err := getSoloOrdererConfig()
+	require.NoError(t, err)
+	peerCfg := getSoloPeerConfig()
+	var dirs = map[string]string{
+		ordererCfg.BlockChain.Path: ".reindex/orderer/blocks",
+		ordererCfg.DB.LevelDB.Path: ".reindex/orderer/leveldb",
+		peerCfg.BlockChain.Path:    ".reindex/peer/blocks",
+		peerCfg.DB.LevelDB.Dir:     ".reindex/peer/leveldb",
+	}
+	for src, dst := range dirs {
+		require.NoError(t, backup.CopyDir(src, dst))
+	}
+	ordererCfg.BlockChain.Path = dirs[ordererCfg.BlockChain.Path]
+	ordererCfg.DB.LevelDB.Path = dirs[ordererCfg.DB.LevelDB.Path]
+	peerCfg.BlockChain.Path = dirs[peerCfg.BlockChain.Path]
+	peerCfg.DB.LevelDB.Dir = dirs[peerCfg.DB.LevelDB.Dir]
+
+	for _, node := range []struct {
+		dir     string
+		reindex func() error
+	}{
+		{ordererCfg.DB.LevelDB.Path, func() error { return orderer.Reindex(ordererCfg, nil) }},
+		{peerCfg.DB.LevelDB.Dir, func() error { return peer.Reindex(peerCfg, nil) }},
+	} {
+		before, err := dumpDB(node.dir)
+		require.NoError(t, err)
+		// the db is lost
+		require.NoError(t, os.RemoveAll(node.dir))
+		require.NoError(t, node.reindex())
+		after, err := dumpDB(node.dir)
+		require.NoError(t, err)
+		require.Equal(t, before, after)
+	}
+}
+
+func TestAllSoloSnapshot(t *testing.T) {
+	source := getSoloPeerConfig()
+	require.NoError(t, backup.CopyDir(source.BlockChain.Path, ".snapshot/source/blocks"))
+	require.NoError(t, backup.CopyDir(source.DB.LevelDB.Dir, ".snapshot/source/leveldb"))
+	source.BlockChain.Path = ".snapshot/source/blocks"
+	source.DB.LevelDB.Dir = ".snapshot/source/leveldb"
+	records, err := dumpDB(source.DB.LevelDB.Dir)
+	require.NoError(t, err)
+	for key := range records {
+		if strings.HasPrefix(key, "bc_data_") {
+			delete(records, key)
+		}
+	}
+	cm, err := peer.NewChannelManager(source)
+	require.NoError(t, err)
+	file, manifest, err := cm.Snapshot(".snapshot/files")
+	require.NoError(t, err)
+	require.NotZero(t, manifest.Channels[core.GLOBALCHANNELID].Height)
+	require.NotNil(t, manifest.Channels["test"])
+
+	target := getSoloPeerConfig()
+	target.BlockChain.Path = ".snapshot/target/blocks"
+	target.DB.LevelDB.Dir = ".snapshot/target/leveldb"
+	restored, err := peer.Bootstrap(target, file)
+	require.NoError(t, err)
+	require.Equal(t, manifest.Hash, restored.Hash)
+	// a peer which is not new could not bootstrap
+	_, err = peer.Bootstrap(target, file)
+	require.Error(t, err)
+
+	// the state is the same except the blocks which are never stored
+	bootstrapped, err := dumpDB(target.DB.LevelDB.Dir)
+	require.NoError(t, err)
+	require.Equal(t, records, bootstrapped)
+	for id, ch := range manifest.Channels {
+		manager, err := blockchain.NewManager(id, filepath.Join(target.BlockChain.Path, id), "")
+		require.NoError(t, err)
+		require.Equal(t, ch.Height, manager.GetExpect())
+		require.Equal(t, ch.Height, manager.GetBase())
+		manager.Close()
+	}
+}
+
+func TestAllSoloCatchUp(t *testing.T) {
+	// a new peer fetches all blocks ahead in a small window
+	cfg := getSoloPeerConfig()
+	cfg.Port += 1000
+	cfg.BlockChain.Path = ".catchup/blocks"
+	cfg.DB.LevelDB.Dir = ".catchup/leveldb"
+	cfg.Orderer.Window = 3
+	server, err := peer.NewServer(cfg)
+	require.NoError(t, err)
+	go server.Start()
+	defer os.RemoveAll(".catchup")
+	var stopped bool
+	defer func() {
+		if !stopped {
+			server.Stop()
+		}
+	}()
+	conn, err := peer.Dial(cfg, fmt.Sprintf("%s:%d", cfg.Address, cfg.Port))
+	require.NoError(t, err)
+	defer conn.Close()
+	client, err := getSoloClient()
+	require.NoError(t, err)
+
+	for _, channelID := range []string{"public", "private", "test"} {
+		expect, err := client.GetStateRoot(channelID)
+		require.NoError(t, err)
+		var root *pb.StateRoot
+		for i := 0; i < 100; i++ {
+			root, err = pb.NewPeerClient(conn).GetStateRoot(context.Background(), &pb.GetStateRootRequest{
+				ChannelID: channelID,
+				Num:       expect.Num,
+			})
+			if err == nil {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		require.NoError(t, err)
+		// roots may differ because channels share accounts and the new peer
+		// interleaves them in another order, so only the chain is compared
+		require.Equal(t, expect.Num, root.Num)
+	}
+	server.Stop()
+	stopped = true
+	reports, err := blockchain.VerifyChannels(cfg.BlockChain.Path, "public", "private", "test")
+	require.NoError(t, err)
+	for _, report := range reports {
+		require.Nil(t, report.Corruption, report.String())
+	}
+}
+
+func TestAllSoloReceipt(t *testing.T) {
+	client, err := getSoloClient()
+	require.NoError(t, err)
+	sender, err := client.GetPrivKey().PubKey().Address()
+	require.NoError(t, err)
+	// the contract stores 42 in memory and logs it with topic 1
+	codes, err := hex.DecodeString("602a600052600160206000a100")
+	require.NoError(t, err)
+	tx, err := core.NewTx("public", common.ZeroAddress, codes, 0, "", client.GetPrivKey())
+	require.NoError(t, err)
+	status, err := client.AddTx(tx)
+	require.NoError(t, err)
+	require.Equal(t, pb.TxCode_SUCCESS, status.Code)
+	require.Equal(t, sender.String(), status.Sender)
+	require.NotZero(t, status.GasUsed)
+	require.True(t, status.GasUsed <= status.GasLimit)
+	require.Len(t, status.Logs, 1)
+	require.Equal(t, status.ContractAddress, common.BytesToAddress(status.Logs[0].Address).String())
+	var topic, data = make([]byte, 32), make([]byte, 32)
+	topic[31], data[31] = 1, 42
+	require.Equal(t, [][]byte{topic}, status.Logs[0].Topics)
+	require.Equal(t, data, status.Logs[0].Data)
+
+	// calling an account without code is rejected
+	tx, err = core.NewTx("public", common.HexToAddress("0x1234"), []byte("call"), 0, "", client.GetPrivKey())
+	require.NoError(t, err)
+	status, err = client.AddTx(tx)
+	require.NoError(t, err)
+	require.Equal(t, pb.TxCode_FAILED, status.Code)
+	require.Equal(t, "Invalid Address", status.Err)
+	require.Zero(t, status.GasUsed)
+	require.Empty(t, status.Logs)
+}
+
+func TestAllSoloEstimateGas(t *testing.T) {
+	client, err := getSoloClient()
+	require.NoError(t, err)
+	codes, err := hex.DecodeString("602a600052600160206000a100")
+	require.NoError(t, err)
+	estimate, err := client.EstimateGas("public", common.ZeroAddress, codes, 0)
+	require.NoError(t, err)
+	require.NotZero(t, estimate.Gas)
+	require.True(t, estimate.GasUsed <= estimate.Gas)
+
+	// the tx fails with less gas than the estimate
+	tx, err := core.NewTxWithGas("public", common.ZeroAddress, codes, 0, "", estimate.Gas-1, client.GetPrivKey())
+	require.NoError(t, err)
+	status, err := client.AddTx(tx)
+	require.NoError(t, err)
+	require.Equal(t, pb.TxCode_REVERTED, status.Code)
+
+	tx, err = core.NewTxWithGas("public", common.ZeroAddress, codes, 0, "", estimate.Gas, client.GetPrivKey())
+	require.NoError(t, err)
+	status, err = client.AddTx(tx)
+	require.NoError(t, err)
+	require.Equal(t, pb.TxCode_SUCCESS, status.Code)
+	require.Equal(t, estimate.Gas, status.GasLimit)
+	require.Equal(t, estimate.GasUsed, status.GasUsed)
+	require.Equal(t, estimate.Tokens, status.Tokens)
+}
+
+func TestAllSoloTrace(t *testing.T) {
+	client, err := getSoloClient()
+	require.NoError(t, err)
+	// the contract stores 42 at slot 1
+	codes, err := hex.DecodeString("602a60015500")
+	require.NoError(t, err)
+	tx, err := core.NewTx("public", common.ZeroAddress, codes, 0, "", client.GetPrivKey())
+	require.NoError(t, err)
+	status, err := client.AddTx(tx)
+	require.NoError(t, err)
+	require.Equal(t, pb.TxCode_SUCCESS, status.Code)
+
+	trace, err := client.TraceTransaction("public", tx.ID, lib.TraceOptions{})
+	require.NoError(t, err)
+	require.Equal(t, status.Code, trace.Status.Code)
+	require.Equal(t, status.GasUsed, trace.Status.GasUsed)
+	require.Equal(t, status.ContractAddress, trace.Status.ContractAddress)
+	require.Equal(t, "CREATE", trace.Call.Op)
+	require.Equal(t, status.ContractAddress, common.BytesToAddress(trace.Call.To).String())
+	var ops []string
+	for _, step := range trace.Steps {
+		ops = append(ops, step.Op)
+	}
+	require.Equal(t, []string{"PUSH1", "PUSH1", "SSTORE", "STOP"}, ops)
+	var key, value = make([]byte, 32), make([]byte, 32)
+	key[31], value[31] = 1, 42
+	require.Len(t, trace.Steps[2].Stack, 2)
+	require.Equal(t, key, trace.Steps[2].Storage.Key)
+	require.Equal(t, value, trace.Steps[2].Storage.After)
+	require.NotZero(t, trace.Steps[2].GasCost)
+	require.Len(t, trace.StorageChanges, 1)
+	require.Equal(t, make([]byte, 32), trace.StorageChanges[0].Before)
+	require.Equal(t, value, trace.StorageChanges[0].After)
+
+	// the stack and memory are not recorded if disabled
+	trace, err = client.TraceTransaction("public", tx.ID, lib.TraceOptions{DisableStack: true, DisableMemory: true})
+	require.NoError(t, err)
+	require.Len(t, trace.Steps, 4)
+	require.Empty(t, trace.Steps[2].Stack)
+	require.Equal(t, value, trace.Steps[2].Storage.After)
+	// the trace fails if it exceeds the limits
+	_, err = client.TraceTransaction("public", tx.ID, lib.TraceOptions{MaxSteps: 3})
+	require.Error(t, err)
+	_, err = client.TraceTransaction("public", tx.ID, lib.TraceOptions{MaxBytes: 32})
+	require.Error(t, err)
+
+	_, err = client.TraceTransaction("public", "unknown", lib.TraceOptions{})
+	require.Error(t, err)
+}
+
+func TestAllSoloNative(t *testing.T) {
+	client, err := getSoloClient()
+	require.NoError(t, err)
+	channelABI, err := eabi.JSON(strings.NewReader(evm.ChannelInfoABI))
+	require.NoError(t, err)
+	payload, err := channelABI.Pack("channelInfo", "public")
+	require.NoError(t, err)
+	tx, err := core.NewTx("public", core.ChannelInfoContractAddress, payload, 0, "", client.GetPrivKey())
+	require.NoError(t, err)
+	status, err := client.AddTx(tx)
+	require.NoError(t, err)
+	require.Equal(t, pb.TxCode_SUCCESS, status.Code)
+	require.NotZero(t, status.GasUsed)
+	values, err := channelABI.Methods["channelInfo"].Outputs.UnpackValues(status.Output)
+	require.NoError(t, err)
+	require.Equal(t, true, values[0])
+	require.Equal(t, true, values[1])
+
+	tokenABI, err := eabi.JSON(strings.NewReader(evm.TokenInfoABI))
+	require.NoError(t, err)
+	address, err := client.GetPrivKey().PubKey().Address()
+	require.NoError(t, err)
+	token, err := client.GetTokenInfo(address, []byte("public"))
+	require.NoError(t, err)
+	payload, err = tokenABI.Pack("balanceOf", address.Bytes())
+	require.NoError(t, err)
+	tx, err = core.NewTx("public", core.TokenInfoContractAddress, payload, 0, "", client.GetPrivKey())
+	require.NoError(t, err)
+	status, err = client.AddTx(tx)
+	require.NoError(t, err)
+	require.Equal(t, pb.TxCode_SUCCESS, status.Code)
+	require.Equal(t, common.Uint64ToWord256(token).Bytes(), status.Output)
+}
+
+func TestAllSoloWASM(t *testing.T) {
+	client, err := getSoloClient()
+	require.NoError(t, err)
+	// counter increases the last byte of storage at the zero key and
+	// outputs the value, the input is ignored
+	code, err := hex.DecodeString("0061736d0100000001090260027f7f0060000002320303656e760b73746f726167655f676574000003656e760b73746f726167655f736574000003656e76066f757470757400000302010105030100010708010463616c6c00030a23012100410041201000413f413f2d000041016a3a00004100412010014120412010020b")
+	require.NoError(t, err)
+	tx, err := core.NewTx("public", common.ZeroAddress, code, 0, "", client.GetPrivKey())
+	require.NoError(t, err)
+	status, err := client.AddTx(tx)
+	require.NoError(t, err)
+	require.Equal(t, pb.TxCode_SUCCESS, status.Code)
+	contract := common.HexToAddress(status.ContractAddress)
+	for i := uint64(1); i <= 2; i++ {
+		tx, err = core.NewTx("public", contract, []byte{byte(i)}, 0, "", client.GetPrivKey())
+		require.NoError(t, err)
+		status, err = client.AddTx(tx)
+		require.NoError(t, err)
+		require.Equal(t, pb.TxCode_SUCCESS, status.Code)
+		require.NotZero(t, status.GasUsed)
+		require.Equal(t, common.Uint64ToWord256(i).Bytes(), status.Output)
+	}
+}
+
 func TestAllSoloEnd(t *testing.T) {
 	stopSoloPeer()
 	stopSoloOrderer()
 	os.RemoveAll(".orderer")
 	os.RemoveAll(".peer")
 	os.RemoveAll(".client")
+	os.RemoveAll(".reindex")
+	os.RemoveAll(".snapshot")
 }
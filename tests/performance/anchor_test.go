@@ -0,0 +1,119 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package performance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	cc "madledger/blockchain/config"
+	"madledger/common"
+	"madledger/common/crypto"
+	"madledger/common/util"
+	"madledger/core"
+	oc "madledger/orderer/config"
+	orderer "madledger/orderer/server"
+	pb "madledger/protos"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// BenchmarkAnchor adds txs into a user channel of a solo orderer by 32
+// clients, the blocks are stored after they are anchored in _global or not.
+func BenchmarkAnchor(b *testing.B) {
+	for _, syncAnchor := range []bool{true, false} {
+		b.Run(fmt.Sprintf("sync=%v", syncAnchor), func(b *testing.B) {
+			benchmarkAnchor(b, syncAnchor)
+		})
+	}
+}
+
+func benchmarkAnchor(b *testing.B, syncAnchor bool) {
+	const channelID = "anchor"
+	dir := ".anchor"
+	require.NoError(b, initDir(dir))
+	defer os.RemoveAll(dir)
+	cfgFilePath, _ := util.MakeFileAbs("src/madledger/tests/config/orderer/solo_orderer.yaml", gopath)
+	cfg, err := oc.LoadConfig(cfgFilePath)
+	require.NoError(b, err)
+	cfg.Port += 100
+	cfg.BlockChain.Path = filepath.Join(dir, "blocks")
+	cfg.DB.LevelDB.Path = filepath.Join(dir, "leveldb")
+	cfg.BlockChain.SyncAnchor = syncAnchor
+	server, err := orderer.NewServer(cfg)
+	require.NoError(b, err)
+	go server.Start()
+	defer server.Stop()
+	time.Sleep(300 * time.Millisecond)
+
+	conn, err := grpc.Dial(fmt.Sprintf("%s:%d", cfg.Address, cfg.Port), grpc.WithInsecure(), grpc.WithBlock(), grpc.WithTimeout(2*time.Second))
+	require.NoError(b, err)
+	defer conn.Close()
+	client := pb.NewOrdererClient(conn)
+
+	key, err := crypto.GeneratePrivateKey()
+	require.NoError(b, err)
+	admin, err := core.NewMember(key.PubKey(), "admin")
+	require.NoError(b, err)
+	payload, _ := json.Marshal(cc.Payload{
+		ChannelID: channelID,
+		Profile: &cc.Profile{
+			Public:          true,
+			Admins:          []*core.Member{admin},
+			AssetTokenRatio: 1,
+			MaxGas:          10000000,
+		},
+		Version: 1,
+	})
+	tx, err := core.NewTx(core.CONFIGCHANNELID, core.CreateChannelContractAddress, payload, 0, "", key)
+	require.NoError(b, err)
+	pbTx, err := pb.NewTx(tx)
+	require.NoError(b, err)
+	_, err = client.CreateChannel(context.Background(), &pb.CreateChannelRequest{Tx: pbTx})
+	require.NoError(b, err)
+
+	var txs []*pb.Tx
+	for i := 0; i < b.N; i++ {
+		tx, err := core.NewTx(channelID, common.ZeroAddress, []byte(fmt.Sprintf("anchor %d", i)), 0, "", key)
+		require.NoError(b, err)
+		pbTx, err := pb.NewTx(tx)
+		require.NoError(b, err)
+		txs = append(txs, pbTx)
+	}
+	b.ResetTimer()
+	var wg sync.WaitGroup
+	var next int64 = -1
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := atomic.AddInt64(&next, 1)
+				if i >= int64(len(txs)) {
+					return
+				}
+				if _, err := client.AddTx(context.Background(), &pb.AddTxRequest{Tx: txs[i]}); err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	b.StopTimer()
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package performance
+
+import (
+	"madledger/blockchain"
+	"madledger/common/compress"
+	"madledger/core"
+	pb "madledger/protos"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/require"
+)
+
+// BenchmarkCompression stores blocks carrying the deploy payload of balance
+// contract, and reports the bytes per block on disk and over FetchBlock.
+func BenchmarkCompression(b *testing.B) {
+	codes, err := readCodes(BalanceBin)
+	require.NoError(b, err)
+	var txs []*core.Tx
+	for i := 0; i < 10; i++ {
+		txs = append(txs, core.NewTxWithoutSig("test", codes, uint64(i)))
+	}
+
+	for _, compression := range []string{compress.None, compress.Snappy} {
+		b.Run(compression, func(b *testing.B) {
+			dir := ".compress"
+			require.NoError(b, initDir(dir))
+			defer os.RemoveAll(dir)
+			manager, err := blockchain.NewManager("test", dir, compression)
+			require.NoError(b, err)
+			defer manager.Close()
+
+			var wire int
+			prev := core.GenesisBlockPrevHash
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				block := core.NewBlock("test", uint64(i), prev, txs)
+				require.NoError(b, manager.AddBlock(block))
+				prev = block.Hash().Bytes()
+
+				pbBlock, err := pb.NewBlock(block)
+				require.NoError(b, err)
+				data, err := proto.Marshal(pbBlock)
+				require.NoError(b, err)
+				data, err = compress.Encode(compression, data)
+				require.NoError(b, err)
+				wire += len(data)
+			}
+			b.StopTimer()
+			b.ReportMetric(float64(dirSize(b, dir))/float64(b.N), "disk-B/block")
+			b.ReportMetric(float64(wire)/float64(b.N), "wire-B/block")
+		})
+	}
+}
+
+func dirSize(b *testing.B, dir string) int64 {
+	var size int64
+	require.NoError(b, filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	}))
+	return size
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package performance
+
+import (
+	"fmt"
+	cc "madledger/blockchain/config"
+	"madledger/common"
+	"madledger/common/abi"
+	"madledger/common/crypto"
+	"madledger/core"
+	"madledger/peer/channel"
+	"madledger/peer/db"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// BenchmarkRunBlock runs a block of 200 contract calls one by one and in
+// parallel, the txs call their own contracts or the same one.
+func BenchmarkRunBlock(b *testing.B) {
+	const channelID = "runblock"
+	dir := ".runblock"
+	require.NoError(b, initDir(dir))
+	defer os.RemoveAll(dir)
+	ldb, err := db.NewLevelDB(filepath.Join(dir, "leveldb"))
+	require.NoError(b, err)
+	defer ldb.Close()
+	require.NoError(b, ldb.UpdateChannel(channelID, &cc.Profile{
+		Public: true,
+		MaxGas: core.GLOBALGASLIMIT,
+	}))
+	manager, err := channel.NewManager(channelID, filepath.Join(dir, "blocks"), "", nil, ldb, nil, nil)
+	require.NoError(b, err)
+
+	// every sender creates a contract
+	codes, err := readCodes(BalanceBin)
+	require.NoError(b, err)
+	var keys []crypto.PrivateKey
+	var txs []*core.Tx
+	for i := 0; i < 200; i++ {
+		key, err := crypto.GeneratePrivateKey()
+		require.NoError(b, err)
+		tx, err := core.NewTx(channelID, common.ZeroAddress, codes, 0, "", key)
+		require.NoError(b, err)
+		keys = append(keys, key)
+		txs = append(txs, tx)
+	}
+	wb, err := manager.RunBlock(core.NewBlock(channelID, 0, nil, txs))
+	require.NoError(b, err)
+	require.NoError(b, wb.Sync())
+	var contracts []common.Address
+	for _, tx := range txs {
+		status, err := ldb.GetTxStatus(channelID, tx.ID)
+		require.NoError(b, err)
+		require.Empty(b, status.Err)
+		contracts = append(contracts, common.HexToAddress(status.ContractAddress))
+	}
+
+	payload, err := abi.Pack(BalanceAbi, "add", "1")
+	require.NoError(b, err)
+	for _, workload := range []string{"independent", "conflict"} {
+		var txs []*core.Tx
+		for i, key := range keys {
+			contract := contracts[i]
+			if workload == "conflict" {
+				contract = contracts[0]
+			}
+			tx, err := core.NewTx(channelID, contract, payload, 0, "", key)
+			require.NoError(b, err)
+			txs = append(txs, tx)
+		}
+		block := core.NewBlock(channelID, 1, nil, txs)
+		for _, parallel := range []int{1, 2, 8} {
+			b.Run(fmt.Sprintf("%s/parallel%d", workload, parallel), func(b *testing.B) {
+				manager.SetParallel(parallel)
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					// the block is never synced, so every run starts on the same state
+					_, err := manager.RunBlock(block)
+					require.NoError(b, err)
+				}
+			})
+		}
+	}
+}
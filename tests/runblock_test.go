@@ -0,0 +1,210 @@
+// Copyright (c) 2020 THU-Arxan
+// Madledger is licensed under Mulan PSL v2.
+// You can use this software according to the terms and conditions of the Mulan PSL v2.
+// You may obtain a copy of Mulan PSL v2 at:
+//          http://license.coscl.org.cn/MulanPSL2
+// THIS SOFTWARE IS PROVIDED ON AN "AS IS" BASIS, WITHOUT WARRANTIES OF ANY KIND,
+// EITHER EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO NON-INFRINGEMENT,
+// MERCHANTABILITY OR FIT FOR A PARTICULAR PURPOSE.
+// See the Mulan PSL v2 for more details.
+
+package tests
+
+import (
+	"fmt"
+	"io/ioutil"
+	cc "madledger/blockchain/config"
+	"madledger/common"
+	"madledger/common/abi"
+	"madledger/common/crypto"
+	"madledger/common/util"
+	"madledger/core"
+	"madledger/peer/channel"
+	"madledger/peer/db"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestRunBlockDeterminism runs random blocks which conflict a lot both one
+// tx by one tx and in parallel, the state roots, tx status and all records
+// of db should be the same.
+func TestRunBlockDeterminism(t *testing.T) {
+	for _, parallel := range []int{2, 4, 16} {
+		for seed := int64(1); seed <= 3; seed++ {
+			t.Run(fmt.Sprintf("parallel%d/seed%d", parallel, seed), func(t *testing.T) {
+				testRunBlockDeterminism(t, parallel, seed)
+			})
+		}
+	}
+}
+
+func testRunBlockDeterminism(t *testing.T, parallel int, seed int64) {
+	const channelID = "parallel"
+	dir, err := ioutil.TempDir("", "runblock")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	w, err := newWorkload(channelID, seed)
+	require.NoError(t, err)
+	var dbs []db.DB
+	var managers []*channel.Manager
+	for i, n := range []int{1, parallel} {
+		ldb, err := db.NewLevelDB(filepath.Join(dir, fmt.Sprintf("leveldb%d", i)))
+		require.NoError(t, err)
+		defer ldb.Close()
+		require.NoError(t, w.init(ldb))
+		manager, err := channel.NewManager(channelID, filepath.Join(dir, fmt.Sprintf("blocks%d", i)), "", nil, ldb, nil, nil)
+		require.NoError(t, err)
+		manager.SetParallel(n)
+		dbs = append(dbs, ldb)
+		managers = append(managers, manager)
+	}
+
+	for num := uint64(0); num < 12; num++ {
+		block, err := w.nextBlock(num, 40)
+		require.NoError(t, err)
+		var roots [][]byte
+		for i := range managers {
+			wb, err := managers[i].RunBlock(block)
+			require.NoError(t, err)
+			root, err := wb.UpdateStateRoot(channelID, num)
+			require.NoError(t, err)
+			require.NoError(t, wb.Sync())
+			roots = append(roots, root)
+		}
+		require.Equal(t, roots[0], roots[1], "state root of block %d", num)
+		for _, tx := range block.Transactions {
+			serial, err := dbs[0].GetTxStatus(channelID, tx.ID)
+			require.NoError(t, err)
+			status, err := dbs[1].GetTxStatus(channelID, tx.ID)
+			require.NoError(t, err)
+			require.Equal(t, serial, status, "status of tx %s", tx.ID)
+			if tx.GetReceiver() == common.ZeroAddress && serial.Err == "" {
+				w.contracts = append(w.contracts, common.HexToAddress(serial.ContractAddress))
+			}
+		}
+	}
+	serial, err := dumpLevelDB(dbs[0])
+	require.NoError(t, err)
+	state, err := dumpLevelDB(dbs[1])
+	require.NoError(t, err)
+	require.Equal(t, serial, state)
+}
+
+// workload generates blocks whose txs touch a few contracts and accounts
+type workload struct {
+	channelID string
+	rand      *rand.Rand
+	keys      []crypto.PrivateKey
+	codes     []byte
+	contracts []common.Address
+}
+
+func newWorkload(channelID string, seed int64) (*workload, error) {
+	codes, err := readCodes(BalanceBin)
+	if err != nil {
+		return nil, err
+	}
+	w := &workload{
+		channelID: channelID,
+		rand:      rand.New(rand.NewSource(seed)),
+		codes:     codes,
+	}
+	for i := 0; i < 6; i++ {
+		key, err := crypto.GeneratePrivateKey()
+		if err != nil {
+			return nil, err
+		}
+		w.keys = append(w.keys, key)
+	}
+	return w, nil
+}
+
+// init sets the profile of channel and the tokens of senders, some of
+// them run out of token in the blocks
+func (w *workload) init(ldb db.DB) error {
+	if err := ldb.UpdateChannel(w.channelID, &cc.Profile{
+		Public:   true,
+		GasPrice: 1,
+		MaxGas:   200000,
+	}); err != nil {
+		return err
+	}
+	wb := ldb.NewWriteBatch()
+	for i, key := range w.keys {
+		address, err := key.PubKey().Address()
+		if err != nil {
+			return err
+		}
+		var token uint64 = 1 << 40
+		if i%2 == 1 {
+			token = uint64(200000 * (i + 2))
+		}
+		value := make([]byte, 8)
+		for j := range value {
+			value[7-j] = byte(token >> (8 * j))
+		}
+		wb.Put(util.BytesCombine(common.AddressFromChannelID(w.channelID).Bytes(), []byte("token"), address.Bytes()), value)
+	}
+	return wb.Sync()
+}
+
+func (w *workload) nextBlock(num uint64, size int) (*core.Block, error) {
+	var txs []*core.Tx
+	for i := 0; i < size; i++ {
+		key := w.keys[w.rand.Intn(len(w.keys))]
+		var receiver = common.ZeroAddress
+		var payload []byte
+		var value uint64
+		var err error
+		switch op := w.rand.Intn(10); {
+		case num == 0 || op == 0 || len(w.contracts) == 0:
+			payload = w.codes
+		case op == 1:
+			receiver = common.HexToAddress("0x829f6d8cc2a094b5b1d9e2c4e14e38bbb0ee1400")
+			payload = []byte("invalid")
+		case op == 2:
+			// senders have no balance, so the transfer fails in evm
+			receiver = w.contracts[w.rand.Intn(len(w.contracts))]
+			payload, err = abi.Pack(BalanceAbi, "get")
+			value = 1
+		default:
+			// most txs call the first contracts, so they conflict
+			receiver = w.contracts[w.rand.Intn(len(w.contracts))%3]
+			switch w.rand.Intn(5) {
+			case 0:
+				payload, err = abi.Pack(BalanceAbi, "get")
+			case 1:
+				payload, err = abi.Pack(BalanceAbi, "set", fmt.Sprint(w.rand.Intn(10000)))
+			case 2:
+				payload, err = abi.Pack(BalanceAbi, "add", fmt.Sprint(w.rand.Intn(100)))
+			case 3:
+				payload, err = abi.Pack(BalanceAbi, "sub", fmt.Sprint(w.rand.Intn(100)))
+			default:
+				payload, err = abi.Pack(BalanceAbi, "info")
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		tx, err := core.NewTx(w.channelID, receiver, payload, value, "", key)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return core.NewBlock(w.channelID, num, nil, txs), nil
+}
+
+func dumpLevelDB(ldb db.DB) (map[string]string, error) {
+	var records = make(map[string]string)
+	err := ldb.Iterate(func(key, value []byte) error {
+		records[string(key)] = string(value)
+		return nil
+	})
+	return records, err
+}
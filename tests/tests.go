@@ -11,6 +11,8 @@
 package tests
 
 import (
+	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"madledger/blockchain/asset"
@@ -19,7 +21,9 @@ import (
 	"madledger/common"
 	"madledger/common/abi"
 	"madledger/common/crypto"
+	"madledger/common/util"
 	"madledger/core"
+	pb "madledger/protos"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -382,6 +386,9 @@ func testAsset(t *testing.T, client *client.Client) {
 	require.NoError(t, err)
 	require.Equal(t, uint64(5), balance)
 
+	//test named asset
+	testNamedAsset(t, client, issuerKey, falseIssuerKey, receiverKey)
+
 	//4.test exchangeToken a.k.a transfer to channel in orderer execution
 	coreTx = getAssetChannelTx(core.TokenExchangeAddress, common.ZeroAddress, "test", uint64(5), receiverKey)
 	_, err = client.AddTx(coreTx)
@@ -395,6 +402,68 @@ func testAsset(t *testing.T, client *client.Client) {
 	require.NoError(t, err)
 	require.Equal(t, uint64(5), token)
 
+	//test redeem token back to asset, the token is burned in channel first
+	burnTx, err := core.NewTx("test", core.TokenBurnAddress, nil, uint64(3), "", receiverKey)
+	require.NoError(t, err)
+	status, err := client.AddTx(burnTx)
+	require.NoError(t, err)
+	require.Equal(t, pb.TxCode_SUCCESS, status.Code)
+	require.Equal(t, uint64(3), status.Tokens)
+	token, err = client.GetTokenInfo(receiver, []byte("test"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), token)
+
+	//only the admin of channel could redeem with the burning tx
+	redeemPayload := &asset.Payload{Address: receiver, ChannelID: "test", BurnID: burnTx.ID}
+	coreTx = getAssetPayloadChannelTx(core.TokenRedeemAddress, redeemPayload, uint64(3), receiverKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	balance, err = client.GetAccountBalance(receiver)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), balance)
+
+	//the admin takes the owner and the asset from the status of burning tx
+	coreTx, _, err = client.RedeemToken("test", burnTx.ID)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), coreTx.Data.Value)
+
+	balance, err = client.GetAccountBalance(receiver)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), balance)
+	requirePeerAssetBalance(t, client, coreTx, receiver, "", 3)
+	balance, err = client.GetAccountBalance(common.AddressFromChannelID("test"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), balance)
+
+	//the burning tx could not be redeemed again
+	_, _, err = client.RedeemToken("test", burnTx.ID)
+	require.NoError(t, err)
+	balance, err = client.GetAccountBalance(receiver)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), balance)
+
+	//burn more than the token left fail, and it could not be redeemed
+	burnTx, err = core.NewTx("test", core.TokenBurnAddress, nil, uint64(3), "", receiverKey)
+	require.NoError(t, err)
+	status, err = client.AddTx(burnTx)
+	require.NoError(t, err)
+	require.Equal(t, pb.TxCode_FAILED, status.Code)
+	token, err = client.GetTokenInfo(receiver, []byte("test"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), token)
+	_, _, err = client.RedeemToken("test", burnTx.ID)
+	require.Error(t, err)
+
+	//the admin burns its own token and redeems it as asset redeem does, the
+	//asset is lent by receiver and given back at last
+	testRedeemToken(t, client, receiverKey)
+
+	//test escrow
+	testEscrow(t, client, issuerKey, receiverKey)
+
+	//test compliance
+	testCompliance(t, client, issuerKey, receiverKey)
+
 	//test Block Price
 	coreTx, err = core.NewTx("test", common.ZeroAddress, []byte("success"), 0, "", issuerKey)
 	_, err = client.AddTx(coreTx)
@@ -413,6 +482,11 @@ func testAsset(t *testing.T, client *client.Client) {
 	_, err = client.AddTx(coreTx)
 	require.NoError(t, err)
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := client.WatchBilling(ctx, "test")
+	require.NoError(t, err)
+
 	//now add tx that cause due
 	coreTx, err = core.NewTx("test", common.ZeroAddress, []byte("cause due but pass"), 0, "", issuerKey)
 	_, err = client.AddTx(coreTx)
@@ -428,11 +502,24 @@ func testAsset(t *testing.T, client *client.Client) {
 	coreTx, err = core.NewTx("test", common.ZeroAddress, []byte("fail"), 0, "", issuerKey)
 	_, err = client.AddTx(coreTx)
 	require.Error(t, err)
+	billing, err := client.GetChannelBilling("test", 0)
+	require.NoError(t, err)
+	require.True(t, billing.Halted)
+	require.NotZero(t, billing.Due)
+	require.NotEmpty(t, billing.Records)
+	event, err := events.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "test", event.ChannelID)
+	require.True(t, event.Halted)
+	require.NotZero(t, event.Due)
 
 	//now issue money to channel account to wake it
 	coreTx = getAssetChannelTx(core.IssueContractAddress, common.ZeroAddress, "test", uint64(1000000), issuerKey)
 	_, err = client.AddTx(coreTx)
 	require.NoError(t, err)
+	event, err = events.Recv()
+	require.NoError(t, err)
+	require.False(t, event.Halted)
 
 	coreTx, err = core.NewTx("test", common.ZeroAddress, []byte("success again"), 0, "", issuerKey)
 	_, err = client.AddTx(coreTx)
@@ -543,6 +630,11 @@ func testAssetByHTTP(t *testing.T, client *client.HTTPClient) {
 	coreTx, err = core.NewTx("test", common.ZeroAddress, []byte("fail"), 0, "", issuerKey)
 	_, err = client.AddTxByHTTP(coreTx)
 	require.Error(t, err)
+	billing, err := client.GetChannelBillingByHTTP("test", 1)
+	require.NoError(t, err)
+	require.True(t, billing.Halted)
+	require.Len(t, billing.Records, 1)
+	require.Equal(t, billing.Due, billing.Records[0].Due)
 
 	//now issue money to channel account to wake it
 	coreTx = getAssetChannelTx(core.IssueContractAddress, common.ZeroAddress, "test", uint64(1000000), issuerKey)
@@ -554,6 +646,348 @@ func testAssetByHTTP(t *testing.T, client *client.HTTPClient) {
 	require.NoError(t, err)
 }
 
+func testNamedAsset(t *testing.T, client *client.Client, issuerKey, falseIssuerKey, receiverKey crypto.PrivateKey) {
+	issuer, _ := issuerKey.PubKey().Address()
+	falseIssuer, _ := falseIssuerKey.PubKey().Address()
+	receiver, _ := receiverKey.PubKey().Address()
+
+	//the first issue creates the asset
+	coreTx := getNamedAssetChannelTx(core.IssueContractAddress, issuer, "GOLD", 2, 20, uint64(15), issuerKey)
+	_, err := client.AddTx(coreTx)
+	require.NoError(t, err)
+	info, err := client.GetAccountInfo(issuer, "GOLD")
+	require.NoError(t, err)
+	require.Equal(t, uint64(15), info.Balance)
+	require.Equal(t, uint64(15), info.Supply)
+	require.Equal(t, uint64(20), info.Cap)
+	require.Equal(t, uint32(2), info.Decimals)
+	// native balance is not changed
+	balance, err := client.GetAccountBalance(issuer)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), balance)
+
+	//only the issuer of asset can issue
+	coreTx = getNamedAssetChannelTx(core.IssueContractAddress, falseIssuer, "GOLD", 0, 0, uint64(1), falseIssuerKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	info, err = client.GetAccountInfo(falseIssuer, "GOLD")
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), info.Balance)
+	require.Equal(t, uint64(15), info.Supply)
+
+	//issue can not exceed the cap
+	coreTx = getNamedAssetChannelTx(core.IssueContractAddress, issuer, "GOLD", 0, 0, uint64(10), issuerKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	info, err = client.GetAccountInfo(issuer, "GOLD")
+	require.NoError(t, err)
+	require.Equal(t, uint64(15), info.Balance)
+	require.Equal(t, uint64(15), info.Supply)
+
+	//transfer named asset
+	coreTx = getNamedAssetChannelTx(core.TransferContractrAddress, receiver, "GOLD", 0, 0, uint64(5), issuerKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	info, err = client.GetAccountInfo(receiver, "GOLD")
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), info.Balance)
+	balance, err = client.GetAccountBalance(receiver)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), balance)
+	requirePeerAssetBalance(t, client, coreTx, receiver, "GOLD", 5)
+
+	//transfer more than balance fail
+	coreTx = getNamedAssetChannelTx(core.TransferContractrAddress, issuer, "GOLD", 0, 0, uint64(6), receiverKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	info, err = client.GetAccountInfo(receiver, "GOLD")
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), info.Balance)
+}
+
+// requirePeerAssetBalance requires the asset balance recorded by peers after
+// every peer runs tx, because the peer answering the query may not be the one
+// that AddTx waits for
+func requirePeerAssetBalance(t *testing.T, client *client.Client, tx *core.Tx, address common.Address, assetID string, expect uint64) {
+	require.NoError(t, client.WaitTx(tx))
+	balance, err := client.GetPeerAssetBalance(address, assetID)
+	require.NoError(t, err)
+	require.Equal(t, expect, balance)
+}
+
+// testRedeemToken burns the token of client and redeems it in the way of
+// client asset redeem, the asset exchanged is lent by lender
+func testRedeemToken(t *testing.T, client *client.Client, lenderKey crypto.PrivateKey) {
+	lender, err := lenderKey.PubKey().Address()
+	require.NoError(t, err)
+	self, err := client.GetPrivKey().PubKey().Address()
+	require.NoError(t, err)
+	lent, err := client.GetAccountBalance(lender)
+	require.NoError(t, err)
+	channelBalance, err := client.GetAccountBalance(common.AddressFromChannelID("test"))
+	require.NoError(t, err)
+
+	coreTx := getAssetChannelTx(core.TransferContractrAddress, self, "", lent, lenderKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	coreTx = getAssetChannelTx(core.TokenExchangeAddress, common.ZeroAddress, "test", lent, client.GetPrivKey())
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	balance, err := client.GetAccountBalance(self)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), balance)
+
+	burnTx, status, err := client.BurnToken("test", lent)
+	require.NoError(t, err)
+	require.Equal(t, pb.TxCode_SUCCESS, status.Code, status.Err)
+	coreTx, _, err = client.RedeemToken("test", burnTx.ID)
+	require.NoError(t, err)
+	require.Equal(t, lent, coreTx.Data.Value)
+	requirePeerAssetBalance(t, client, coreTx, self, "", lent)
+	balance, err = client.GetAccountBalance(self)
+	require.NoError(t, err)
+	require.Equal(t, lent, balance)
+	balance, err = client.GetAccountBalance(common.AddressFromChannelID("test"))
+	require.NoError(t, err)
+	require.Equal(t, channelBalance, balance)
+
+	coreTx = getAssetChannelTx(core.TransferContractrAddress, lender, "", lent, client.GetPrivKey())
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	balance, err = client.GetAccountBalance(lender)
+	require.NoError(t, err)
+	require.Equal(t, lent, balance)
+}
+
+// testEscrow is called when issuer has 5 native and 10 GOLD, receiver has 3 native and 5 GOLD
+func testEscrow(t *testing.T, client *client.Client, issuerKey, receiverKey crypto.PrivateKey) {
+	issuer, _ := issuerKey.PubKey().Address()
+	receiver, _ := receiverKey.PubKey().Address()
+	preimage := []byte("madledger")
+	hashLock := sha256.Sum256(preimage)
+
+	//lock native asset with hash lock
+	htlcTx := getAssetPayloadChannelTx(core.EscrowContractAddress, &asset.Payload{
+		Address:    receiver,
+		HashLock:   hashLock[:],
+		ExpireTime: util.Now() + 3600,
+	}, uint64(2), issuerKey)
+	_, err := client.AddTx(htlcTx)
+	require.NoError(t, err)
+	balance, err := client.GetAccountBalance(issuer)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), balance)
+
+	//claim with wrong preimage fail
+	coreTx := getAssetPayloadChannelTx(core.ClaimContractAddress, &asset.Payload{
+		EscrowID: htlcTx.ID,
+		Preimage: []byte("wrong"),
+	}, 0, receiverKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	balance, err = client.GetAccountBalance(receiver)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), balance)
+
+	//refund before expiry fail
+	coreTx = getAssetPayloadChannelTx(core.RefundContractAddress, &asset.Payload{
+		EscrowID: htlcTx.ID,
+	}, 0, issuerKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	balance, err = client.GetAccountBalance(issuer)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), balance)
+
+	//claim with the preimage
+	coreTx = getAssetPayloadChannelTx(core.ClaimContractAddress, &asset.Payload{
+		EscrowID: htlcTx.ID,
+		Preimage: preimage,
+	}, 0, receiverKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	balance, err = client.GetAccountBalance(receiver)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), balance)
+	requirePeerAssetBalance(t, client, coreTx, receiver, "", 5)
+
+	//claim twice fail
+	coreTx = getAssetPayloadChannelTx(core.ClaimContractAddress, &asset.Payload{
+		EscrowID: htlcTx.ID,
+		Preimage: preimage,
+	}, 0, receiverKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	balance, err = client.GetAccountBalance(receiver)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), balance)
+
+	//an expired HTLC could not be claimed but refunded
+	htlcTx = getAssetPayloadChannelTx(core.EscrowContractAddress, &asset.Payload{
+		Address:      receiver,
+		AssetID:      "GOLD",
+		HashLock:     hashLock[:],
+		ExpireHeight: 1,
+	}, uint64(4), issuerKey)
+	_, err = client.AddTx(htlcTx)
+	require.NoError(t, err)
+	info, err := client.GetAccountInfo(issuer, "GOLD")
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), info.Balance)
+
+	coreTx = getAssetPayloadChannelTx(core.ClaimContractAddress, &asset.Payload{
+		EscrowID: htlcTx.ID,
+		Preimage: preimage,
+	}, 0, receiverKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	info, err = client.GetAccountInfo(receiver, "GOLD")
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), info.Balance)
+
+	coreTx = getAssetPayloadChannelTx(core.RefundContractAddress, &asset.Payload{
+		EscrowID: htlcTx.ID,
+	}, 0, issuerKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	info, err = client.GetAccountInfo(issuer, "GOLD")
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), info.Balance)
+	requirePeerAssetBalance(t, client, coreTx, issuer, "GOLD", 10)
+
+	//a time-locked transfer could only be claimed after expiry
+	lockTx := getAssetPayloadChannelTx(core.EscrowContractAddress, &asset.Payload{
+		Address:      receiver,
+		ExpireHeight: 1,
+	}, uint64(1), issuerKey)
+	_, err = client.AddTx(lockTx)
+	require.NoError(t, err)
+
+	coreTx = getAssetPayloadChannelTx(core.RefundContractAddress, &asset.Payload{
+		EscrowID: lockTx.ID,
+	}, 0, issuerKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	balance, err = client.GetAccountBalance(issuer)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), balance)
+
+	coreTx = getAssetPayloadChannelTx(core.ClaimContractAddress, &asset.Payload{
+		EscrowID: lockTx.ID,
+	}, 0, receiverKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	balance, err = client.GetAccountBalance(receiver)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), balance)
+}
+
+// testCompliance is called when issuer has 2 native and 10 GOLD, receiver has 6 native and 5 GOLD
+func testCompliance(t *testing.T, client *client.Client, adminKey, receiverKey crypto.PrivateKey) {
+	admin, _ := adminKey.PubKey().Address()
+	receiver, _ := receiverKey.PubKey().Address()
+
+	//only the asset admin could freeze
+	coreTx := getAssetPayloadChannelTx(core.FreezeContractAddress, &asset.Payload{
+		Address: admin,
+	}, 0, receiverKey)
+	_, err := client.AddTx(coreTx)
+	require.NoError(t, err)
+	info, err := client.GetAccountInfo(admin, "")
+	require.NoError(t, err)
+	require.False(t, info.Frozen)
+
+	coreTx = getAssetPayloadChannelTx(core.FreezeContractAddress, &asset.Payload{
+		Address: receiver,
+		Reason:  "AML01",
+	}, 0, adminKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	info, err = client.GetAccountInfo(receiver, "")
+	require.NoError(t, err)
+	require.True(t, info.Frozen)
+
+	//frozen account can not transfer or exchange token
+	coreTx = getAssetChannelTx(core.TransferContractrAddress, admin, "", uint64(1), receiverKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	coreTx = getAssetChannelTx(core.TokenExchangeAddress, common.ZeroAddress, "test", uint64(1), receiverKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	balance, err := client.GetAccountBalance(receiver)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), balance)
+
+	//but it could receive asset
+	coreTx = getAssetChannelTx(core.TransferContractrAddress, receiver, "", uint64(1), adminKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	balance, err = client.GetAccountBalance(receiver)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), balance)
+
+	//burn named asset
+	coreTx = getAssetPayloadChannelTx(core.BurnContractAddress, &asset.Payload{
+		Address: receiver,
+		AssetID: "GOLD",
+		Reason:  "COURT",
+	}, uint64(2), adminKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	info, err = client.GetAccountInfo(receiver, "GOLD")
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), info.Balance)
+	require.Equal(t, uint64(13), info.Supply)
+	balance, err = client.GetPeerAssetBalance(receiver, "GOLD")
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), balance)
+
+	//unfreeze and transfer again
+	coreTx = getAssetPayloadChannelTx(core.UnfreezeContractAddress, &asset.Payload{
+		Address: receiver,
+		Reason:  "AML01",
+	}, 0, adminKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	coreTx = getAssetChannelTx(core.TransferContractrAddress, admin, "", uint64(1), receiverKey)
+	_, err = client.AddTx(coreTx)
+	require.NoError(t, err)
+	balance, err = client.GetAccountBalance(receiver)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), balance)
+
+	records, err := client.GetComplianceHistory(receiver)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	require.Equal(t, asset.ActionFreeze, records[0].Action)
+	require.Equal(t, "AML01", records[0].Reason)
+	require.Equal(t, asset.ActionBurn, records[1].Action)
+	require.Equal(t, "GOLD", records[1].AssetID)
+	require.Equal(t, uint64(2), records[1].Value)
+	require.Equal(t, "COURT", records[1].Reason)
+	require.Equal(t, asset.ActionUnfreeze, records[2].Action)
+	records, err = client.GetComplianceHistory(admin)
+	require.NoError(t, err)
+	require.Len(t, records, 0)
+}
+
+func getAssetPayloadChannelTx(contract common.Address, payload *asset.Payload, value uint64, privKey crypto.PrivateKey) *core.Tx {
+	payloadBytes, _ := json.Marshal(payload)
+	coreTx, _ := core.NewTx(core.ASSETCHANNELID, contract, payloadBytes, value, "", privKey)
+	return coreTx
+}
+
+func getNamedAssetChannelTx(contract, addressInPayload common.Address, assetID string, decimals uint8, cap, value uint64, privKey crypto.PrivateKey) *core.Tx {
+	payload, _ := json.Marshal(asset.Payload{
+		Address:  addressInPayload,
+		AssetID:  assetID,
+		Decimals: decimals,
+		Cap:      cap,
+	})
+	coreTx, _ := core.NewTx(core.ASSETCHANNELID, contract, payload, value, "", privKey)
+	return coreTx
+}
+
 func getAssetChannelTx(contract, addressInPayload common.Address, channelInPayload string, value uint64, privKey crypto.PrivateKey) *core.Tx {
 	payload, _ := json.Marshal(asset.Payload{
 		Address:   addressInPayload,
@@ -17,6 +17,8 @@ import (
 	"io/ioutil"
 	pb "madledger/protos"
 	"os"
+
+	"github.com/syndtr/goleveldb/leveldb"
 )
 
 var (
@@ -37,6 +39,22 @@ func initDir(dir string) error {
 	return nil
 }
 
+// dumpDB return all the records in the leveldb at dir
+func dumpDB(dir string) (map[string]string, error) {
+	db, err := leveldb.OpenFile(dir, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	var records = make(map[string]string)
+	iter := db.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		records[string(iter.Key())] = string(iter.Value())
+	}
+	return records, iter.Error()
+}
+
 func readCodes(file string) ([]byte, error) {
 	data, err := ioutil.ReadFile(file)
 	if err != nil {
@@ -0,0 +1,32 @@
+//  Copyright 2020 The THU-Arxan Authors
+//  This file is part of the evm library.
+//
+//  The evm library is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Lesser General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  The evm library is distributed in the hope that it will be useful,/
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+//  GNU Lesser General Public License for more details.
+//
+//  You should have received a copy of the GNU Lesser General Public License
+//  along with the evm library. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package evm
+
+// Context defines some context
+type Context struct {
+	Input []byte
+	Value uint64
+	Gas   *uint64
+
+	BlockHeight uint64
+	BlockTime   int64
+	Difficulty  uint64
+	GasLimit    uint64
+	GasPrice    uint64
+	CoinBase    []byte
+}
@@ -0,0 +1,54 @@
+//  Copyright 2020 The THU-Arxan Authors
+//  This file is part of the evm library.
+//
+//  The evm library is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Lesser General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  The evm library is distributed in the hope that it will be useful,/
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+//  GNU Lesser General Public License for more details.
+//
+//  You should have received a copy of the GNU Lesser General Public License
+//  along with the evm library. If not, see <http://www.gnu.org/licenses/>.
+//
+
+package core
+
+import "fmt"
+
+// Lengths of hashes in bytes.
+const (
+	HashLength = 32
+)
+
+// Hash represents the 32 byte sha3-256 hash of arbitrary data.
+type Hash [HashLength]byte
+
+// BytesToHash convert bytes to hash
+func BytesToHash(b []byte) Hash {
+	var h Hash
+	h.SetBytes(b)
+	return h
+}
+
+// SetBytes Sets the hash to the value of b. If b is larger than len(h), 'b' will be cropped (from the left).
+func (h *Hash) SetBytes(b []byte) {
+	if len(b) > len(h) {
+		b = b[len(b)-HashLength:]
+	}
+
+	copy(h[HashLength-len(b):], b)
+}
+
+// Bytes return bytes of hash
+func (h Hash) Bytes() []byte {
+	return h[:]
+}
+
+// Hex return hex of hash
+func (h Hash) Hex() string {
+	return fmt.Sprintf("%x", h.Bytes())
+}
@@ -0,0 +1,13 @@
+module github.com/thu-arxan/evm
+
+go 1.14
+
+require (
+	github.com/btcsuite/btcd v0.20.1-beta.0.20200327205923-57d44d022ecb
+	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc
+	github.com/sirupsen/logrus v1.5.0
+	github.com/stretchr/testify v1.5.2-0.20200402113425-012967472b95
+	github.com/tmthrgd/go-hex v0.0.0-20190904060850-447a3041c3bc
+	golang.org/x/crypto v0.0.0-20200311171314-f7b00557c8c4
+	golang.org/x/sys v0.0.0-20200302150141-5c8b2ff67527
+)
@@ -0,0 +1,46 @@
+//  Copyright 2020 The THU-Arxan Authors
+//  This file is part of the evm library.
+//
+//  The evm library is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Lesser General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  The evm library is distributed in the hope that it will be useful,/
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+//  GNU Lesser General Public License for more details.
+//
+//  You should have received a copy of the GNU Lesser General Public License
+//  along with the evm library. If not, see <http://www.gnu.org/licenses/>.
+//
+//  This file is added for MadLedger, see MADLEDGER.md for the changes.
+//
+
+package evm
+
+import (
+	"github.com/thu-arxan/evm/precompile"
+)
+
+// SetPrecompiles sets the precompiled contracts besides the ones of
+// Ethereum, the key is the bytes of the address
+func (evm *EVM) SetPrecompiles(contracts map[string]precompile.Contract) {
+	evm.precompiles = contracts
+}
+
+// getPrecompile return the precompiled contract at address, the ones set by
+// SetPrecompiles come first
+func (evm *EVM) getPrecompile(address Address) (precompile.Contract, bool) {
+	if contract, ok := evm.precompiles[string(address.Bytes())]; ok {
+		return contract, true
+	}
+	if !precompile.IsPrecompile(address.Bytes()) {
+		return nil, false
+	}
+	contract, err := precompile.New(address.Bytes())
+	if err != nil {
+		return nil, false
+	}
+	return contract, true
+}
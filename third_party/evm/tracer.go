@@ -0,0 +1,59 @@
+//  Copyright 2020 The THU-Arxan Authors
+//  This file is part of the evm library.
+//
+//  The evm library is free software: you can redistribute it and/or modify
+//  it under the terms of the GNU Lesser General Public License as published by
+//  the Free Software Foundation, either version 3 of the License, or
+//  (at your option) any later version.
+//
+//  The evm library is distributed in the hope that it will be useful,/
+//  but WITHOUT ANY WARRANTY; without even the implied warranty of
+//  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+//  GNU Lesser General Public License for more details.
+//
+//  You should have received a copy of the GNU Lesser General Public License
+//  along with the evm library. If not, see <http://www.gnu.org/licenses/>.
+//
+//  This file is added for MadLedger, see MADLEDGER.md for the changes.
+//
+
+package evm
+
+import (
+	"math/big"
+
+	"github.com/thu-arxan/evm/core"
+)
+
+// Tracer is notified while the evm runs, the stack and memory passed to it
+// should never be changed by it
+type Tracer interface {
+	// CaptureStep is called before the op at pc runs, depth is 1 for the
+	// call or create of tx
+	CaptureStep(depth, pc uint64, op OpCode, gas uint64, stack *Stack, memory Memory)
+	// CaptureEnter is called when a call or create starts, op is the op
+	// which starts it, which is CALL or CREATE for the tx
+	CaptureEnter(depth uint64, op OpCode, caller, callee Address, input []byte, value, gas uint64)
+	// CaptureExit is called when the call or create entered last returns
+	CaptureExit(output []byte, gasLeft uint64, err error)
+}
+
+// SetTracer sets the tracer of evm, nothing is traced if tracer is nil
+func (evm *EVM) SetTracer(tracer Tracer) {
+	evm.tracer = tracer
+}
+
+// Data return a copy of the elements in stack, the top is the last one
+func (st *Stack) Data() []core.Word256 {
+	var data = make([]core.Word256, st.ptr)
+	for i := range data {
+		data[i] = core.BytesToWord256(st.data[i].Bytes())
+	}
+	return data
+}
+
+// MemoryData return a copy of all the bytes in memory
+func MemoryData(memory Memory) []byte {
+	data, _ := memory.Read(big.NewInt(0), memory.Capacity())
+	return data
+}